@@ -29,9 +29,11 @@ import (
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
 	diceorc "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice"
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/actor"
 	"github.com/KirkDiggler/rpg-api/internal/pkg/clock"
 	"github.com/KirkDiggler/rpg-api/internal/pkg/idgen"
 	"github.com/KirkDiggler/rpg-api/internal/redis"
+	campaignrepo "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
 	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
 	characterdraftrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft"
 	dicesessionrepo "github.com/KirkDiggler/rpg-api/internal/repositories/dice_session"
@@ -42,6 +44,9 @@ var (
 	grpcPort int
 )
 
+// deletedCharacterSweepInterval is how often expired soft deleted characters are purged
+const deletedCharacterSweepInterval = time.Hour
+
 var serverCmd = &cobra.Command{
 	Use:   "server",
 	Short: "Start the gRPC server",
@@ -75,10 +80,12 @@ func runServer(_ *cobra.Command, _ []string) error {
 		grpc.ChainUnaryInterceptor(
 			grpc_logging.UnaryServerInterceptor(grpc_logging.LoggerFunc(logFunc)),
 			grpc_recovery.UnaryServerInterceptor(),
+			actor.UnaryServerInterceptor(),
 		),
 		grpc.ChainStreamInterceptor(
 			grpc_logging.StreamServerInterceptor(grpc_logging.LoggerFunc(logFunc)),
 			grpc_recovery.StreamServerInterceptor(),
+			actor.StreamServerInterceptor(),
 		),
 	)
 
@@ -133,31 +140,53 @@ func runServer(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("failed to create dice service: %w", err)
 	}
 
-	// Create encounter repository (in-memory for now)
-	encounterRepo := encountersrepo.NewInMemory()
-
-	// Create encounter service
-	encounterService, err := encounter.NewOrchestrator(&encounter.Config{
-		IDGenerator: idgen.NewPrefixed("enc-"),
-		Repository:  encounterRepo,
+	campaignRepo, err := campaignrepo.NewRedis(&campaignrepo.Config{
+		Client:      mustRedisClient(),
+		Clock:       clock.New(),
+		IDGenerator: idgen.NewPrefixed("campaign-"),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to create encounter service: %w", err)
+		return fmt.Errorf("failed to create campaign repository: %w", err)
+	}
+
+	encounterRepo, err := newEncounterRepository()
+	if err != nil {
+		return fmt.Errorf("failed to create encounter repository: %w", err)
 	}
 
 	// Initialize services
 	characterService, err := character.New(&character.Config{
 		CharacterRepo:      charRepo,
 		CharacterDraftRepo: draftRepo,
+		CampaignRepo:       campaignRepo,
 		ExternalClient:     client,
 		DiceService:        diceService,
 		IDGenerator:        idgen.NewUUID("char"),
 		DraftIDGenerator:   idgen.NewUUID("draft"),
+		// Set CHARACTER_SINGLE_ACTIVE_SESSION=true to keep each character to one session
+		SingleActiveSession: os.Getenv("CHARACTER_SINGLE_ACTIVE_SESSION") == "true",
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create character service: %w", err)
 	}
 
+	// Create encounter service
+	encounterService, err := encounter.NewOrchestrator(&encounter.Config{
+		IDGenerator:      idgen.NewPrefixed("enc-"),
+		Repository:       encounterRepo,
+		DiceService:      diceService,
+		CharacterRepo:    charRepo,
+		ExternalClient:   client,
+		CharacterService: characterService,
+		CampaignRepo:     campaignRepo,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create encounter service: %w", err)
+	}
+
+	// Permanently delete characters whose restore window has passed
+	go sweepDeletedCharacters(ctx, characterService)
+
 	// Initialize handlers
 	characterHandler, err := v1alpha1.NewHandler(&v1alpha1.HandlerConfig{
 		CharacterService: characterService,
@@ -231,6 +260,24 @@ func runServer(_ *cobra.Command, _ []string) error {
 	}
 }
 
+// sweepDeletedCharacters purges expired soft deleted characters until the context is cancelled
+func sweepDeletedCharacters(ctx context.Context, characterService character.Service) {
+	ticker := time.NewTicker(deletedCharacterSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := characterService.PurgeDeletedCharacters(ctx, &character.PurgeDeletedCharactersInput{})
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to purge deleted characters", "error", err)
+			}
+		}
+	}
+}
+
 func logFunc(_ context.Context, level grpc_logging.Level, msg string, fields ...any) {
 	// Extract useful information from fields
 	var method, code, errorMsg string
@@ -279,6 +326,34 @@ func logFunc(_ context.Context, level grpc_logging.Level, msg string, fields ...
 	}
 }
 
+// newEncounterRepository picks the encounter backend from ENCOUNTER_STORE: "redis" keeps
+// encounters across restarts and replicas, "memory" (the default) keeps them in process.
+// ENCOUNTER_TTL (e.g. "12h") sets how long an abandoned encounter is kept in Redis.
+func newEncounterRepository() (encountersrepo.Repository, error) {
+	store := os.Getenv("ENCOUNTER_STORE")
+	switch store {
+	case "", "memory":
+		slog.Info("using in-memory encounter repository")
+		return encountersrepo.NewInMemory(), nil
+	case "redis":
+		var ttl time.Duration
+		if raw := os.Getenv("ENCOUNTER_TTL"); raw != "" {
+			var err error
+			ttl, err = time.ParseDuration(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ENCOUNTER_TTL %q: %w", raw, err)
+			}
+		}
+		slog.Info("using Redis encounter repository", "ttl", ttl)
+		return encountersrepo.NewRedis(&encountersrepo.RedisConfig{
+			Client: mustRedisClient(),
+			TTL:    ttl,
+		})
+	default:
+		return nil, fmt.Errorf("unknown ENCOUNTER_STORE %q, expected \"memory\" or \"redis\"", store)
+	}
+}
+
 func mustRedisClient() redis.Client {
 	redisAddr := os.Getenv("REDIS_ADDR")
 	if redisAddr == "" {
@@ -99,10 +99,28 @@ type Client interface {
 
 	// GetFeatureData fetches feature information from external source
 	GetFeatureData(ctx context.Context, featureID string) (*FeatureData, error)
+
+	// GetMagicItemData fetches magic item information from the SRD magic-items endpoint
+	GetMagicItemData(ctx context.Context, itemID string) (*MagicItemData, error)
+
+	// ListAvailableMagicItems returns all available magic items with full details
+	ListAvailableMagicItems(ctx context.Context) ([]*MagicItemData, error)
+
+	// GetMonsterData fetches a monster stat block by its SRD index, e.g. "goblin"
+	GetMonsterData(ctx context.Context, monsterID string) (*MonsterData, error)
+
+	// ListMonstersByChallengeRating lists the SRD monsters with exactly the given challenge rating
+	ListMonstersByChallengeRating(ctx context.Context, challengeRating float32) ([]*MonsterSummaryData, error)
 }
 
 type client struct {
 	dnd5eClient dnd5e.Interface
+
+	// The dnd5e-api client doesn't cover magic items, so they are fetched directly
+	httpClient     *http.Client
+	baseURL        string
+	cacheTTL       time.Duration
+	magicItemCache sync.Map
 }
 
 // toAPIFormat converts our internal constant format to API format
@@ -179,6 +197,9 @@ func New(cfg *Config) (Client, error) {
 
 	return &client{
 		dnd5eClient: cachedClient,
+		httpClient:  httpClient,
+		baseURL:     cfg.BaseURL,
+		cacheTTL:    cfg.CacheTTL,
 	}, nil
 }
 
@@ -1217,6 +1238,15 @@ func convertEquipmentToEquipmentData(equipment dnd5e.EquipmentInterface) *Equipm
 				equipmentData.Properties[i] = prop.Name
 			}
 		}
+		if eq.TwoHandedDamage != nil {
+			equipmentData.TwoHandedDamage = &DamageData{
+				DamageDice: eq.TwoHandedDamage.DamageDice,
+			}
+			if eq.TwoHandedDamage.DamageType != nil {
+				equipmentData.TwoHandedDamage.DamageType = eq.TwoHandedDamage.DamageType.Name
+			}
+		}
+		equipmentData.Range, equipmentData.ThrowRange = weaponRanges(eq)
 
 	case *entities.Armor:
 		equipmentData.ID = eq.Key
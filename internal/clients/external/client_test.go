@@ -288,6 +288,47 @@ func TestGetEquipmentData(t *testing.T) {
 		mockClient.AssertExpectations(t)
 	})
 
+	t.Run("weapon ranges and two-handed damage", func(t *testing.T) {
+		mockClient := new(mockDND5eClient)
+		client := &client{dnd5eClient: mockClient}
+
+		spear := &entities.Weapon{
+			Key:             "spear",
+			Name:            "Spear",
+			WeaponCategory:  "Simple",
+			WeaponRange:     "Melee",
+			Range:           &entities.Range{Normal: 5},
+			Damage:          &entities.Damage{DamageDice: "1d6", DamageType: &entities.ReferenceItem{Name: "Piercing"}},
+			TwoHandedDamage: &entities.Damage{DamageDice: "1d8", DamageType: &entities.ReferenceItem{Name: "Piercing"}},
+			Properties:      []*entities.ReferenceItem{{Name: "Thrown"}, {Name: "Versatile"}},
+		}
+		longbow := &entities.Weapon{
+			Key:            "longbow",
+			Name:           "Longbow",
+			WeaponCategory: "Martial",
+			WeaponRange:    "Ranged",
+			Range:          &entities.Range{Normal: 150},
+			Damage:         &entities.Damage{DamageDice: "1d8", DamageType: &entities.ReferenceItem{Name: "Piercing"}},
+		}
+
+		mockClient.On("GetEquipment", "spear").Return(spear, nil)
+		mockClient.On("GetEquipment", "longbow").Return(longbow, nil)
+
+		result, err := client.GetEquipmentData(context.Background(), "spear")
+		assert.NoError(t, err)
+		assert.Equal(t, "1d8", result.TwoHandedDamage.DamageDice)
+		assert.Equal(t, "Piercing", result.TwoHandedDamage.DamageType)
+		assert.Nil(t, result.Range)
+		assert.Equal(t, &WeaponRangeData{Normal: 20, Long: 60}, result.ThrowRange)
+
+		result, err = client.GetEquipmentData(context.Background(), "longbow")
+		assert.NoError(t, err)
+		assert.Equal(t, &WeaponRangeData{Normal: 150, Long: 600}, result.Range)
+		assert.Nil(t, result.ThrowRange)
+
+		mockClient.AssertExpectations(t)
+	})
+
 	t.Run("equipment not found", func(t *testing.T) {
 		mockClient := new(mockDND5eClient)
 		client := &client{dnd5eClient: mockClient}
@@ -0,0 +1,233 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// magicItemPrefix is the prefix for magic item IDs in our internal format
+	magicItemPrefix = "MAGIC_ITEM"
+
+	magicItemListCacheKey = "list:magic-items"
+
+	// maxConcurrentMagicItemLoads caps the requests made to the SRD API while listing magic items
+	maxConcurrentMagicItemLoads = 8
+)
+
+var (
+	// Passive bonuses are only described in prose, so they are parsed from the description
+	acBonusPattern     = regexp.MustCompile(`\+(\d+) bonus to AC`)
+	saveBonusPattern   = regexp.MustCompile(`\+(\d+) bonus to (?:AC and )?saving throws`)
+	attackBonusPattern = regexp.MustCompile(`\+(\d+) bonus to attack and damage rolls`)
+
+	attunementPattern  = regexp.MustCompile(`requires attunement(?: (by [^)]+))?`)
+	variantNamePattern = regexp.MustCompile(`^(.+), \+\d+$`)
+)
+
+// magicItemCacheEntry holds a cached magic item response with its fetch time
+type magicItemCacheEntry struct {
+	data      interface{}
+	timestamp time.Time
+}
+
+// apiReference is a reference to another resource in the SRD API
+type apiReference struct {
+	Index string `json:"index"`
+	Name  string `json:"name"`
+}
+
+// magicItemListResponse is the SRD response for listing magic items
+type magicItemListResponse struct {
+	Count   int             `json:"count"`
+	Results []*apiReference `json:"results"`
+}
+
+// magicItemResponse is the SRD response for a single magic item
+type magicItemResponse struct {
+	Index             string        `json:"index"`
+	Name              string        `json:"name"`
+	EquipmentCategory *apiReference `json:"equipment_category"`
+	Rarity            *struct {
+		Name string `json:"name"`
+	} `json:"rarity"`
+	Desc    []string `json:"desc"`
+	Variant bool     `json:"variant"`
+}
+
+// IsMagicItemID reports whether an item ID refers to a magic item rather than mundane equipment
+func IsMagicItemID(itemID string) bool {
+	return strings.HasPrefix(itemID, magicItemPrefix+"_")
+}
+
+// magicItemAPIIndex converts "MAGIC_ITEM_CLOAK_OF_PROTECTION" to "cloak-of-protection"
+func magicItemAPIIndex(itemID string) string {
+	index := strings.TrimPrefix(itemID, magicItemPrefix+"_")
+	return strings.ToLower(strings.ReplaceAll(index, "_", "-"))
+}
+
+func (c *client) GetMagicItemData(ctx context.Context, itemID string) (*MagicItemData, error) {
+	if itemID == "" {
+		return nil, fmt.Errorf("magic item ID is required")
+	}
+
+	index := magicItemAPIIndex(itemID)
+	cacheKey := "magic-item:" + index
+	if cached, ok := c.getCachedMagicItem(cacheKey); ok {
+		if item, ok := cached.(*MagicItemData); ok {
+			return item, nil
+		}
+	}
+
+	slog.Info("Calling D&D 5e API to get magic item", "magic_item", itemID, "api", index)
+	var response magicItemResponse
+	if err := c.getSRDResource(ctx, "magic-items/"+index, &response); err != nil {
+		return nil, fmt.Errorf("failed to get magic item %s (api: %s): %w", itemID, index, err)
+	}
+
+	item := convertMagicItemResponse(&response)
+	c.magicItemCache.Store(cacheKey, &magicItemCacheEntry{data: item, timestamp: time.Now()})
+
+	return item, nil
+}
+
+func (c *client) ListAvailableMagicItems(ctx context.Context) ([]*MagicItemData, error) {
+	if cached, ok := c.getCachedMagicItem(magicItemListCacheKey); ok {
+		if items, ok := cached.([]*MagicItemData); ok {
+			return items, nil
+		}
+	}
+
+	var list magicItemListResponse
+	if err := c.getSRDResource(ctx, "magic-items", &list); err != nil {
+		return nil, fmt.Errorf("failed to list magic items from D&D 5e API: %w", err)
+	}
+
+	// Load full details concurrently, individual items are cached after the first call
+	slog.Info("Loading full details for magic items concurrently", "count", len(list.Results))
+	items := make([]*MagicItemData, len(list.Results))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentMagicItemLoads)
+
+	for i, ref := range list.Results {
+		g.Go(func() error {
+			item, err := c.GetMagicItemData(gctx, fromAPIFormat(ref.Index, magicItemPrefix))
+			if err != nil {
+				return err
+			}
+			items[i] = item
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	c.magicItemCache.Store(magicItemListCacheKey, &magicItemCacheEntry{data: items, timestamp: time.Now()})
+
+	return items, nil
+}
+
+// getCachedMagicItem returns a cached entry if present and not expired
+func (c *client) getCachedMagicItem(key string) (interface{}, bool) {
+	cached, ok := c.magicItemCache.Load(key)
+	if !ok {
+		return nil, false
+	}
+	entry, ok := cached.(*magicItemCacheEntry)
+	if !ok || (c.cacheTTL > 0 && time.Since(entry.timestamp) > c.cacheTTL) {
+		c.magicItemCache.Delete(key)
+		return nil, false
+	}
+	return entry.data, true
+}
+
+// getSRDResource fetches a resource path from the SRD API and decodes the JSON body
+func (c *client) getSRDResource(ctx context.Context, path string, out interface{}) error {
+	if c.httpClient == nil {
+		return fmt.Errorf("http client not configured")
+	}
+
+	url := strings.TrimSuffix(c.baseURL, "/") + "/" + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// convertMagicItemResponse converts the SRD magic item to our internal format
+func convertMagicItemResponse(response *magicItemResponse) *MagicItemData {
+	item := &MagicItemData{
+		ID:          fromAPIFormat(response.Index, magicItemPrefix),
+		Name:        response.Name,
+		Description: strings.Join(response.Desc, "\n"),
+		Effects:     &MagicItemEffects{},
+	}
+
+	if response.EquipmentCategory != nil {
+		item.Category = response.EquipmentCategory.Index
+	}
+	if response.Rarity != nil {
+		item.Rarity = response.Rarity.Name
+	}
+
+	// The first description line reads like "Wondrous item, uncommon (requires attunement)"
+	if len(response.Desc) > 0 {
+		if matches := attunementPattern.FindStringSubmatch(response.Desc[0]); matches != nil {
+			item.RequiresAttunement = true
+			item.AttunementRequirement = matches[1]
+		}
+	}
+
+	// Variants such as "Chain Mail, +1" are built on a piece of mundane equipment
+	if response.Variant {
+		if matches := variantNamePattern.FindStringSubmatch(response.Name); matches != nil {
+			item.BaseEquipmentID = fromAPIFormat(generateSlug(matches[1]), "EQUIPMENT")
+		}
+	}
+
+	item.Effects.ACBonus = parseBonus(acBonusPattern, item.Description)
+	item.Effects.SavingThrowBonus = parseBonus(saveBonusPattern, item.Description)
+	item.Effects.AttackBonus = parseBonus(attackBonusPattern, item.Description)
+	item.Effects.DamageBonus = item.Effects.AttackBonus
+
+	return item
+}
+
+// parseBonus extracts the first numeric bonus matched by pattern
+func parseBonus(pattern *regexp.Regexp, description string) int32 {
+	matches := pattern.FindStringSubmatch(description)
+	if len(matches) < 2 {
+		return 0
+	}
+	bonus, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0
+	}
+	// nolint:gosec // magic item bonuses are single digits
+	return int32(bonus)
+}
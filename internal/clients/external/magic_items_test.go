@@ -0,0 +1,111 @@
+package external
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMagicItemTestServer(t *testing.T, calls *int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/magic-items":
+			_, _ = w.Write([]byte(`{"count":2,"results":[
+				{"index":"cloak-of-protection","name":"Cloak of Protection"},
+				{"index":"chain-mail-1","name":"Chain Mail, +1"}]}`))
+		case "/magic-items/cloak-of-protection":
+			_, _ = w.Write([]byte(`{"index":"cloak-of-protection","name":"Cloak of Protection",
+				"equipment_category":{"index":"wondrous-items","name":"Wondrous Items"},
+				"rarity":{"name":"Uncommon"},"variant":false,
+				"desc":["Wondrous item, uncommon (requires attunement)",
+					"You gain a +1 bonus to AC and saving throws while you wear this cloak."]}`))
+		case "/magic-items/chain-mail-1":
+			_, _ = w.Write([]byte(`{"index":"chain-mail-1","name":"Chain Mail, +1",
+				"equipment_category":{"index":"armor","name":"Armor"},
+				"rarity":{"name":"Rare"},"variant":true,
+				"desc":["Armor (chain mail), rare","You have a +1 bonus to AC while wearing this armor."]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestGetMagicItemData(t *testing.T) {
+	t.Run("parses attunement and passive bonuses", func(t *testing.T) {
+		var calls int32
+		server := newMagicItemTestServer(t, &calls)
+		defer server.Close()
+
+		client := &client{httpClient: server.Client(), baseURL: server.URL + "/", cacheTTL: time.Hour}
+
+		item, err := client.GetMagicItemData(context.Background(), "MAGIC_ITEM_CLOAK_OF_PROTECTION")
+
+		require.NoError(t, err)
+		assert.Equal(t, "MAGIC_ITEM_CLOAK_OF_PROTECTION", item.ID)
+		assert.Equal(t, "wondrous-items", item.Category)
+		assert.Equal(t, "Uncommon", item.Rarity)
+		assert.True(t, item.RequiresAttunement)
+		assert.Equal(t, int32(1), item.Effects.ACBonus)
+		assert.Equal(t, int32(1), item.Effects.SavingThrowBonus)
+
+		// Second call is served from the cache
+		_, err = client.GetMagicItemData(context.Background(), "MAGIC_ITEM_CLOAK_OF_PROTECTION")
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("variant armor references its base equipment", func(t *testing.T) {
+		var calls int32
+		server := newMagicItemTestServer(t, &calls)
+		defer server.Close()
+
+		client := &client{httpClient: server.Client(), baseURL: server.URL}
+
+		item, err := client.GetMagicItemData(context.Background(), "MAGIC_ITEM_CHAIN_MAIL_1")
+
+		require.NoError(t, err)
+		assert.False(t, item.RequiresAttunement)
+		assert.Equal(t, "EQUIPMENT_CHAIN_MAIL", item.BaseEquipmentID)
+		assert.Equal(t, int32(1), item.Effects.ACBonus)
+		assert.Equal(t, int32(0), item.Effects.SavingThrowBonus)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		var calls int32
+		server := newMagicItemTestServer(t, &calls)
+		defer server.Close()
+
+		client := &client{httpClient: server.Client(), baseURL: server.URL}
+
+		item, err := client.GetMagicItemData(context.Background(), "MAGIC_ITEM_VORPAL_SPOON")
+
+		assert.Error(t, err)
+		assert.Nil(t, item)
+	})
+}
+
+func TestListAvailableMagicItems(t *testing.T) {
+	var calls int32
+	server := newMagicItemTestServer(t, &calls)
+	defer server.Close()
+
+	client := &client{httpClient: server.Client(), baseURL: server.URL}
+
+	items, err := client.ListAvailableMagicItems(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "MAGIC_ITEM_CLOAK_OF_PROTECTION", items[0].ID)
+	assert.Equal(t, "MAGIC_ITEM_CHAIN_MAIL_1", items[1].ID)
+}
@@ -102,6 +102,36 @@ func (mr *MockClientMockRecorder) GetFeatureData(ctx, featureID any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureData", reflect.TypeOf((*MockClient)(nil).GetFeatureData), ctx, featureID)
 }
 
+// GetMagicItemData mocks base method.
+func (m *MockClient) GetMagicItemData(ctx context.Context, itemID string) (*external.MagicItemData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMagicItemData", ctx, itemID)
+	ret0, _ := ret[0].(*external.MagicItemData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMagicItemData indicates an expected call of GetMagicItemData.
+func (mr *MockClientMockRecorder) GetMagicItemData(ctx, itemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMagicItemData", reflect.TypeOf((*MockClient)(nil).GetMagicItemData), ctx, itemID)
+}
+
+// GetMonsterData mocks base method.
+func (m *MockClient) GetMonsterData(ctx context.Context, monsterID string) (*external.MonsterData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonsterData", ctx, monsterID)
+	ret0, _ := ret[0].(*external.MonsterData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonsterData indicates an expected call of GetMonsterData.
+func (mr *MockClientMockRecorder) GetMonsterData(ctx, monsterID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonsterData", reflect.TypeOf((*MockClient)(nil).GetMonsterData), ctx, monsterID)
+}
+
 // GetRaceData mocks base method.
 func (m *MockClient) GetRaceData(ctx context.Context, raceID string) (*external.RaceDataOutput, error) {
 	m.ctrl.T.Helper()
@@ -177,6 +207,21 @@ func (mr *MockClientMockRecorder) ListAvailableEquipment(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailableEquipment", reflect.TypeOf((*MockClient)(nil).ListAvailableEquipment), ctx)
 }
 
+// ListAvailableMagicItems mocks base method.
+func (m *MockClient) ListAvailableMagicItems(ctx context.Context) ([]*external.MagicItemData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAvailableMagicItems", ctx)
+	ret0, _ := ret[0].([]*external.MagicItemData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAvailableMagicItems indicates an expected call of ListAvailableMagicItems.
+func (mr *MockClientMockRecorder) ListAvailableMagicItems(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailableMagicItems", reflect.TypeOf((*MockClient)(nil).ListAvailableMagicItems), ctx)
+}
+
 // ListAvailableRaces mocks base method.
 func (m *MockClient) ListAvailableRaces(ctx context.Context) ([]*external.RaceData, error) {
 	m.ctrl.T.Helper()
@@ -221,3 +266,18 @@ func (mr *MockClientMockRecorder) ListEquipmentByCategory(ctx, category any) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEquipmentByCategory", reflect.TypeOf((*MockClient)(nil).ListEquipmentByCategory), ctx, category)
 }
+
+// ListMonstersByChallengeRating mocks base method.
+func (m *MockClient) ListMonstersByChallengeRating(ctx context.Context, challengeRating float32) ([]*external.MonsterSummaryData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMonstersByChallengeRating", ctx, challengeRating)
+	ret0, _ := ret[0].([]*external.MonsterSummaryData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMonstersByChallengeRating indicates an expected call of ListMonstersByChallengeRating.
+func (mr *MockClientMockRecorder) ListMonstersByChallengeRating(ctx, challengeRating any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMonstersByChallengeRating", reflect.TypeOf((*MockClient)(nil).ListMonstersByChallengeRating), ctx, challengeRating)
+}
@@ -0,0 +1,162 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+
+	"github.com/fadedpez/dnd5e-api/clients/dnd5e"
+	"github.com/fadedpez/dnd5e-api/entities"
+)
+
+var (
+	// Reach and range are only given in the action's prose, e.g.
+	// "Melee Weapon Attack: +4 to hit, reach 5 ft., one target." or "range 80/320 ft."
+	reachPattern = regexp.MustCompile(`reach (\d+) ft`)
+	rangePattern = regexp.MustCompile(`range (\d+)(?:/(\d+))? ft`)
+
+	speedPattern = regexp.MustCompile(`^(\d+)`)
+)
+
+func (c *client) GetMonsterData(_ context.Context, monsterID string) (*MonsterData, error) {
+	if monsterID == "" {
+		return nil, fmt.Errorf("monster ID is required")
+	}
+
+	// Monster IDs are SRD indexes already, e.g. "goblin" or "giant-rat"
+	slog.Info("Calling D&D 5e API to get monster", "monster", monsterID)
+	apiMonster, err := c.dnd5eClient.GetMonster(monsterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monster %s: %w", monsterID, err)
+	}
+
+	return convertMonster(apiMonster), nil
+}
+
+func (c *client) ListMonstersByChallengeRating(_ context.Context, challengeRating float32) ([]*MonsterSummaryData, error) {
+	rating := float64(challengeRating)
+	slog.Info("Calling D&D 5e API to list monsters", "challenge_rating", rating)
+	monsters, err := c.dnd5eClient.ListMonstersWithFilter(&dnd5e.ListMonstersInput{ChallengeRating: &rating})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list monsters with challenge rating %g: %w", rating, err)
+	}
+
+	summaries := make([]*MonsterSummaryData, 0, len(monsters))
+	for _, monster := range monsters {
+		if monster == nil {
+			continue
+		}
+		summaries = append(summaries, &MonsterSummaryData{
+			ID:              monster.Key,
+			Name:            monster.Name,
+			ChallengeRating: challengeRating,
+		})
+	}
+	return summaries, nil
+}
+
+// convertMonster converts the SRD monster to our internal format
+func convertMonster(monster *entities.Monster) *MonsterData {
+	// nolint:gosec // stat block numbers are small
+	data := &MonsterData{
+		ID:                    monster.Key,
+		Name:                  monster.Name,
+		Size:                  monster.Size,
+		Type:                  monster.Type,
+		Alignment:             monster.Alignment,
+		ArmorClass:            int32(monster.ArmorClass),
+		HitPoints:             int32(monster.HitPoints),
+		HitDice:               monster.HitDice,
+		Speed:                 &MonsterSpeedData{},
+		DamageVulnerabilities: monster.DamageVulnerabilities,
+		DamageResistances:     monster.DamageResistances,
+		DamageImmunities:      monster.DamageImmunities,
+		ChallengeRating:       monster.ChallengeRating,
+		XP:                    int32(monster.XP),
+		AbilityScores: map[string]int32{
+			"str": int32(monster.Strength),
+			"dex": int32(monster.Dexterity),
+			"con": int32(monster.Constitution),
+			"int": int32(monster.Intelligence),
+			"wis": int32(monster.Wisdom),
+			"cha": int32(monster.Charisma),
+		},
+	}
+
+	if monster.Speed != nil {
+		data.Speed = &MonsterSpeedData{
+			Walk:   parseSpeed(monster.Speed.Walk),
+			Fly:    parseSpeed(monster.Speed.Fly),
+			Swim:   parseSpeed(monster.Speed.Swim),
+			Climb:  parseSpeed(monster.Speed.Climb),
+			Burrow: parseSpeed(monster.Speed.Burrow),
+		}
+	}
+
+	for _, immunity := range monster.ConditionImmunities {
+		if immunity != nil {
+			data.ConditionImmunities = append(data.ConditionImmunities, immunity.Key)
+		}
+	}
+
+	for _, action := range monster.MonsterActions {
+		if action != nil {
+			data.Actions = append(data.Actions, convertMonsterAction(action))
+		}
+	}
+
+	return data
+}
+
+// convertMonsterAction converts one SRD monster action, reading reach and range from its description
+func convertMonsterAction(action *entities.MonsterAction) *MonsterActionData {
+	data := &MonsterActionData{
+		Name:        action.Name,
+		Description: action.Description,
+		AttackBonus: int32(action.AttackBonus), // nolint:gosec // attack bonuses are small
+	}
+
+	for _, damage := range action.Damage {
+		if damage == nil || damage.DamageDice == "" {
+			continue // Choice-based damage, such as a form-dependent attack, has no dice here
+		}
+		damageData := &DamageData{DamageDice: damage.DamageDice}
+		if damage.DamageType != nil {
+			// The index matches how resistances are listed, e.g. "slashing"
+			damageData.DamageType = damage.DamageType.Key
+		}
+		data.Damage = append(data.Damage, damageData)
+	}
+
+	if matches := reachPattern.FindStringSubmatch(action.Description); matches != nil {
+		data.ReachFeet = atoi32(matches[1])
+	}
+	if matches := rangePattern.FindStringSubmatch(action.Description); matches != nil {
+		data.Range = &WeaponRangeData{Normal: int(atoi32(matches[1]))}
+		if matches[2] != "" {
+			data.Range.Long = int(atoi32(matches[2]))
+		}
+	}
+
+	return data
+}
+
+// parseSpeed reads the feet from a speed such as "30 ft."
+func parseSpeed(speed string) int32 {
+	matches := speedPattern.FindStringSubmatch(speed)
+	if matches == nil {
+		return 0
+	}
+	return atoi32(matches[1])
+}
+
+func atoi32(s string) int32 {
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	// nolint:gosec // parsed from short digit runs in stat blocks
+	return int32(value)
+}
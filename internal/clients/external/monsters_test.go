@@ -0,0 +1,142 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fadedpez/dnd5e-api/clients/dnd5e"
+	"github.com/fadedpez/dnd5e-api/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMonsterData(t *testing.T) {
+	t.Run("converts the stat block", func(t *testing.T) {
+		mockClient := new(mockDND5eClient)
+		client := &client{dnd5eClient: mockClient}
+
+		monster := &entities.Monster{
+			Key:               "goblin",
+			Name:              "Goblin",
+			Size:              "Small",
+			Type:              "humanoid",
+			ArmorClass:        15,
+			HitPoints:         7,
+			HitDice:           "2d6",
+			Speed:             &entities.Speed{Walk: "30 ft.", Swim: "10 ft."},
+			Strength:          8,
+			Dexterity:         14,
+			Constitution:      10,
+			Intelligence:      10,
+			Wisdom:            8,
+			Charisma:          8,
+			DamageResistances: []string{"fire"},
+			ConditionImmunities: []*entities.ReferenceItem{
+				{Key: "charmed", Name: "Charmed"},
+			},
+			ChallengeRating: 0.25,
+			XP:              50,
+			MonsterActions: []*entities.MonsterAction{
+				{
+					Name:        "Scimitar",
+					AttackBonus: 4,
+					Description: "Melee Weapon Attack: +4 to hit, reach 5 ft., one target. Hit: 5 (1d6 + 2) slashing damage.",
+					Damage: []*entities.Damage{
+						{DamageDice: "1d6+2", DamageType: &entities.ReferenceItem{Key: "slashing", Name: "Slashing"}},
+					},
+				},
+				{
+					Name:        "Shortbow",
+					AttackBonus: 4,
+					Description: "Ranged Weapon Attack: +4 to hit, range 80/320 ft., one target. Hit: 5 (1d6 + 2) piercing damage.",
+					Damage: []*entities.Damage{
+						{DamageDice: "1d6+2", DamageType: &entities.ReferenceItem{Key: "piercing", Name: "Piercing"}},
+					},
+				},
+			},
+		}
+		mockClient.On("GetMonster", "goblin").Return(monster, nil)
+
+		result, err := client.GetMonsterData(context.Background(), "goblin")
+
+		require.NoError(t, err)
+		assert.Equal(t, "goblin", result.ID)
+		assert.Equal(t, int32(15), result.ArmorClass)
+		assert.Equal(t, int32(7), result.HitPoints)
+		assert.Equal(t, "2d6", result.HitDice)
+		assert.Equal(t, &MonsterSpeedData{Walk: 30, Swim: 10}, result.Speed)
+		assert.Equal(t, int32(14), result.AbilityScores["dex"])
+		assert.Equal(t, []string{"fire"}, result.DamageResistances)
+		assert.Equal(t, []string{"charmed"}, result.ConditionImmunities)
+		assert.Equal(t, float32(0.25), result.ChallengeRating)
+		assert.Equal(t, int32(50), result.XP)
+
+		require.Len(t, result.Actions, 2)
+		scimitar, shortbow := result.Actions[0], result.Actions[1]
+		assert.Equal(t, int32(4), scimitar.AttackBonus)
+		assert.Equal(t, int32(5), scimitar.ReachFeet)
+		assert.Nil(t, scimitar.Range)
+		assert.Equal(t, []*DamageData{{DamageDice: "1d6+2", DamageType: "slashing"}}, scimitar.Damage)
+		assert.Zero(t, shortbow.ReachFeet)
+		assert.Equal(t, &WeaponRangeData{Normal: 80, Long: 320}, shortbow.Range)
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		mockClient := new(mockDND5eClient)
+		client := &client{dnd5eClient: mockClient}
+
+		mockClient.On("GetMonster", "tarrasque").Return((*entities.Monster)(nil), errors.New("unexpected status code: 404"))
+
+		result, err := client.GetMonsterData(context.Background(), "tarrasque")
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "failed to get monster")
+
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestListMonstersByChallengeRating(t *testing.T) {
+	t.Run("lists monsters at the rating", func(t *testing.T) {
+		mockClient := new(mockDND5eClient)
+		client := &client{dnd5eClient: mockClient}
+
+		rating := 0.25
+		mockClient.On("ListMonstersWithFilter", &dnd5e.ListMonstersInput{ChallengeRating: &rating}).
+			Return([]*entities.ReferenceItem{
+				{Key: "goblin", Name: "Goblin"},
+				{Key: "skeleton", Name: "Skeleton"},
+			}, nil)
+
+		result, err := client.ListMonstersByChallengeRating(context.Background(), 0.25)
+
+		require.NoError(t, err)
+		assert.Equal(t, []*MonsterSummaryData{
+			{ID: "goblin", Name: "Goblin", ChallengeRating: 0.25},
+			{ID: "skeleton", Name: "Skeleton", ChallengeRating: 0.25},
+		}, result)
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		mockClient := new(mockDND5eClient)
+		client := &client{dnd5eClient: mockClient}
+
+		mockClient.On("ListMonstersWithFilter", mock.Anything).
+			Return([]*entities.ReferenceItem(nil), errors.New("connection refused"))
+
+		result, err := client.ListMonstersByChallengeRating(context.Background(), 2)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "failed to list monsters with challenge rating 2")
+
+		mockClient.AssertExpectations(t)
+	})
+}
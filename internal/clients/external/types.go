@@ -21,6 +21,13 @@ type (
 	CostData            = dnd5e.CostData
 	DamageData          = dnd5e.DamageData
 	ArmorClassData      = dnd5e.ArmorClassData
+	WeaponRangeData     = dnd5e.WeaponRangeData
+	MagicItemData       = dnd5e.MagicItemData
+	MagicItemEffects    = dnd5e.MagicItemEffects
+	MonsterData         = dnd5e.MonsterData
+	MonsterSpeedData    = dnd5e.MonsterSpeedData
+	MonsterActionData   = dnd5e.MonsterActionData
+	MonsterSummaryData  = dnd5e.MonsterSummaryData
 )
 
 // ListSpellsInput represents input for listing spells
@@ -0,0 +1,61 @@
+package external
+
+import (
+	"strings"
+
+	"github.com/fadedpez/dnd5e-api/entities"
+)
+
+// The dnd5e-api client only keeps a weapon's normal range and drops its long and
+// thrown ranges, so the SRD values are filled in from these tables.
+var (
+	srdLongRanges = map[string]int{
+		"blowgun":        100,
+		"crossbow-hand":  120,
+		"crossbow-heavy": 400,
+		"crossbow-light": 320,
+		"dart":           60,
+		"longbow":        600,
+		"net":            15,
+		"shortbow":       320,
+		"sling":          120,
+	}
+
+	srdThrowRanges = map[string]WeaponRangeData{
+		"dagger":       {Normal: 20, Long: 60},
+		"dart":         {Normal: 20, Long: 60},
+		"handaxe":      {Normal: 20, Long: 60},
+		"javelin":      {Normal: 30, Long: 120},
+		"light-hammer": {Normal: 20, Long: 60},
+		"net":          {Normal: 5, Long: 15},
+		"spear":        {Normal: 20, Long: 60},
+		"trident":      {Normal: 20, Long: 60},
+	}
+)
+
+// weaponRanges returns the ranged and thrown ranges for a weapon, nil when not applicable
+func weaponRanges(weapon *entities.Weapon) (*WeaponRangeData, *WeaponRangeData) {
+	var rangeData, throwRange *WeaponRangeData
+
+	if strings.EqualFold(weapon.WeaponRange, "Ranged") && weapon.Range != nil {
+		rangeData = &WeaponRangeData{
+			Normal: weapon.Range.Normal,
+			Long:   srdLongRanges[weapon.Key],
+		}
+		// Unknown ranged weapons fall back to the usual 4x long range
+		if rangeData.Long == 0 {
+			rangeData.Long = rangeData.Normal * 4
+		}
+	}
+
+	for _, prop := range weapon.Properties {
+		if prop == nil || !strings.EqualFold(prop.Name, "Thrown") {
+			continue
+		}
+		if thrown, ok := srdThrowRanges[weapon.Key]; ok {
+			throwRange = &thrown
+		}
+	}
+
+	return rangeData, throwRange
+}
@@ -0,0 +1,30 @@
+package dnd5e
+
+import "strings"
+
+// AbilityModifier returns the modifier for an ability score, rounding down for odd scores
+// below 10, so a score of 9 is -1
+func AbilityModifier(score int) int32 {
+	// nolint:gosec // ability modifiers are small
+	return int32(score/2 - 5)
+}
+
+// ProficiencyBonus returns the proficiency bonus for a character level, treating anything
+// below 1 as level 1
+func ProficiencyBonus(level int) int32 {
+	// nolint:gosec // character levels are 1-20
+	return int32(2 + (max(level, 1)-1)/4)
+}
+
+// HasTool matches tool names ignoring case and apostrophes, e.g. "thieves tools"
+func HasTool(tools []string, tool string) bool {
+	normalize := func(name string) string {
+		return strings.ToLower(strings.ReplaceAll(name, "'", ""))
+	}
+	for _, t := range tools {
+		if normalize(t) == normalize(tool) {
+			return true
+		}
+	}
+	return false
+}
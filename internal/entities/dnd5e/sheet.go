@@ -0,0 +1,42 @@
+package dnd5e
+
+// spellScrollPrefix marks inventory items that are spell scrolls
+const spellScrollPrefix = "spell-scroll:"
+
+// SpellScrollItemID returns the inventory item ID of a spell scroll holding the spell
+func SpellScrollItemID(spellID string) string {
+	return spellScrollPrefix + spellID
+}
+
+// SheetOrEmpty returns the sheet, or an empty one for characters that have never stored one
+func SheetOrEmpty(sheet *CharacterSheet) *CharacterSheet {
+	if sheet == nil {
+		return &CharacterSheet{}
+	}
+	return sheet
+}
+
+// CountItem returns how many copies of an item are in a list of equipment IDs
+func CountItem(items []string, itemID string) int {
+	count := 0
+	for _, item := range items {
+		if item == itemID {
+			count++
+		}
+	}
+	return count
+}
+
+// RemoveItems removes up to quantity occurrences of itemID and reports how many were found
+func RemoveItems(items []string, itemID string, quantity int32) ([]string, int32) {
+	remaining := make([]string, 0, len(items))
+	var removed int32
+	for _, item := range items {
+		if removed < quantity && item == itemID {
+			removed++
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	return remaining, removed
+}
@@ -1,6 +1,9 @@
 package dnd5e
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/KirkDiggler/rpg-api/internal/types/choices"
 )
 
@@ -128,6 +131,12 @@ type EquipmentAPIData struct {
 	WeaponRange    string // "Melee", "Ranged"
 	Damage         *DamageData
 	Properties     []string
+	// TwoHandedDamage is the damage of a versatile weapon wielded with two hands
+	TwoHandedDamage *DamageData
+	// Range is the normal and long range of a ranged weapon
+	Range *WeaponRangeData
+	// ThrowRange is the normal and long range of a weapon with the thrown property
+	ThrowRange *WeaponRangeData
 	// Armor-specific fields
 	ArmorCategory       string // "Light", "Medium", "Heavy"
 	ArmorClass          *ArmorClassData
@@ -168,6 +177,12 @@ type DamageData struct {
 	DamageType string
 }
 
+// WeaponRangeData represents a weapon's normal and long range in feet
+type WeaponRangeData struct {
+	Normal int
+	Long   int
+}
+
 // ArmorClassData represents armor class information
 type ArmorClassData struct {
 	Base     int
@@ -210,3 +225,191 @@ type EncumbranceInfo struct {
 	Encumbered        bool
 	HeavilyEncumbered bool
 }
+
+// MagicItemData represents magic item information from external source
+type MagicItemData struct {
+	ID                    string
+	Name                  string
+	Description           string
+	Category              string // "armor", "weapon", "wondrous-items", "ring", etc.
+	Rarity                string // "Common", "Uncommon", "Rare", "Very Rare", "Legendary"
+	RequiresAttunement    bool
+	AttunementRequirement string // e.g. "by a cleric", empty when anyone can attune
+	BaseEquipmentID       string // Mundane equipment a variant is built on, e.g. chain mail for "Chain Mail, +1"
+	Effects               *MagicItemEffects
+}
+
+// MagicItemEffects represents the passive bonuses a magic item grants
+type MagicItemEffects struct {
+	ACBonus          int32
+	SavingThrowBonus int32
+	AttackBonus      int32
+	DamageBonus      int32
+}
+
+// MonsterData represents a monster stat block from external source
+type MonsterData struct {
+	ID                    string // SRD index, e.g. "goblin"
+	Name                  string
+	Size                  string
+	Type                  string
+	Alignment             string
+	ArmorClass            int32
+	HitPoints             int32  // Average hit points
+	HitDice               string // e.g. "2d6"; add the CON modifier once per die when rolling
+	Speed                 *MonsterSpeedData
+	AbilityScores         map[string]int32 // "str", "dex", "con", "int", "wis", "cha"
+	Actions               []*MonsterActionData
+	DamageVulnerabilities []string
+	DamageResistances     []string
+	DamageImmunities      []string
+	ConditionImmunities   []string
+	ChallengeRating       float32
+	XP                    int32
+}
+
+// MonsterSummaryData represents a monster in an SRD listing, without its stat block
+type MonsterSummaryData struct {
+	ID              string // SRD index, e.g. "goblin"
+	Name            string
+	ChallengeRating float32
+}
+
+// MonsterSpeedData represents a monster's movement speeds in feet, 0 when it lacks one
+type MonsterSpeedData struct {
+	Walk   int32
+	Fly    int32
+	Swim   int32
+	Climb  int32
+	Burrow int32
+}
+
+// MonsterActionData represents one action from a monster stat block
+type MonsterActionData struct {
+	Name        string
+	Description string
+	AttackBonus int32         // 0 for actions that are not attacks, such as Multiattack
+	Damage      []*DamageData // Damage dice include the bonus, e.g. "1d6+2"
+	ReachFeet   int32         // Melee reach, parsed from the description
+	Range       *WeaponRangeData
+}
+
+// Wallet represents the coins a character is carrying
+type Wallet struct {
+	Copper   int32 `json:"copper"`
+	Silver   int32 `json:"silver"`
+	Electrum int32 `json:"electrum"`
+	Gold     int32 `json:"gold"`
+	Platinum int32 `json:"platinum"`
+}
+
+// CharacterSheet holds character state owned by rpg-api that the toolkit
+// character data does not model. It is persisted alongside the character.
+type CharacterSheet struct {
+	Wallet        *Wallet           `json:"wallet,omitempty"`
+	EquippedSlots map[string]string `json:"equipped_slots,omitempty"` // slot -> item ID
+	AttunedItems  []string          `json:"attuned_items,omitempty"`
+	// ToolExpertise lists tools the character has Expertise with, e.g. "Thieves' Tools"
+	ToolExpertise []string `json:"tool_expertise,omitempty"`
+	// AlwaysPreparedSpells are granted by a subclass, such as cleric domain spells
+	AlwaysPreparedSpells []string `json:"always_prepared_spells,omitempty"`
+	// DragonAncestor is the dragon type a Draconic Bloodline sorcerer descends from, e.g. "red"
+	DragonAncestor string `json:"dragon_ancestor,omitempty"`
+	// Spellbook lists the spells a wizard has written in their spellbook
+	Spellbook []string `json:"spellbook,omitempty"`
+	// CopiedSpells are spellbook entries paid for by copying, rather than learned on level up
+	CopiedSpells []string `json:"copied_spells,omitempty"`
+	// PreparedSpells is the list a prepared caster chose after their last long rest
+	PreparedSpells []string `json:"prepared_spells,omitempty"`
+	// PreparedSinceLongRest is set once spells are prepared and cleared by a long rest
+	PreparedSinceLongRest bool `json:"prepared_since_long_rest,omitempty"`
+	// Details is the player-written description of the character
+	Details *CharacterDetails `json:"details,omitempty"`
+	// SharedWithPlayers can view the character read-only
+	SharedWithPlayers []string `json:"shared_with_players,omitempty"`
+	// SharedWithSessions lets anyone viewing from these sessions, such as the DM, see the character read-only
+	SharedWithSessions []string `json:"shared_with_sessions,omitempty"`
+	// PendingTransfer is an ownership transfer waiting for the recipient to accept
+	PendingTransfer *CharacterTransfer `json:"pending_transfer,omitempty"`
+	// SessionIDs are the game sessions the character has joined. The repository keeps the
+	// session index in step with this list.
+	SessionIDs []string `json:"session_ids,omitempty"`
+}
+
+// CharacterTransfer is an owner's offer to give a character to another player
+type CharacterTransfer struct {
+	FromPlayerID string    `json:"from_player_id"`
+	ToPlayerID   string    `json:"to_player_id"`
+	RequestedAt  time.Time `json:"requested_at"`
+}
+
+// Character alignments
+const (
+	AlignmentLawfulGood     = "lawful-good"
+	AlignmentNeutralGood    = "neutral-good"
+	AlignmentChaoticGood    = "chaotic-good"
+	AlignmentLawfulNeutral  = "lawful-neutral"
+	AlignmentTrueNeutral    = "true-neutral"
+	AlignmentChaoticNeutral = "chaotic-neutral"
+	AlignmentLawfulEvil     = "lawful-evil"
+	AlignmentNeutralEvil    = "neutral-evil"
+	AlignmentChaoticEvil    = "chaotic-evil"
+	AlignmentUnaligned      = "unaligned"
+)
+
+// CharacterDetails holds the free-form roleplaying details of a character
+type CharacterDetails struct {
+	Alignment         string `json:"alignment,omitempty"`
+	Appearance        string `json:"appearance,omitempty"`
+	Backstory         string `json:"backstory,omitempty"`
+	PersonalityTraits string `json:"personality_traits,omitempty"`
+	Ideals            string `json:"ideals,omitempty"`
+	Bonds             string `json:"bonds,omitempty"`
+	Flaws             string `json:"flaws,omitempty"`
+	Notes             string `json:"notes,omitempty"`
+}
+
+// Character event types recorded in a character's history
+const (
+	CharacterEventCreated           = "created"
+	CharacterEventUpdated           = "updated"
+	CharacterEventEquipmentChanged  = "equipment_changed"
+	CharacterEventAttunementChanged = "attunement_changed"
+	CharacterEventItemBought        = "item_bought"
+	CharacterEventItemSold          = "item_sold"
+	CharacterEventItemsAdded        = "items_added"
+	CharacterEventSpellsPrepared    = "spells_prepared"
+	CharacterEventSpellbookChanged  = "spellbook_changed"
+	CharacterEventLongRest          = "long_rest"
+	CharacterEventSpellSlotUsed     = "spell_slot_used"
+	CharacterEventDetailsUpdated    = "details_updated"
+	CharacterEventDeleted           = "deleted"
+	CharacterEventRestored          = "restored"
+	CharacterEventSharingChanged    = "sharing_changed"
+	CharacterEventTransferRequested = "transfer_requested"
+	CharacterEventTransferCancelled = "transfer_cancelled"
+	CharacterEventTransferred       = "transferred"
+	CharacterEventJoinedSession     = "joined_session"
+	CharacterEventLeftSession       = "left_session"
+	CharacterEventHistoryCompacted  = "history_compacted"
+	CharacterEventHitPointsChanged  = "hit_points_changed"
+)
+
+// CharacterEvent records a single change to a character and its sheet
+type CharacterEvent struct {
+	CharacterID string                 `json:"character_id"`
+	Sequence    int64                  `json:"sequence"` // Counts up from 1, and keeps counting when history is compacted
+	Type        string                 `json:"type"`
+	ActorID     string                 `json:"actor_id,omitempty"`
+	Timestamp   time.Time              `json:"timestamp"`
+	Changes     []CharacterFieldChange `json:"changes"`
+}
+
+// CharacterFieldChange is the before and after JSON value of one top level field.
+// Field is prefixed with "character." or "sheet.", e.g. "character.hit_points".
+// A missing Before or After means the field was absent.
+type CharacterFieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
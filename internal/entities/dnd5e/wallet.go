@@ -0,0 +1,89 @@
+package dnd5e
+
+// Copper value of each coin
+const (
+	CopperPerSilver   = 10
+	CopperPerElectrum = 50
+	CopperPerGold     = 100
+	CopperPerPlatinum = 1000
+)
+
+// TotalCopper returns the value of every coin in the wallet in copper pieces. A nil
+// wallet is empty.
+func (w *Wallet) TotalCopper() int64 {
+	if w == nil {
+		return 0
+	}
+	return int64(w.Copper) +
+		int64(w.Silver)*CopperPerSilver +
+		int64(w.Electrum)*CopperPerElectrum +
+		int64(w.Gold)*CopperPerGold +
+		int64(w.Platinum)*CopperPerPlatinum
+}
+
+// Spend returns the wallet left after paying copper, or false if the amount is negative
+// or the wallet holds too little. Copper, silver and gold are paid smallest first, and
+// electrum and platinum only once those run out. When no exact payment is left, the first coin in that order that
+// covers the rest is broken and the change comes back in gold, silver and copper.
+func (w *Wallet) Spend(copper int64) (*Wallet, bool) {
+	if copper < 0 || copper > w.TotalCopper() {
+		return nil, false
+	}
+
+	left := Wallet{}
+	if w != nil {
+		left = *w
+	}
+	coins := left.paymentOrder()
+	for _, coin := range coins {
+		paid := min(int64(*coin.count), copper/coin.value)
+		// nolint:gosec // paid is at most the coin count
+		*coin.count -= int32(paid)
+		copper -= paid * coin.value
+	}
+	if copper > 0 {
+		for _, coin := range coins {
+			if *coin.count > 0 {
+				*coin.count--
+				return left.Receive(coin.value - copper), true
+			}
+		}
+	}
+	return &left, true
+}
+
+// Receive returns the wallet after adding copper, made up in gold, silver and copper
+func (w *Wallet) Receive(copper int64) *Wallet {
+	received := Wallet{}
+	if w != nil {
+		received = *w
+	}
+	gold := copper / CopperPerGold
+	copper -= gold * CopperPerGold
+	silver := copper / CopperPerSilver
+	copper -= silver * CopperPerSilver
+
+	// nolint:gosec // wallet totals are far below int32 limits
+	received.Gold += int32(gold)
+	// nolint:gosec // less than 10 silver
+	received.Silver += int32(silver)
+	// nolint:gosec // less than 10 copper
+	received.Copper += int32(copper)
+	return &received
+}
+
+type coin struct {
+	count *int32
+	value int64
+}
+
+// paymentOrder lists the wallet's coins in the order Spend pays with them
+func (w *Wallet) paymentOrder() []coin {
+	return []coin{
+		{&w.Copper, 1},
+		{&w.Silver, CopperPerSilver},
+		{&w.Gold, CopperPerGold},
+		{&w.Electrum, CopperPerElectrum},
+		{&w.Platinum, CopperPerPlatinum},
+	}
+}
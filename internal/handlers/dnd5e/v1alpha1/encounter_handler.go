@@ -10,6 +10,8 @@ import (
 	dnd5ev1alpha1 "github.com/KirkDiggler/rpg-api-protos/gen/go/dnd5e/api/v1alpha1"
 	"github.com/KirkDiggler/rpg-api/internal/errors"
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/actor"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
 	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/initiative"
 	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
 )
@@ -67,6 +69,7 @@ func (h *EncounterHandler) DungeonStart(
 	protoCombatState := convertInitiativeDataToProto(
 		output.EncounterID,
 		output.InitiativeData,
+		output.Initiative,
 		output.CurrentTurn,
 	)
 
@@ -126,23 +129,31 @@ func convertRoomDataToProto(roomData *spatial.RoomData) *dnd5ev1alpha1.Room {
 func convertInitiativeDataToProto(
 	encounterID string,
 	initiativeData *initiative.TrackerData,
+	initiativeRolls []*encounters.InitiativeEntryData,
 	currentTurn string,
 ) *dnd5ev1alpha1.CombatState {
 	if initiativeData == nil {
 		return nil
 	}
 
+	rollsByID := make(map[string]*encounters.InitiativeEntryData, len(initiativeRolls))
+	for _, roll := range initiativeRolls {
+		rollsByID[roll.EntityID] = roll
+	}
+
 	// Convert turn order
 	var turnOrder []*dnd5ev1alpha1.InitiativeEntry
-	for _, entity := range initiativeData.Order {
-		turnOrder = append(turnOrder, &dnd5ev1alpha1.InitiativeEntry{
+	for i, entity := range initiativeData.Order {
+		entry := &dnd5ev1alpha1.InitiativeEntry{
 			EntityId:   entity.ID,
 			EntityType: entity.Type,
-			// TODO: Get actual initiative values and modifiers from combat data
-			Initiative: 10, // Placeholder
-			Modifier:   0,  // Placeholder
-			HasActed:   false,
-		})
+			HasActed:   i < initiativeData.Current,
+		}
+		if roll, ok := rollsByID[entity.ID]; ok {
+			entry.Initiative = int32(roll.Total)  // nolint:gosec // d20 plus a small modifier
+			entry.Modifier = int32(roll.Modifier) // nolint:gosec // small modifier
+		}
+		turnOrder = append(turnOrder, entry)
 	}
 
 	// Create current turn state
@@ -170,14 +181,56 @@ func convertInitiativeDataToProto(
 	}
 }
 
+// applyTurnStateToProto copies what the active combatant has left this turn onto its proto turn state
+func applyTurnStateToProto(protoTurn *dnd5ev1alpha1.TurnState, turn *encounter.TurnState) {
+	protoTurn.MovementUsed = int32(turn.MovementUsed) // nolint:gosec // bounded by speed
+	protoTurn.MovementMax = int32(turn.Speed)         // nolint:gosec // bounded by speed
+	protoTurn.ActionUsed = !turn.ActionAvailable
+	protoTurn.BonusActionUsed = !turn.BonusActionAvailable
+	protoTurn.ReactionAvailable = turn.ReactionAvailable
+}
+
 // GetCombatState retrieves current state (mainly for reconnection)
 func (h *EncounterHandler) GetCombatState(
 	ctx context.Context,
 	req *dnd5ev1alpha1.GetCombatStateRequest,
 ) (*dnd5ev1alpha1.GetCombatStateResponse, error) {
-	// For now, return unimplemented as we focus on the core gameplay flow
-	// This will be implemented when we need reconnection support
-	return nil, status.Error(codes.Unimplemented, "GetCombatState not yet implemented")
+	// Validate request
+	if req.GetEncounterId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "encounter_id is required")
+	}
+
+	output, err := h.encounterService.GetCombatState(ctx, &encounter.GetCombatStateInput{
+		EncounterID: req.GetEncounterId(),
+		ViewerID:    actor.ID(ctx),
+	})
+	if err != nil {
+		return nil, errors.ToGRPCError(err)
+	}
+
+	protoCombatState := convertInitiativeDataToProto(
+		req.GetEncounterId(),
+		output.InitiativeData,
+		output.Initiative,
+		output.CurrentTurn,
+	)
+	if protoCombatState != nil && protoCombatState.CurrentTurn != nil && output.Turn != nil {
+		applyTurnStateToProto(protoCombatState.CurrentTurn, output.Turn)
+		if output.RoomData != nil {
+			if placement, ok := output.RoomData.Entities[output.CurrentTurn]; ok {
+				protoCombatState.CurrentTurn.Position = &apiv1alpha1.Position{X: placement.Position.X, Y: placement.Position.Y}
+			}
+		}
+	}
+
+	// The proto has no fields for combatant hit points or conditions yet
+	response := &dnd5ev1alpha1.GetCombatStateResponse{
+		CombatState: protoCombatState,
+	}
+	if output.RoomData != nil {
+		response.Room = convertRoomDataToProto(output.RoomData)
+	}
+	return response, nil
 }
 
 // MoveCharacter moves an entity to a new position
@@ -185,9 +238,107 @@ func (h *EncounterHandler) MoveCharacter(
 	ctx context.Context,
 	req *dnd5ev1alpha1.MoveCharacterRequest,
 ) (*dnd5ev1alpha1.MoveCharacterResponse, error) {
-	// For now, return unimplemented
-	// Movement will be implemented after basic display works
-	return nil, status.Error(codes.Unimplemented, "MoveCharacter not yet implemented")
+	// Validate request
+	if req.GetEncounterId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "encounter_id is required")
+	}
+	if req.GetEntityId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "entity_id is required")
+	}
+	if req.GetTargetPosition() == nil {
+		return nil, status.Error(codes.InvalidArgument, "target_position is required")
+	}
+
+	output, err := h.encounterService.Move(ctx, &encounter.MoveInput{
+		EncounterID: req.GetEncounterId(),
+		EntityID:    req.GetEntityId(),
+		Target: spatial.Position{
+			X: req.GetTargetPosition().GetX(),
+			Y: req.GetTargetPosition().GetY(),
+		},
+	})
+	if err != nil {
+		// A move the rules reject is a normal outcome, reported in the response
+		if movementErr := convertMovementErrorToProto(err); movementErr != nil {
+			return &dnd5ev1alpha1.MoveCharacterResponse{
+				Success: false,
+				Error:   movementErr,
+			}, nil
+		}
+		return nil, errors.ToGRPCError(err)
+	}
+
+	protoCombatState := convertInitiativeDataToProto(
+		req.GetEncounterId(),
+		output.InitiativeData,
+		output.Initiative,
+		output.CurrentTurn,
+	)
+	if protoCombatState != nil && protoCombatState.CurrentTurn != nil {
+		if output.Turn != nil && output.Turn.EntityID == output.CurrentTurn {
+			applyTurnStateToProto(protoCombatState.CurrentTurn, output.Turn)
+		}
+		if placement, ok := output.RoomData.Entities[output.CurrentTurn]; ok {
+			protoCombatState.CurrentTurn.Position = &apiv1alpha1.Position{X: placement.Position.X, Y: placement.Position.Y}
+		}
+	}
+
+	// The proto has no field for the path; clients animate from the updated room
+	response := &dnd5ev1alpha1.MoveCharacterResponse{
+		Success:           true,
+		MovementRemaining: int32(output.MovementRemaining), // nolint:gosec // bounded by speed
+		UpdatedRoom:       convertRoomDataToProto(output.RoomData),
+		CombatState:       protoCombatState,
+	}
+
+	// A move that stopped short is reported as unsuccessful, with the room as it now stands
+	switch {
+	case output.Pending != nil:
+		response.Success = false
+		response.Error = &dnd5ev1alpha1.MovementError{
+			Code:    dnd5ev1alpha1.MovementError_ERROR_CODE_UNSPECIFIED,
+			Message: "the move is waiting on reactions",
+		}
+	case output.Interrupted:
+		response.Success = false
+		response.Error = &dnd5ev1alpha1.MovementError{
+			Code:    dnd5ev1alpha1.MovementError_ERROR_CODE_UNSPECIFIED,
+			Message: "an opportunity attack stopped the move",
+		}
+	}
+	return response, nil
+}
+
+// convertMovementErrorToProto converts a rejected move to a proto MovementError.
+// Returns nil if the error is not a movement rule failure.
+func convertMovementErrorToProto(err error) *dnd5ev1alpha1.MovementError {
+	reason, ok := errors.GetMeta(err)[encounter.MetaKeyMovementError].(string)
+	if !ok {
+		return nil
+	}
+
+	var code dnd5ev1alpha1.MovementError_ErrorCode
+	switch reason {
+	case encounter.MovementErrorInvalidPosition:
+		code = dnd5ev1alpha1.MovementError_ERROR_CODE_INVALID_POSITION
+	case encounter.MovementErrorOutOfBounds:
+		code = dnd5ev1alpha1.MovementError_ERROR_CODE_OUT_OF_BOUNDS
+	case encounter.MovementErrorPositionOccupied:
+		code = dnd5ev1alpha1.MovementError_ERROR_CODE_POSITION_OCCUPIED
+	case encounter.MovementErrorPathBlocked:
+		code = dnd5ev1alpha1.MovementError_ERROR_CODE_PATH_BLOCKED
+	case encounter.MovementErrorInsufficientMovement:
+		code = dnd5ev1alpha1.MovementError_ERROR_CODE_INSUFFICIENT_MOVEMENT
+	case encounter.MovementErrorNotYourTurn:
+		code = dnd5ev1alpha1.MovementError_ERROR_CODE_NOT_YOUR_TURN
+	default:
+		code = dnd5ev1alpha1.MovementError_ERROR_CODE_UNSPECIFIED
+	}
+
+	return &dnd5ev1alpha1.MovementError{
+		Code:    code,
+		Message: errors.GetMessage(err),
+	}
 }
 
 // EndTurn advances to the next turn
@@ -207,7 +358,7 @@ func (h *EncounterHandler) EndTurn(
 
 	nextTurnOutput, err := h.encounterService.NextTurn(ctx, nextTurnInput)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, errors.ToGRPCError(err)
 	}
 
 	// Get updated turn order to return full state
@@ -217,13 +368,14 @@ func (h *EncounterHandler) EndTurn(
 
 	turnOrderOutput, err := h.encounterService.GetTurnOrder(ctx, turnOrderInput)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, errors.ToGRPCError(err)
 	}
 
 	// Convert to proto CombatState
 	protoCombatState := convertInitiativeDataToProto(
 		req.GetEncounterId(),
 		turnOrderOutput.InitiativeData,
+		turnOrderOutput.Initiative,
 		nextTurnOutput.CurrentTurn,
 	)
 
@@ -239,7 +391,61 @@ func (h *EncounterHandler) Attack(
 	ctx context.Context,
 	req *dnd5ev1alpha1.AttackRequest,
 ) (*dnd5ev1alpha1.AttackResponse, error) {
-	// For now, return unimplemented
-	// Combat will be implemented last
-	return nil, status.Error(codes.Unimplemented, "Attack not yet implemented")
+	// Validate request
+	if req.GetEncounterId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "encounter_id is required")
+	}
+	if req.GetAttackerId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "attacker_id is required")
+	}
+	if req.GetTargetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "target_id is required")
+	}
+
+	output, err := h.encounterService.Attack(ctx, &encounter.AttackInput{
+		EncounterID: req.GetEncounterId(),
+		AttackerID:  req.GetAttackerId(),
+		TargetID:    req.GetTargetId(),
+		WeaponID:    req.GetWeaponId(),
+	})
+	if err != nil {
+		// An attack the rules don't allow is a normal outcome, reported in the response
+		if errors.IsFailedPrecondition(err) {
+			return &dnd5ev1alpha1.AttackResponse{
+				Success: false,
+				Error:   errors.GetMessage(err),
+			}, nil
+		}
+		return nil, errors.ToGRPCError(err)
+	}
+
+	// Nothing answers reactions over this API, so an attack should never pause here
+	if output.Pending != nil {
+		return &dnd5ev1alpha1.AttackResponse{
+			Success: false,
+			Error:   "the attack is waiting on reactions",
+		}, nil
+	}
+
+	// The proto has no field for the step by step log; clients get the totals
+	result := output.Result
+	return &dnd5ev1alpha1.AttackResponse{
+		Success: true,
+		Result: &dnd5ev1alpha1.AttackResult{
+			Hit:         result.Hit,
+			AttackRoll:  result.Roll,
+			AttackTotal: result.Total,
+			TargetAc:    result.TargetAC,
+			Damage:      result.Damage,
+			DamageType:  result.DamageType,
+			Critical:    result.Critical,
+		},
+		CombatState: convertInitiativeDataToProto(
+			req.GetEncounterId(),
+			output.InitiativeData,
+			output.Initiative,
+			output.CurrentTurn,
+		),
+		UpdatedRoom: convertRoomDataToProto(output.RoomData),
+	}, nil
 }
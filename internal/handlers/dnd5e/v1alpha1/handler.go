@@ -11,8 +11,10 @@ import (
 
 	dnd5ev1alpha1 "github.com/KirkDiggler/rpg-api-protos/gen/go/dnd5e/api/v1alpha1"
 	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
 	"github.com/KirkDiggler/rpg-api/internal/errors"
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/actor"
 	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
 	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/class"
 	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
@@ -457,6 +459,7 @@ func (h *Handler) GetCharacter(
 	// Call orchestrator to get the character
 	output, err := h.characterService.GetCharacter(ctx, &character.GetCharacterInput{
 		CharacterID: req.CharacterId,
+		ViewerID:    actor.ID(ctx),
 	})
 	if err != nil {
 		// Convert errors to gRPC status
@@ -466,11 +469,15 @@ func (h *Handler) GetCharacter(
 		if errors.IsInvalidArgument(err) {
 			return nil, status.Error(codes.InvalidArgument, err.Error())
 		}
+		if errors.IsPermissionDenied(err) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
 	// Convert character to proto
 	protoCharacter := ConvertCharacterDataToProto(output.Character)
+	applyDerivedStatsToProto(protoCharacter, output.DerivedStats)
 
 	return &dnd5ev1alpha1.GetCharacterResponse{
 		Character: protoCharacter,
@@ -484,12 +491,19 @@ func (h *Handler) ListCharacters(
 ) (*dnd5ev1alpha1.ListCharactersResponse, error) {
 	// Call orchestrator to list characters
 	output, err := h.characterService.ListCharacters(ctx, &character.ListCharactersInput{
-		PlayerID: req.PlayerId,
+		PlayerID:  req.PlayerId,
+		SessionID: req.SessionId,
+		ViewerID:  actor.ID(ctx),
+		PageSize:  req.PageSize,
+		PageToken: req.PageToken,
 	})
 	if err != nil {
 		if errors.IsInvalidArgument(err) {
 			return nil, status.Error(codes.InvalidArgument, err.Error())
 		}
+		if errors.IsPermissionDenied(err) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -500,7 +514,9 @@ func (h *Handler) ListCharacters(
 	}
 
 	return &dnd5ev1alpha1.ListCharactersResponse{
-		Characters: protoCharacters,
+		Characters:    protoCharacters,
+		NextPageToken: output.NextPageToken,
+		TotalSize:     output.TotalSize,
 	}, nil
 }
 
@@ -722,7 +738,42 @@ func (h *Handler) AddToInventory(
 	ctx context.Context,
 	req *dnd5ev1alpha1.AddToInventoryRequest,
 ) (*dnd5ev1alpha1.AddToInventoryResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "not implemented")
+	if req.CharacterId == "" {
+		return nil, status.Error(codes.InvalidArgument, "character_id is required")
+	}
+
+	items := make([]character.InventoryAddition, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, character.InventoryAddition{
+			Item: &dnd5e.InventoryItem{
+				ID:       item.GetItemId(),
+				Quantity: item.GetQuantity(),
+			},
+		})
+	}
+
+	output, err := h.characterService.AddToInventory(ctx, &character.AddToInventoryInput{
+		CharacterID: req.CharacterId,
+		PlayerID:    actor.ID(ctx),
+		Items:       items,
+	})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		if errors.IsInvalidArgument(err) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.IsPermissionDenied(err) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &dnd5ev1alpha1.AddToInventoryResponse{
+		Character: ConvertCharacterDataToProto(output.Character),
+		Errors:    output.Errors,
+	}, nil
 }
 
 // RemoveFromInventory removes items from inventory
@@ -829,7 +880,7 @@ func convertToolkitChoicesToProto(choices []toolkitchar.ChoiceData) []*dnd5ev1al
 
 		// Convert selection based on category
 		switch choice.Category {
-		case shared.ChoiceSkills:
+		case shared.ChoiceSkills, character.ChoiceExpertise:
 			if len(choice.SkillSelection) > 0 {
 				skills := make([]dnd5ev1alpha1.Skill, 0, len(choice.SkillSelection))
 				for _, s := range choice.SkillSelection {
@@ -2086,3 +2137,16 @@ func ConvertCharacterDataToProto(char *toolkitchar.Data) *dnd5ev1alpha1.Characte
 
 	return protoChar
 }
+
+// applyDerivedStatsToProto fills in combat stats the orchestrator calculated. The Character
+// message has no fields for attack profiles, so those are only used by encounters for now.
+func applyDerivedStatsToProto(protoChar *dnd5ev1alpha1.Character, stats *character.DerivedStats) {
+	if protoChar == nil || stats == nil {
+		return
+	}
+	if protoChar.CombatStats == nil {
+		protoChar.CombatStats = &dnd5ev1alpha1.CombatStats{}
+	}
+	protoChar.CombatStats.ArmorClass = stats.ArmorClass
+	protoChar.CombatStats.ProficiencyBonus = stats.ProficiencyBonus
+}
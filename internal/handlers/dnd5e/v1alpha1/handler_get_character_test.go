@@ -14,6 +14,7 @@ import (
 	v1alpha1 "github.com/KirkDiggler/rpg-api/internal/handlers/dnd5e/v1alpha1"
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
 	charactermock "github.com/KirkDiggler/rpg-api/internal/orchestrators/character/mock"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/actor"
 	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
 	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
 	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
@@ -198,3 +199,22 @@ func (s *HandlerGetCharacterTestSuite) TestGetCharacter_InternalError() {
 	s.Equal(codes.Internal, st.Code())
 	s.Contains(st.Message(), "database connection failed")
 }
+
+func (s *HandlerGetCharacterTestSuite) TestGetCharacter_NotShared() {
+	ctx := actor.WithID(s.ctx, "player-2")
+
+	// The viewer comes from the request context
+	s.mockService.EXPECT().
+		GetCharacter(ctx, &character.GetCharacterInput{
+			CharacterID: "char-123",
+			ViewerID:    "player-2",
+		}).
+		Return(nil, errors.PermissionDenied("character char-123 is not shared with player \"player-2\""))
+
+	resp, err := s.handler.GetCharacter(ctx, &dnd5ev1alpha1.GetCharacterRequest{CharacterId: "char-123"})
+
+	s.Nil(resp)
+	st, ok := status.FromError(err)
+	s.Require().True(ok)
+	s.Equal(codes.PermissionDenied, st.Code())
+}
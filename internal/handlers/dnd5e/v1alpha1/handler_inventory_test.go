@@ -0,0 +1,90 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	dnd5ev1alpha1 "github.com/KirkDiggler/rpg-api-protos/gen/go/dnd5e/api/v1alpha1"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	v1alpha1 "github.com/KirkDiggler/rpg-api/internal/handlers/dnd5e/v1alpha1"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	charactermock "github.com/KirkDiggler/rpg-api/internal/orchestrators/character/mock"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/actor"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+)
+
+type HandlerInventoryTestSuite struct {
+	suite.Suite
+	ctrl        *gomock.Controller
+	mockService *charactermock.MockService
+	handler     *v1alpha1.Handler
+	ctx         context.Context
+}
+
+func TestHandlerInventoryTestSuite(t *testing.T) {
+	suite.Run(t, new(HandlerInventoryTestSuite))
+}
+
+func (s *HandlerInventoryTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockService = charactermock.NewMockService(s.ctrl)
+	s.ctx = actor.WithID(context.Background(), "player-1")
+
+	handler, err := v1alpha1.NewHandler(&v1alpha1.HandlerConfig{
+		CharacterService: s.mockService,
+	})
+	s.Require().NoError(err)
+	s.handler = handler
+}
+
+func (s *HandlerInventoryTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *HandlerInventoryTestSuite) TestAddToInventory_AddsForTheCaller() {
+	s.mockService.EXPECT().
+		AddToInventory(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *character.AddToInventoryInput) (*character.AddToInventoryOutput, error) {
+			s.Equal("char-1", input.CharacterID)
+			s.Equal("player-1", input.PlayerID)
+			s.Require().Len(input.Items, 2)
+			s.Equal("MAGIC_ITEM_CLOAK_OF_PROTECTION", input.Items[0].Item.ID)
+			s.Equal(int32(1), input.Items[0].Item.Quantity)
+			s.Equal(int32(2), input.Items[1].Item.Quantity)
+			return &character.AddToInventoryOutput{
+				Character: &toolkitchar.Data{ID: "char-1", Equipment: []string{"MAGIC_ITEM_CLOAK_OF_PROTECTION"}},
+				Errors:    []string{"failed to get equipment EQUIPMENT_UNKNOWN"},
+			}, nil
+		})
+
+	resp, err := s.handler.AddToInventory(s.ctx, &dnd5ev1alpha1.AddToInventoryRequest{
+		CharacterId: "char-1",
+		Items: []*dnd5ev1alpha1.InventoryAddition{
+			{ItemId: "MAGIC_ITEM_CLOAK_OF_PROTECTION", Quantity: 1},
+			{ItemId: "EQUIPMENT_UNKNOWN", Quantity: 2},
+		},
+	})
+
+	s.Require().NoError(err)
+	s.Equal("char-1", resp.Character.Id)
+	s.Equal([]string{"failed to get equipment EQUIPMENT_UNKNOWN"}, resp.Errors)
+}
+
+func (s *HandlerInventoryTestSuite) TestAddToInventory_NotOwner() {
+	s.mockService.EXPECT().
+		AddToInventory(s.ctx, gomock.Any()).
+		Return(nil, errors.PermissionDenied("character char-1 is not owned by player player-1"))
+
+	resp, err := s.handler.AddToInventory(s.ctx, &dnd5ev1alpha1.AddToInventoryRequest{
+		CharacterId: "char-1",
+		Items:       []*dnd5ev1alpha1.InventoryAddition{{ItemId: "EQUIPMENT_ROPE", Quantity: 1}},
+	})
+
+	s.Nil(resp)
+	s.Equal(codes.PermissionDenied, status.Code(err))
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
 	dicemock "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice/mock"
 	idgenmock "github.com/KirkDiggler/rpg-api/internal/pkg/idgen/mock"
+	campaignmock "github.com/KirkDiggler/rpg-api/internal/repositories/campaign/mock"
 	characterrepomock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
 	draftrepomock "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft/mock"
 	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
@@ -81,6 +82,7 @@ func (s *HandlerListClassesChoicesIntegrationTestSuite) SetupSuite() {
 	characterOrch, err := character.New(&character.Config{
 		CharacterRepo:      mockCharRepo,
 		CharacterDraftRepo: mockDraftRepo,
+		CampaignRepo:       campaignmock.NewMockRepository(s.ctrl),
 		ExternalClient:     s.externalClient, // REAL external client
 		DiceService:        mockDiceService,
 		IDGenerator:        mockIDGenerator,
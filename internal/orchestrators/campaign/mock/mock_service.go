@@ -0,0 +1,268 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/KirkDiggler/rpg-api/internal/orchestrators/campaign (interfaces: Service)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/mock_service.go -package=campaignmock github.com/KirkDiggler/rpg-api/internal/orchestrators/campaign Service
+//
+
+// Package campaignmock is a generated GoMock package.
+package campaignmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+
+	campaign "github.com/KirkDiggler/rpg-api/internal/orchestrators/campaign"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// AttachCharacter mocks base method.
+func (m *MockService) AttachCharacter(ctx context.Context, input *campaign.AttachCharacterInput) (*campaign.AttachCharacterOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachCharacter", ctx, input)
+	ret0, _ := ret[0].(*campaign.AttachCharacterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachCharacter indicates an expected call of AttachCharacter.
+func (mr *MockServiceMockRecorder) AttachCharacter(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachCharacter", reflect.TypeOf((*MockService)(nil).AttachCharacter), ctx, input)
+}
+
+// AttachDiceSession mocks base method.
+func (m *MockService) AttachDiceSession(ctx context.Context, input *campaign.AttachDiceSessionInput) (*campaign.AttachDiceSessionOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachDiceSession", ctx, input)
+	ret0, _ := ret[0].(*campaign.AttachDiceSessionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachDiceSession indicates an expected call of AttachDiceSession.
+func (mr *MockServiceMockRecorder) AttachDiceSession(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachDiceSession", reflect.TypeOf((*MockService)(nil).AttachDiceSession), ctx, input)
+}
+
+// AttachEncounter mocks base method.
+func (m *MockService) AttachEncounter(ctx context.Context, input *campaign.AttachEncounterInput) (*campaign.AttachEncounterOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachEncounter", ctx, input)
+	ret0, _ := ret[0].(*campaign.AttachEncounterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachEncounter indicates an expected call of AttachEncounter.
+func (mr *MockServiceMockRecorder) AttachEncounter(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachEncounter", reflect.TypeOf((*MockService)(nil).AttachEncounter), ctx, input)
+}
+
+// CreateCampaign mocks base method.
+func (m *MockService) CreateCampaign(ctx context.Context, input *campaign.CreateCampaignInput) (*campaign.CreateCampaignOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCampaign", ctx, input)
+	ret0, _ := ret[0].(*campaign.CreateCampaignOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCampaign indicates an expected call of CreateCampaign.
+func (mr *MockServiceMockRecorder) CreateCampaign(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCampaign", reflect.TypeOf((*MockService)(nil).CreateCampaign), ctx, input)
+}
+
+// DeleteCampaign mocks base method.
+func (m *MockService) DeleteCampaign(ctx context.Context, input *campaign.DeleteCampaignInput) (*campaign.DeleteCampaignOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCampaign", ctx, input)
+	ret0, _ := ret[0].(*campaign.DeleteCampaignOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCampaign indicates an expected call of DeleteCampaign.
+func (mr *MockServiceMockRecorder) DeleteCampaign(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCampaign", reflect.TypeOf((*MockService)(nil).DeleteCampaign), ctx, input)
+}
+
+// DetachCharacter mocks base method.
+func (m *MockService) DetachCharacter(ctx context.Context, input *campaign.DetachCharacterInput) (*campaign.DetachCharacterOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachCharacter", ctx, input)
+	ret0, _ := ret[0].(*campaign.DetachCharacterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachCharacter indicates an expected call of DetachCharacter.
+func (mr *MockServiceMockRecorder) DetachCharacter(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachCharacter", reflect.TypeOf((*MockService)(nil).DetachCharacter), ctx, input)
+}
+
+// EndSession mocks base method.
+func (m *MockService) EndSession(ctx context.Context, input *campaign.EndSessionInput) (*campaign.EndSessionOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EndSession", ctx, input)
+	ret0, _ := ret[0].(*campaign.EndSessionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EndSession indicates an expected call of EndSession.
+func (mr *MockServiceMockRecorder) EndSession(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndSession", reflect.TypeOf((*MockService)(nil).EndSession), ctx, input)
+}
+
+// GetCampaign mocks base method.
+func (m *MockService) GetCampaign(ctx context.Context, input *campaign.GetCampaignInput) (*campaign.GetCampaignOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaign", ctx, input)
+	ret0, _ := ret[0].(*campaign.GetCampaignOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaign indicates an expected call of GetCampaign.
+func (mr *MockServiceMockRecorder) GetCampaign(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaign", reflect.TypeOf((*MockService)(nil).GetCampaign), ctx, input)
+}
+
+// JoinCampaign mocks base method.
+func (m *MockService) JoinCampaign(ctx context.Context, input *campaign.JoinCampaignInput) (*campaign.JoinCampaignOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinCampaign", ctx, input)
+	ret0, _ := ret[0].(*campaign.JoinCampaignOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JoinCampaign indicates an expected call of JoinCampaign.
+func (mr *MockServiceMockRecorder) JoinCampaign(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinCampaign", reflect.TypeOf((*MockService)(nil).JoinCampaign), ctx, input)
+}
+
+// ListCampaigns mocks base method.
+func (m *MockService) ListCampaigns(ctx context.Context, input *campaign.ListCampaignsInput) (*campaign.ListCampaignsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCampaigns", ctx, input)
+	ret0, _ := ret[0].(*campaign.ListCampaignsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCampaigns indicates an expected call of ListCampaigns.
+func (mr *MockServiceMockRecorder) ListCampaigns(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCampaigns", reflect.TypeOf((*MockService)(nil).ListCampaigns), ctx, input)
+}
+
+// RegenerateInviteCode mocks base method.
+func (m *MockService) RegenerateInviteCode(ctx context.Context, input *campaign.RegenerateInviteCodeInput) (*campaign.RegenerateInviteCodeOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateInviteCode", ctx, input)
+	ret0, _ := ret[0].(*campaign.RegenerateInviteCodeOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateInviteCode indicates an expected call of RegenerateInviteCode.
+func (mr *MockServiceMockRecorder) RegenerateInviteCode(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateInviteCode", reflect.TypeOf((*MockService)(nil).RegenerateInviteCode), ctx, input)
+}
+
+// RemoveMember mocks base method.
+func (m *MockService) RemoveMember(ctx context.Context, input *campaign.RemoveMemberInput) (*campaign.RemoveMemberOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, input)
+	ret0, _ := ret[0].(*campaign.RemoveMemberOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockServiceMockRecorder) RemoveMember(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockService)(nil).RemoveMember), ctx, input)
+}
+
+// SetMemberRole mocks base method.
+func (m *MockService) SetMemberRole(ctx context.Context, input *campaign.SetMemberRoleInput) (*campaign.SetMemberRoleOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMemberRole", ctx, input)
+	ret0, _ := ret[0].(*campaign.SetMemberRoleOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMemberRole indicates an expected call of SetMemberRole.
+func (mr *MockServiceMockRecorder) SetMemberRole(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMemberRole", reflect.TypeOf((*MockService)(nil).SetMemberRole), ctx, input)
+}
+
+// StartSession mocks base method.
+func (m *MockService) StartSession(ctx context.Context, input *campaign.StartSessionInput) (*campaign.StartSessionOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartSession", ctx, input)
+	ret0, _ := ret[0].(*campaign.StartSessionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartSession indicates an expected call of StartSession.
+func (mr *MockServiceMockRecorder) StartSession(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSession", reflect.TypeOf((*MockService)(nil).StartSession), ctx, input)
+}
+
+// UpdateCampaign mocks base method.
+func (m *MockService) UpdateCampaign(ctx context.Context, input *campaign.UpdateCampaignInput) (*campaign.UpdateCampaignOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCampaign", ctx, input)
+	ret0, _ := ret[0].(*campaign.UpdateCampaignOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCampaign indicates an expected call of UpdateCampaign.
+func (mr *MockServiceMockRecorder) UpdateCampaign(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCampaign", reflect.TypeOf((*MockService)(nil).UpdateCampaign), ctx, input)
+}
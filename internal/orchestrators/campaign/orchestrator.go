@@ -0,0 +1,729 @@
+// Package campaign implements campaigns: the members of a table, their invite codes, rule
+// settings and game sessions, and the characters, encounters and dice sessions they play with
+package campaign
+
+//go:generate mockgen -destination=mock/mock_service.go -package=campaignmock github.com/KirkDiggler/rpg-api/internal/orchestrators/campaign Service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/clock"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/idgen"
+	campaignrepo "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+)
+
+// maxInviteCodeAttempts bounds how many codes are tried before giving up on a collision
+const maxInviteCodeAttempts = 5
+
+// Service defines the interface for campaign operations
+type Service interface {
+	// CreateCampaign creates a campaign with its owner as the first DM
+	CreateCampaign(ctx context.Context, input *CreateCampaignInput) (*CreateCampaignOutput, error)
+
+	// GetCampaign retrieves a campaign the player is a member of
+	GetCampaign(ctx context.Context, input *GetCampaignInput) (*GetCampaignOutput, error)
+
+	// ListCampaigns lists every campaign a player is a member of
+	ListCampaigns(ctx context.Context, input *ListCampaignsInput) (*ListCampaignsOutput, error)
+
+	// UpdateCampaign changes a campaign's name, description or rule settings
+	UpdateCampaign(ctx context.Context, input *UpdateCampaignInput) (*UpdateCampaignOutput, error)
+
+	// DeleteCampaign deletes a campaign; only the owner may do this
+	DeleteCampaign(ctx context.Context, input *DeleteCampaignInput) (*DeleteCampaignOutput, error)
+
+	// JoinCampaign adds a player to the campaign an invite code belongs to
+	JoinCampaign(ctx context.Context, input *JoinCampaignInput) (*JoinCampaignOutput, error)
+
+	// RegenerateInviteCode replaces a campaign's invite code, invalidating the old one
+	RegenerateInviteCode(ctx context.Context, input *RegenerateInviteCodeInput) (*RegenerateInviteCodeOutput, error)
+
+	// SetMemberRole changes a member between player, DM and spectator
+	SetMemberRole(ctx context.Context, input *SetMemberRoleInput) (*SetMemberRoleOutput, error)
+
+	// RemoveMember removes a member and detaches their characters
+	RemoveMember(ctx context.Context, input *RemoveMemberInput) (*RemoveMemberOutput, error)
+
+	// StartSession starts a new game session
+	StartSession(ctx context.Context, input *StartSessionInput) (*StartSessionOutput, error)
+
+	// EndSession ends a running game session
+	EndSession(ctx context.Context, input *EndSessionInput) (*EndSessionOutput, error)
+
+	// AttachCharacter brings a member's character into the campaign
+	AttachCharacter(ctx context.Context, input *AttachCharacterInput) (*AttachCharacterOutput, error)
+
+	// DetachCharacter removes a character from the campaign
+	DetachCharacter(ctx context.Context, input *DetachCharacterInput) (*DetachCharacterOutput, error)
+
+	// AttachEncounter adds an encounter to the campaign
+	AttachEncounter(ctx context.Context, input *AttachEncounterInput) (*AttachEncounterOutput, error)
+
+	// AttachDiceSession adds a dice session to the campaign
+	AttachDiceSession(ctx context.Context, input *AttachDiceSessionInput) (*AttachDiceSessionOutput, error)
+}
+
+// Config holds the dependencies for the campaign orchestrator
+type Config struct {
+	Repository          campaignrepo.Repository
+	CharacterRepo       characterrepo.Repository
+	EncounterRepo       encounters.Repository
+	IDGenerator         idgen.Generator // Generates session IDs
+	InviteCodeGenerator idgen.Generator
+	Clock               clock.Clock
+}
+
+// Validate ensures all required dependencies are provided
+func (c *Config) Validate() error {
+	vb := errors.NewValidationBuilder()
+
+	if c.Repository == nil {
+		vb.RequiredField("Repository")
+	}
+	if c.CharacterRepo == nil {
+		vb.RequiredField("CharacterRepo")
+	}
+	if c.EncounterRepo == nil {
+		vb.RequiredField("EncounterRepo")
+	}
+	if c.IDGenerator == nil {
+		vb.RequiredField("IDGenerator")
+	}
+	if c.InviteCodeGenerator == nil {
+		vb.RequiredField("InviteCodeGenerator")
+	}
+	if c.Clock == nil {
+		vb.RequiredField("Clock")
+	}
+
+	return vb.Build()
+}
+
+type orchestrator struct {
+	repo          campaignrepo.Repository
+	charRepo      characterrepo.Repository
+	encounterRepo encounters.Repository
+	idGen         idgen.Generator
+	codeGen       idgen.Generator
+	clock         clock.Clock
+}
+
+// NewOrchestrator creates a new campaign orchestrator with the provided dependencies
+func NewOrchestrator(cfg *Config) (Service, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid config")
+	}
+
+	return &orchestrator{
+		repo:          cfg.Repository,
+		charRepo:      cfg.CharacterRepo,
+		encounterRepo: cfg.EncounterRepo,
+		idGen:         cfg.IDGenerator,
+		codeGen:       cfg.InviteCodeGenerator,
+		clock:         cfg.Clock,
+	}, nil
+}
+
+// CreateCampaign creates a campaign with its owner as the first DM
+func (o *orchestrator) CreateCampaign(ctx context.Context, input *CreateCampaignInput) (*CreateCampaignOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("name", strings.TrimSpace(input.Name), vb)
+	errors.ValidateRequired("owner_id", input.OwnerID, vb)
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+
+	campaign := &campaignrepo.Campaign{
+		Name:        strings.TrimSpace(input.Name),
+		Description: input.Description,
+		OwnerID:     input.OwnerID,
+		Members: []*campaignrepo.Member{
+			{PlayerID: input.OwnerID, Role: campaignrepo.RoleDM, JoinedAt: o.clock.Now()},
+		},
+		Rules: input.Rules,
+	}
+
+	var created *campaignrepo.Campaign
+	err := o.withNewInviteCode(campaign, func() error {
+		createOutput, err := o.repo.Create(ctx, campaignrepo.CreateInput{Campaign: campaign})
+		if err != nil {
+			return err
+		}
+		created = createOutput.Campaign
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create campaign")
+	}
+
+	slog.InfoContext(ctx, "campaign created",
+		"campaign_id", created.ID,
+		"owner_id", created.OwnerID)
+
+	return &CreateCampaignOutput{Campaign: created}, nil
+}
+
+// GetCampaign retrieves a campaign the player is a member of
+func (o *orchestrator) GetCampaign(ctx context.Context, input *GetCampaignInput) (*GetCampaignOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	campaign, _, err := o.getAsMember(ctx, input.CampaignID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetCampaignOutput{Campaign: campaign}, nil
+}
+
+// ListCampaigns lists every campaign a player is a member of
+func (o *orchestrator) ListCampaigns(ctx context.Context, input *ListCampaignsInput) (*ListCampaignsOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument("player ID is required")
+	}
+
+	listOutput, err := o.repo.ListByMember(ctx, campaignrepo.ListByMemberInput{PlayerID: input.PlayerID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list campaigns for player %s", input.PlayerID)
+	}
+
+	return &ListCampaignsOutput{Campaigns: listOutput.Campaigns}, nil
+}
+
+// UpdateCampaign changes a campaign's name, description or rule settings
+func (o *orchestrator) UpdateCampaign(ctx context.Context, input *UpdateCampaignInput) (*UpdateCampaignOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.Name != nil && strings.TrimSpace(*input.Name) == "" {
+		return nil, errors.InvalidArgument("name cannot be empty")
+	}
+
+	campaign, err := o.getAsDM(ctx, input.CampaignID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		campaign.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Description != nil {
+		campaign.Description = *input.Description
+	}
+	if input.Rules != nil {
+		campaign.Rules = input.Rules
+	}
+
+	updated, err := o.update(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateCampaignOutput{Campaign: updated}, nil
+}
+
+// DeleteCampaign deletes a campaign; only the owner may do this
+func (o *orchestrator) DeleteCampaign(ctx context.Context, input *DeleteCampaignInput) (*DeleteCampaignOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	campaign, _, err := o.getAsMember(ctx, input.CampaignID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.OwnerID != input.PlayerID {
+		return nil, errors.PermissionDeniedf("only the owner can delete campaign %s", input.CampaignID)
+	}
+
+	if _, err := o.repo.Delete(ctx, campaignrepo.DeleteInput{ID: input.CampaignID}); err != nil {
+		return nil, errors.Wrapf(err, "failed to delete campaign %s", input.CampaignID)
+	}
+
+	slog.InfoContext(ctx, "campaign deleted", "campaign_id", input.CampaignID)
+
+	return &DeleteCampaignOutput{}, nil
+}
+
+// JoinCampaign adds a player to the campaign an invite code belongs to
+func (o *orchestrator) JoinCampaign(ctx context.Context, input *JoinCampaignInput) (*JoinCampaignOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("invite_code", input.InviteCode, vb)
+	errors.ValidateRequired("player_id", input.PlayerID, vb)
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+
+	// Codes are handed out upper case but are often typed in lower case
+	code := strings.ToUpper(strings.TrimSpace(input.InviteCode))
+	getOutput, err := o.repo.GetByInviteCode(ctx, campaignrepo.GetByInviteCodeInput{Code: code})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find campaign for invite code")
+	}
+	campaign := getOutput.Campaign
+
+	if findMember(campaign, input.PlayerID) != nil {
+		return &JoinCampaignOutput{Campaign: campaign}, nil
+	}
+
+	campaign.Members = append(campaign.Members, &campaignrepo.Member{
+		PlayerID: input.PlayerID,
+		Role:     campaignrepo.RolePlayer,
+		JoinedAt: o.clock.Now(),
+	})
+
+	updated, err := o.update(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "player joined campaign",
+		"campaign_id", campaign.ID,
+		"player_id", input.PlayerID)
+
+	return &JoinCampaignOutput{Campaign: updated}, nil
+}
+
+// RegenerateInviteCode replaces a campaign's invite code, invalidating the old one
+func (o *orchestrator) RegenerateInviteCode(
+	ctx context.Context,
+	input *RegenerateInviteCodeInput,
+) (*RegenerateInviteCodeOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	campaign, err := o.getAsDM(ctx, input.CampaignID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated *campaignrepo.Campaign
+	err = o.withNewInviteCode(campaign, func() error {
+		updateOutput, err := o.repo.Update(ctx, campaignrepo.UpdateInput{Campaign: campaign})
+		if err != nil {
+			return err
+		}
+		updated = updateOutput.Campaign
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to regenerate invite code for campaign %s", input.CampaignID)
+	}
+
+	return &RegenerateInviteCodeOutput{InviteCode: updated.InviteCode}, nil
+}
+
+// SetMemberRole changes a member between player, DM and spectator
+func (o *orchestrator) SetMemberRole(ctx context.Context, input *SetMemberRoleInput) (*SetMemberRoleOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("member_player_id", input.MemberPlayerID, vb)
+	errors.ValidateEnum("role", input.Role,
+		[]string{campaignrepo.RoleDM, campaignrepo.RolePlayer, campaignrepo.RoleSpectator}, vb)
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+
+	campaign, err := o.getAsDM(ctx, input.CampaignID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	member := findMember(campaign, input.MemberPlayerID)
+	if member == nil {
+		return nil, errors.NotFoundf("player %s is not a member of campaign %s", input.MemberPlayerID, input.CampaignID)
+	}
+	if member.PlayerID == campaign.OwnerID && input.Role != campaignrepo.RoleDM {
+		return nil, errors.FailedPrecondition("the campaign owner must remain a DM")
+	}
+	if member.Role == input.Role {
+		return &SetMemberRoleOutput{Campaign: campaign}, nil
+	}
+
+	member.Role = input.Role
+	// Spectators watch; they don't bring characters to the table
+	if input.Role == campaignrepo.RoleSpectator {
+		if err := o.detachPlayerCharacters(ctx, campaign, member.PlayerID); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := o.update(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SetMemberRoleOutput{Campaign: updated}, nil
+}
+
+// RemoveMember removes a member and detaches their characters
+func (o *orchestrator) RemoveMember(ctx context.Context, input *RemoveMemberInput) (*RemoveMemberOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.MemberPlayerID == "" {
+		return nil, errors.InvalidArgument("member player ID is required")
+	}
+
+	campaign, caller, err := o.getAsMember(ctx, input.CampaignID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	// Members may leave on their own; only a DM can remove someone else
+	if input.MemberPlayerID != input.PlayerID && caller.Role != campaignrepo.RoleDM {
+		return nil, errors.PermissionDenied("only a DM can remove other members")
+	}
+	if findMember(campaign, input.MemberPlayerID) == nil {
+		return nil, errors.NotFoundf("player %s is not a member of campaign %s", input.MemberPlayerID, input.CampaignID)
+	}
+	if input.MemberPlayerID == campaign.OwnerID {
+		return nil, errors.FailedPrecondition("the campaign owner cannot leave the campaign")
+	}
+
+	if err := o.detachPlayerCharacters(ctx, campaign, input.MemberPlayerID); err != nil {
+		return nil, err
+	}
+	campaign.Members = slices.DeleteFunc(campaign.Members, func(m *campaignrepo.Member) bool {
+		return m.PlayerID == input.MemberPlayerID
+	})
+
+	updated, err := o.update(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "member removed from campaign",
+		"campaign_id", campaign.ID,
+		"player_id", input.MemberPlayerID)
+
+	return &RemoveMemberOutput{Campaign: updated}, nil
+}
+
+// StartSession starts a new game session
+func (o *orchestrator) StartSession(ctx context.Context, input *StartSessionInput) (*StartSessionOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	campaign, err := o.getAsDM(ctx, input.CampaignID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range campaign.Sessions {
+		if session.EndedAt == nil {
+			return nil, errors.FailedPreconditionf("session %s is still running", session.ID)
+		}
+	}
+
+	session := &campaignrepo.Session{
+		ID:        o.idGen.Generate(),
+		Name:      strings.TrimSpace(input.Name),
+		StartedAt: o.clock.Now(),
+	}
+	campaign.Sessions = append(campaign.Sessions, session)
+
+	if _, err := o.update(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "campaign session started",
+		"campaign_id", campaign.ID,
+		"session_id", session.ID)
+
+	return &StartSessionOutput{Session: session}, nil
+}
+
+// EndSession ends a running game session
+func (o *orchestrator) EndSession(ctx context.Context, input *EndSessionInput) (*EndSessionOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.SessionID == "" {
+		return nil, errors.InvalidArgument("session ID is required")
+	}
+
+	campaign, err := o.getAsDM(ctx, input.CampaignID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := slices.IndexFunc(campaign.Sessions, func(s *campaignrepo.Session) bool {
+		return s.ID == input.SessionID
+	})
+	if idx < 0 {
+		return nil, errors.NotFoundf("session %s not found in campaign %s", input.SessionID, input.CampaignID)
+	}
+	session := campaign.Sessions[idx]
+	if session.EndedAt != nil {
+		return nil, errors.FailedPreconditionf("session %s has already ended", input.SessionID)
+	}
+
+	endedAt := o.clock.Now()
+	session.EndedAt = &endedAt
+
+	if _, err := o.update(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "campaign session ended",
+		"campaign_id", campaign.ID,
+		"session_id", session.ID)
+
+	return &EndSessionOutput{Session: session}, nil
+}
+
+// AttachCharacter brings a member's character into the campaign
+func (o *orchestrator) AttachCharacter(ctx context.Context, input *AttachCharacterInput) (*AttachCharacterOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+
+	campaign, member, err := o.getAsMember(ctx, input.CampaignID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == campaignrepo.RoleSpectator {
+		return nil, errors.PermissionDenied("spectators cannot attach characters")
+	}
+
+	charOutput, err := o.charRepo.Get(ctx, characterrepo.GetInput{ID: input.CharacterID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get character %s", input.CharacterID)
+	}
+	if charOutput.CharacterData.PlayerID != input.PlayerID {
+		return nil, errors.PermissionDeniedf("character %s does not belong to player %s", input.CharacterID, input.PlayerID)
+	}
+
+	if slices.Contains(campaign.CharacterIDs, input.CharacterID) {
+		return &AttachCharacterOutput{Campaign: campaign}, nil
+	}
+	campaign.CharacterIDs = append(campaign.CharacterIDs, input.CharacterID)
+
+	updated, err := o.update(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttachCharacterOutput{Campaign: updated}, nil
+}
+
+// DetachCharacter removes a character from the campaign
+func (o *orchestrator) DetachCharacter(ctx context.Context, input *DetachCharacterInput) (*DetachCharacterOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+
+	campaign, member, err := o.getAsMember(ctx, input.CampaignID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(campaign.CharacterIDs, input.CharacterID) {
+		return nil, errors.NotFoundf("character %s is not in campaign %s", input.CharacterID, input.CampaignID)
+	}
+
+	if member.Role != campaignrepo.RoleDM {
+		charOutput, err := o.charRepo.Get(ctx, characterrepo.GetInput{ID: input.CharacterID})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get character %s", input.CharacterID)
+		}
+		if charOutput.CharacterData.PlayerID != input.PlayerID {
+			return nil, errors.PermissionDeniedf("character %s does not belong to player %s", input.CharacterID, input.PlayerID)
+		}
+	}
+
+	campaign.CharacterIDs = slices.DeleteFunc(campaign.CharacterIDs, func(id string) bool {
+		return id == input.CharacterID
+	})
+
+	updated, err := o.update(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DetachCharacterOutput{Campaign: updated}, nil
+}
+
+// AttachEncounter adds an encounter to the campaign
+func (o *orchestrator) AttachEncounter(ctx context.Context, input *AttachEncounterInput) (*AttachEncounterOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.EncounterID == "" {
+		return nil, errors.InvalidArgument("encounter ID is required")
+	}
+
+	campaign, err := o.getAsDM(ctx, input.CampaignID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := o.encounterRepo.Get(ctx, &encounters.GetInput{EncounterID: input.EncounterID}); err != nil {
+		return nil, errors.Wrapf(err, "failed to get encounter %s", input.EncounterID)
+	}
+
+	if slices.Contains(campaign.EncounterIDs, input.EncounterID) {
+		return &AttachEncounterOutput{Campaign: campaign}, nil
+	}
+	campaign.EncounterIDs = append(campaign.EncounterIDs, input.EncounterID)
+
+	updated, err := o.update(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttachEncounterOutput{Campaign: updated}, nil
+}
+
+// AttachDiceSession adds a dice session to the campaign
+func (o *orchestrator) AttachDiceSession(
+	ctx context.Context,
+	input *AttachDiceSessionInput,
+) (*AttachDiceSessionOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("entity_id", input.EntityID, vb)
+	errors.ValidateRequired("context", input.Context, vb)
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+
+	campaign, _, err := o.getAsMember(ctx, input.CampaignID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &campaignrepo.DiceSessionRef{EntityID: input.EntityID, Context: input.Context}
+	if slices.ContainsFunc(campaign.DiceSessions, func(d *campaignrepo.DiceSessionRef) bool {
+		return *d == *ref
+	}) {
+		return &AttachDiceSessionOutput{Campaign: campaign}, nil
+	}
+	campaign.DiceSessions = append(campaign.DiceSessions, ref)
+
+	updated, err := o.update(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttachDiceSessionOutput{Campaign: updated}, nil
+}
+
+// getAsMember loads a campaign and the calling player's membership in it
+func (o *orchestrator) getAsMember(
+	ctx context.Context,
+	campaignID, playerID string,
+) (*campaignrepo.Campaign, *campaignrepo.Member, error) {
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("campaign_id", campaignID, vb)
+	errors.ValidateRequired("player_id", playerID, vb)
+	if err := vb.Build(); err != nil {
+		return nil, nil, err
+	}
+
+	getOutput, err := o.repo.Get(ctx, campaignrepo.GetInput{ID: campaignID})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get campaign %s", campaignID)
+	}
+
+	member := findMember(getOutput.Campaign, playerID)
+	if member == nil {
+		return nil, nil, errors.PermissionDeniedf("player %s is not a member of campaign %s", playerID, campaignID)
+	}
+
+	return getOutput.Campaign, member, nil
+}
+
+// getAsDM loads a campaign the calling player runs
+func (o *orchestrator) getAsDM(ctx context.Context, campaignID, playerID string) (*campaignrepo.Campaign, error) {
+	campaign, member, err := o.getAsMember(ctx, campaignID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role != campaignrepo.RoleDM {
+		return nil, errors.PermissionDeniedf("player %s is not a DM of campaign %s", playerID, campaignID)
+	}
+	return campaign, nil
+}
+
+func (o *orchestrator) update(ctx context.Context, campaign *campaignrepo.Campaign) (*campaignrepo.Campaign, error) {
+	updateOutput, err := o.repo.Update(ctx, campaignrepo.UpdateInput{Campaign: campaign})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update campaign %s", campaign.ID)
+	}
+	return updateOutput.Campaign, nil
+}
+
+// withNewInviteCode gives the campaign a fresh invite code and saves it, trying another
+// code if the repository reports the first is already taken
+func (o *orchestrator) withNewInviteCode(campaign *campaignrepo.Campaign, save func() error) error {
+	var err error
+	for range maxInviteCodeAttempts {
+		campaign.InviteCode = o.codeGen.Generate()
+		err = save()
+		if !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return errors.Wrap(err, "could not generate a unique invite code")
+}
+
+// detachPlayerCharacters removes every character the player owns from the campaign
+func (o *orchestrator) detachPlayerCharacters(ctx context.Context, campaign *campaignrepo.Campaign, playerID string) error {
+	kept := make([]string, 0, len(campaign.CharacterIDs))
+	for _, characterID := range campaign.CharacterIDs {
+		charOutput, err := o.charRepo.Get(ctx, characterrepo.GetInput{ID: characterID})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// The character is gone; drop the stale attachment
+				continue
+			}
+			return errors.Wrapf(err, "failed to get character %s", characterID)
+		}
+		if charOutput.CharacterData.PlayerID != playerID {
+			kept = append(kept, characterID)
+		}
+	}
+	campaign.CharacterIDs = kept
+	return nil
+}
+
+func findMember(campaign *campaignrepo.Campaign, playerID string) *campaignrepo.Member {
+	for _, member := range campaign.Members {
+		if member.PlayerID == playerID {
+			return member
+		}
+	}
+	return nil
+}
@@ -0,0 +1,569 @@
+package campaign_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/campaign"
+	mockclock "github.com/KirkDiggler/rpg-api/internal/pkg/clock/mock"
+	idgenmock "github.com/KirkDiggler/rpg-api/internal/pkg/idgen/mock"
+	campaignrepo "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
+	campaignrepomock "github.com/KirkDiggler/rpg-api/internal/repositories/campaign/mock"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	characterrepomock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	encountermock "github.com/KirkDiggler/rpg-api/internal/repositories/encounters/mock"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+)
+
+type OrchestratorTestSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	mockRepo          *campaignrepomock.MockRepository
+	mockCharRepo      *characterrepomock.MockRepository
+	mockEncounterRepo *encountermock.MockRepository
+	mockIDGen         *idgenmock.MockGenerator
+	mockCodeGen       *idgenmock.MockGenerator
+	mockClock         *mockclock.MockClock
+	orchestrator      campaign.Service
+	ctx               context.Context
+
+	now      time.Time
+	campaign *campaignrepo.Campaign
+}
+
+func (s *OrchestratorTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockRepo = campaignrepomock.NewMockRepository(s.ctrl)
+	s.mockCharRepo = characterrepomock.NewMockRepository(s.ctrl)
+	s.mockEncounterRepo = encountermock.NewMockRepository(s.ctrl)
+	s.mockIDGen = idgenmock.NewMockGenerator(s.ctrl)
+	s.mockCodeGen = idgenmock.NewMockGenerator(s.ctrl)
+	s.mockClock = mockclock.NewMockClock(s.ctrl)
+	s.ctx = context.Background()
+	s.now = time.Date(2025, 8, 1, 19, 0, 0, 0, time.UTC)
+	s.mockClock.EXPECT().Now().Return(s.now).AnyTimes()
+
+	orchestrator, err := campaign.NewOrchestrator(&campaign.Config{
+		Repository:          s.mockRepo,
+		CharacterRepo:       s.mockCharRepo,
+		EncounterRepo:       s.mockEncounterRepo,
+		IDGenerator:         s.mockIDGen,
+		InviteCodeGenerator: s.mockCodeGen,
+		Clock:               s.mockClock,
+	})
+	s.Require().NoError(err)
+	s.orchestrator = orchestrator
+
+	s.campaign = &campaignrepo.Campaign{
+		ID:         "campaign-1",
+		Name:       "Curse of Strahd",
+		OwnerID:    "dm-1",
+		InviteCode: "ABCD2345",
+		Members: []*campaignrepo.Member{
+			{PlayerID: "dm-1", Role: campaignrepo.RoleDM},
+			{PlayerID: "player-1", Role: campaignrepo.RolePlayer},
+			{PlayerID: "spectator-1", Role: campaignrepo.RoleSpectator},
+		},
+	}
+}
+
+func (s *OrchestratorTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *OrchestratorTestSuite) expectGet() {
+	s.mockRepo.EXPECT().
+		Get(s.ctx, campaignrepo.GetInput{ID: "campaign-1"}).
+		Return(&campaignrepo.GetOutput{Campaign: s.campaign}, nil)
+}
+
+func (s *OrchestratorTestSuite) expectUpdate(check func(c *campaignrepo.Campaign)) {
+	s.mockRepo.EXPECT().
+		Update(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, input campaignrepo.UpdateInput) (*campaignrepo.UpdateOutput, error) {
+			check(input.Campaign)
+			return &campaignrepo.UpdateOutput{Campaign: input.Campaign}, nil
+		})
+}
+
+func (s *OrchestratorTestSuite) expectCharacter(id, playerID string) {
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: id}).
+		Return(&characterrepo.GetOutput{CharacterData: &toolkitchar.Data{ID: id, PlayerID: playerID}}, nil)
+}
+
+func (s *OrchestratorTestSuite) TestNewOrchestrator_MissingDependencies() {
+	orchestrator, err := campaign.NewOrchestrator(&campaign.Config{})
+
+	s.Nil(orchestrator)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *OrchestratorTestSuite) TestCreateCampaign() {
+	rules := &campaignrepo.RuleSettings{StartingLevel: 3, AllowFeats: true}
+	s.mockCodeGen.EXPECT().Generate().Return("ABCD2345")
+	s.mockRepo.EXPECT().
+		Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, input campaignrepo.CreateInput) (*campaignrepo.CreateOutput, error) {
+			created := *input.Campaign
+			created.ID = "campaign-1"
+			return &campaignrepo.CreateOutput{Campaign: &created}, nil
+		})
+
+	output, err := s.orchestrator.CreateCampaign(s.ctx, &campaign.CreateCampaignInput{
+		Name:    "  Curse of Strahd ",
+		OwnerID: "dm-1",
+		Rules:   rules,
+	})
+
+	s.Require().NoError(err)
+	s.Equal("campaign-1", output.Campaign.ID)
+	s.Equal("Curse of Strahd", output.Campaign.Name)
+	s.Equal("ABCD2345", output.Campaign.InviteCode)
+	s.Equal(rules, output.Campaign.Rules)
+	s.Require().Len(output.Campaign.Members, 1)
+	s.Equal(&campaignrepo.Member{PlayerID: "dm-1", Role: campaignrepo.RoleDM, JoinedAt: s.now}, output.Campaign.Members[0])
+}
+
+func (s *OrchestratorTestSuite) TestCreateCampaign_RetriesInviteCodeCollision() {
+	gomock.InOrder(
+		s.mockCodeGen.EXPECT().Generate().Return("TAKEN234"),
+		s.mockCodeGen.EXPECT().Generate().Return("FREE2345"),
+	)
+	gomock.InOrder(
+		s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).
+			Return(nil, errors.AlreadyExists("invite code is already in use")),
+		s.mockRepo.EXPECT().Create(s.ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, input campaignrepo.CreateInput) (*campaignrepo.CreateOutput, error) {
+				return &campaignrepo.CreateOutput{Campaign: input.Campaign}, nil
+			}),
+	)
+
+	output, err := s.orchestrator.CreateCampaign(s.ctx, &campaign.CreateCampaignInput{
+		Name:    "Curse of Strahd",
+		OwnerID: "dm-1",
+	})
+
+	s.Require().NoError(err)
+	s.Equal("FREE2345", output.Campaign.InviteCode)
+}
+
+func (s *OrchestratorTestSuite) TestCreateCampaign_MissingName() {
+	output, err := s.orchestrator.CreateCampaign(s.ctx, &campaign.CreateCampaignInput{
+		Name:    "  ",
+		OwnerID: "dm-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *OrchestratorTestSuite) TestGetCampaign_NotMember() {
+	s.expectGet()
+
+	output, err := s.orchestrator.GetCampaign(s.ctx, &campaign.GetCampaignInput{
+		CampaignID: "campaign-1",
+		PlayerID:   "stranger",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *OrchestratorTestSuite) TestUpdateCampaign() {
+	s.expectGet()
+	name := "Tomb of Annihilation"
+	rules := &campaignrepo.RuleSettings{FlankingAdvantage: true}
+	s.expectUpdate(func(c *campaignrepo.Campaign) {
+		s.Equal(name, c.Name)
+		s.Equal(rules, c.Rules)
+	})
+
+	output, err := s.orchestrator.UpdateCampaign(s.ctx, &campaign.UpdateCampaignInput{
+		CampaignID: "campaign-1",
+		PlayerID:   "dm-1",
+		Name:       &name,
+		Rules:      rules,
+	})
+
+	s.Require().NoError(err)
+	s.Equal(name, output.Campaign.Name)
+}
+
+func (s *OrchestratorTestSuite) TestUpdateCampaign_NotDM() {
+	s.expectGet()
+	name := "Tomb of Annihilation"
+
+	output, err := s.orchestrator.UpdateCampaign(s.ctx, &campaign.UpdateCampaignInput{
+		CampaignID: "campaign-1",
+		PlayerID:   "player-1",
+		Name:       &name,
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *OrchestratorTestSuite) TestDeleteCampaign_NotOwner() {
+	s.campaign.Members[1].Role = campaignrepo.RoleDM
+	s.expectGet()
+
+	output, err := s.orchestrator.DeleteCampaign(s.ctx, &campaign.DeleteCampaignInput{
+		CampaignID: "campaign-1",
+		PlayerID:   "player-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *OrchestratorTestSuite) TestDeleteCampaign() {
+	s.expectGet()
+	s.mockRepo.EXPECT().
+		Delete(s.ctx, campaignrepo.DeleteInput{ID: "campaign-1"}).
+		Return(&campaignrepo.DeleteOutput{}, nil)
+
+	_, err := s.orchestrator.DeleteCampaign(s.ctx, &campaign.DeleteCampaignInput{
+		CampaignID: "campaign-1",
+		PlayerID:   "dm-1",
+	})
+
+	s.NoError(err)
+}
+
+func (s *OrchestratorTestSuite) TestJoinCampaign() {
+	s.mockRepo.EXPECT().
+		GetByInviteCode(s.ctx, campaignrepo.GetByInviteCodeInput{Code: "ABCD2345"}).
+		Return(&campaignrepo.GetByInviteCodeOutput{Campaign: s.campaign}, nil)
+	s.expectUpdate(func(c *campaignrepo.Campaign) {
+		s.Require().Len(c.Members, 4)
+		s.Equal(&campaignrepo.Member{PlayerID: "player-2", Role: campaignrepo.RolePlayer, JoinedAt: s.now}, c.Members[3])
+	})
+
+	output, err := s.orchestrator.JoinCampaign(s.ctx, &campaign.JoinCampaignInput{
+		InviteCode: "abcd2345",
+		PlayerID:   "player-2",
+	})
+
+	s.Require().NoError(err)
+	s.Len(output.Campaign.Members, 4)
+}
+
+func (s *OrchestratorTestSuite) TestJoinCampaign_AlreadyMember() {
+	s.mockRepo.EXPECT().
+		GetByInviteCode(s.ctx, campaignrepo.GetByInviteCodeInput{Code: "ABCD2345"}).
+		Return(&campaignrepo.GetByInviteCodeOutput{Campaign: s.campaign}, nil)
+
+	output, err := s.orchestrator.JoinCampaign(s.ctx, &campaign.JoinCampaignInput{
+		InviteCode: "ABCD2345",
+		PlayerID:   "player-1",
+	})
+
+	s.Require().NoError(err)
+	s.Len(output.Campaign.Members, 3)
+}
+
+func (s *OrchestratorTestSuite) TestRegenerateInviteCode() {
+	s.expectGet()
+	s.mockCodeGen.EXPECT().Generate().Return("WXYZ6789")
+	s.expectUpdate(func(c *campaignrepo.Campaign) {
+		s.Equal("WXYZ6789", c.InviteCode)
+	})
+
+	output, err := s.orchestrator.RegenerateInviteCode(s.ctx, &campaign.RegenerateInviteCodeInput{
+		CampaignID: "campaign-1",
+		PlayerID:   "dm-1",
+	})
+
+	s.Require().NoError(err)
+	s.Equal("WXYZ6789", output.InviteCode)
+}
+
+func (s *OrchestratorTestSuite) TestSetMemberRole_PromoteToDM() {
+	s.expectGet()
+	s.expectUpdate(func(c *campaignrepo.Campaign) {
+		s.Equal(campaignrepo.RoleDM, c.Members[1].Role)
+	})
+
+	_, err := s.orchestrator.SetMemberRole(s.ctx, &campaign.SetMemberRoleInput{
+		CampaignID:     "campaign-1",
+		PlayerID:       "dm-1",
+		MemberPlayerID: "player-1",
+		Role:           campaignrepo.RoleDM,
+	})
+
+	s.NoError(err)
+}
+
+func (s *OrchestratorTestSuite) TestSetMemberRole_SpectatorDetachesCharacters() {
+	s.campaign.CharacterIDs = []string{"char-1", "char-2"}
+	s.expectGet()
+	s.expectCharacter("char-1", "player-1")
+	s.expectCharacter("char-2", "dm-1")
+	s.expectUpdate(func(c *campaignrepo.Campaign) {
+		s.Equal(campaignrepo.RoleSpectator, c.Members[1].Role)
+		s.Equal([]string{"char-2"}, c.CharacterIDs)
+	})
+
+	_, err := s.orchestrator.SetMemberRole(s.ctx, &campaign.SetMemberRoleInput{
+		CampaignID:     "campaign-1",
+		PlayerID:       "dm-1",
+		MemberPlayerID: "player-1",
+		Role:           campaignrepo.RoleSpectator,
+	})
+
+	s.NoError(err)
+}
+
+func (s *OrchestratorTestSuite) TestSetMemberRole_OwnerStaysDM() {
+	s.campaign.Members[1].Role = campaignrepo.RoleDM
+	s.expectGet()
+
+	output, err := s.orchestrator.SetMemberRole(s.ctx, &campaign.SetMemberRoleInput{
+		CampaignID:     "campaign-1",
+		PlayerID:       "player-1",
+		MemberPlayerID: "dm-1",
+		Role:           campaignrepo.RolePlayer,
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *OrchestratorTestSuite) TestSetMemberRole_InvalidRole() {
+	output, err := s.orchestrator.SetMemberRole(s.ctx, &campaign.SetMemberRoleInput{
+		CampaignID:     "campaign-1",
+		PlayerID:       "dm-1",
+		MemberPlayerID: "player-1",
+		Role:           "bard",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *OrchestratorTestSuite) TestRemoveMember_Leave() {
+	s.campaign.CharacterIDs = []string{"char-1"}
+	s.expectGet()
+	s.expectCharacter("char-1", "player-1")
+	s.expectUpdate(func(c *campaignrepo.Campaign) {
+		s.Len(c.Members, 2)
+		s.Empty(c.CharacterIDs)
+	})
+
+	_, err := s.orchestrator.RemoveMember(s.ctx, &campaign.RemoveMemberInput{
+		CampaignID:     "campaign-1",
+		PlayerID:       "player-1",
+		MemberPlayerID: "player-1",
+	})
+
+	s.NoError(err)
+}
+
+func (s *OrchestratorTestSuite) TestRemoveMember_PlayerCannotRemoveOthers() {
+	s.expectGet()
+
+	output, err := s.orchestrator.RemoveMember(s.ctx, &campaign.RemoveMemberInput{
+		CampaignID:     "campaign-1",
+		PlayerID:       "player-1",
+		MemberPlayerID: "spectator-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *OrchestratorTestSuite) TestRemoveMember_OwnerCannotLeave() {
+	s.expectGet()
+
+	output, err := s.orchestrator.RemoveMember(s.ctx, &campaign.RemoveMemberInput{
+		CampaignID:     "campaign-1",
+		PlayerID:       "dm-1",
+		MemberPlayerID: "dm-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *OrchestratorTestSuite) TestStartSession() {
+	s.expectGet()
+	s.mockIDGen.EXPECT().Generate().Return("session-1")
+	s.expectUpdate(func(c *campaignrepo.Campaign) {
+		s.Require().Len(c.Sessions, 1)
+	})
+
+	output, err := s.orchestrator.StartSession(s.ctx, &campaign.StartSessionInput{
+		CampaignID: "campaign-1",
+		PlayerID:   "dm-1",
+		Name:       "Death House",
+	})
+
+	s.Require().NoError(err)
+	s.Equal(&campaignrepo.Session{ID: "session-1", Name: "Death House", StartedAt: s.now}, output.Session)
+}
+
+func (s *OrchestratorTestSuite) TestStartSession_AlreadyRunning() {
+	s.campaign.Sessions = []*campaignrepo.Session{{ID: "session-1", StartedAt: s.now}}
+	s.expectGet()
+
+	output, err := s.orchestrator.StartSession(s.ctx, &campaign.StartSessionInput{
+		CampaignID: "campaign-1",
+		PlayerID:   "dm-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *OrchestratorTestSuite) TestEndSession() {
+	s.campaign.Sessions = []*campaignrepo.Session{{ID: "session-1", StartedAt: s.now.Add(-3 * time.Hour)}}
+	s.expectGet()
+	s.expectUpdate(func(c *campaignrepo.Campaign) {
+		s.Require().NotNil(c.Sessions[0].EndedAt)
+	})
+
+	output, err := s.orchestrator.EndSession(s.ctx, &campaign.EndSessionInput{
+		CampaignID: "campaign-1",
+		PlayerID:   "dm-1",
+		SessionID:  "session-1",
+	})
+
+	s.Require().NoError(err)
+	s.Equal(s.now, *output.Session.EndedAt)
+}
+
+func (s *OrchestratorTestSuite) TestEndSession_AlreadyEnded() {
+	endedAt := s.now
+	s.campaign.Sessions = []*campaignrepo.Session{{ID: "session-1", EndedAt: &endedAt}}
+	s.expectGet()
+
+	output, err := s.orchestrator.EndSession(s.ctx, &campaign.EndSessionInput{
+		CampaignID: "campaign-1",
+		PlayerID:   "dm-1",
+		SessionID:  "session-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *OrchestratorTestSuite) TestAttachCharacter() {
+	s.expectGet()
+	s.expectCharacter("char-1", "player-1")
+	s.expectUpdate(func(c *campaignrepo.Campaign) {
+		s.Equal([]string{"char-1"}, c.CharacterIDs)
+	})
+
+	_, err := s.orchestrator.AttachCharacter(s.ctx, &campaign.AttachCharacterInput{
+		CampaignID:  "campaign-1",
+		PlayerID:    "player-1",
+		CharacterID: "char-1",
+	})
+
+	s.NoError(err)
+}
+
+func (s *OrchestratorTestSuite) TestAttachCharacter_NotOwner() {
+	s.expectGet()
+	s.expectCharacter("char-1", "dm-1")
+
+	output, err := s.orchestrator.AttachCharacter(s.ctx, &campaign.AttachCharacterInput{
+		CampaignID:  "campaign-1",
+		PlayerID:    "player-1",
+		CharacterID: "char-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *OrchestratorTestSuite) TestAttachCharacter_Spectator() {
+	s.expectGet()
+
+	output, err := s.orchestrator.AttachCharacter(s.ctx, &campaign.AttachCharacterInput{
+		CampaignID:  "campaign-1",
+		PlayerID:    "spectator-1",
+		CharacterID: "char-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *OrchestratorTestSuite) TestDetachCharacter_DM() {
+	s.campaign.CharacterIDs = []string{"char-1"}
+	s.expectGet()
+	s.expectUpdate(func(c *campaignrepo.Campaign) {
+		s.Empty(c.CharacterIDs)
+	})
+
+	_, err := s.orchestrator.DetachCharacter(s.ctx, &campaign.DetachCharacterInput{
+		CampaignID:  "campaign-1",
+		PlayerID:    "dm-1",
+		CharacterID: "char-1",
+	})
+
+	s.NoError(err)
+}
+
+func (s *OrchestratorTestSuite) TestAttachEncounter() {
+	s.expectGet()
+	s.mockEncounterRepo.EXPECT().
+		Get(s.ctx, &encounters.GetInput{EncounterID: "enc-1"}).
+		Return(&encounters.GetOutput{Data: &encounters.EncounterData{ID: "enc-1"}}, nil)
+	s.expectUpdate(func(c *campaignrepo.Campaign) {
+		s.Equal([]string{"enc-1"}, c.EncounterIDs)
+	})
+
+	_, err := s.orchestrator.AttachEncounter(s.ctx, &campaign.AttachEncounterInput{
+		CampaignID:  "campaign-1",
+		PlayerID:    "dm-1",
+		EncounterID: "enc-1",
+	})
+
+	s.NoError(err)
+}
+
+func (s *OrchestratorTestSuite) TestAttachEncounter_NotFound() {
+	s.expectGet()
+	s.mockEncounterRepo.EXPECT().
+		Get(s.ctx, &encounters.GetInput{EncounterID: "enc-1"}).
+		Return(nil, errors.NotFound("encounter not found"))
+
+	output, err := s.orchestrator.AttachEncounter(s.ctx, &campaign.AttachEncounterInput{
+		CampaignID:  "campaign-1",
+		PlayerID:    "dm-1",
+		EncounterID: "enc-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsNotFound(err))
+}
+
+func (s *OrchestratorTestSuite) TestAttachDiceSession() {
+	s.campaign.DiceSessions = []*campaignrepo.DiceSessionRef{{EntityID: "char-1", Context: "checks"}}
+	s.expectGet()
+	s.expectUpdate(func(c *campaignrepo.Campaign) {
+		s.Len(c.DiceSessions, 2)
+	})
+
+	_, err := s.orchestrator.AttachDiceSession(s.ctx, &campaign.AttachDiceSessionInput{
+		CampaignID: "campaign-1",
+		PlayerID:   "player-1",
+		EntityID:   "char-1",
+		Context:    "attack",
+	})
+
+	s.NoError(err)
+}
+
+func TestOrchestratorTestSuite(t *testing.T) {
+	suite.Run(t, new(OrchestratorTestSuite))
+}
@@ -0,0 +1,183 @@
+package campaign
+
+import (
+	campaignrepo "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
+)
+
+// CreateCampaignInput defines the request for creating a campaign
+type CreateCampaignInput struct {
+	Name        string
+	Description string
+	OwnerID     string // Becomes the campaign's first DM
+	Rules       *campaignrepo.RuleSettings
+}
+
+// CreateCampaignOutput defines the response for creating a campaign
+type CreateCampaignOutput struct {
+	Campaign *campaignrepo.Campaign
+}
+
+// GetCampaignInput defines the request for getting a campaign
+type GetCampaignInput struct {
+	CampaignID string
+	PlayerID   string // Must be a member
+}
+
+// GetCampaignOutput defines the response for getting a campaign
+type GetCampaignOutput struct {
+	Campaign *campaignrepo.Campaign
+}
+
+// ListCampaignsInput defines the request for listing a player's campaigns
+type ListCampaignsInput struct {
+	PlayerID string
+}
+
+// ListCampaignsOutput defines the response for listing a player's campaigns
+type ListCampaignsOutput struct {
+	Campaigns []*campaignrepo.Campaign
+}
+
+// UpdateCampaignInput defines the request for updating a campaign's details.
+// Nil fields are left unchanged.
+type UpdateCampaignInput struct {
+	CampaignID  string
+	PlayerID    string // Must be a DM
+	Name        *string
+	Description *string
+	Rules       *campaignrepo.RuleSettings
+}
+
+// UpdateCampaignOutput defines the response for updating a campaign
+type UpdateCampaignOutput struct {
+	Campaign *campaignrepo.Campaign
+}
+
+// DeleteCampaignInput defines the request for deleting a campaign
+type DeleteCampaignInput struct {
+	CampaignID string
+	PlayerID   string // Must be the owner
+}
+
+// DeleteCampaignOutput defines the response for deleting a campaign
+type DeleteCampaignOutput struct{}
+
+// JoinCampaignInput defines the request for joining a campaign with an invite code
+type JoinCampaignInput struct {
+	InviteCode string
+	PlayerID   string
+}
+
+// JoinCampaignOutput defines the response for joining a campaign
+type JoinCampaignOutput struct {
+	Campaign *campaignrepo.Campaign
+}
+
+// RegenerateInviteCodeInput defines the request for replacing a campaign's invite code
+type RegenerateInviteCodeInput struct {
+	CampaignID string
+	PlayerID   string // Must be a DM
+}
+
+// RegenerateInviteCodeOutput defines the response for replacing a campaign's invite code
+type RegenerateInviteCodeOutput struct {
+	InviteCode string
+}
+
+// SetMemberRoleInput defines the request for changing a member's role
+type SetMemberRoleInput struct {
+	CampaignID     string
+	PlayerID       string // Must be a DM
+	MemberPlayerID string
+	Role           string
+}
+
+// SetMemberRoleOutput defines the response for changing a member's role
+type SetMemberRoleOutput struct {
+	Campaign *campaignrepo.Campaign
+}
+
+// RemoveMemberInput defines the request for removing a member, or for a member leaving
+type RemoveMemberInput struct {
+	CampaignID     string
+	PlayerID       string // Must be a DM, or the member themselves
+	MemberPlayerID string
+}
+
+// RemoveMemberOutput defines the response for removing a member
+type RemoveMemberOutput struct {
+	Campaign *campaignrepo.Campaign
+}
+
+// StartSessionInput defines the request for starting a game session
+type StartSessionInput struct {
+	CampaignID string
+	PlayerID   string // Must be a DM
+	Name       string
+}
+
+// StartSessionOutput defines the response for starting a game session
+type StartSessionOutput struct {
+	Session *campaignrepo.Session
+}
+
+// EndSessionInput defines the request for ending a game session
+type EndSessionInput struct {
+	CampaignID string
+	PlayerID   string // Must be a DM
+	SessionID  string
+}
+
+// EndSessionOutput defines the response for ending a game session
+type EndSessionOutput struct {
+	Session *campaignrepo.Session
+}
+
+// AttachCharacterInput defines the request for bringing a character into a campaign
+type AttachCharacterInput struct {
+	CampaignID  string
+	PlayerID    string // Must own the character and be a player or DM
+	CharacterID string
+}
+
+// AttachCharacterOutput defines the response for attaching a character
+type AttachCharacterOutput struct {
+	Campaign *campaignrepo.Campaign
+}
+
+// DetachCharacterInput defines the request for removing a character from a campaign
+type DetachCharacterInput struct {
+	CampaignID  string
+	PlayerID    string // Must own the character, or be a DM
+	CharacterID string
+}
+
+// DetachCharacterOutput defines the response for detaching a character
+type DetachCharacterOutput struct {
+	Campaign *campaignrepo.Campaign
+}
+
+// AttachEncounterInput defines the request for adding an encounter to a campaign
+type AttachEncounterInput struct {
+	CampaignID  string
+	PlayerID    string // Must be a DM
+	EncounterID string
+}
+
+// AttachEncounterOutput defines the response for attaching an encounter
+type AttachEncounterOutput struct {
+	Campaign *campaignrepo.Campaign
+}
+
+// AttachDiceSessionInput defines the request for adding a dice session to a campaign
+type AttachDiceSessionInput struct {
+	CampaignID string
+	PlayerID   string // Must be a member
+	EntityID   string
+	Context    string
+}
+
+// AttachDiceSessionOutput defines the response for attaching a dice session
+type AttachDiceSessionOutput struct {
+	Campaign *campaignrepo.Campaign
+}
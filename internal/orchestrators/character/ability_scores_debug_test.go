@@ -14,6 +14,7 @@ import (
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/dice"
 	dicemock "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice/mock"
 	idgenmock "github.com/KirkDiggler/rpg-api/internal/pkg/idgen/mock"
+	campaignmock "github.com/KirkDiggler/rpg-api/internal/repositories/campaign/mock"
 	charmock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
 	draftrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft"
 	draftmock "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft/mock"
@@ -46,6 +47,7 @@ func (s *AbilityScoresDebugTestSuite) SetupTest() {
 	cfg := &character.Config{
 		CharacterRepo:      s.mockCharRepo,
 		CharacterDraftRepo: s.mockDraftRepo,
+		CampaignRepo:       campaignmock.NewMockRepository(s.ctrl),
 		ExternalClient:     s.mockExtClient,
 		DiceService:        s.mockDiceService,
 		IDGenerator:        s.mockIDGen,
@@ -0,0 +1,224 @@
+package character
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+)
+
+const (
+	defaultReachFeet = 5
+	reachWeaponFeet  = 10
+
+	archeryAttackBonus = 2
+	duelingDamageBonus = 2
+)
+
+// weaponSlots are the slots that can hold a weapon, in the order attacks are listed
+var weaponSlots = []string{SlotMainHand, SlotOffHand}
+
+// weaponAttack is an attack profile along with the die a versatile weapon rolls in two hands
+type weaponAttack struct {
+	*AttackProfile
+	twoHandedDamageDice string
+}
+
+// attackProfiles calculates to-hit and damage for each weapon the character has equipped,
+// including the Archery, Dueling and Two-Weapon Fighting styles. Like derived stats, lookup
+// failures are logged and the weapon is left out.
+func (o *Orchestrator) attackProfiles(
+	ctx context.Context,
+	charData *toolkitchar.Data,
+	sheet *dnd5e.CharacterSheet,
+) []*AttackProfile {
+	if charData == nil {
+		return nil
+	}
+	sheet = dnd5e.SheetOrEmpty(sheet)
+
+	weapons := make(map[string]*weaponAttack, len(weaponSlots))
+	for _, slot := range weaponSlots {
+		itemID, ok := sheet.EquippedSlots[slot]
+		if !ok {
+			continue
+		}
+		if weapon := o.weaponAttack(ctx, charData, sheet, slot, itemID); weapon != nil {
+			weapons[slot] = weapon
+		}
+	}
+
+	style := fightingStyle(charData)
+	mainHand, offHand := weapons[SlotMainHand], weapons[SlotOffHand]
+	switch {
+	case mainHand != nil && offHand != nil:
+		// Fighting with two weapons, the off hand only adds a negative ability modifier to
+		// damage unless the character has the Two-Weapon Fighting style
+		if mod := dnd5e.AbilityModifier(charData.AbilityScores[offHand.Ability]); mod > 0 &&
+			style != FightingStyleTwoWeaponFighting {
+			offHand.DamageBonus -= mod
+		}
+	case mainHand != nil:
+		_, offHandFull := sheet.EquippedSlots[SlotOffHand]
+		switch {
+		case style == FightingStyleDueling && mainHand.AttackType == AttackTypeMelee &&
+			!slices.ContainsFunc(mainHand.Properties, isTwoHanded):
+			// Dueling adds +2 damage to a melee weapon held in one hand with no other weapon
+			mainHand.DamageBonus += duelingDamageBonus
+		case mainHand.twoHandedDamageDice != "" && !offHandFull:
+			// A versatile weapon with the off hand free is wielded in both hands
+			mainHand.DamageDice = mainHand.twoHandedDamageDice
+		}
+	}
+
+	profiles := make([]*AttackProfile, 0, len(weapons))
+	for _, slot := range weaponSlots {
+		if weapon, ok := weapons[slot]; ok {
+			profiles = append(profiles, weapon.AttackProfile)
+		}
+	}
+
+	return profiles
+}
+
+// weaponAttack builds the attack for a single equipped item, nil when it is not a weapon
+func (o *Orchestrator) weaponAttack(
+	ctx context.Context,
+	charData *toolkitchar.Data,
+	sheet *dnd5e.CharacterSheet,
+	slot, itemID string,
+) *weaponAttack {
+	var magicItem *external.MagicItemData
+	equipmentID := itemID
+	if external.IsMagicItemID(itemID) {
+		item, err := o.externalClient.GetMagicItemData(ctx, itemID)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load magic weapon",
+				"item_id", itemID,
+				"error", err.Error())
+			return nil
+		}
+		if item.BaseEquipmentID == "" {
+			return nil
+		}
+		magicItem = item
+		equipmentID = item.BaseEquipmentID
+	}
+
+	weapon, err := o.externalClient.GetEquipmentData(ctx, equipmentID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load weapon",
+			"item_id", equipmentID,
+			"error", err.Error())
+		return nil
+	}
+	if weapon == nil || weapon.Damage == nil {
+		return nil
+	}
+
+	profile := &AttackProfile{
+		WeaponID:   itemID,
+		WeaponName: weapon.Name,
+		Slot:       slot,
+		AttackType: AttackTypeMelee,
+		Ability:    constants.STR,
+		Proficient: hasWeaponProficiency(charData.Proficiencies.Weapons, weapon),
+		DamageDice: weapon.Damage.DamageDice,
+		DamageType: weapon.Damage.DamageType,
+		ReachFeet:  defaultReachFeet,
+		Properties: weapon.Properties,
+	}
+	if magicItem != nil {
+		profile.WeaponName = magicItem.Name
+	}
+
+	// Ranged weapons use DEX, finesse weapons use whichever of STR and DEX is higher
+	strMod := dnd5e.AbilityModifier(charData.AbilityScores[constants.STR])
+	dexMod := dnd5e.AbilityModifier(charData.AbilityScores[constants.DEX])
+	if strings.EqualFold(weapon.WeaponRange, "Ranged") {
+		profile.AttackType = AttackTypeRanged
+		profile.Ability = constants.DEX
+		profile.ReachFeet = 0
+	} else if hasProperty(weapon, "Finesse") && dexMod > strMod {
+		profile.Ability = constants.DEX
+	}
+	if hasProperty(weapon, "Reach") {
+		profile.ReachFeet = reachWeaponFeet
+	}
+
+	abilityMod := dnd5e.AbilityModifier(charData.AbilityScores[profile.Ability])
+	profile.AttackBonus = abilityMod
+	profile.DamageBonus = abilityMod
+	if profile.Proficient {
+		profile.AttackBonus += dnd5e.ProficiencyBonus(charData.Level)
+	}
+
+	if magicItem != nil && magicItem.Effects != nil &&
+		(!magicItem.RequiresAttunement || slices.Contains(sheet.AttunedItems, itemID)) {
+		profile.AttackBonus += magicItem.Effects.AttackBonus
+		profile.DamageBonus += magicItem.Effects.DamageBonus
+	}
+
+	// The Archery fighting style adds +2 to attack rolls with ranged weapons
+	if profile.AttackType == AttackTypeRanged && fightingStyle(charData) == FightingStyleArchery {
+		profile.AttackBonus += archeryAttackBonus
+	}
+
+	attack := &weaponAttack{AttackProfile: profile}
+	if weapon.TwoHandedDamage != nil && hasProperty(weapon, "Versatile") {
+		attack.twoHandedDamageDice = weapon.TwoHandedDamage.DamageDice
+	}
+
+	weaponRange := weapon.Range
+	if weaponRange == nil {
+		weaponRange = weapon.ThrowRange
+	}
+	if weaponRange != nil {
+		// nolint:gosec // weapon ranges are at most a few hundred feet
+		profile.NormalRange = int32(weaponRange.Normal)
+		// nolint:gosec // weapon ranges are at most a few hundred feet
+		profile.LongRange = int32(weaponRange.Long)
+	}
+
+	return attack
+}
+
+// hasWeaponProficiency matches weapon proficiencies such as "Simple Weapons", "martial"
+// or "Longswords" against a weapon's category and name
+func hasWeaponProficiency(proficiencies []string, weapon *external.EquipmentData) bool {
+	category := strings.ToLower(weapon.WeaponCategory)
+	name := strings.ToLower(weapon.Name)
+
+	for _, proficiency := range proficiencies {
+		if proficiency == weapon.ID {
+			return true
+		}
+		normalized := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(proficiency)), " weapons")
+		if category != "" && normalized == category {
+			return true
+		}
+		if normalized == name || strings.TrimSuffix(normalized, "s") == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isTwoHanded(property string) bool {
+	return strings.EqualFold(property, "Two-Handed")
+}
+
+func hasProperty(equipment *external.EquipmentData, property string) bool {
+	for _, prop := range equipment.Properties {
+		if strings.EqualFold(prop, property) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+package character_test
+
+import (
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+const longswordPlusOne = "MAGIC_ITEM_LONGSWORD_1"
+
+func (s *EquipmentOrchestratorTestSuite) expectEquipment(equipment *external.EquipmentData) {
+	s.mockExtClient.EXPECT().GetEquipmentData(s.ctx, equipment.ID).Return(equipment, nil).AnyTimes()
+}
+
+func (s *EquipmentOrchestratorTestSuite) expectLongsword() {
+	s.expectEquipment(&external.EquipmentData{
+		ID:              "EQUIPMENT_LONGSWORD",
+		Name:            "Longsword",
+		WeaponCategory:  "Martial",
+		WeaponRange:     "Melee",
+		Damage:          &external.DamageData{DamageDice: "1d8", DamageType: "Slashing"},
+		TwoHandedDamage: &external.DamageData{DamageDice: "1d10", DamageType: "Slashing"},
+		Properties:      []string{"Versatile"},
+	})
+}
+
+func (s *EquipmentOrchestratorTestSuite) expectDagger() {
+	s.expectEquipment(&external.EquipmentData{
+		ID:             "EQUIPMENT_DAGGER",
+		Name:           "Dagger",
+		WeaponCategory: "Simple",
+		WeaponRange:    "Melee",
+		Damage:         &external.DamageData{DamageDice: "1d4", DamageType: "Piercing"},
+		ThrowRange:     &external.WeaponRangeData{Normal: 20, Long: 60},
+		Properties:     []string{"Finesse", "Light", "Thrown"},
+	})
+}
+
+func (s *EquipmentOrchestratorTestSuite) expectLongbow() {
+	s.expectEquipment(&external.EquipmentData{
+		ID:             "EQUIPMENT_LONGBOW",
+		Name:           "Longbow",
+		WeaponCategory: "Martial",
+		WeaponRange:    "Ranged",
+		Damage:         &external.DamageData{DamageDice: "1d8", DamageType: "Piercing"},
+		Range:          &external.WeaponRangeData{Normal: 150, Long: 600},
+		Properties:     []string{"Ammunition", "Heavy", "Two-Handed"},
+	})
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacter_AttackProfilesTwoWeapons() {
+	s.charData.Equipment = []string{longswordPlusOne, "EQUIPMENT_DAGGER"}
+	s.charData.Proficiencies = shared.Proficiencies{Weapons: []string{"Simple Weapons", "Daggers"}}
+	s.expectGet(&dnd5e.CharacterSheet{
+		EquippedSlots: map[string]string{
+			character.SlotMainHand: longswordPlusOne,
+			character.SlotOffHand:  "EQUIPMENT_DAGGER",
+		},
+	})
+
+	s.mockExtClient.EXPECT().GetMagicItemData(s.ctx, longswordPlusOne).Return(&external.MagicItemData{
+		ID:              longswordPlusOne,
+		Name:            "Longsword, +1",
+		BaseEquipmentID: "EQUIPMENT_LONGSWORD",
+		Effects:         &external.MagicItemEffects{AttackBonus: 1, DamageBonus: 1},
+	}, nil).AnyTimes()
+	s.expectLongsword()
+	s.expectDagger()
+
+	output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "char-1", ViewerID: "player-1"})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.AttackProfiles, 2)
+
+	// Not proficient with martial weapons: +3 STR, +1 weapon
+	longsword := output.AttackProfiles[0]
+	s.Equal(character.SlotMainHand, longsword.Slot)
+	s.Equal("Longsword, +1", longsword.WeaponName)
+	s.False(longsword.Proficient)
+	s.Equal(constants.STR, longsword.Ability)
+	s.Equal(int32(4), longsword.AttackBonus)
+	s.Equal(int32(4), longsword.DamageBonus)
+	// Versatile, but the off hand holds the dagger
+	s.Equal("1d8", longsword.DamageDice)
+	s.Equal(int32(5), longsword.ReachFeet)
+
+	// STR beats DEX for the finesse dagger, but the off hand adds no positive damage modifier
+	dagger := output.AttackProfiles[1]
+	s.Equal(character.SlotOffHand, dagger.Slot)
+	s.True(dagger.Proficient)
+	s.Equal(constants.STR, dagger.Ability)
+	s.Equal(int32(5), dagger.AttackBonus)
+	s.Equal(int32(0), dagger.DamageBonus)
+	s.Equal(int32(20), dagger.NormalRange)
+	s.Equal(int32(60), dagger.LongRange)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacter_AttackProfileFinesseUsesDex() {
+	s.charData.AbilityScores[constants.DEX] = 18
+	s.charData.Equipment = []string{"EQUIPMENT_RAPIER"}
+	s.charData.Proficiencies = shared.Proficiencies{Weapons: []string{"martial"}}
+	s.expectGet(&dnd5e.CharacterSheet{
+		EquippedSlots: map[string]string{character.SlotMainHand: "EQUIPMENT_RAPIER"},
+	})
+	s.expectEquipment(&external.EquipmentData{
+		ID:             "EQUIPMENT_RAPIER",
+		Name:           "Rapier",
+		WeaponCategory: "Martial",
+		WeaponRange:    "Melee",
+		Damage:         &external.DamageData{DamageDice: "1d8", DamageType: "Piercing"},
+		Properties:     []string{"Finesse"},
+	})
+
+	output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "char-1", ViewerID: "player-1"})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.AttackProfiles, 1)
+	rapier := output.AttackProfiles[0]
+	s.Equal(constants.DEX, rapier.Ability)
+	s.True(rapier.Proficient)
+	s.Equal(int32(6), rapier.AttackBonus)
+	s.Equal(int32(4), rapier.DamageBonus)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacter_AttackProfileRangedWeapon() {
+	s.charData.Equipment = []string{"EQUIPMENT_LONGBOW"}
+	s.charData.Proficiencies = shared.Proficiencies{Weapons: []string{"Longbows"}}
+	s.expectGet(&dnd5e.CharacterSheet{
+		EquippedSlots: map[string]string{character.SlotMainHand: "EQUIPMENT_LONGBOW"},
+	})
+	s.expectLongbow()
+
+	output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "char-1", ViewerID: "player-1"})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.AttackProfiles, 1)
+	longbow := output.AttackProfiles[0]
+	s.Equal(character.AttackTypeRanged, longbow.AttackType)
+	s.Equal(constants.DEX, longbow.Ability)
+	s.True(longbow.Proficient)
+	s.Equal(int32(4), longbow.AttackBonus)
+	s.Equal(int32(2), longbow.DamageBonus)
+	s.Equal(int32(0), longbow.ReachFeet)
+	s.Equal(int32(150), longbow.NormalRange)
+	s.Equal(int32(600), longbow.LongRange)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacter_AttackProfileVersatileInTwoHands() {
+	s.charData.Equipment = []string{"EQUIPMENT_LONGSWORD"}
+	s.expectGet(&dnd5e.CharacterSheet{
+		EquippedSlots: map[string]string{character.SlotMainHand: "EQUIPMENT_LONGSWORD"},
+	})
+	s.expectLongsword()
+
+	output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "char-1", ViewerID: "player-1"})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.AttackProfiles, 1)
+	s.Equal("1d10", output.AttackProfiles[0].DamageDice)
+	s.Equal(int32(3), output.AttackProfiles[0].DamageBonus)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacter_AttackProfileFightingStyles() {
+	s.Run("dueling", func() {
+		s.SetupTest()
+		s.charData.Choices = []toolkitchar.ChoiceData{fightingStyleChoice(character.FightingStyleDueling)}
+		s.expectGet(&dnd5e.CharacterSheet{
+			EquippedSlots: map[string]string{character.SlotMainHand: "EQUIPMENT_LONGSWORD"},
+		})
+		s.expectLongsword()
+
+		output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "char-1", ViewerID: "player-1"})
+
+		s.Require().NoError(err)
+		s.Require().Len(output.AttackProfiles, 1)
+		// Held in one hand for the +2, rather than two-handed for the d10
+		s.Equal("1d8", output.AttackProfiles[0].DamageDice)
+		s.Equal(int32(5), output.AttackProfiles[0].DamageBonus)
+	})
+
+	s.Run("dueling with a second weapon", func() {
+		s.SetupTest()
+		s.charData.Choices = []toolkitchar.ChoiceData{fightingStyleChoice(character.FightingStyleDueling)}
+		s.expectGet(&dnd5e.CharacterSheet{
+			EquippedSlots: map[string]string{
+				character.SlotMainHand: "EQUIPMENT_LONGSWORD",
+				character.SlotOffHand:  "EQUIPMENT_DAGGER",
+			},
+		})
+		s.expectLongsword()
+		s.expectDagger()
+
+		output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "char-1", ViewerID: "player-1"})
+
+		s.Require().NoError(err)
+		s.Require().Len(output.AttackProfiles, 2)
+		s.Equal(int32(3), output.AttackProfiles[0].DamageBonus)
+	})
+
+	s.Run("two-weapon fighting", func() {
+		s.SetupTest()
+		s.charData.Choices = []toolkitchar.ChoiceData{fightingStyleChoice(character.FightingStyleTwoWeaponFighting)}
+		s.expectGet(&dnd5e.CharacterSheet{
+			EquippedSlots: map[string]string{
+				character.SlotMainHand: "EQUIPMENT_LONGSWORD",
+				character.SlotOffHand:  "EQUIPMENT_DAGGER",
+			},
+		})
+		s.expectLongsword()
+		s.expectDagger()
+
+		output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "char-1", ViewerID: "player-1"})
+
+		s.Require().NoError(err)
+		s.Require().Len(output.AttackProfiles, 2)
+		// The off hand keeps its STR modifier
+		s.Equal(int32(3), output.AttackProfiles[1].DamageBonus)
+	})
+
+	s.Run("archery", func() {
+		s.SetupTest()
+		s.charData.Choices = []toolkitchar.ChoiceData{fightingStyleChoice(character.FightingStyleArchery)}
+		s.charData.Proficiencies = shared.Proficiencies{Weapons: []string{"Longbows"}}
+		s.expectGet(&dnd5e.CharacterSheet{
+			EquippedSlots: map[string]string{character.SlotMainHand: "EQUIPMENT_LONGBOW"},
+		})
+		s.expectLongbow()
+
+		output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "char-1", ViewerID: "player-1"})
+
+		s.Require().NoError(err)
+		s.Require().Len(output.AttackProfiles, 1)
+		// +2 DEX, +2 proficiency, +2 Archery
+		s.Equal(int32(6), output.AttackProfiles[0].AttackBonus)
+		s.Equal(int32(2), output.AttackProfiles[0].DamageBonus)
+	})
+}
@@ -0,0 +1,205 @@
+package character
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/character"
+)
+
+// defaultDeletedCharacterRetention is how long a deleted character can be restored before it is purged
+const defaultDeletedCharacterRetention = 30 * 24 * time.Hour
+
+// DeleteCharacter soft deletes a character. It can be restored until the retention window passes.
+func (o *Orchestrator) DeleteCharacter(ctx context.Context, input *DeleteCharacterInput) (*DeleteCharacterOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+
+	deleteOutput, err := o.charRepo.SoftDelete(ctx, character.SoftDeleteInput{ID: input.CharacterID})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NotFoundf("character %s not found", input.CharacterID)
+		}
+		return nil, errors.Wrapf(err, "failed to delete character %s", input.CharacterID)
+	}
+
+	purgeAt := deleteOutput.DeletedAt.Add(o.retention)
+	slog.InfoContext(ctx, "soft deleted character",
+		"character_id", input.CharacterID,
+		"purge_at", purgeAt)
+
+	return &DeleteCharacterOutput{
+		Message: fmt.Sprintf("Character %s deleted successfully, it can be restored until %s",
+			input.CharacterID, purgeAt.Format(time.RFC3339)),
+		PurgeAt: purgeAt,
+	}, nil
+}
+
+// RestoreCharacter brings back a soft deleted character. Only its owner may restore it.
+func (o *Orchestrator) RestoreCharacter(
+	ctx context.Context,
+	input *RestoreCharacterInput,
+) (*RestoreCharacterOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument("player ID is required")
+	}
+
+	if _, err := o.getOwnedCharacterIncludingDeleted(ctx, input.CharacterID, input.PlayerID); err != nil {
+		return nil, err
+	}
+
+	restoreOutput, err := o.charRepo.Restore(ctx, character.RestoreInput{ID: input.CharacterID})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NotFoundf("deleted character %s not found", input.CharacterID)
+		}
+		return nil, errors.Wrapf(err, "failed to restore character %s", input.CharacterID)
+	}
+
+	slog.InfoContext(ctx, "restored character", "character_id", input.CharacterID)
+
+	return &RestoreCharacterOutput{Character: restoreOutput.CharacterData}, nil
+}
+
+// ListDeletedCharacters returns a player's soft deleted characters and when each will be purged
+func (o *Orchestrator) ListDeletedCharacters(
+	ctx context.Context,
+	input *ListDeletedCharactersInput,
+) (*ListDeletedCharactersOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument("player ID is required")
+	}
+
+	listOutput, err := o.charRepo.ListDeleted(ctx, character.ListDeletedInput{PlayerID: input.PlayerID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list deleted characters for player %s", input.PlayerID)
+	}
+
+	characters := make([]*DeletedCharacter, 0, len(listOutput.Characters))
+	for _, deleted := range listOutput.Characters {
+		characters = append(characters, &DeletedCharacter{
+			Character: deleted.CharacterData,
+			DeletedAt: deleted.DeletedAt,
+			PurgeAt:   deleted.DeletedAt.Add(o.retention),
+		})
+	}
+
+	return &ListDeletedCharactersOutput{Characters: characters}, nil
+}
+
+// HardDeleteCharacter permanently deletes a character, active or soft deleted, along with its history.
+// Only its owner may delete it, and the confirmation must match the character's name so a stray
+// request cannot destroy a character.
+func (o *Orchestrator) HardDeleteCharacter(
+	ctx context.Context,
+	input *HardDeleteCharacterInput,
+) (*HardDeleteCharacterOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument("player ID is required")
+	}
+	if strings.TrimSpace(input.Confirmation) == "" {
+		return nil, errors.InvalidArgument("confirmation is required")
+	}
+
+	getOutput, err := o.getOwnedCharacterIncludingDeleted(ctx, input.CharacterID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(input.Confirmation) != getOutput.CharacterData.Name {
+		return nil, errors.InvalidArgument("confirmation must match the character name")
+	}
+
+	_, err = o.charRepo.Delete(ctx, character.DeleteInput{ID: input.CharacterID})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NotFoundf("character %s not found", input.CharacterID)
+		}
+		return nil, errors.Wrapf(err, "failed to delete character %s", input.CharacterID)
+	}
+
+	slog.InfoContext(ctx, "permanently deleted character", "character_id", input.CharacterID)
+
+	return &HardDeleteCharacterOutput{
+		Message: fmt.Sprintf("Character %s permanently deleted", input.CharacterID),
+	}, nil
+}
+
+// PurgeDeletedCharacters permanently deletes characters whose retention window has passed.
+// It is run periodically by the server's sweeper.
+func (o *Orchestrator) PurgeDeletedCharacters(
+	ctx context.Context,
+	input *PurgeDeletedCharactersInput,
+) (*PurgeDeletedCharactersOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	cutoff := o.clock.Now().Add(-o.retention)
+	listOutput, err := o.charRepo.ListDeleted(ctx, character.ListDeletedInput{DeletedBefore: cutoff})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list expired characters")
+	}
+
+	purged := make([]string, 0, len(listOutput.Characters))
+	for _, deleted := range listOutput.Characters {
+		id := deleted.CharacterData.ID
+		_, err = o.charRepo.Delete(ctx, character.DeleteInput{ID: id})
+		if err != nil {
+			// Already gone, e.g. hard deleted since it was listed
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to purge character %s", id)
+		}
+		purged = append(purged, id)
+	}
+
+	if len(purged) > 0 {
+		slog.InfoContext(ctx, "purged deleted characters",
+			"count", len(purged),
+			"character_ids", purged)
+	}
+
+	return &PurgeDeletedCharactersOutput{PurgedCharacterIDs: purged}, nil
+}
+
+// getOwnedCharacterIncludingDeleted is getOwnedCharacter for a character that may be soft deleted
+func (o *Orchestrator) getOwnedCharacterIncludingDeleted(
+	ctx context.Context,
+	characterID, playerID string,
+) (*character.GetOutput, error) {
+	getOutput, err := o.charRepo.Get(ctx, character.GetInput{ID: characterID, IncludeDeleted: true})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NotFoundf("character %s not found", characterID)
+		}
+		return nil, errors.Wrapf(err, "failed to get character %s", characterID)
+	}
+	if getOutput.CharacterData.PlayerID != playerID {
+		return nil, errors.PermissionDeniedf("character %s is not owned by player %s", characterID, playerID)
+	}
+	return getOutput, nil
+}
@@ -0,0 +1,241 @@
+package character_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	extmock "github.com/KirkDiggler/rpg-api/internal/clients/external/mock"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	dicemock "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice/mock"
+	mockclock "github.com/KirkDiggler/rpg-api/internal/pkg/clock/mock"
+	campaignmock "github.com/KirkDiggler/rpg-api/internal/repositories/campaign/mock"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	charmock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
+	draftmock "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft/mock"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+)
+
+var deletedAt = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+type CharacterDeletionTestSuite struct {
+	suite.Suite
+	ctrl         *gomock.Controller
+	orchestrator *character.Orchestrator
+	mockCharRepo *charmock.MockRepository
+	mockClock    *mockclock.MockClock
+	ctx          context.Context
+
+	charData *toolkitchar.Data
+}
+
+func (s *CharacterDeletionTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockCharRepo = charmock.NewMockRepository(s.ctrl)
+	s.mockClock = mockclock.NewMockClock(s.ctrl)
+	s.ctx = context.Background()
+
+	orch, err := character.New(&character.Config{
+		CharacterRepo:             s.mockCharRepo,
+		CharacterDraftRepo:        draftmock.NewMockRepository(s.ctrl),
+		CampaignRepo:              campaignmock.NewMockRepository(s.ctrl),
+		ExternalClient:            extmock.NewMockClient(s.ctrl),
+		DiceService:               dicemock.NewMockService(s.ctrl),
+		IDGenerator:               &mockIDGenerator{},
+		DraftIDGenerator:          &mockIDGenerator{},
+		Clock:                     s.mockClock,
+		DeletedCharacterRetention: 7 * 24 * time.Hour,
+	})
+	s.Require().NoError(err)
+	s.orchestrator = orch
+
+	s.charData = &toolkitchar.Data{ID: "char-1", PlayerID: "player-1", Name: "Brienne"}
+}
+
+func (s *CharacterDeletionTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *CharacterDeletionTestSuite) TestDeleteCharacter_SoftDeletes() {
+	s.mockCharRepo.EXPECT().
+		SoftDelete(s.ctx, characterrepo.SoftDeleteInput{ID: "char-1"}).
+		Return(&characterrepo.SoftDeleteOutput{DeletedAt: deletedAt}, nil)
+
+	output, err := s.orchestrator.DeleteCharacter(s.ctx, &character.DeleteCharacterInput{CharacterID: "char-1"})
+
+	s.Require().NoError(err)
+	s.Equal(deletedAt.Add(7*24*time.Hour), output.PurgeAt)
+	s.Contains(output.Message, "2025-06-08T12:00:00Z")
+}
+
+func (s *CharacterDeletionTestSuite) TestDeleteCharacter_NotFound() {
+	s.mockCharRepo.EXPECT().
+		SoftDelete(s.ctx, characterrepo.SoftDeleteInput{ID: "missing"}).
+		Return(nil, errors.NotFound("character not found"))
+
+	output, err := s.orchestrator.DeleteCharacter(s.ctx, &character.DeleteCharacterInput{CharacterID: "missing"})
+
+	s.Nil(output)
+	s.True(errors.IsNotFound(err))
+}
+
+func (s *CharacterDeletionTestSuite) TestRestoreCharacter() {
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: "char-1", IncludeDeleted: true}).
+		Return(&characterrepo.GetOutput{CharacterData: s.charData, DeletedAt: &deletedAt}, nil)
+	s.mockCharRepo.EXPECT().
+		Restore(s.ctx, characterrepo.RestoreInput{ID: "char-1"}).
+		Return(&characterrepo.RestoreOutput{CharacterData: s.charData}, nil)
+
+	output, err := s.orchestrator.RestoreCharacter(s.ctx, &character.RestoreCharacterInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+	})
+
+	s.Require().NoError(err)
+	s.Equal(s.charData, output.Character)
+}
+
+func (s *CharacterDeletionTestSuite) TestRestoreCharacter_NotDeleted() {
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: "char-1", IncludeDeleted: true}).
+		Return(&characterrepo.GetOutput{CharacterData: s.charData}, nil)
+	s.mockCharRepo.EXPECT().
+		Restore(s.ctx, characterrepo.RestoreInput{ID: "char-1"}).
+		Return(nil, errors.NotFound("deleted character not found"))
+
+	output, err := s.orchestrator.RestoreCharacter(s.ctx, &character.RestoreCharacterInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsNotFound(err))
+}
+
+func (s *CharacterDeletionTestSuite) TestRestoreCharacter_NotOwner() {
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: "char-1", IncludeDeleted: true}).
+		Return(&characterrepo.GetOutput{CharacterData: s.charData, DeletedAt: &deletedAt}, nil)
+
+	output, err := s.orchestrator.RestoreCharacter(s.ctx, &character.RestoreCharacterInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-2",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *CharacterDeletionTestSuite) TestListDeletedCharacters() {
+	s.mockCharRepo.EXPECT().
+		ListDeleted(s.ctx, characterrepo.ListDeletedInput{PlayerID: "player-1"}).
+		Return(&characterrepo.ListDeletedOutput{Characters: []*characterrepo.DeletedCharacter{
+			{CharacterData: s.charData, DeletedAt: deletedAt},
+		}}, nil)
+
+	output, err := s.orchestrator.ListDeletedCharacters(s.ctx, &character.ListDeletedCharactersInput{
+		PlayerID: "player-1",
+	})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.Characters, 1)
+	s.Equal(s.charData, output.Characters[0].Character)
+	s.Equal(deletedAt, output.Characters[0].DeletedAt)
+	s.Equal(deletedAt.Add(7*24*time.Hour), output.Characters[0].PurgeAt)
+}
+
+func (s *CharacterDeletionTestSuite) TestListDeletedCharacters_RequiresPlayer() {
+	output, err := s.orchestrator.ListDeletedCharacters(s.ctx, &character.ListDeletedCharactersInput{})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *CharacterDeletionTestSuite) TestHardDeleteCharacter() {
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: "char-1", IncludeDeleted: true}).
+		Return(&characterrepo.GetOutput{CharacterData: s.charData, DeletedAt: &deletedAt}, nil)
+	s.mockCharRepo.EXPECT().
+		Delete(s.ctx, characterrepo.DeleteInput{ID: "char-1"}).
+		Return(&characterrepo.DeleteOutput{}, nil)
+
+	output, err := s.orchestrator.HardDeleteCharacter(s.ctx, &character.HardDeleteCharacterInput{
+		CharacterID:  "char-1",
+		PlayerID:     "player-1",
+		Confirmation: " Brienne ",
+	})
+
+	s.Require().NoError(err)
+	s.Contains(output.Message, "permanently deleted")
+}
+
+func (s *CharacterDeletionTestSuite) TestHardDeleteCharacter_WrongConfirmation() {
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: "char-1", IncludeDeleted: true}).
+		Return(&characterrepo.GetOutput{CharacterData: s.charData}, nil)
+
+	output, err := s.orchestrator.HardDeleteCharacter(s.ctx, &character.HardDeleteCharacterInput{
+		CharacterID:  "char-1",
+		PlayerID:     "player-1",
+		Confirmation: "brienne",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *CharacterDeletionTestSuite) TestHardDeleteCharacter_NotOwner() {
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: "char-1", IncludeDeleted: true}).
+		Return(&characterrepo.GetOutput{CharacterData: s.charData, DeletedAt: &deletedAt}, nil)
+
+	output, err := s.orchestrator.HardDeleteCharacter(s.ctx, &character.HardDeleteCharacterInput{
+		CharacterID:  "char-1",
+		PlayerID:     "player-2",
+		Confirmation: "Brienne",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *CharacterDeletionTestSuite) TestHardDeleteCharacter_RequiresConfirmation() {
+	output, err := s.orchestrator.HardDeleteCharacter(s.ctx, &character.HardDeleteCharacterInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *CharacterDeletionTestSuite) TestPurgeDeletedCharacters() {
+	now := deletedAt.Add(10 * 24 * time.Hour)
+	s.mockClock.EXPECT().Now().Return(now)
+	s.mockCharRepo.EXPECT().
+		ListDeleted(s.ctx, characterrepo.ListDeletedInput{DeletedBefore: now.Add(-7 * 24 * time.Hour)}).
+		Return(&characterrepo.ListDeletedOutput{Characters: []*characterrepo.DeletedCharacter{
+			{CharacterData: s.charData, DeletedAt: deletedAt},
+			{CharacterData: &toolkitchar.Data{ID: "char-2"}, DeletedAt: deletedAt},
+		}}, nil)
+	s.mockCharRepo.EXPECT().
+		Delete(s.ctx, characterrepo.DeleteInput{ID: "char-1"}).
+		Return(&characterrepo.DeleteOutput{}, nil)
+	s.mockCharRepo.EXPECT().
+		Delete(s.ctx, characterrepo.DeleteInput{ID: "char-2"}).
+		Return(nil, errors.NotFound("character not found"))
+
+	output, err := s.orchestrator.PurgeDeletedCharacters(s.ctx, &character.PurgeDeletedCharactersInput{})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"char-1"}, output.PurgedCharacterIDs)
+}
+
+func TestCharacterDeletionTestSuite(t *testing.T) {
+	suite.Run(t, new(CharacterDeletionTestSuite))
+}
@@ -0,0 +1,139 @@
+package character
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/character"
+)
+
+// Length limits for character details, in characters
+const (
+	maxNameLength        = 64
+	maxShortDetailLength = 1000  // personality traits, ideals, bonds and flaws
+	maxLongDetailLength  = 10000 // appearance, backstory and notes
+)
+
+var validAlignments = []string{
+	dnd5e.AlignmentLawfulGood,
+	dnd5e.AlignmentNeutralGood,
+	dnd5e.AlignmentChaoticGood,
+	dnd5e.AlignmentLawfulNeutral,
+	dnd5e.AlignmentTrueNeutral,
+	dnd5e.AlignmentChaoticNeutral,
+	dnd5e.AlignmentLawfulEvil,
+	dnd5e.AlignmentNeutralEvil,
+	dnd5e.AlignmentChaoticEvil,
+	dnd5e.AlignmentUnaligned,
+}
+
+// UpdateCharacterDetails edits the name and roleplaying details of a finalized character
+func (o *Orchestrator) UpdateCharacterDetails(
+	ctx context.Context,
+	input *UpdateCharacterDetailsInput,
+) (*UpdateCharacterDetailsOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if err := validateCharacterDetails(input); err != nil {
+		return nil, err
+	}
+
+	getOutput, err := o.getCharacterWithSheet(ctx, input.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+	charData := getOutput.CharacterData
+	sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+	if sheet.Details == nil {
+		sheet.Details = &dnd5e.CharacterDetails{}
+	}
+	details := sheet.Details
+
+	if input.Name != nil {
+		charData.Name = strings.TrimSpace(*input.Name)
+	}
+	setDetail(&details.Alignment, input.Alignment)
+	setDetail(&details.Appearance, input.Appearance)
+	setDetail(&details.Backstory, input.Backstory)
+	setDetail(&details.PersonalityTraits, input.PersonalityTraits)
+	setDetail(&details.Ideals, input.Ideals)
+	setDetail(&details.Bonds, input.Bonds)
+	setDetail(&details.Flaws, input.Flaws)
+	setDetail(&details.Notes, input.Notes)
+
+	updateOutput, err := o.charRepo.Update(ctx, character.UpdateInput{
+		CharacterData: charData,
+		Sheet:         sheet,
+		EventType:     dnd5e.CharacterEventDetailsUpdated,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update character %s details", input.CharacterID)
+	}
+
+	slog.InfoContext(ctx, "updated character details", "character_id", input.CharacterID)
+
+	return &UpdateCharacterDetailsOutput{
+		Character: updateOutput.CharacterData,
+		Details:   details,
+	}, nil
+}
+
+// validateCharacterDetails checks every provided field so all problems are reported together
+func validateCharacterDetails(input *UpdateCharacterDetailsInput) error {
+	vb := errors.NewValidationBuilder()
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		errors.ValidateRequired("name", name, vb)
+		validateDetailLength("name", name, maxNameLength, vb)
+	}
+	if input.Alignment != nil && *input.Alignment != "" {
+		errors.ValidateEnum("alignment", *input.Alignment, validAlignments, vb)
+	}
+
+	longFields := map[string]*string{
+		"appearance": input.Appearance,
+		"backstory":  input.Backstory,
+		"notes":      input.Notes,
+	}
+	for field, value := range longFields {
+		if value != nil {
+			validateDetailLength(field, *value, maxLongDetailLength, vb)
+		}
+	}
+
+	shortFields := map[string]*string{
+		"personality_traits": input.PersonalityTraits,
+		"ideals":             input.Ideals,
+		"bonds":              input.Bonds,
+		"flaws":              input.Flaws,
+	}
+	for field, value := range shortFields {
+		if value != nil {
+			validateDetailLength(field, *value, maxShortDetailLength, vb)
+		}
+	}
+
+	return vb.Build()
+}
+
+// validateDetailLength limits characters rather than bytes so non-ASCII text gets the same allowance
+func validateDetailLength(field, value string, maxLength int, vb *errors.ValidationBuilder) {
+	if utf8.RuneCountInString(value) > maxLength {
+		vb.Fieldf(field, "must be no more than %d characters", maxLength)
+	}
+}
+
+func setDetail(field *string, value *string) {
+	if value != nil {
+		*field = strings.TrimSpace(*value)
+	}
+}
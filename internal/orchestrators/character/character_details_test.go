@@ -0,0 +1,111 @@
+package character_test
+
+import (
+	"strings"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+)
+
+func ptr(value string) *string {
+	return &value
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestUpdateCharacterDetails() {
+	s.charData.Name = "Old Name"
+	s.expectGet(&dnd5e.CharacterSheet{
+		Wallet:  &dnd5e.Wallet{Gold: 5},
+		Details: &dnd5e.CharacterDetails{Backstory: "Raised by wolves", Notes: "Owes Grem 5 gp"},
+	})
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Equal("Thorin Oakenshield", input.CharacterData.Name)
+		s.Equal(&dnd5e.Wallet{Gold: 5}, input.Sheet.Wallet)
+		s.Equal(&dnd5e.CharacterDetails{
+			Alignment: dnd5e.AlignmentLawfulGood,
+			Backstory: "Raised by wolves",
+			Ideals:    "Honor above all",
+		}, input.Sheet.Details)
+	})
+
+	output, err := s.orchestrator.UpdateCharacterDetails(s.ctx, &character.UpdateCharacterDetailsInput{
+		CharacterID: "char-1",
+		Name:        ptr("  Thorin Oakenshield "),
+		Alignment:   ptr(dnd5e.AlignmentLawfulGood),
+		Ideals:      ptr("Honor above all"),
+		Notes:       ptr(""),
+	})
+
+	s.Require().NoError(err)
+	s.Equal("Raised by wolves", output.Details.Backstory)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestUpdateCharacterDetails_Validation() {
+	testCases := []struct {
+		name  string
+		input *character.UpdateCharacterDetailsInput
+		field string
+	}{
+		{
+			name:  "blank name",
+			input: &character.UpdateCharacterDetailsInput{CharacterID: "char-1", Name: ptr("   ")},
+			field: "name",
+		},
+		{
+			name:  "unknown alignment",
+			input: &character.UpdateCharacterDetailsInput{CharacterID: "char-1", Alignment: ptr("chaotic-stupid")},
+			field: "alignment",
+		},
+		{
+			name: "backstory too long",
+			input: &character.UpdateCharacterDetailsInput{
+				CharacterID: "char-1",
+				Backstory:   ptr(strings.Repeat("a", 10001)),
+			},
+			field: "backstory",
+		},
+		{
+			name: "flaws too long",
+			input: &character.UpdateCharacterDetailsInput{
+				CharacterID: "char-1",
+				Flaws:       ptr(strings.Repeat("é", 1001)),
+			},
+			field: "flaws",
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			output, err := s.orchestrator.UpdateCharacterDetails(s.ctx, tc.input)
+
+			s.Nil(output)
+			s.True(errors.IsInvalidArgument(err))
+			s.Contains(err.Error(), tc.field)
+		})
+	}
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestUpdateCharacterDetails_NotFound() {
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: "missing"}).
+		Return(nil, errors.NotFound("character not found"))
+
+	output, err := s.orchestrator.UpdateCharacterDetails(s.ctx, &character.UpdateCharacterDetailsInput{
+		CharacterID: "missing",
+		Notes:       ptr("hello"),
+	})
+
+	s.Nil(output)
+	s.True(errors.IsNotFound(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacter_IncludesDetails() {
+	details := &dnd5e.CharacterDetails{Alignment: dnd5e.AlignmentChaoticGood}
+	s.expectGet(&dnd5e.CharacterSheet{Details: details})
+
+	output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "char-1", ViewerID: "player-1"})
+
+	s.Require().NoError(err)
+	s.Equal(details, output.Details)
+}
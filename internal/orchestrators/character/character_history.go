@@ -0,0 +1,180 @@
+package character
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+)
+
+const (
+	characterFieldPrefix = "character."
+	sheetFieldPrefix     = "sheet."
+)
+
+// GetCharacterTimeline returns the recorded changes to a character, oldest first, to players who
+// may view it. The API has no RPC for history yet; a handler for it must pass actor.ID as ViewerID.
+func (o *Orchestrator) GetCharacterTimeline(
+	ctx context.Context,
+	input *GetCharacterTimelineInput,
+) (*GetCharacterTimelineOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if !input.Since.IsZero() && !input.Until.IsZero() && input.Until.Before(input.Since) {
+		return nil, errors.InvalidArgument("until must not be before since")
+	}
+	if err := o.checkViewer(ctx, input.CharacterID, input.ViewerID, input.SessionID); err != nil {
+		return nil, err
+	}
+
+	listOutput, err := o.charRepo.ListEvents(ctx, character.ListEventsInput{CharacterID: input.CharacterID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list events for character %s", input.CharacterID)
+	}
+
+	events := make([]*dnd5e.CharacterEvent, 0, len(listOutput.Events))
+	for _, event := range listOutput.Events {
+		if !input.Since.IsZero() && event.Timestamp.Before(input.Since) {
+			continue
+		}
+		if !input.Until.IsZero() && event.Timestamp.After(input.Until) {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return &GetCharacterTimelineOutput{Events: events}, nil
+}
+
+// GetCharacterAtTime rebuilds a character and its sheet by replaying its history up to a point in time,
+// for players who may view it. Returns NotFound for a time before the character was created, or
+// before the snapshot that replaced its early history once the history was compacted.
+func (o *Orchestrator) GetCharacterAtTime(
+	ctx context.Context,
+	input *GetCharacterAtTimeInput,
+) (*GetCharacterAtTimeOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.At.IsZero() {
+		return nil, errors.InvalidArgument("time is required")
+	}
+	if err := o.checkViewer(ctx, input.CharacterID, input.ViewerID, input.SessionID); err != nil {
+		return nil, err
+	}
+
+	listOutput, err := o.charRepo.ListEvents(ctx, character.ListEventsInput{CharacterID: input.CharacterID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list events for character %s", input.CharacterID)
+	}
+
+	var applied []*dnd5e.CharacterEvent
+	for _, event := range listOutput.Events {
+		if event.Timestamp.After(input.At) {
+			break
+		}
+		applied = append(applied, event)
+	}
+	if len(applied) == 0 {
+		if len(listOutput.Events) > 0 && listOutput.Events[0].Type == dnd5e.CharacterEventHistoryCompacted {
+			return nil, errors.NotFoundf("history of character %s before %s was compacted",
+				input.CharacterID, listOutput.Events[0].Timestamp.Format(time.RFC3339))
+		}
+		return nil, errors.NotFoundf("character %s has no history at %s",
+			input.CharacterID, input.At.Format(time.RFC3339))
+	}
+
+	charData, sheet, err := replayCharacterEvents(applied)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetCharacterAtTimeOutput{
+		Character: charData,
+		Sheet:     sheet,
+		LastEvent: applied[len(applied)-1],
+	}, nil
+}
+
+// checkViewer applies GetCharacter's view rules to a character's history
+func (o *Orchestrator) checkViewer(ctx context.Context, characterID, viewerID, sessionID string) error {
+	getOutput, err := o.getCharacterWithSheet(ctx, characterID)
+	if err != nil {
+		return err
+	}
+	allowed, err := o.canViewCharacter(ctx, &viewer{playerID: viewerID, sessionID: sessionID},
+		getOutput.CharacterData, getOutput.Sheet)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errors.PermissionDeniedf("character %s is not shared with player %q", characterID, viewerID)
+	}
+	return nil
+}
+
+// replayCharacterEvents applies each event's after values in order, starting from nothing.
+// The first event must be the character's creation, or the snapshot that replaced its early
+// history, for the result to be complete.
+func replayCharacterEvents(events []*dnd5e.CharacterEvent) (*toolkitchar.Data, *dnd5e.CharacterSheet, error) {
+	charFields := make(map[string]json.RawMessage)
+	sheetFields := make(map[string]json.RawMessage)
+
+	for _, event := range events {
+		for _, change := range event.Changes {
+			fields := charFields
+			name, ok := strings.CutPrefix(change.Field, characterFieldPrefix)
+			if !ok {
+				fields = sheetFields
+				name, ok = strings.CutPrefix(change.Field, sheetFieldPrefix)
+			}
+			if !ok {
+				return nil, nil, errors.Internalf("unknown field %s in character event %d", change.Field, event.Sequence)
+			}
+
+			if len(change.After) == 0 {
+				delete(fields, name)
+				continue
+			}
+			fields[name] = change.After
+		}
+	}
+
+	var charData toolkitchar.Data
+	if err := unmarshalFields(charFields, &charData); err != nil {
+		return nil, nil, err
+	}
+
+	var sheet *dnd5e.CharacterSheet
+	if len(sheetFields) > 0 {
+		sheet = &dnd5e.CharacterSheet{}
+		if err := unmarshalFields(sheetFields, sheet); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return &charData, sheet, nil
+}
+
+func unmarshalFields(fields map[string]json.RawMessage, target any) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return errors.Wrap(err, "failed to rebuild character from history")
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return errors.Wrap(err, "failed to rebuild character from history")
+	}
+	return nil
+}
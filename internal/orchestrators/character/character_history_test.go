@@ -0,0 +1,188 @@
+package character_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+)
+
+var historyStart = time.Date(2025, 3, 1, 19, 0, 0, 0, time.UTC)
+
+// expectHistory mocks a character created with 12 HP and 15 gp, hit for 7 damage an hour later,
+// then spending all their gold another hour after that
+func (s *EquipmentOrchestratorTestSuite) expectHistory() {
+	raw := func(value string) json.RawMessage { return json.RawMessage(value) }
+	s.mockCharRepo.EXPECT().
+		ListEvents(s.ctx, characterrepo.ListEventsInput{CharacterID: "char-1"}).
+		Return(&characterrepo.ListEventsOutput{Events: []*dnd5e.CharacterEvent{
+			{
+				CharacterID: "char-1",
+				Sequence:    1,
+				Type:        dnd5e.CharacterEventCreated,
+				ActorID:     "player-1",
+				Timestamp:   historyStart,
+				Changes: []dnd5e.CharacterFieldChange{
+					{Field: "character.id", After: raw(`"char-1"`)},
+					{Field: "character.name", After: raw(`"Brienne"`)},
+					{Field: "character.hit_points", After: raw(`12`)},
+					{Field: "character.max_hit_points", After: raw(`12`)},
+					{Field: "sheet.wallet", After: raw(`{"copper":0,"silver":0,"electrum":0,"gold":15,"platinum":0}`)},
+				},
+			},
+			{
+				CharacterID: "char-1",
+				Sequence:    2,
+				Type:        dnd5e.CharacterEventUpdated,
+				ActorID:     "dm-1",
+				Timestamp:   historyStart.Add(time.Hour),
+				Changes: []dnd5e.CharacterFieldChange{
+					{Field: "character.hit_points", Before: raw(`12`), After: raw(`5`)},
+				},
+			},
+			{
+				CharacterID: "char-1",
+				Sequence:    3,
+				Type:        dnd5e.CharacterEventItemBought,
+				ActorID:     "player-1",
+				Timestamp:   historyStart.Add(2 * time.Hour),
+				Changes: []dnd5e.CharacterFieldChange{
+					{Field: "character.equipment", After: raw(`["EQUIPMENT_LONGSWORD"]`)},
+					{Field: "sheet.wallet", Before: raw(`{"copper":0,"silver":0,"electrum":0,"gold":15,"platinum":0}`)},
+				},
+			},
+		}}, nil)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacterTimeline_FiltersByTime() {
+	s.expectGet(nil)
+	s.expectHistory()
+
+	output, err := s.orchestrator.GetCharacterTimeline(s.ctx, &character.GetCharacterTimelineInput{
+		CharacterID: "char-1",
+		ViewerID:    "player-1",
+		Since:       historyStart.Add(30 * time.Minute),
+		Until:       historyStart.Add(time.Hour),
+	})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.Events, 1)
+	s.Equal(int64(2), output.Events[0].Sequence)
+	s.Equal("dm-1", output.Events[0].ActorID)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacterTimeline_InvalidRange() {
+	output, err := s.orchestrator.GetCharacterTimeline(s.ctx, &character.GetCharacterTimelineInput{
+		CharacterID: "char-1",
+		ViewerID:    "player-1",
+		Since:       historyStart,
+		Until:       historyStart.Add(-time.Minute),
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacterTimeline_NotShared() {
+	s.expectGet(&dnd5e.CharacterSheet{SharedWithPlayers: []string{"player-3"}})
+
+	output, err := s.orchestrator.GetCharacterTimeline(s.ctx, &character.GetCharacterTimelineInput{
+		CharacterID: "char-1",
+		ViewerID:    "player-2",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacterAtTime_NotShared() {
+	s.expectGet(nil)
+
+	output, err := s.orchestrator.GetCharacterAtTime(s.ctx, &character.GetCharacterAtTimeInput{
+		CharacterID: "char-1",
+		ViewerID:    "player-2",
+		At:          historyStart.Add(90 * time.Minute),
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacterAtTime_ReplaysHistory() {
+	s.expectGet(nil)
+	s.expectHistory()
+
+	output, err := s.orchestrator.GetCharacterAtTime(s.ctx, &character.GetCharacterAtTimeInput{
+		CharacterID: "char-1",
+		ViewerID:    "player-1",
+		At:          historyStart.Add(90 * time.Minute),
+	})
+
+	s.Require().NoError(err)
+	s.Equal("Brienne", output.Character.Name)
+	s.Equal(5, output.Character.HitPoints)
+	s.Equal(12, output.Character.MaxHitPoints)
+	s.Empty(output.Character.Equipment)
+	s.Require().NotNil(output.Sheet)
+	s.Equal(int32(15), output.Sheet.Wallet.Gold)
+	s.Equal(int64(2), output.LastEvent.Sequence)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacterAtTime_RemovedFields() {
+	s.expectGet(nil)
+	s.expectHistory()
+
+	output, err := s.orchestrator.GetCharacterAtTime(s.ctx, &character.GetCharacterAtTimeInput{
+		CharacterID: "char-1",
+		ViewerID:    "player-1",
+		At:          historyStart.Add(2 * time.Hour),
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"EQUIPMENT_LONGSWORD"}, output.Character.Equipment)
+	s.Nil(output.Sheet)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacterAtTime_BeforeCreation() {
+	s.expectGet(nil)
+	s.expectHistory()
+
+	output, err := s.orchestrator.GetCharacterAtTime(s.ctx, &character.GetCharacterAtTimeInput{
+		CharacterID: "char-1",
+		ViewerID:    "player-1",
+		At:          historyStart.Add(-time.Minute),
+	})
+
+	s.Nil(output)
+	s.True(errors.IsNotFound(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacterAtTime_BeforeCompactedHistory() {
+	s.expectGet(nil)
+	s.mockCharRepo.EXPECT().
+		ListEvents(s.ctx, characterrepo.ListEventsInput{CharacterID: "char-1"}).
+		Return(&characterrepo.ListEventsOutput{Events: []*dnd5e.CharacterEvent{
+			{
+				CharacterID: "char-1",
+				Sequence:    500,
+				Type:        dnd5e.CharacterEventHistoryCompacted,
+				Timestamp:   historyStart,
+				Changes: []dnd5e.CharacterFieldChange{
+					{Field: "character.id", After: json.RawMessage(`"char-1"`)},
+				},
+			},
+		}}, nil)
+
+	output, err := s.orchestrator.GetCharacterAtTime(s.ctx, &character.GetCharacterAtTimeInput{
+		CharacterID: "char-1",
+		ViewerID:    "player-1",
+		At:          historyStart.Add(-time.Minute),
+	})
+
+	s.Nil(output)
+	s.True(errors.IsNotFound(err))
+	s.Contains(err.Error(), "compacted")
+}
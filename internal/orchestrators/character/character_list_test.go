@@ -0,0 +1,111 @@
+package character_test
+
+import (
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+)
+
+func (s *EquipmentOrchestratorTestSuite) TestListCharacters_FiltersAndPages() {
+	characters := []*toolkitchar.Data{
+		{ID: "char-1", PlayerID: "player-1", ClassID: constants.ClassFighter, Level: 3},
+		{ID: "char-2", PlayerID: "player-1", ClassID: constants.ClassFighter, Level: 4},
+	}
+	s.mockCharRepo.EXPECT().
+		List(s.ctx, characterrepo.ListInput{
+			PlayerID:   "player-1",
+			ClassID:    constants.ClassFighter,
+			MinLevel:   3,
+			MaxLevel:   5,
+			SortBy:     characterrepo.SortByLevel,
+			Descending: true,
+			PageSize:   2,
+			PageToken:  "page-1",
+			ViewerID:   "player-1",
+		}).
+		Return(&characterrepo.ListOutput{Characters: characters, NextPageToken: "page-2", TotalSize: 5}, nil)
+
+	output, err := s.orchestrator.ListCharacters(s.ctx, &character.ListCharactersInput{
+		PlayerID:       "player-1",
+		ViewerID:       "player-1",
+		ClassID:        constants.ClassFighter,
+		MinLevel:       3,
+		MaxLevel:       5,
+		SortBy:         characterrepo.SortByLevel,
+		SortDescending: true,
+		PageSize:       2,
+		PageToken:      "page-1",
+	})
+
+	s.Require().NoError(err)
+	s.Equal(characters, output.Characters)
+	s.Equal("page-2", output.NextPageToken)
+	s.Equal(int32(5), output.TotalSize)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestListCharacters_FollowsPageTokens() {
+	first := []*toolkitchar.Data{{ID: "char-1", PlayerID: "player-1"}, {ID: "char-2", PlayerID: "player-1"}}
+	second := []*toolkitchar.Data{{ID: "char-3", PlayerID: "player-1"}}
+	s.mockCharRepo.EXPECT().
+		List(s.ctx, characterrepo.ListInput{PlayerID: "player-1", SortBy: characterrepo.SortByName, PageSize: 2, ViewerID: "player-1"}).
+		Return(&characterrepo.ListOutput{Characters: first, NextPageToken: "after-char-2", TotalSize: 3}, nil)
+	s.mockCharRepo.EXPECT().
+		List(s.ctx, characterrepo.ListInput{
+			PlayerID:  "player-1",
+			SortBy:    characterrepo.SortByName,
+			PageSize:  2,
+			PageToken: "after-char-2",
+			ViewerID:  "player-1",
+		}).
+		Return(&characterrepo.ListOutput{Characters: second, TotalSize: 3}, nil)
+
+	input := &character.ListCharactersInput{
+		PlayerID: "player-1",
+		ViewerID: "player-1",
+		SortBy:   characterrepo.SortByName,
+		PageSize: 2,
+	}
+	output, err := s.orchestrator.ListCharacters(s.ctx, input)
+	s.Require().NoError(err)
+	s.Equal(first, output.Characters)
+
+	input.PageToken = output.NextPageToken
+	output, err = s.orchestrator.ListCharacters(s.ctx, input)
+	s.Require().NoError(err)
+	s.Equal(second, output.Characters)
+	s.Empty(output.NextPageToken)
+	s.Equal(int32(3), output.TotalSize)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestListCharacters_AnotherPlayersCharacters() {
+	shared := []*toolkitchar.Data{{ID: "char-9", PlayerID: "player-2"}}
+	s.mockCharRepo.EXPECT().
+		List(s.ctx, characterrepo.ListInput{PlayerID: "player-2", ViewerID: "player-1"}).
+		Return(&characterrepo.ListOutput{Characters: shared, TotalSize: 1}, nil)
+
+	output, err := s.orchestrator.ListCharacters(s.ctx, &character.ListCharactersInput{
+		PlayerID: "player-2",
+		ViewerID: "player-1",
+	})
+
+	s.Require().NoError(err)
+	s.Equal(shared, output.Characters)
+	s.Equal(int32(1), output.TotalSize)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestListCharacters_InvalidPageToken() {
+	s.mockCharRepo.EXPECT().
+		List(s.ctx, characterrepo.ListInput{PlayerID: "player-1", PageToken: "garbage", ViewerID: "player-1"}).
+		Return(nil, errors.InvalidArgument("invalid page token"))
+
+	output, err := s.orchestrator.ListCharacters(s.ctx, &character.ListCharactersInput{
+		PlayerID:  "player-1",
+		ViewerID:  "player-1",
+		PageToken: "garbage",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
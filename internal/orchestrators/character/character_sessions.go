@@ -0,0 +1,166 @@
+package character
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	campaignrepo "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+)
+
+// JoinSession adds a character to a running game session in one of the player's campaigns.
+// When the orchestrator is configured for a single active session, the character must leave
+// its current session first.
+func (o *Orchestrator) JoinSession(ctx context.Context, input *JoinSessionInput) (*JoinSessionOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if err := validateSessionMembership(input.CharacterID, input.PlayerID, input.SessionID); err != nil {
+		return nil, err
+	}
+
+	getOutput, err := o.getOwnedCharacter(ctx, input.CharacterID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+
+	if slices.Contains(sheet.SessionIDs, input.SessionID) {
+		return &JoinSessionOutput{SessionIDs: sheet.SessionIDs}, nil
+	}
+
+	session, err := o.memberSession(ctx, input.PlayerID, input.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, errors.NotFoundf("session %s not found in player %s's campaigns", input.SessionID, input.PlayerID)
+	}
+	if session.EndedAt != nil {
+		return nil, errors.FailedPreconditionf("session %s has ended", input.SessionID)
+	}
+	if o.singleSession && len(sheet.SessionIDs) > 0 {
+		return nil, errors.FailedPreconditionf("character %s is already in session %s",
+			input.CharacterID, sheet.SessionIDs[0])
+	}
+
+	sheet.SessionIDs = append(sheet.SessionIDs, input.SessionID)
+	_, err = o.charRepo.Update(ctx, character.UpdateInput{
+		CharacterData: getOutput.CharacterData,
+		Sheet:         sheet,
+		EventType:     dnd5e.CharacterEventJoinedSession,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to add character %s to session %s", input.CharacterID, input.SessionID)
+	}
+
+	slog.InfoContext(ctx, "character joined session",
+		"character_id", input.CharacterID,
+		"session_id", input.SessionID)
+
+	return &JoinSessionOutput{SessionIDs: sheet.SessionIDs}, nil
+}
+
+// LeaveSession removes a character from a game session
+func (o *Orchestrator) LeaveSession(ctx context.Context, input *LeaveSessionInput) (*LeaveSessionOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if err := validateSessionMembership(input.CharacterID, input.PlayerID, input.SessionID); err != nil {
+		return nil, err
+	}
+
+	getOutput, err := o.getOwnedCharacter(ctx, input.CharacterID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+
+	if !slices.Contains(sheet.SessionIDs, input.SessionID) {
+		return nil, errors.NotFoundf("character %s is not in session %s", input.CharacterID, input.SessionID)
+	}
+
+	sheet.SessionIDs = removeAll(sheet.SessionIDs, []string{input.SessionID})
+	_, err = o.charRepo.Update(ctx, character.UpdateInput{
+		CharacterData: getOutput.CharacterData,
+		Sheet:         sheet,
+		EventType:     dnd5e.CharacterEventLeftSession,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to remove character %s from session %s", input.CharacterID, input.SessionID)
+	}
+
+	slog.InfoContext(ctx, "character left session",
+		"character_id", input.CharacterID,
+		"session_id", input.SessionID)
+
+	return &LeaveSessionOutput{SessionIDs: sheet.SessionIDs}, nil
+}
+
+// ListSessionCharacters loads every character in a session so a table can load the whole party.
+// The API has no RPC for it yet, so it is only reachable from other orchestrators.
+func (o *Orchestrator) ListSessionCharacters(
+	ctx context.Context,
+	input *ListSessionCharactersInput,
+) (*ListSessionCharactersOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.SessionID == "" {
+		return nil, errors.InvalidArgument("session ID is required")
+	}
+
+	listOutput, err := o.charRepo.ListBySessionID(ctx, character.ListBySessionIDInput{SessionID: input.SessionID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list characters in session %s", input.SessionID)
+	}
+
+	characters, err := o.filterViewableCharacters(ctx, listOutput.Characters, &viewer{
+		playerID:  input.ViewerID,
+		sessionID: input.SessionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The index is a set, so order the party for a stable display
+	slices.SortFunc(characters, func(a, b *toolkitchar.Data) int {
+		return cmp.Or(
+			strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)),
+			strings.Compare(a.ID, b.ID),
+		)
+	})
+
+	return &ListSessionCharactersOutput{Characters: characters}, nil
+}
+
+func validateSessionMembership(characterID, playerID, sessionID string) error {
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("character_id", characterID, vb)
+	errors.ValidateRequired("player_id", playerID, vb)
+	errors.ValidateRequired("session_id", sessionID, vb)
+	return vb.Build()
+}
+
+// memberSession finds a session in one of the player's campaigns, returning nil when the
+// player belongs to no campaign with that session
+func (o *Orchestrator) memberSession(ctx context.Context, playerID, sessionID string) (*campaignrepo.Session, error) {
+	listOutput, err := o.campaignRepo.ListByMember(ctx, campaignrepo.ListByMemberInput{PlayerID: playerID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list campaigns for player %s", playerID)
+	}
+	for _, campaign := range listOutput.Campaigns {
+		for _, session := range campaign.Sessions {
+			if session.ID == sessionID {
+				return session, nil
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,178 @@
+package character_test
+
+import (
+	"time"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	dicemock "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice/mock"
+	campaignrepo "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	draftmock "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft/mock"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+)
+
+// expectSession lists player-1's campaigns, which hold the session
+func (s *EquipmentOrchestratorTestSuite) expectSession(session *campaignrepo.Session) {
+	s.mockCampaignRepo.EXPECT().
+		ListByMember(s.ctx, campaignrepo.ListByMemberInput{PlayerID: "player-1"}).
+		Return(&campaignrepo.ListByMemberOutput{Campaigns: []*campaignrepo.Campaign{
+			{ID: "campaign-1", Sessions: []*campaignrepo.Session{session}},
+		}}, nil)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestJoinSession() {
+	s.charData.PlayerID = "player-1"
+	s.expectGet(&dnd5e.CharacterSheet{SessionIDs: []string{"session-1"}})
+	s.expectSession(&campaignrepo.Session{ID: "session-2"})
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Equal(dnd5e.CharacterEventJoinedSession, input.EventType)
+		s.Equal([]string{"session-1", "session-2"}, input.Sheet.SessionIDs)
+	})
+
+	output, err := s.orchestrator.JoinSession(s.ctx, &character.JoinSessionInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		SessionID:   "session-2",
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"session-1", "session-2"}, output.SessionIDs)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestJoinSession_AlreadyJoined() {
+	s.charData.PlayerID = "player-1"
+	s.expectGet(&dnd5e.CharacterSheet{SessionIDs: []string{"session-1"}})
+
+	output, err := s.orchestrator.JoinSession(s.ctx, &character.JoinSessionInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		SessionID:   "session-1",
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"session-1"}, output.SessionIDs)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestJoinSession_SingleActiveSession() {
+	orch, err := character.New(&character.Config{
+		CharacterRepo:       s.mockCharRepo,
+		CharacterDraftRepo:  draftmock.NewMockRepository(s.ctrl),
+		CampaignRepo:        s.mockCampaignRepo,
+		ExternalClient:      s.mockExtClient,
+		DiceService:         dicemock.NewMockService(s.ctrl),
+		IDGenerator:         &mockIDGenerator{},
+		DraftIDGenerator:    &mockIDGenerator{},
+		SingleActiveSession: true,
+	})
+	s.Require().NoError(err)
+	s.charData.PlayerID = "player-1"
+	s.expectGet(&dnd5e.CharacterSheet{SessionIDs: []string{"session-1"}})
+	s.expectSession(&campaignrepo.Session{ID: "session-2"})
+
+	output, err := orch.JoinSession(s.ctx, &character.JoinSessionInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		SessionID:   "session-2",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestJoinSession_UnknownSession() {
+	s.charData.PlayerID = "player-1"
+	s.expectGet(nil)
+	s.expectSession(&campaignrepo.Session{ID: "session-1"})
+
+	output, err := s.orchestrator.JoinSession(s.ctx, &character.JoinSessionInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		SessionID:   "session-2",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsNotFound(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestJoinSession_SessionEnded() {
+	endedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.charData.PlayerID = "player-1"
+	s.expectGet(nil)
+	s.expectSession(&campaignrepo.Session{ID: "session-1", EndedAt: &endedAt})
+
+	output, err := s.orchestrator.JoinSession(s.ctx, &character.JoinSessionInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		SessionID:   "session-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestJoinSession_NotOwner() {
+	s.charData.PlayerID = "player-1"
+	s.expectGet(nil)
+
+	output, err := s.orchestrator.JoinSession(s.ctx, &character.JoinSessionInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-2",
+		SessionID:   "session-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestLeaveSession() {
+	s.charData.PlayerID = "player-1"
+	s.expectGet(&dnd5e.CharacterSheet{SessionIDs: []string{"session-1", "session-2"}})
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Equal(dnd5e.CharacterEventLeftSession, input.EventType)
+		s.Equal([]string{"session-2"}, input.Sheet.SessionIDs)
+	})
+
+	output, err := s.orchestrator.LeaveSession(s.ctx, &character.LeaveSessionInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		SessionID:   "session-1",
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"session-2"}, output.SessionIDs)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestLeaveSession_NotInSession() {
+	s.charData.PlayerID = "player-1"
+	s.expectGet(nil)
+
+	output, err := s.orchestrator.LeaveSession(s.ctx, &character.LeaveSessionInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		SessionID:   "session-1",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsNotFound(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestListSessionCharacters() {
+	s.mockCharRepo.EXPECT().
+		ListBySessionID(s.ctx, characterrepo.ListBySessionIDInput{SessionID: "session-1"}).
+		Return(&characterrepo.ListBySessionIDOutput{Characters: []*toolkitchar.Data{
+			{ID: "char-2", Name: "thorin", PlayerID: "player-1"},
+			{ID: "char-1", Name: "Brienne", PlayerID: "player-1"},
+		}}, nil)
+
+	output, err := s.orchestrator.ListSessionCharacters(s.ctx, &character.ListSessionCharactersInput{
+		SessionID: "session-1",
+		ViewerID:  "player-1",
+	})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.Characters, 2)
+	s.Equal("char-1", output.Characters[0].ID)
+	s.Equal("char-2", output.Characters[1].ID)
+}
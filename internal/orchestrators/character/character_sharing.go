@@ -0,0 +1,334 @@
+package character
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+)
+
+// ShareCharacter gives players or whole sessions read-only access to a character
+func (o *Orchestrator) ShareCharacter(ctx context.Context, input *ShareCharacterInput) (*ShareCharacterOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if err := validateSharingTargets(input.CharacterID, input.PlayerID, input.PlayerIDs, input.SessionIDs); err != nil {
+		return nil, err
+	}
+
+	getOutput, err := o.getOwnedCharacter(ctx, input.CharacterID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(input.PlayerIDs, input.PlayerID) {
+		return nil, errors.InvalidArgument("cannot share a character with its owner")
+	}
+
+	sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+	sheet.SharedWithPlayers = addUnique(sheet.SharedWithPlayers, input.PlayerIDs)
+	sheet.SharedWithSessions = addUnique(sheet.SharedWithSessions, input.SessionIDs)
+
+	err = o.updateSharing(ctx, getOutput.CharacterData, sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShareCharacterOutput{
+		SharedWithPlayers:  sheet.SharedWithPlayers,
+		SharedWithSessions: sheet.SharedWithSessions,
+	}, nil
+}
+
+// UnshareCharacter revokes read-only access previously given to players or sessions
+func (o *Orchestrator) UnshareCharacter(
+	ctx context.Context,
+	input *UnshareCharacterInput,
+) (*UnshareCharacterOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if err := validateSharingTargets(input.CharacterID, input.PlayerID, input.PlayerIDs, input.SessionIDs); err != nil {
+		return nil, err
+	}
+
+	getOutput, err := o.getOwnedCharacter(ctx, input.CharacterID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+	sheet.SharedWithPlayers = removeAll(sheet.SharedWithPlayers, input.PlayerIDs)
+	sheet.SharedWithSessions = removeAll(sheet.SharedWithSessions, input.SessionIDs)
+
+	err = o.updateSharing(ctx, getOutput.CharacterData, sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnshareCharacterOutput{
+		SharedWithPlayers:  sheet.SharedWithPlayers,
+		SharedWithSessions: sheet.SharedWithSessions,
+	}, nil
+}
+
+// RequestCharacterTransfer offers a character to another player. Ownership only changes once
+// the recipient accepts; a new request replaces any pending one.
+func (o *Orchestrator) RequestCharacterTransfer(
+	ctx context.Context,
+	input *RequestCharacterTransferInput,
+) (*RequestCharacterTransferOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("character_id", input.CharacterID, vb)
+	errors.ValidateRequired("player_id", input.PlayerID, vb)
+	errors.ValidateRequired("to_player_id", input.ToPlayerID, vb)
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+	if input.ToPlayerID == input.PlayerID {
+		return nil, errors.InvalidArgument("cannot transfer a character to its owner")
+	}
+
+	getOutput, err := o.getOwnedCharacter(ctx, input.CharacterID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+	sheet.PendingTransfer = &dnd5e.CharacterTransfer{
+		FromPlayerID: input.PlayerID,
+		ToPlayerID:   input.ToPlayerID,
+		RequestedAt:  o.clock.Now(),
+	}
+
+	_, err = o.charRepo.Update(ctx, character.UpdateInput{
+		CharacterData: getOutput.CharacterData,
+		Sheet:         sheet,
+		EventType:     dnd5e.CharacterEventTransferRequested,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to request transfer of character %s", input.CharacterID)
+	}
+
+	slog.InfoContext(ctx, "requested character transfer",
+		"character_id", input.CharacterID,
+		"from_player_id", input.PlayerID,
+		"to_player_id", input.ToPlayerID)
+
+	return &RequestCharacterTransferOutput{Transfer: sheet.PendingTransfer}, nil
+}
+
+// RespondToCharacterTransfer lets the recipient accept or decline a pending transfer,
+// or the owner cancel it. Accepting moves the character to the recipient.
+func (o *Orchestrator) RespondToCharacterTransfer(
+	ctx context.Context,
+	input *RespondToCharacterTransferInput,
+) (*RespondToCharacterTransferOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument("player ID is required")
+	}
+
+	eventType := dnd5e.CharacterEventTransferCancelled
+	if input.Accept {
+		eventType = dnd5e.CharacterEventTransferred
+	}
+
+	// A concurrent change, such as another response, makes the transfer start over
+	// and check the offer again
+	updateOutput, err := o.updateAtRevision(ctx, input.CharacterID, eventType,
+		func(charData *toolkitchar.Data, sheet *dnd5e.CharacterSheet) error {
+			transfer := sheet.PendingTransfer
+			if transfer == nil {
+				return errors.FailedPreconditionf("character %s has no pending transfer", input.CharacterID)
+			}
+			// The character changed hands some other way since the offer was made
+			if transfer.FromPlayerID != charData.PlayerID {
+				return errors.FailedPreconditionf("transfer of character %s is no longer valid", input.CharacterID)
+			}
+
+			switch {
+			case input.PlayerID == transfer.ToPlayerID:
+			case input.PlayerID == charData.PlayerID && !input.Accept:
+			default:
+				return errors.PermissionDeniedf("player %s cannot respond to the transfer of character %s",
+					input.PlayerID, input.CharacterID)
+			}
+
+			sheet.PendingTransfer = nil
+			if input.Accept {
+				charData.PlayerID = transfer.ToPlayerID
+				// The new owner no longer needs a share
+				sheet.SharedWithPlayers = removeAll(sheet.SharedWithPlayers, []string{transfer.ToPlayerID})
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to respond to transfer of character %s", input.CharacterID)
+	}
+
+	slog.InfoContext(ctx, "responded to character transfer",
+		"character_id", input.CharacterID,
+		"player_id", input.PlayerID,
+		"accepted", input.Accept)
+
+	return &RespondToCharacterTransferOutput{
+		Character:   updateOutput.CharacterData,
+		Transferred: input.Accept,
+	}, nil
+}
+
+// viewer is a player reading characters, possibly from within a game session
+type viewer struct {
+	playerID  string
+	sessionID string
+
+	// inSession records whether the player belongs to the campaign the session is part
+	// of. It is loaded the first time a session share needs it.
+	inSession *bool
+}
+
+// canViewCharacter reports whether a viewer may read a character. The viewer must own it,
+// have it shared with them directly, or have it shared with a session they play in and
+// are viewing from and belong to. A request with no viewer is denied.
+func (o *Orchestrator) canViewCharacter(
+	ctx context.Context,
+	v *viewer,
+	charData *toolkitchar.Data,
+	sheet *dnd5e.CharacterSheet,
+) (bool, error) {
+	if v.playerID == "" {
+		return false, nil
+	}
+	if v.playerID == charData.PlayerID {
+		return true, nil
+	}
+	if sheet == nil {
+		return false, nil
+	}
+	if slices.Contains(sheet.SharedWithPlayers, v.playerID) {
+		return true, nil
+	}
+	if v.sessionID == "" || !slices.Contains(sheet.SharedWithSessions, v.sessionID) {
+		return false, nil
+	}
+
+	if v.inSession == nil {
+		session, err := o.memberSession(ctx, v.playerID, v.sessionID)
+		if err != nil {
+			return false, err
+		}
+		inSession := session != nil
+		v.inSession = &inSession
+	}
+	return *v.inSession, nil
+}
+
+// filterViewableCharacters drops characters the viewer may not read. Sheets are only loaded
+// for characters the viewer does not own.
+func (o *Orchestrator) filterViewableCharacters(
+	ctx context.Context,
+	characters []*toolkitchar.Data,
+	v *viewer,
+) ([]*toolkitchar.Data, error) {
+	if v.playerID == "" {
+		return []*toolkitchar.Data{}, nil
+	}
+
+	viewable := make([]*toolkitchar.Data, 0, len(characters))
+	for _, charData := range characters {
+		if charData.PlayerID != v.playerID {
+			getOutput, err := o.charRepo.Get(ctx, character.GetInput{ID: charData.ID})
+			if err != nil {
+				// Deleted since it was listed
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return nil, errors.Wrapf(err, "failed to get character %s", charData.ID)
+			}
+			allowed, err := o.canViewCharacter(ctx, v, getOutput.CharacterData, getOutput.Sheet)
+			if err != nil {
+				return nil, err
+			}
+			if !allowed {
+				continue
+			}
+		}
+		viewable = append(viewable, charData)
+	}
+	return viewable, nil
+}
+
+// getOwnedCharacter loads a character and its sheet, checking the player owns it
+func (o *Orchestrator) getOwnedCharacter(ctx context.Context, characterID, playerID string) (*character.GetOutput, error) {
+	getOutput, err := o.getCharacterWithSheet(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if getOutput.CharacterData.PlayerID != playerID {
+		return nil, errors.PermissionDeniedf("character %s is not owned by player %s", characterID, playerID)
+	}
+	return getOutput, nil
+}
+
+func (o *Orchestrator) updateSharing(ctx context.Context, charData *toolkitchar.Data, sheet *dnd5e.CharacterSheet) error {
+	_, err := o.charRepo.Update(ctx, character.UpdateInput{
+		CharacterData: charData,
+		Sheet:         sheet,
+		EventType:     dnd5e.CharacterEventSharingChanged,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update sharing for character %s", charData.ID)
+	}
+
+	slog.InfoContext(ctx, "updated character sharing",
+		"character_id", charData.ID,
+		"shared_with_players", sheet.SharedWithPlayers,
+		"shared_with_sessions", sheet.SharedWithSessions)
+	return nil
+}
+
+func validateSharingTargets(characterID, playerID string, playerIDs, sessionIDs []string) error {
+	if characterID == "" {
+		return errors.InvalidArgument("character ID is required")
+	}
+	if playerID == "" {
+		return errors.InvalidArgument("player ID is required")
+	}
+	if len(playerIDs) == 0 && len(sessionIDs) == 0 {
+		return errors.InvalidArgument("at least one player or session is required")
+	}
+	if slices.Contains(playerIDs, "") || slices.Contains(sessionIDs, "") {
+		return errors.InvalidArgument("player and session IDs cannot be empty")
+	}
+	return nil
+}
+
+// addUnique appends the values not already in the list
+func addUnique(list, values []string) []string {
+	for _, value := range values {
+		if !slices.Contains(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// removeAll returns the list without any of the values
+func removeAll(list, values []string) []string {
+	return slices.DeleteFunc(list, func(item string) bool {
+		return slices.Contains(values, item)
+	})
+}
@@ -0,0 +1,322 @@
+package character_test
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	campaignrepo "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+)
+
+func (s *EquipmentOrchestratorTestSuite) TestShareCharacter() {
+	s.charData.PlayerID = "player-1"
+	s.expectGet(&dnd5e.CharacterSheet{SharedWithPlayers: []string{"player-2"}})
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Equal(dnd5e.CharacterEventSharingChanged, input.EventType)
+		s.Equal([]string{"player-2", "player-3"}, input.Sheet.SharedWithPlayers)
+		s.Equal([]string{"session-1"}, input.Sheet.SharedWithSessions)
+	})
+
+	output, err := s.orchestrator.ShareCharacter(s.ctx, &character.ShareCharacterInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		PlayerIDs:   []string{"player-2", "player-3"},
+		SessionIDs:  []string{"session-1"},
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"player-2", "player-3"}, output.SharedWithPlayers)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestShareCharacter_NotOwner() {
+	s.charData.PlayerID = "player-1"
+	s.expectGet(nil)
+
+	output, err := s.orchestrator.ShareCharacter(s.ctx, &character.ShareCharacterInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-2",
+		PlayerIDs:   []string{"player-2"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestUnshareCharacter() {
+	s.charData.PlayerID = "player-1"
+	s.expectGet(&dnd5e.CharacterSheet{
+		SharedWithPlayers:  []string{"player-2", "player-3"},
+		SharedWithSessions: []string{"session-1"},
+	})
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Equal([]string{"player-3"}, input.Sheet.SharedWithPlayers)
+		s.Empty(input.Sheet.SharedWithSessions)
+	})
+
+	output, err := s.orchestrator.UnshareCharacter(s.ctx, &character.UnshareCharacterInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		PlayerIDs:   []string{"player-2"},
+		SessionIDs:  []string{"session-1"},
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"player-3"}, output.SharedWithPlayers)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacter_Sharing() {
+	sheet := &dnd5e.CharacterSheet{
+		SharedWithPlayers:  []string{"player-2"},
+		SharedWithSessions: []string{"session-1"},
+	}
+	testCases := []struct {
+		name      string
+		viewerID  string
+		sessionID string
+		member    bool // Whether the viewer's campaigns are looked up and include the session
+		allowed   bool
+	}{
+		{name: "owner", viewerID: "player-1", allowed: true},
+		{name: "shared player", viewerID: "player-2", allowed: true},
+		{name: "shared session", viewerID: "dm-1", sessionID: "session-1", member: true, allowed: true},
+		{name: "shared session but not a member", viewerID: "player-9", sessionID: "session-1"},
+		{name: "other session", viewerID: "dm-1", sessionID: "session-2"},
+		{name: "stranger", viewerID: "player-9"},
+		{name: "no viewer"},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.charData.PlayerID = "player-1"
+			s.expectGet(sheet)
+			if tc.sessionID == "session-1" {
+				s.expectCampaigns(tc.viewerID, tc.member)
+			}
+
+			output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{
+				CharacterID: "char-1",
+				ViewerID:    tc.viewerID,
+				SessionID:   tc.sessionID,
+			})
+
+			if tc.allowed {
+				s.Require().NoError(err)
+				s.Equal("char-1", output.Character.ID)
+				return
+			}
+			s.Nil(output)
+			s.True(errors.IsPermissionDenied(err))
+		})
+	}
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacter_Internal() {
+	s.charData.PlayerID = "player-1"
+	s.expectGet(nil)
+
+	output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{
+		CharacterID: "char-1",
+		Internal:    true,
+	})
+
+	s.Require().NoError(err)
+	s.Equal("char-1", output.Character.ID)
+}
+
+// expectCampaigns returns the player's campaigns, with session-1 in one of them if member is set
+func (s *EquipmentOrchestratorTestSuite) expectCampaigns(playerID string, member bool) {
+	campaign := &campaignrepo.Campaign{ID: "campaign-1", Sessions: []*campaignrepo.Session{{ID: "session-0"}}}
+	if member {
+		campaign.Sessions = append(campaign.Sessions, &campaignrepo.Session{ID: "session-1"})
+	}
+	s.mockCampaignRepo.EXPECT().
+		ListByMember(s.ctx, campaignrepo.ListByMemberInput{PlayerID: playerID}).
+		Return(&campaignrepo.ListByMemberOutput{Campaigns: []*campaignrepo.Campaign{campaign}}, nil)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestListCharacters_OnlyShared() {
+	party := []*toolkitchar.Data{
+		{ID: "char-1", PlayerID: "player-1"},
+		{ID: "char-2", PlayerID: "player-2"},
+	}
+	s.expectCampaigns("player-1", true)
+	// The repository leaves out what the viewer may not see, so the total matches the page
+	s.mockCharRepo.EXPECT().
+		List(s.ctx, characterrepo.ListInput{
+			SessionID:       "session-1",
+			ViewerID:        "player-1",
+			ViewerSessionID: "session-1",
+		}).
+		Return(&characterrepo.ListOutput{Characters: party, TotalSize: 2}, nil)
+
+	output, err := s.orchestrator.ListCharacters(s.ctx, &character.ListCharactersInput{
+		SessionID: "session-1",
+		ViewerID:  "player-1",
+	})
+
+	s.Require().NoError(err)
+	s.Equal(party, output.Characters)
+	s.Equal(int32(2), output.TotalSize)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestListCharacters_SessionSharesNeedMembership() {
+	s.expectCampaigns("player-5", false)
+	s.mockCharRepo.EXPECT().
+		List(s.ctx, characterrepo.ListInput{SessionID: "session-1", ViewerID: "player-5"}).
+		Return(&characterrepo.ListOutput{Characters: []*toolkitchar.Data{}}, nil)
+
+	output, err := s.orchestrator.ListCharacters(s.ctx, &character.ListCharactersInput{
+		SessionID: "session-1",
+		ViewerID:  "player-5",
+	})
+
+	s.Require().NoError(err)
+	s.Empty(output.Characters)
+	s.Zero(output.TotalSize)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestListCharacters_NoViewer() {
+	output, err := s.orchestrator.ListCharacters(s.ctx, &character.ListCharactersInput{PlayerID: "player-1"})
+
+	s.Require().NoError(err)
+	s.Empty(output.Characters)
+	s.Zero(output.TotalSize)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestRequestCharacterTransfer() {
+	s.charData.PlayerID = "player-1"
+	s.expectGet(nil)
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Equal(dnd5e.CharacterEventTransferRequested, input.EventType)
+		s.Equal("player-1", input.CharacterData.PlayerID)
+		s.Require().NotNil(input.Sheet.PendingTransfer)
+		s.Equal("player-2", input.Sheet.PendingTransfer.ToPlayerID)
+	})
+
+	output, err := s.orchestrator.RequestCharacterTransfer(s.ctx, &character.RequestCharacterTransferInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		ToPlayerID:  "player-2",
+	})
+
+	s.Require().NoError(err)
+	s.Equal("player-1", output.Transfer.FromPlayerID)
+	s.False(output.Transfer.RequestedAt.IsZero())
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestRespondToCharacterTransfer() {
+	pending := func() *dnd5e.CharacterSheet {
+		return &dnd5e.CharacterSheet{
+			SharedWithPlayers: []string{"player-2", "player-3"},
+			PendingTransfer: &dnd5e.CharacterTransfer{
+				FromPlayerID: "player-1",
+				ToPlayerID:   "player-2",
+				RequestedAt:  time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			},
+		}
+	}
+
+	s.Run("recipient accepts", func() {
+		s.charData.PlayerID = "player-1"
+		s.expectGet(pending())
+		s.expectUpdate(func(input characterrepo.UpdateInput) {
+			s.Equal(dnd5e.CharacterEventTransferred, input.EventType)
+			s.Equal("player-2", input.CharacterData.PlayerID)
+			s.Nil(input.Sheet.PendingTransfer)
+			s.Equal([]string{"player-3"}, input.Sheet.SharedWithPlayers)
+		})
+
+		output, err := s.orchestrator.RespondToCharacterTransfer(s.ctx, &character.RespondToCharacterTransferInput{
+			CharacterID: "char-1",
+			PlayerID:    "player-2",
+			Accept:      true,
+		})
+
+		s.Require().NoError(err)
+		s.True(output.Transferred)
+	})
+
+	s.Run("owner cancels", func() {
+		s.charData.PlayerID = "player-1"
+		s.expectGet(pending())
+		s.expectUpdate(func(input characterrepo.UpdateInput) {
+			s.Equal(dnd5e.CharacterEventTransferCancelled, input.EventType)
+			s.Equal("player-1", input.CharacterData.PlayerID)
+			s.Nil(input.Sheet.PendingTransfer)
+		})
+
+		output, err := s.orchestrator.RespondToCharacterTransfer(s.ctx, &character.RespondToCharacterTransferInput{
+			CharacterID: "char-1",
+			PlayerID:    "player-1",
+		})
+
+		s.Require().NoError(err)
+		s.False(output.Transferred)
+	})
+
+	s.Run("owner cannot accept", func() {
+		s.charData.PlayerID = "player-1"
+		s.expectGet(pending())
+
+		output, err := s.orchestrator.RespondToCharacterTransfer(s.ctx, &character.RespondToCharacterTransferInput{
+			CharacterID: "char-1",
+			PlayerID:    "player-1",
+			Accept:      true,
+		})
+
+		s.Nil(output)
+		s.True(errors.IsPermissionDenied(err))
+	})
+
+	s.Run("no pending transfer", func() {
+		s.charData.PlayerID = "player-1"
+		s.expectGet(nil)
+
+		output, err := s.orchestrator.RespondToCharacterTransfer(s.ctx, &character.RespondToCharacterTransferInput{
+			CharacterID: "char-1",
+			PlayerID:    "player-2",
+			Accept:      true,
+		})
+
+		s.Nil(output)
+		s.True(errors.IsFailedPrecondition(err))
+	})
+
+	s.Run("cancelled while accepting", func() {
+		s.charData.PlayerID = "player-1"
+		first := *s.charData
+		second := *s.charData
+		gomock.InOrder(
+			s.mockCharRepo.EXPECT().
+				Get(s.ctx, characterrepo.GetInput{ID: "char-1"}).
+				Return(&characterrepo.GetOutput{CharacterData: &first, Sheet: pending(), Revision: 4}, nil),
+			s.mockCharRepo.EXPECT().
+				Update(s.ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+					s.Require().NotNil(input.ExpectedRevision)
+					s.Equal(int64(4), *input.ExpectedRevision)
+					return nil, errors.Aborted("character changed")
+				}),
+			// The owner cancelled in between, so the offer is gone on the second read
+			s.mockCharRepo.EXPECT().
+				Get(s.ctx, characterrepo.GetInput{ID: "char-1"}).
+				Return(&characterrepo.GetOutput{CharacterData: &second, Revision: 5}, nil),
+		)
+
+		output, err := s.orchestrator.RespondToCharacterTransfer(s.ctx, &character.RespondToCharacterTransferInput{
+			CharacterID: "char-1",
+			PlayerID:    "player-2",
+			Accept:      true,
+		})
+
+		s.Nil(output)
+		s.True(errors.IsFailedPrecondition(err))
+		s.Equal("player-1", second.PlayerID)
+	})
+}
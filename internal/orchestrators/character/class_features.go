@@ -0,0 +1,262 @@
+package character
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+// ChoiceSubclass is the draft choice category for classes that pick a subclass at level 1.
+// The selection is made in ClassSelection.SubclassID.
+const ChoiceSubclass shared.ChoiceCategory = "subclass"
+
+// ChoiceDragonAncestor is the draft choice category for a Draconic Bloodline sorcerer's dragon
+// ancestor. The selection is made in NameSelection.
+const ChoiceDragonAncestor shared.ChoiceCategory = "dragon_ancestor"
+
+// Subclasses chosen at level 1
+const (
+	// Cleric Divine Domains
+	SubclassKnowledgeDomain constants.Subclass = "knowledge"
+	SubclassLifeDomain      constants.Subclass = "life"
+	SubclassLightDomain     constants.Subclass = "light"
+	SubclassNatureDomain    constants.Subclass = "nature"
+	SubclassTempestDomain   constants.Subclass = "tempest"
+	SubclassTrickeryDomain  constants.Subclass = "trickery"
+	SubclassWarDomain       constants.Subclass = "war"
+
+	// Sorcerer Sorcerous Origins
+	SubclassDraconicBloodline constants.Subclass = "draconic"
+	SubclassWildMagic         constants.Subclass = "wild-magic"
+
+	// Warlock Otherworldly Patrons
+	SubclassArchfey     constants.Subclass = "archfey"
+	SubclassFiend       constants.Subclass = "fiend"
+	SubclassGreatOldOne constants.Subclass = "great-old-one"
+)
+
+// Fighting styles available to fighters at level 1
+const (
+	FightingStyleArchery             = "archery"
+	FightingStyleDefense             = "defense"
+	FightingStyleDueling             = "dueling"
+	FightingStyleGreatWeaponFighting = "great-weapon-fighting"
+	FightingStyleProtection          = "protection"
+	FightingStyleTwoWeaponFighting   = "two-weapon-fighting"
+)
+
+// Dragon ancestors a Draconic Bloodline sorcerer can descend from
+const (
+	DragonBlack  = "black"
+	DragonBlue   = "blue"
+	DragonBrass  = "brass"
+	DragonBronze = "bronze"
+	DragonCopper = "copper"
+	DragonGold   = "gold"
+	DragonGreen  = "green"
+	DragonRed    = "red"
+	DragonSilver = "silver"
+	DragonWhite  = "white"
+)
+
+var dragonAncestors = []string{
+	DragonBlack, DragonBlue, DragonBrass, DragonBronze, DragonCopper,
+	DragonGold, DragonGreen, DragonRed, DragonSilver, DragonWhite,
+}
+
+var levelOneSubclasses = map[constants.Class][]constants.Subclass{
+	constants.ClassCleric: {
+		SubclassKnowledgeDomain, SubclassLifeDomain, SubclassLightDomain, SubclassNatureDomain,
+		SubclassTempestDomain, SubclassTrickeryDomain, SubclassWarDomain,
+	},
+	constants.ClassSorcerer: {SubclassDraconicBloodline, SubclassWildMagic},
+	constants.ClassWarlock:  {SubclassArchfey, SubclassFiend, SubclassGreatOldOne},
+}
+
+var levelOneFightingStyles = map[constants.Class][]string{
+	constants.ClassFighter: {
+		FightingStyleArchery, FightingStyleDefense, FightingStyleDueling,
+		FightingStyleGreatWeaponFighting, FightingStyleProtection, FightingStyleTwoWeaponFighting,
+	},
+}
+
+// domainSpells are the 1st level domain spells a cleric always has prepared
+var domainSpells = map[constants.Subclass][]string{
+	SubclassKnowledgeDomain: {"SPELL_COMMAND", "SPELL_IDENTIFY"},
+	SubclassLifeDomain:      {"SPELL_BLESS", "SPELL_CURE_WOUNDS"},
+	SubclassLightDomain:     {"SPELL_BURNING_HANDS", "SPELL_FAERIE_FIRE"},
+	SubclassNatureDomain:    {"SPELL_ANIMAL_FRIENDSHIP", "SPELL_SPEAK_WITH_ANIMALS"},
+	SubclassTempestDomain:   {"SPELL_FOG_CLOUD", "SPELL_THUNDERWAVE"},
+	SubclassTrickeryDomain:  {"SPELL_CHARM_PERSON", "SPELL_DISGUISE_SELF"},
+	SubclassWarDomain:       {"SPELL_DIVINE_FAVOR", "SPELL_SHIELD_OF_FAITH"},
+}
+
+// levelOneFeatureChoices returns the placeholder choices for features a class picks at level 1.
+// A Draconic Bloodline sorcerer also picks a dragon ancestor.
+func levelOneFeatureChoices(classID constants.Class, subclass constants.Subclass) []toolkitchar.ChoiceData {
+	var result []toolkitchar.ChoiceData
+	if _, ok := levelOneSubclasses[classID]; ok {
+		result = append(result, toolkitchar.ChoiceData{
+			Category: ChoiceSubclass,
+			Source:   shared.SourceClass,
+			ChoiceID: string(classID) + "_subclass",
+		})
+	}
+	if subclass == SubclassDraconicBloodline {
+		result = append(result, toolkitchar.ChoiceData{
+			Category: ChoiceDragonAncestor,
+			Source:   shared.SourceClass,
+			ChoiceID: string(classID) + "_dragon_ancestor",
+		})
+	}
+	if expertiseAllowance(classID, 1) > 0 {
+		result = append(result, toolkitchar.ChoiceData{
+			Category: ChoiceExpertise,
+			Source:   shared.SourceClass,
+			ChoiceID: string(classID) + "_expertise",
+		})
+	}
+	if _, ok := levelOneFightingStyles[classID]; ok {
+		result = append(result, toolkitchar.ChoiceData{
+			Category: shared.ChoiceFightingStyle,
+			Source:   shared.SourceClass,
+			ChoiceID: string(classID) + "_fighting_style",
+		})
+	}
+	return result
+}
+
+// validateLevelOneFeatureChoices checks subclass, dragon ancestor and fighting style selections
+// against the class. Unselected placeholder choices are allowed so a draft can be saved part way
+// through; FinalizeDraft requires them.
+func validateLevelOneFeatureChoices(classID constants.Class, choices []toolkitchar.ChoiceData) error {
+	subclass := chosenSubclass(choices)
+	for _, choice := range choices {
+		switch choice.Category {
+		case ChoiceSubclass:
+			selection := selectedSubclass(choice)
+			if selection == "" {
+				continue
+			}
+			if !slices.Contains(levelOneSubclasses[classID], selection) {
+				return errors.InvalidArgumentf("%s is not a level 1 subclass for %s", selection, classID)
+			}
+		case ChoiceDragonAncestor:
+			ancestor := selectedDragonAncestor(choice)
+			if ancestor == "" {
+				continue
+			}
+			if subclass != SubclassDraconicBloodline {
+				return errors.InvalidArgument("only draconic bloodline sorcerers choose a dragon ancestor")
+			}
+			if !slices.Contains(dragonAncestors, ancestor) {
+				return errors.InvalidArgumentf("%s is not a dragon ancestor", *choice.NameSelection)
+			}
+		case shared.ChoiceFightingStyle:
+			if choice.FightingStyleSelection == nil || *choice.FightingStyleSelection == "" {
+				continue
+			}
+			if !slices.Contains(levelOneFightingStyles[classID], normalizeFightingStyle(*choice.FightingStyleSelection)) {
+				return errors.InvalidArgumentf("%s is not a fighting style for %s", *choice.FightingStyleSelection, classID)
+			}
+		}
+	}
+	return nil
+}
+
+// levelOneFeatures are the results of the features a new character picked at level 1
+type levelOneFeatures struct {
+	// alwaysPrepared are spells the subclass keeps prepared, such as cleric domain spells
+	alwaysPrepared []string
+	dragonAncestor string
+}
+
+// applyLevelOneFeatureChoices applies the chosen subclass to a new character. Every level 1
+// feature choice the class has must be made.
+func applyLevelOneFeatureChoices(characterData *toolkitchar.Data, choices []toolkitchar.ChoiceData) (*levelOneFeatures, error) {
+	if err := validateLevelOneFeatureChoices(characterData.ClassID, choices); err != nil {
+		return nil, err
+	}
+
+	subclass := chosenSubclass(choices)
+	if _, ok := levelOneSubclasses[characterData.ClassID]; ok && subclass == "" {
+		return nil, errors.InvalidArgumentf("draft is incomplete: %s must choose a subclass", characterData.ClassID)
+	}
+	if _, ok := levelOneFightingStyles[characterData.ClassID]; ok && chosenFightingStyle(choices) == "" {
+		return nil, errors.InvalidArgumentf("draft is incomplete: %s must choose a fighting style", characterData.ClassID)
+	}
+
+	features := &levelOneFeatures{}
+	if subclass != "" {
+		characterData.SubclassID = subclass
+	}
+
+	switch characterData.SubclassID {
+	case SubclassDraconicBloodline:
+		for _, choice := range choices {
+			if choice.Category == ChoiceDragonAncestor {
+				features.dragonAncestor = selectedDragonAncestor(choice)
+			}
+		}
+		if features.dragonAncestor == "" {
+			return nil, errors.InvalidArgument("draft is incomplete: draconic bloodline must choose a dragon ancestor")
+		}
+		// Draconic Resilience: hit point maximum increases by 1 per sorcerer level
+		characterData.MaxHitPoints += characterData.Level
+		characterData.HitPoints += characterData.Level
+	}
+
+	features.alwaysPrepared = slices.Clone(domainSpells[characterData.SubclassID])
+	return features, nil
+}
+
+// fightingStyle returns the character's chosen fighting style, if any
+func fightingStyle(charData *toolkitchar.Data) string {
+	return chosenFightingStyle(charData.Choices)
+}
+
+// chosenFightingStyle returns the fighting style selected among the choices, if any
+func chosenFightingStyle(choices []toolkitchar.ChoiceData) string {
+	for _, choice := range choices {
+		if choice.Category == shared.ChoiceFightingStyle && choice.FightingStyleSelection != nil {
+			return normalizeFightingStyle(*choice.FightingStyleSelection)
+		}
+	}
+	return ""
+}
+
+// normalizeFightingStyle accepts display names such as "Great Weapon Fighting"
+func normalizeFightingStyle(style string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(style), " ", "-"))
+}
+
+// chosenSubclass returns the subclass selected among the choices, if any
+func chosenSubclass(choices []toolkitchar.ChoiceData) constants.Subclass {
+	var subclass constants.Subclass
+	for _, choice := range choices {
+		if choice.Category == ChoiceSubclass {
+			if selection := selectedSubclass(choice); selection != "" {
+				subclass = selection
+			}
+		}
+	}
+	return subclass
+}
+
+func selectedDragonAncestor(choice toolkitchar.ChoiceData) string {
+	if choice.NameSelection == nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(*choice.NameSelection))
+}
+
+func selectedSubclass(choice toolkitchar.ChoiceData) constants.Subclass {
+	if choice.ClassSelection == nil {
+		return ""
+	}
+	return choice.ClassSelection.SubclassID
+}
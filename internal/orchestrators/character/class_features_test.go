@@ -0,0 +1,276 @@
+package character_test
+
+import (
+	"context"
+	"slices"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	charrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	draftrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/class"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/race"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+// setupClassDraft mocks finalizing a human draft of the given class with extra choices
+func (s *FinalizeDraftOrchestratorTestSuite) setupClassDraft(classID constants.Class, hitDice int, choices ...toolkitchar.ChoiceData) {
+	s.mockIDGen.EXPECT().Generate().Return("char-" + string(classID))
+
+	draft := &toolkitchar.DraftData{
+		ID:          "draft-" + string(classID),
+		PlayerID:    "player-1",
+		Name:        "Test " + string(classID),
+		RaceChoice:  toolkitchar.RaceChoice{RaceID: constants.RaceHuman},
+		ClassChoice: toolkitchar.ClassChoice{ClassID: classID},
+		AbilityScoreChoice: shared.AbilityScores{
+			constants.STR: 10,
+			constants.DEX: 14,
+			constants.CON: 14,
+			constants.INT: 10,
+			constants.WIS: 15,
+			constants.CHA: 15,
+		},
+		Choices: choices,
+	}
+
+	s.mockDraftRepo.EXPECT().
+		Get(gomock.Any(), draftrepo.GetInput{ID: draft.ID}).
+		Return(&draftrepo.GetOutput{Draft: draft}, nil)
+	s.mockExtClient.EXPECT().
+		GetRaceData(gomock.Any(), string(constants.RaceHuman)).
+		Return(&external.RaceDataOutput{
+			RaceData: &race.Data{ID: constants.RaceHuman, Speed: 30, Size: "Medium"},
+		}, nil)
+	s.mockExtClient.EXPECT().
+		GetClassData(gomock.Any(), string(classID)).
+		Return(&external.ClassDataOutput{
+			ClassData: &class.Data{ID: classID, HitDice: hitDice},
+		}, nil)
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) expectCreateAndDelete(check func(input charrepo.CreateInput)) {
+	s.mockCharRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input charrepo.CreateInput) (*charrepo.CreateOutput, error) {
+			check(input)
+			return &charrepo.CreateOutput{CharacterData: input.CharacterData, Sheet: input.Sheet}, nil
+		})
+	s.mockDraftRepo.EXPECT().
+		Delete(gomock.Any(), gomock.Any()).
+		Return(&draftrepo.DeleteOutput{}, nil)
+}
+
+func subclassChoice(classID constants.Class, subclass constants.Subclass) toolkitchar.ChoiceData {
+	return toolkitchar.ChoiceData{
+		Category:       character.ChoiceSubclass,
+		Source:         shared.SourceClass,
+		ChoiceID:       string(classID) + "_subclass",
+		ClassSelection: &toolkitchar.ClassChoice{ClassID: classID, SubclassID: subclass},
+	}
+}
+
+func fightingStyleChoice(style string) toolkitchar.ChoiceData {
+	return toolkitchar.ChoiceData{
+		Category:               shared.ChoiceFightingStyle,
+		Source:                 shared.SourceClass,
+		ChoiceID:               "fighter_fighting_style",
+		FightingStyleSelection: &style,
+	}
+}
+
+func dragonAncestorChoice(ancestor string) toolkitchar.ChoiceData {
+	return toolkitchar.ChoiceData{
+		Category:      character.ChoiceDragonAncestor,
+		Source:        shared.SourceClass,
+		ChoiceID:      "sorcerer_dragon_ancestor",
+		NameSelection: &ancestor,
+	}
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_ClericDomainSpells() {
+	s.setupClassDraft(constants.ClassCleric, 8, subclassChoice(constants.ClassCleric, character.SubclassLifeDomain))
+	s.expectCreateAndDelete(func(input charrepo.CreateInput) {
+		s.Equal(character.SubclassLifeDomain, input.CharacterData.SubclassID)
+		s.Require().NotNil(input.Sheet)
+		s.Equal([]string{"SPELL_BLESS", "SPELL_CURE_WOUNDS"}, input.Sheet.AlwaysPreparedSpells)
+	})
+
+	_, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-cleric"})
+
+	s.Require().NoError(err)
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_DraconicResilienceHP() {
+	s.setupClassDraft(constants.ClassSorcerer, 6,
+		subclassChoice(constants.ClassSorcerer, character.SubclassDraconicBloodline),
+		dragonAncestorChoice("Red"))
+	s.expectCreateAndDelete(func(input charrepo.CreateInput) {
+		// 6 hit die + 2 CON + 1 Draconic Resilience
+		s.Equal(9, input.CharacterData.MaxHitPoints)
+		s.Equal(9, input.CharacterData.HitPoints)
+		s.Require().NotNil(input.Sheet)
+		s.Equal(character.DragonRed, input.Sheet.DragonAncestor)
+	})
+
+	_, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-sorcerer"})
+
+	s.Require().NoError(err)
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_RequiresDragonAncestor() {
+	s.setupClassDraft(constants.ClassSorcerer, 6,
+		subclassChoice(constants.ClassSorcerer, character.SubclassDraconicBloodline))
+
+	output, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-sorcerer"})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_UnknownDragonAncestor() {
+	s.setupClassDraft(constants.ClassSorcerer, 6,
+		subclassChoice(constants.ClassSorcerer, character.SubclassDraconicBloodline),
+		dragonAncestorChoice("purple"))
+
+	output, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-sorcerer"})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_DragonAncestorNeedsDraconicBloodline() {
+	s.setupClassDraft(constants.ClassSorcerer, 6,
+		subclassChoice(constants.ClassSorcerer, character.SubclassWildMagic),
+		dragonAncestorChoice(character.DragonGold))
+
+	output, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-sorcerer"})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_RequiresSubclass() {
+	s.setupClassDraft(constants.ClassCleric, 8)
+
+	output, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-cleric"})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_RequiresFightingStyle() {
+	s.setupClassDraft(constants.ClassFighter, 10)
+
+	output, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-fighter"})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_SubclassFromAnotherClass() {
+	s.setupClassDraft(constants.ClassWarlock, 8, subclassChoice(constants.ClassWarlock, character.SubclassLifeDomain))
+
+	output, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-warlock"})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestUpdateClass_InvalidFightingStyle() {
+	s.mockDraftRepo.EXPECT().
+		Get(gomock.Any(), draftrepo.GetInput{ID: "draft-fighter"}).
+		Return(&draftrepo.GetOutput{Draft: &toolkitchar.DraftData{ID: "draft-fighter"}}, nil)
+
+	style := "interpretive-dance"
+	output, err := s.orchestrator.UpdateClass(s.ctx, &character.UpdateClassInput{
+		DraftID: "draft-fighter",
+		ClassID: constants.ClassFighter,
+		Choices: []toolkitchar.ChoiceData{{
+			Category:               shared.ChoiceFightingStyle,
+			FightingStyleSelection: &style,
+		}},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestUpdateClass_SubclassSelectionReplacesPlaceholder() {
+	s.mockDraftRepo.EXPECT().
+		Get(gomock.Any(), draftrepo.GetInput{ID: "draft-cleric"}).
+		Return(&draftrepo.GetOutput{Draft: &toolkitchar.DraftData{ID: "draft-cleric"}}, nil)
+	s.mockDraftRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input draftrepo.UpdateInput) (*draftrepo.UpdateOutput, error) {
+			return &draftrepo.UpdateOutput{Draft: input.Draft}, nil
+		})
+
+	output, err := s.orchestrator.UpdateClass(s.ctx, &character.UpdateClassInput{
+		DraftID: "draft-cleric",
+		ClassID: constants.ClassCleric,
+		Choices: []toolkitchar.ChoiceData{subclassChoice(constants.ClassCleric, character.SubclassWarDomain)},
+	})
+
+	s.Require().NoError(err)
+	s.Equal(character.SubclassWarDomain, output.Draft.ClassChoice.SubclassID)
+	subclassChoices := 0
+	for _, choice := range output.Draft.Choices {
+		if choice.Category == character.ChoiceSubclass {
+			subclassChoices++
+		}
+	}
+	s.Equal(1, subclassChoices)
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestUpdateClass_DraconicBloodlineAddsDragonAncestorChoice() {
+	s.mockDraftRepo.EXPECT().
+		Get(gomock.Any(), draftrepo.GetInput{ID: "draft-sorcerer"}).
+		Return(&draftrepo.GetOutput{Draft: &toolkitchar.DraftData{ID: "draft-sorcerer"}}, nil)
+	s.mockDraftRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input draftrepo.UpdateInput) (*draftrepo.UpdateOutput, error) {
+			return &draftrepo.UpdateOutput{Draft: input.Draft}, nil
+		})
+
+	output, err := s.orchestrator.UpdateClass(s.ctx, &character.UpdateClassInput{
+		DraftID: "draft-sorcerer",
+		ClassID: constants.ClassSorcerer,
+		Choices: []toolkitchar.ChoiceData{
+			subclassChoice(constants.ClassSorcerer, character.SubclassDraconicBloodline),
+		},
+	})
+
+	s.Require().NoError(err)
+	s.True(slices.ContainsFunc(output.Draft.Choices, func(choice toolkitchar.ChoiceData) bool {
+		return choice.Category == character.ChoiceDragonAncestor
+	}))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacter_DefenseFightingStyle() {
+	style := "Defense"
+	s.charData.Choices = []toolkitchar.ChoiceData{{
+		Category:               shared.ChoiceFightingStyle,
+		FightingStyleSelection: &style,
+	}}
+	s.expectGet(&dnd5e.CharacterSheet{
+		EquippedSlots: map[string]string{character.SlotArmor: "EQUIPMENT_SCALE_MAIL"},
+	})
+	s.mockExtClient.EXPECT().GetEquipmentData(s.ctx, "EQUIPMENT_SCALE_MAIL").Return(&external.EquipmentData{
+		ID:            "EQUIPMENT_SCALE_MAIL",
+		ArmorCategory: "Medium",
+		ArmorClass:    &external.ArmorClassData{Base: 14, DexBonus: true},
+	}, nil)
+
+	output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "char-1", ViewerID: "player-1"})
+
+	s.Require().NoError(err)
+	// 14 scale mail + 2 DEX + 1 Defense
+	s.Equal(int32(17), output.DerivedStats.ArmorClass)
+}
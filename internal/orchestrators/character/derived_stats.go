@@ -0,0 +1,150 @@
+package character
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+)
+
+// deriveStats calculates armor class and the saving throw bonus from passive magic items.
+// Equipment lookup failures are logged and skipped so reading a character never depends on
+// the external API being available.
+func (o *Orchestrator) deriveStats(
+	ctx context.Context,
+	charData *toolkitchar.Data,
+	sheet *dnd5e.CharacterSheet,
+) *DerivedStats {
+	if charData == nil {
+		return nil
+	}
+	sheet = dnd5e.SheetOrEmpty(sheet)
+
+	bonuses := o.activeMagicItemEffects(ctx, sheet)
+
+	return &DerivedStats{
+		ArmorClass:       o.baseArmorClass(ctx, charData, sheet) + bonuses.ACBonus,
+		ProficiencyBonus: dnd5e.ProficiencyBonus(charData.Level),
+		SavingThrowBonus: bonuses.SavingThrowBonus,
+	}
+}
+
+// baseArmorClass returns AC from worn armor and shield, or 10 + DEX when unarmored
+func (o *Orchestrator) baseArmorClass(
+	ctx context.Context,
+	charData *toolkitchar.Data,
+	sheet *dnd5e.CharacterSheet,
+) int32 {
+	dexMod := dnd5e.AbilityModifier(charData.AbilityScores[constants.DEX])
+	armorClass := 10 + dexMod
+	// Draconic Resilience makes unarmored AC 13 + DEX
+	if charData.SubclassID == SubclassDraconicBloodline {
+		armorClass = 13 + dexMod
+	}
+
+	if armorID, ok := sheet.EquippedSlots[SlotArmor]; ok {
+		armor := o.lookupEquipment(ctx, armorID)
+		if armor != nil && armor.ArmorClass != nil {
+			// nolint:gosec // armor class values are small
+			armorClass = int32(armor.ArmorClass.Base)
+			if armor.ArmorClass.DexBonus {
+				// Medium armor caps the DEX bonus at +2
+				if strings.EqualFold(armor.ArmorCategory, "Medium") {
+					dexMod = min(dexMod, 2)
+				}
+				armorClass += dexMod
+			}
+			// The Defense fighting style adds +1 AC while wearing armor
+			if fightingStyle(charData) == FightingStyleDefense {
+				armorClass++
+			}
+		}
+	}
+
+	if shieldID, ok := sheet.EquippedSlots[SlotOffHand]; ok {
+		shield := o.lookupEquipment(ctx, shieldID)
+		if shield != nil && shield.ArmorClass != nil && strings.EqualFold(shield.ArmorCategory, "Shield") {
+			// nolint:gosec // armor class values are small
+			armorClass += int32(shield.ArmorClass.Base)
+		}
+	}
+
+	return armorClass
+}
+
+// activeMagicItemEffects sums the passive bonuses of magic items currently in effect. Only
+// equipped items count, and items that require attunement must also be attuned.
+func (o *Orchestrator) activeMagicItemEffects(ctx context.Context, sheet *dnd5e.CharacterSheet) *dnd5e.MagicItemEffects {
+	total := &dnd5e.MagicItemEffects{}
+
+	// Each item counts once, even with copies worn in two slots such as both ring slots
+	candidates := make([]string, 0, len(sheet.EquippedSlots))
+	for _, itemID := range sheet.EquippedSlots {
+		if !slices.Contains(candidates, itemID) {
+			candidates = append(candidates, itemID)
+		}
+	}
+	slices.Sort(candidates)
+
+	for _, itemID := range candidates {
+		if !external.IsMagicItemID(itemID) {
+			continue
+		}
+
+		item, err := o.externalClient.GetMagicItemData(ctx, itemID)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load magic item for derived stats",
+				"item_id", itemID,
+				"error", err.Error())
+			continue
+		}
+		if item.Effects == nil {
+			continue
+		}
+
+		if item.RequiresAttunement && !slices.Contains(sheet.AttunedItems, itemID) {
+			continue
+		}
+
+		total.ACBonus += item.Effects.ACBonus
+		total.SavingThrowBonus += item.Effects.SavingThrowBonus
+		total.AttackBonus += item.Effects.AttackBonus
+		total.DamageBonus += item.Effects.DamageBonus
+	}
+
+	return total
+}
+
+// lookupEquipment resolves mundane equipment data for an item, following magic
+// item variants back to the equipment they are built on
+func (o *Orchestrator) lookupEquipment(ctx context.Context, itemID string) *external.EquipmentData {
+	equipmentID := itemID
+	if external.IsMagicItemID(itemID) {
+		item, err := o.externalClient.GetMagicItemData(ctx, itemID)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load magic item",
+				"item_id", itemID,
+				"error", err.Error())
+			return nil
+		}
+		if item.BaseEquipmentID == "" {
+			return nil
+		}
+		equipmentID = item.BaseEquipmentID
+	}
+
+	equipment, err := o.externalClient.GetEquipmentData(ctx, equipmentID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load equipment",
+			"item_id", equipmentID,
+			"error", err.Error())
+		return nil
+	}
+
+	return equipment
+}
@@ -0,0 +1,456 @@
+package character
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	campaignrepo "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+)
+
+// Equipment slot names accepted by EquipItem and UnequipItem
+const (
+	SlotMainHand = "main_hand"
+	SlotOffHand  = "off_hand"
+	SlotArmor    = "armor"
+	SlotHelmet   = "helmet"
+	SlotBoots    = "boots"
+	SlotGloves   = "gloves"
+	SlotCloak    = "cloak"
+	SlotAmulet   = "amulet"
+	SlotRing1    = "ring_1"
+	SlotRing2    = "ring_2"
+	SlotBelt     = "belt"
+
+	// MaxAttunedItems is the number of magic items a character can be attuned to at once
+	MaxAttunedItems = 3
+
+	// maxItemsAdded bounds how many items one AddToInventory call can grant
+	maxItemsAdded = 100
+
+	// maxUpdateAttempts bounds how often an update starts over when the character is
+	// saved by someone else between the read and the write
+	maxUpdateAttempts = 3
+)
+
+var validEquipmentSlots = map[string]bool{
+	SlotMainHand: true,
+	SlotOffHand:  true,
+	SlotArmor:    true,
+	SlotHelmet:   true,
+	SlotBoots:    true,
+	SlotGloves:   true,
+	SlotCloak:    true,
+	SlotAmulet:   true,
+	SlotRing1:    true,
+	SlotRing2:    true,
+	SlotBelt:     true,
+}
+
+// EquipItem places an item the owner's character carries into a slot it fits, such as a
+// cloak on the shoulders or a weapon in a hand
+func (o *Orchestrator) EquipItem(ctx context.Context, input *EquipItemInput) (*EquipItemOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument("player ID is required")
+	}
+	if input.ItemID == "" {
+		return nil, errors.InvalidArgument("item ID is required")
+	}
+	if !validEquipmentSlots[input.Slot] {
+		return nil, errors.InvalidArgumentf("invalid equipment slot: %s", input.Slot)
+	}
+
+	getOutput, err := o.getOwnedCharacter(ctx, input.CharacterID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	charData := getOutput.CharacterData
+	sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+
+	// A character can only equip as many copies of an item as they carry
+	owned := dnd5e.CountItem(charData.Equipment, input.ItemID)
+	if owned == 0 {
+		return nil, errors.FailedPreconditionf("character does not have item %s", input.ItemID)
+	}
+	inUse := 0
+	for slot, itemID := range sheet.EquippedSlots {
+		if itemID == input.ItemID && slot != input.Slot {
+			inUse++
+		}
+	}
+	if inUse >= owned {
+		return nil, errors.FailedPreconditionf("all copies of %s are already equipped", input.ItemID)
+	}
+
+	slots, err := o.itemSlots(ctx, input.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(slots, input.Slot) {
+		return nil, errors.InvalidArgumentf("%s cannot be equipped in slot %s, only in %s",
+			input.ItemID, input.Slot, strings.Join(slots, " or "))
+	}
+
+	var previous *dnd5e.InventoryItem
+	if previousID, ok := sheet.EquippedSlots[input.Slot]; ok {
+		previous = &dnd5e.InventoryItem{
+			ID:       previousID,
+			Quantity: 1,
+		}
+	}
+
+	if sheet.EquippedSlots == nil {
+		sheet.EquippedSlots = make(map[string]string)
+	}
+	sheet.EquippedSlots[input.Slot] = input.ItemID
+
+	updateOutput, err := o.charRepo.Update(ctx, character.UpdateInput{
+		CharacterData: charData,
+		Sheet:         sheet,
+		EventType:     dnd5e.CharacterEventEquipmentChanged,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to equip item")
+	}
+
+	slog.InfoContext(ctx, "equipped item",
+		"character_id", input.CharacterID,
+		"item_id", input.ItemID,
+		"slot", input.Slot)
+
+	return &EquipItemOutput{
+		Success:                true,
+		Character:              updateOutput.CharacterData,
+		PreviouslyEquippedItem: previous,
+	}, nil
+}
+
+// UnequipItem clears an equipment slot, leaving the item in the character's equipment
+func (o *Orchestrator) UnequipItem(ctx context.Context, input *UnequipItemInput) (*UnequipItemOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument("player ID is required")
+	}
+	if !validEquipmentSlots[input.Slot] {
+		return nil, errors.InvalidArgumentf("invalid equipment slot: %s", input.Slot)
+	}
+
+	getOutput, err := o.getOwnedCharacter(ctx, input.CharacterID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+
+	if _, ok := sheet.EquippedSlots[input.Slot]; !ok {
+		return nil, errors.FailedPreconditionf("nothing is equipped in slot %s", input.Slot)
+	}
+	delete(sheet.EquippedSlots, input.Slot)
+
+	updateOutput, err := o.charRepo.Update(ctx, character.UpdateInput{
+		CharacterData: getOutput.CharacterData,
+		Sheet:         sheet,
+		EventType:     dnd5e.CharacterEventEquipmentChanged,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unequip item")
+	}
+
+	return &UnequipItemOutput{
+		Success:   true,
+		Character: updateOutput.CharacterData,
+	}, nil
+}
+
+// AttuneItem attunes the owner's character to a magic item they carry, if the character
+// meets the item's attunement requirement
+func (o *Orchestrator) AttuneItem(ctx context.Context, input *AttuneItemInput) (*AttuneItemOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument("player ID is required")
+	}
+	if input.ItemID == "" {
+		return nil, errors.InvalidArgument("item ID is required")
+	}
+	if !external.IsMagicItemID(input.ItemID) {
+		return nil, errors.InvalidArgumentf("item %s is not a magic item", input.ItemID)
+	}
+
+	getOutput, err := o.getOwnedCharacter(ctx, input.CharacterID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	charData := getOutput.CharacterData
+	sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+
+	if dnd5e.CountItem(charData.Equipment, input.ItemID) == 0 {
+		return nil, errors.FailedPreconditionf("character does not have item %s", input.ItemID)
+	}
+	if slices.Contains(sheet.AttunedItems, input.ItemID) {
+		return nil, errors.FailedPreconditionf("character is already attuned to %s", input.ItemID)
+	}
+	if len(sheet.AttunedItems) >= MaxAttunedItems {
+		return nil, errors.FailedPreconditionf("character is already attuned to %d items", MaxAttunedItems)
+	}
+
+	item, err := o.externalClient.GetMagicItemData(ctx, input.ItemID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get magic item %s", input.ItemID)
+	}
+	if !item.RequiresAttunement {
+		return nil, errors.FailedPreconditionf("%s does not require attunement", item.Name)
+	}
+	if !meetsAttunementRequirement(charData, item.AttunementRequirement) {
+		return nil, errors.FailedPreconditionf("%s requires attunement %s", item.Name, item.AttunementRequirement)
+	}
+
+	sheet.AttunedItems = append(sheet.AttunedItems, input.ItemID)
+
+	updateOutput, err := o.charRepo.Update(ctx, character.UpdateInput{
+		CharacterData: charData,
+		Sheet:         sheet,
+		EventType:     dnd5e.CharacterEventAttunementChanged,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to attune item")
+	}
+
+	slog.InfoContext(ctx, "attuned to magic item",
+		"character_id", input.CharacterID,
+		"item_id", input.ItemID,
+		"attuned_count", len(sheet.AttunedItems))
+
+	return &AttuneItemOutput{
+		Character:    updateOutput.CharacterData,
+		AttunedItems: sheet.AttunedItems,
+	}, nil
+}
+
+// UnattuneItem ends the character's attunement to a magic item
+func (o *Orchestrator) UnattuneItem(ctx context.Context, input *UnattuneItemInput) (*UnattuneItemOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument("player ID is required")
+	}
+	if input.ItemID == "" {
+		return nil, errors.InvalidArgument("item ID is required")
+	}
+
+	getOutput, err := o.getOwnedCharacter(ctx, input.CharacterID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+
+	index := slices.Index(sheet.AttunedItems, input.ItemID)
+	if index < 0 {
+		return nil, errors.FailedPreconditionf("character is not attuned to %s", input.ItemID)
+	}
+	sheet.AttunedItems = slices.Delete(sheet.AttunedItems, index, index+1)
+
+	updateOutput, err := o.charRepo.Update(ctx, character.UpdateInput{
+		CharacterData: getOutput.CharacterData,
+		Sheet:         sheet,
+		EventType:     dnd5e.CharacterEventAttunementChanged,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to end attunement")
+	}
+
+	return &UnattuneItemOutput{
+		Character:    updateOutput.CharacterData,
+		AttunedItems: sheet.AttunedItems,
+	}, nil
+}
+
+// getCharacterWithSheet loads a character and its sheet, mapping not found errors
+func (o *Orchestrator) getCharacterWithSheet(ctx context.Context, characterID string) (*character.GetOutput, error) {
+	getOutput, err := o.charRepo.Get(ctx, character.GetInput{ID: characterID})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NotFoundf("character %s not found", characterID)
+		}
+		return nil, errors.Wrapf(err, "failed to get character %s", characterID)
+	}
+	return getOutput, nil
+}
+
+// updateAtRevision reads a character, lets apply change it and its sheet, and saves both at
+// the revision it read. If the character was saved by someone else in between, it starts
+// over from a fresh read so it never overwrites that change.
+func (o *Orchestrator) updateAtRevision(
+	ctx context.Context,
+	characterID, eventType string,
+	apply func(charData *toolkitchar.Data, sheet *dnd5e.CharacterSheet) error,
+) (*character.UpdateOutput, error) {
+	for range maxUpdateAttempts {
+		getOutput, err := o.getCharacterWithSheet(ctx, characterID)
+		if err != nil {
+			return nil, err
+		}
+
+		sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+		if err := apply(getOutput.CharacterData, sheet); err != nil {
+			return nil, err
+		}
+
+		updateOutput, err := o.charRepo.Update(ctx, character.UpdateInput{
+			CharacterData:    getOutput.CharacterData,
+			Sheet:            sheet,
+			EventType:        eventType,
+			ExpectedRevision: &getOutput.Revision,
+		})
+		if errors.IsAborted(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return updateOutput, nil
+	}
+	return nil, errors.Abortedf("character %s kept changing, try again", characterID)
+}
+
+// AddToInventory grants items, such as loot or quest rewards, to a character's equipment.
+// Only the DM of a campaign the character is in, or an internal caller, may grant items;
+// players acquire them through the shop. Magic items are looked up in the magic item
+// catalog and everything else in the equipment catalog. Items that cannot be added are
+// reported in Errors and the rest are still added.
+func (o *Orchestrator) AddToInventory(ctx context.Context, input *AddToInventoryInput) (*AddToInventoryOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("character_id", input.CharacterID, vb)
+	if !input.Internal {
+		errors.ValidateRequired("player_id", input.PlayerID, vb)
+	}
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+	if len(input.Items) == 0 {
+		return nil, errors.InvalidArgument("at least one item is required")
+	}
+
+	getOutput, err := o.getCharacterWithSheet(ctx, input.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+	charData := getOutput.CharacterData
+
+	if !input.Internal {
+		isDM, err := o.isCampaignDM(ctx, input.PlayerID, input.CharacterID)
+		if err != nil {
+			return nil, err
+		}
+		if !isDM {
+			return nil, errors.PermissionDeniedf(
+				"player %s is not the DM of a campaign with character %s", input.PlayerID, input.CharacterID)
+		}
+	}
+
+	var added []string
+	var itemErrors []string
+	for _, addition := range input.Items {
+		item := addition.Item
+		if item == nil || item.ID == "" {
+			itemErrors = append(itemErrors, "item ID is required")
+			continue
+		}
+		if item.Quantity <= 0 {
+			itemErrors = append(itemErrors, fmt.Sprintf("%s: quantity must be positive", item.ID))
+			continue
+		}
+		if len(added)+int(item.Quantity) > maxItemsAdded {
+			itemErrors = append(itemErrors, fmt.Sprintf("%s: cannot add more than %d items at once", item.ID, maxItemsAdded))
+			continue
+		}
+		if err := o.checkItemExists(ctx, item.ID); err != nil {
+			itemErrors = append(itemErrors, err.Error())
+			continue
+		}
+		for range item.Quantity {
+			added = append(added, item.ID)
+		}
+	}
+
+	if len(added) == 0 {
+		return &AddToInventoryOutput{
+			Character: charData,
+			Errors:    itemErrors,
+		}, nil
+	}
+
+	charData.Equipment = append(charData.Equipment, added...)
+	updateOutput, err := o.charRepo.Update(ctx, character.UpdateInput{
+		CharacterData: charData,
+		Sheet:         getOutput.Sheet,
+		EventType:     dnd5e.CharacterEventItemsAdded,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to add items to character %s", input.CharacterID)
+	}
+
+	slog.InfoContext(ctx, "added items to inventory",
+		"character_id", input.CharacterID,
+		"items_added", len(added),
+		"errors", len(itemErrors))
+
+	return &AddToInventoryOutput{
+		Success:   len(itemErrors) == 0,
+		Character: updateOutput.CharacterData,
+		Errors:    itemErrors,
+	}, nil
+}
+
+// isCampaignDM reports whether the player owns a campaign the character has joined
+func (o *Orchestrator) isCampaignDM(ctx context.Context, playerID, characterID string) (bool, error) {
+	listOutput, err := o.campaignRepo.ListByMember(ctx, campaignrepo.ListByMemberInput{PlayerID: playerID})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list campaigns for player %s", playerID)
+	}
+	for _, campaign := range listOutput.Campaigns {
+		if campaign.OwnerID == playerID && slices.Contains(campaign.CharacterIDs, characterID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkItemExists looks an item up in the magic item or equipment catalog
+func (o *Orchestrator) checkItemExists(ctx context.Context, itemID string) error {
+	if external.IsMagicItemID(itemID) {
+		_, err := o.externalClient.GetMagicItemData(ctx, itemID)
+		return err
+	}
+	_, err := o.externalClient.GetEquipmentData(ctx, itemID)
+	return err
+}
@@ -0,0 +1,160 @@
+package character
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+)
+
+// SRD magic item categories that decide where an item is worn
+const (
+	magicItemCategoryArmor    = "armor"
+	magicItemCategoryRing     = "ring"
+	magicItemCategoryWondrous = "wondrous-items"
+)
+
+const (
+	armorCategoryShield     = "Shield"
+	attunementBySpellcaster = "spellcaster"
+)
+
+// handSlots are where weapons and anything else held are equipped
+var handSlots = []string{SlotMainHand, SlotOffHand}
+
+// wornItemSlots maps a word in a wondrous item's name to the slot it is worn in
+var wornItemSlots = map[string]string{
+	"cloak":     SlotCloak,
+	"cape":      SlotCloak,
+	"mantle":    SlotCloak,
+	"boots":     SlotBoots,
+	"slippers":  SlotBoots,
+	"gloves":    SlotGloves,
+	"gauntlets": SlotGloves,
+	"bracers":   SlotGloves,
+	"helm":      SlotHelmet,
+	"hat":       SlotHelmet,
+	"cap":       SlotHelmet,
+	"circlet":   SlotHelmet,
+	"headband":  SlotHelmet,
+	"amulet":    SlotAmulet,
+	"necklace":  SlotAmulet,
+	"periapt":   SlotAmulet,
+	"medallion": SlotAmulet,
+	"brooch":    SlotAmulet,
+	"scarab":    SlotAmulet,
+	"belt":      SlotBelt,
+}
+
+// attunementClasses and attunementRaces are the classes and races an attunement
+// requirement such as "by a cleric, druid, or warlock" can name
+var (
+	attunementClasses = []constants.Class{
+		constants.ClassBarbarian, constants.ClassBard, constants.ClassCleric, constants.ClassDruid,
+		constants.ClassFighter, constants.ClassMonk, constants.ClassPaladin, constants.ClassRanger,
+		constants.ClassRogue, constants.ClassSorcerer, constants.ClassWarlock, constants.ClassWizard,
+	}
+	attunementRaces = []constants.Race{
+		constants.RaceHuman, constants.RaceDwarf, constants.RaceElf, constants.RaceHalfling,
+		constants.RaceDragonborn, constants.RaceGnome, constants.RaceHalfElf, constants.RaceHalfOrc,
+		constants.RaceTiefling,
+	}
+)
+
+// itemSlots returns the slots an item can be equipped in. Armor is worn in the armor slot,
+// shields in the off hand, rings on either hand and wondrous items where their name says,
+// such as boots on the feet. Weapons and anything else are held in a hand.
+func (o *Orchestrator) itemSlots(ctx context.Context, itemID string) ([]string, error) {
+	if !external.IsMagicItemID(itemID) {
+		equipment, err := o.externalClient.GetEquipmentData(ctx, itemID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get equipment %s", itemID)
+		}
+		return equipmentSlots(equipment), nil
+	}
+
+	item, err := o.externalClient.GetMagicItemData(ctx, itemID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get magic item %s", itemID)
+	}
+	switch item.Category {
+	case magicItemCategoryRing:
+		return []string{SlotRing1, SlotRing2}, nil
+	case magicItemCategoryArmor:
+		// Variants such as "Chain Mail, +1" fit wherever their mundane armor does
+		if item.BaseEquipmentID != "" {
+			equipment, err := o.externalClient.GetEquipmentData(ctx, item.BaseEquipmentID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get equipment %s", item.BaseEquipmentID)
+			}
+			return equipmentSlots(equipment), nil
+		}
+		if slices.Contains(words(item.Name), "shield") {
+			return []string{SlotOffHand}, nil
+		}
+		return []string{SlotArmor}, nil
+	case magicItemCategoryWondrous:
+		for _, word := range words(item.Name) {
+			if slot, ok := wornItemSlots[word]; ok {
+				return []string{slot}, nil
+			}
+		}
+	}
+	return handSlots, nil
+}
+
+// equipmentSlots returns the slots a piece of mundane equipment can be equipped in
+func equipmentSlots(equipment *external.EquipmentData) []string {
+	if equipment == nil || equipment.ArmorCategory == "" {
+		return handSlots
+	}
+	if strings.EqualFold(equipment.ArmorCategory, armorCategoryShield) {
+		return []string{SlotOffHand}
+	}
+	return []string{SlotArmor}
+}
+
+// meetsAttunementRequirement reports whether a character may attune to an item with the
+// given requirement, such as "by a cleric" or "by a dwarf". Named classes and races are
+// checked and "by a spellcaster" needs spell slots. Anything else, such as an alignment,
+// is left to the DM.
+func meetsAttunementRequirement(charData *toolkitchar.Data, requirement string) bool {
+	if requirement == "" {
+		return true
+	}
+	required := words(requirement)
+	if slices.Contains(required, attunementBySpellcaster) {
+		return highestSpellSlotLevel(charData) > 0
+	}
+
+	named := false
+	for _, class := range attunementClasses {
+		if slices.Contains(required, string(class)) {
+			if charData.ClassID == class {
+				return true
+			}
+			named = true
+		}
+	}
+	for _, race := range attunementRaces {
+		if slices.Contains(required, string(race)) {
+			if charData.RaceID == race {
+				return true
+			}
+			named = true
+		}
+	}
+	return !named
+}
+
+// words splits text into lowercase words, keeping hyphenated words such as "half-elf" whole
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-'
+	})
+}
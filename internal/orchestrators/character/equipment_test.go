@@ -0,0 +1,589 @@
+package character_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	extmock "github.com/KirkDiggler/rpg-api/internal/clients/external/mock"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	dicemock "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice/mock"
+	campaignrepo "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
+	campaignmock "github.com/KirkDiggler/rpg-api/internal/repositories/campaign/mock"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	charmock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
+	draftmock "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft/mock"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+const (
+	cloakOfProtection = "MAGIC_ITEM_CLOAK_OF_PROTECTION"
+	chainMailPlusOne  = "MAGIC_ITEM_CHAIN_MAIL_1"
+)
+
+type EquipmentOrchestratorTestSuite struct {
+	suite.Suite
+	ctrl             *gomock.Controller
+	orchestrator     *character.Orchestrator
+	mockCharRepo     *charmock.MockRepository
+	mockCampaignRepo *campaignmock.MockRepository
+	mockExtClient    *extmock.MockClient
+	ctx              context.Context
+
+	charData *toolkitchar.Data
+}
+
+func (s *EquipmentOrchestratorTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockCharRepo = charmock.NewMockRepository(s.ctrl)
+	s.mockCampaignRepo = campaignmock.NewMockRepository(s.ctrl)
+	s.mockExtClient = extmock.NewMockClient(s.ctrl)
+	s.ctx = context.Background()
+
+	orch, err := character.New(&character.Config{
+		CharacterRepo:      s.mockCharRepo,
+		CharacterDraftRepo: draftmock.NewMockRepository(s.ctrl),
+		CampaignRepo:       s.mockCampaignRepo,
+		ExternalClient:     s.mockExtClient,
+		DiceService:        dicemock.NewMockService(s.ctrl),
+		IDGenerator:        &mockIDGenerator{},
+		DraftIDGenerator:   &mockIDGenerator{},
+	})
+	s.Require().NoError(err)
+	s.orchestrator = orch
+
+	s.charData = &toolkitchar.Data{
+		ID:       "char-1",
+		PlayerID: "player-1",
+		Level:    1,
+		AbilityScores: shared.AbilityScores{
+			constants.STR: 16,
+			constants.DEX: 14,
+			constants.CON: 14,
+			constants.INT: 10,
+			constants.WIS: 12,
+			constants.CHA: 8,
+		},
+		SavingThrows: map[constants.Ability]shared.ProficiencyLevel{
+			constants.STR: shared.Proficient,
+			constants.CON: shared.Proficient,
+		},
+		Equipment: []string{"EQUIPMENT_SHIELD", chainMailPlusOne, cloakOfProtection, "MAGIC_ITEM_RING_OF_PROTECTION"},
+	}
+}
+
+func (s *EquipmentOrchestratorTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *EquipmentOrchestratorTestSuite) expectGet(sheet *dnd5e.CharacterSheet) {
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: "char-1"}).
+		Return(&characterrepo.GetOutput{CharacterData: s.charData, Sheet: sheet}, nil)
+}
+
+func (s *EquipmentOrchestratorTestSuite) expectUpdate(check func(input characterrepo.UpdateInput)) {
+	s.mockCharRepo.EXPECT().
+		Update(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+			check(input)
+			return &characterrepo.UpdateOutput{CharacterData: input.CharacterData, Sheet: input.Sheet}, nil
+		})
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestEquipItem_ReturnsPreviousItem() {
+	s.expectGet(&dnd5e.CharacterSheet{
+		EquippedSlots: map[string]string{character.SlotCloak: "EQUIPMENT_TRAVELERS_CLOAK"},
+	})
+	s.expectMagicItem(&external.MagicItemData{
+		ID:       cloakOfProtection,
+		Name:     "Cloak of Protection",
+		Category: "wondrous-items",
+	})
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Equal(cloakOfProtection, input.Sheet.EquippedSlots[character.SlotCloak])
+	})
+
+	output, err := s.orchestrator.EquipItem(s.ctx, &character.EquipItemInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		ItemID:      cloakOfProtection,
+		Slot:        character.SlotCloak,
+	})
+
+	s.Require().NoError(err)
+	s.True(output.Success)
+	s.Require().NotNil(output.PreviouslyEquippedItem)
+	s.Equal("EQUIPMENT_TRAVELERS_CLOAK", output.PreviouslyEquippedItem.ID)
+}
+
+func (s *EquipmentOrchestratorTestSuite) expectMagicItem(item *external.MagicItemData) {
+	s.mockExtClient.EXPECT().GetMagicItemData(s.ctx, item.ID).Return(item, nil)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestEquipItem_ArmorVariantInArmorSlot() {
+	s.expectGet(nil)
+	s.expectMagicItem(&external.MagicItemData{
+		ID:              chainMailPlusOne,
+		Name:            "Chain Mail, +1",
+		Category:        "armor",
+		BaseEquipmentID: "EQUIPMENT_CHAIN_MAIL",
+	})
+	s.mockExtClient.EXPECT().GetEquipmentData(s.ctx, "EQUIPMENT_CHAIN_MAIL").Return(&external.EquipmentData{
+		ID:            "EQUIPMENT_CHAIN_MAIL",
+		ArmorCategory: "Heavy",
+	}, nil)
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Equal(chainMailPlusOne, input.Sheet.EquippedSlots[character.SlotArmor])
+	})
+
+	output, err := s.orchestrator.EquipItem(s.ctx, &character.EquipItemInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		ItemID:      chainMailPlusOne,
+		Slot:        character.SlotArmor,
+	})
+
+	s.Require().NoError(err)
+	s.True(output.Success)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestEquipItem_WrongSlot() {
+	s.Run("cloak in a hand", func() {
+		s.expectGet(nil)
+		s.expectMagicItem(&external.MagicItemData{
+			ID:       cloakOfProtection,
+			Name:     "Cloak of Protection",
+			Category: "wondrous-items",
+		})
+
+		output, err := s.orchestrator.EquipItem(s.ctx, &character.EquipItemInput{
+			CharacterID: "char-1",
+			PlayerID:    "player-1",
+			ItemID:      cloakOfProtection,
+			Slot:        character.SlotMainHand,
+		})
+
+		s.Nil(output)
+		s.True(errors.IsInvalidArgument(err))
+	})
+
+	s.Run("shield as armor", func() {
+		s.expectGet(nil)
+		s.mockExtClient.EXPECT().GetEquipmentData(s.ctx, "EQUIPMENT_SHIELD").Return(&external.EquipmentData{
+			ID:            "EQUIPMENT_SHIELD",
+			ArmorCategory: "Shield",
+		}, nil)
+
+		output, err := s.orchestrator.EquipItem(s.ctx, &character.EquipItemInput{
+			CharacterID: "char-1",
+			PlayerID:    "player-1",
+			ItemID:      "EQUIPMENT_SHIELD",
+			Slot:        character.SlotArmor,
+		})
+
+		s.Nil(output)
+		s.True(errors.IsInvalidArgument(err))
+	})
+
+	s.Run("ring on a belt", func() {
+		s.expectGet(nil)
+		s.expectMagicItem(&external.MagicItemData{
+			ID:       "MAGIC_ITEM_RING_OF_PROTECTION",
+			Name:     "Ring of Protection",
+			Category: "ring",
+		})
+
+		output, err := s.orchestrator.EquipItem(s.ctx, &character.EquipItemInput{
+			CharacterID: "char-1",
+			PlayerID:    "player-1",
+			ItemID:      "MAGIC_ITEM_RING_OF_PROTECTION",
+			Slot:        character.SlotBelt,
+		})
+
+		s.Nil(output)
+		s.True(errors.IsInvalidArgument(err))
+	})
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestEquipItem_NotOwner() {
+	s.expectGet(nil)
+
+	output, err := s.orchestrator.EquipItem(s.ctx, &character.EquipItemInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-2",
+		ItemID:      cloakOfProtection,
+		Slot:        character.SlotCloak,
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestUnequipItem_NotOwner() {
+	s.expectGet(&dnd5e.CharacterSheet{
+		EquippedSlots: map[string]string{character.SlotCloak: cloakOfProtection},
+	})
+
+	output, err := s.orchestrator.UnequipItem(s.ctx, &character.UnequipItemInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-2",
+		Slot:        character.SlotCloak,
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestEquipItem_NotCarried() {
+	s.expectGet(nil)
+
+	output, err := s.orchestrator.EquipItem(s.ctx, &character.EquipItemInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		ItemID:      "EQUIPMENT_LONGSWORD",
+		Slot:        character.SlotMainHand,
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestEquipItem_InvalidSlot() {
+	output, err := s.orchestrator.EquipItem(s.ctx, &character.EquipItemInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		ItemID:      cloakOfProtection,
+		Slot:        "tail",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestAttuneItem_Success() {
+	s.expectGet(nil)
+	s.mockExtClient.EXPECT().
+		GetMagicItemData(s.ctx, cloakOfProtection).
+		Return(&external.MagicItemData{ID: cloakOfProtection, Name: "Cloak of Protection", RequiresAttunement: true}, nil)
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Equal([]string{cloakOfProtection}, input.Sheet.AttunedItems)
+	})
+
+	output, err := s.orchestrator.AttuneItem(s.ctx, &character.AttuneItemInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		ItemID:      cloakOfProtection,
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]string{cloakOfProtection}, output.AttunedItems)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestAttuneItem_Requirement() {
+	holyAvenger := &external.MagicItemData{
+		ID:                    "MAGIC_ITEM_HOLY_AVENGER",
+		Name:                  "Holy Avenger",
+		RequiresAttunement:    true,
+		AttunementRequirement: "by a paladin",
+	}
+	s.charData.Equipment = append(s.charData.Equipment, holyAvenger.ID)
+
+	s.Run("not met", func() {
+		s.charData.ClassID = constants.ClassFighter
+		s.expectGet(nil)
+		s.expectMagicItem(holyAvenger)
+
+		output, err := s.orchestrator.AttuneItem(s.ctx, &character.AttuneItemInput{
+			CharacterID: "char-1",
+			PlayerID:    "player-1",
+			ItemID:      holyAvenger.ID,
+		})
+
+		s.Nil(output)
+		s.True(errors.IsFailedPrecondition(err))
+		s.Contains(err.Error(), "by a paladin")
+	})
+
+	s.Run("met", func() {
+		s.charData.ClassID = constants.ClassPaladin
+		s.expectGet(nil)
+		s.expectMagicItem(holyAvenger)
+		s.expectUpdate(func(input characterrepo.UpdateInput) {
+			s.Equal([]string{holyAvenger.ID}, input.Sheet.AttunedItems)
+		})
+
+		output, err := s.orchestrator.AttuneItem(s.ctx, &character.AttuneItemInput{
+			CharacterID: "char-1",
+			PlayerID:    "player-1",
+			ItemID:      holyAvenger.ID,
+		})
+
+		s.Require().NoError(err)
+		s.Equal([]string{holyAvenger.ID}, output.AttunedItems)
+	})
+
+	s.Run("spellcaster", func() {
+		s.charData.ClassID = constants.ClassFighter
+		s.expectGet(nil)
+		s.expectMagicItem(&external.MagicItemData{
+			ID:                    holyAvenger.ID,
+			Name:                  "Holy Avenger",
+			RequiresAttunement:    true,
+			AttunementRequirement: "by a spellcaster",
+		})
+
+		output, err := s.orchestrator.AttuneItem(s.ctx, &character.AttuneItemInput{
+			CharacterID: "char-1",
+			PlayerID:    "player-1",
+			ItemID:      holyAvenger.ID,
+		})
+
+		s.Nil(output)
+		s.True(errors.IsFailedPrecondition(err))
+	})
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestAttuneItem_NotOwner() {
+	s.expectGet(nil)
+
+	output, err := s.orchestrator.AttuneItem(s.ctx, &character.AttuneItemInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-2",
+		ItemID:      cloakOfProtection,
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestUnattuneItem_NotOwner() {
+	s.expectGet(&dnd5e.CharacterSheet{AttunedItems: []string{cloakOfProtection}})
+
+	output, err := s.orchestrator.UnattuneItem(s.ctx, &character.UnattuneItemInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-2",
+		ItemID:      cloakOfProtection,
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestAttuneItem_LimitReached() {
+	s.expectGet(&dnd5e.CharacterSheet{
+		AttunedItems: []string{"MAGIC_ITEM_A", "MAGIC_ITEM_B", "MAGIC_ITEM_C"},
+	})
+
+	output, err := s.orchestrator.AttuneItem(s.ctx, &character.AttuneItemInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		ItemID:      cloakOfProtection,
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestAttuneItem_NotRequired() {
+	s.expectGet(nil)
+	s.mockExtClient.EXPECT().
+		GetMagicItemData(s.ctx, chainMailPlusOne).
+		Return(&external.MagicItemData{ID: chainMailPlusOne, Name: "Chain Mail, +1"}, nil)
+
+	output, err := s.orchestrator.AttuneItem(s.ctx, &character.AttuneItemInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		ItemID:      chainMailPlusOne,
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestUnattuneItem_NotAttuned() {
+	s.expectGet(nil)
+
+	output, err := s.orchestrator.UnattuneItem(s.ctx, &character.UnattuneItemInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		ItemID:      cloakOfProtection,
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacter_UnarmoredDerivedStats() {
+	s.expectGet(nil)
+
+	output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "char-1", ViewerID: "player-1"})
+
+	s.Require().NoError(err)
+	s.Require().NotNil(output.DerivedStats)
+	s.Equal(int32(12), output.DerivedStats.ArmorClass)
+	s.Equal(int32(2), output.DerivedStats.ProficiencyBonus)
+	s.Equal(int32(0), output.DerivedStats.SavingThrowBonus)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacter_MagicItemsFeedDerivedStats() {
+	s.expectGet(&dnd5e.CharacterSheet{
+		EquippedSlots: map[string]string{
+			character.SlotArmor:   chainMailPlusOne,
+			character.SlotOffHand: "EQUIPMENT_SHIELD",
+			character.SlotCloak:   cloakOfProtection,
+			character.SlotRing1:   "MAGIC_ITEM_RING_OF_PROTECTION",
+		},
+		// Only the cloak is attuned, so the ring grants nothing
+		AttunedItems: []string{cloakOfProtection},
+	})
+
+	chainMail := &external.MagicItemData{
+		ID:              chainMailPlusOne,
+		BaseEquipmentID: "EQUIPMENT_CHAIN_MAIL",
+		Effects:         &external.MagicItemEffects{ACBonus: 1},
+	}
+	s.mockExtClient.EXPECT().GetMagicItemData(s.ctx, chainMailPlusOne).Return(chainMail, nil).Times(2)
+	s.mockExtClient.EXPECT().GetMagicItemData(s.ctx, cloakOfProtection).Return(&external.MagicItemData{
+		ID:                 cloakOfProtection,
+		RequiresAttunement: true,
+		Effects:            &external.MagicItemEffects{ACBonus: 1, SavingThrowBonus: 1},
+	}, nil)
+	s.mockExtClient.EXPECT().GetMagicItemData(s.ctx, "MAGIC_ITEM_RING_OF_PROTECTION").Return(&external.MagicItemData{
+		ID:                 "MAGIC_ITEM_RING_OF_PROTECTION",
+		RequiresAttunement: true,
+		Effects:            &external.MagicItemEffects{ACBonus: 1, SavingThrowBonus: 1},
+	}, nil)
+	s.mockExtClient.EXPECT().GetEquipmentData(s.ctx, "EQUIPMENT_CHAIN_MAIL").Return(&external.EquipmentData{
+		ID:            "EQUIPMENT_CHAIN_MAIL",
+		ArmorCategory: "Heavy",
+		ArmorClass:    &external.ArmorClassData{Base: 16},
+	}, nil)
+	s.mockExtClient.EXPECT().GetEquipmentData(s.ctx, "EQUIPMENT_SHIELD").Return(&external.EquipmentData{
+		ID:            "EQUIPMENT_SHIELD",
+		ArmorCategory: "Shield",
+		ArmorClass:    &external.ArmorClassData{Base: 2},
+	}, nil).Times(2)
+
+	output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "char-1", ViewerID: "player-1"})
+
+	s.Require().NoError(err)
+	// 16 chain mail + 2 shield + 1 armor bonus + 1 cloak
+	s.Equal(int32(20), output.DerivedStats.ArmorClass)
+	// Only the attuned cloak adds to saving throws
+	s.Equal(int32(1), output.DerivedStats.SavingThrowBonus)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestGetCharacter_AttunedItemMustBeEquipped() {
+	s.expectGet(&dnd5e.CharacterSheet{
+		// Attuned, but the cloak is in the pack rather than worn
+		AttunedItems: []string{cloakOfProtection},
+	})
+
+	output, err := s.orchestrator.GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "char-1", ViewerID: "player-1"})
+
+	s.Require().NoError(err)
+	s.Equal(int32(12), output.DerivedStats.ArmorClass)
+	s.Equal(int32(0), output.DerivedStats.SavingThrowBonus)
+}
+
+func (s *EquipmentOrchestratorTestSuite) expectDMCampaigns(playerID string, campaigns ...*campaignrepo.Campaign) {
+	s.mockCampaignRepo.EXPECT().
+		ListByMember(s.ctx, campaignrepo.ListByMemberInput{PlayerID: playerID}).
+		Return(&campaignrepo.ListByMemberOutput{Campaigns: campaigns}, nil)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestAddToInventory_AddsMagicItems() {
+	s.charData.Equipment = nil
+	s.expectGet(nil)
+	s.expectDMCampaigns("dm-1", &campaignrepo.Campaign{ID: "campaign-1", OwnerID: "dm-1", CharacterIDs: []string{"char-1"}})
+	s.mockExtClient.EXPECT().GetMagicItemData(s.ctx, cloakOfProtection).Return(&external.MagicItemData{
+		ID:                 cloakOfProtection,
+		RequiresAttunement: true,
+	}, nil)
+	s.mockExtClient.EXPECT().GetEquipmentData(s.ctx, "EQUIPMENT_TORCH").Return(&external.EquipmentData{
+		ID: "EQUIPMENT_TORCH",
+	}, nil)
+	s.mockExtClient.EXPECT().GetEquipmentData(s.ctx, "EQUIPMENT_UNKNOWN").Return(nil, errors.Internal("not in the SRD"))
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Equal([]string{cloakOfProtection, "EQUIPMENT_TORCH", "EQUIPMENT_TORCH"}, input.CharacterData.Equipment)
+		s.Equal(dnd5e.CharacterEventItemsAdded, input.EventType)
+	})
+
+	output, err := s.orchestrator.AddToInventory(s.ctx, &character.AddToInventoryInput{
+		CharacterID: "char-1",
+		PlayerID:    "dm-1",
+		Items: []character.InventoryAddition{
+			{Item: &dnd5e.InventoryItem{ID: cloakOfProtection, Quantity: 1}},
+			{Item: &dnd5e.InventoryItem{ID: "EQUIPMENT_TORCH", Quantity: 2}},
+			{Item: &dnd5e.InventoryItem{ID: "EQUIPMENT_UNKNOWN", Quantity: 1}},
+		},
+	})
+
+	s.Require().NoError(err)
+	s.False(output.Success)
+	s.Len(output.Errors, 1)
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestAddToInventory_OwnerCannotGrantThemselvesItems() {
+	s.expectGet(nil)
+	s.expectDMCampaigns("player-1", &campaignrepo.Campaign{ID: "campaign-1", OwnerID: "dm-1", CharacterIDs: []string{"char-1"}})
+
+	output, err := s.orchestrator.AddToInventory(s.ctx, &character.AddToInventoryInput{
+		CharacterID: "char-1",
+		PlayerID:    "player-1",
+		Items:       []character.InventoryAddition{{Item: &dnd5e.InventoryItem{ID: cloakOfProtection, Quantity: 1}}},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestAddToInventory_DMOfAnotherCampaign() {
+	s.expectGet(nil)
+	s.expectDMCampaigns("dm-2", &campaignrepo.Campaign{ID: "campaign-2", OwnerID: "dm-2"})
+
+	output, err := s.orchestrator.AddToInventory(s.ctx, &character.AddToInventoryInput{
+		CharacterID: "char-1",
+		PlayerID:    "dm-2",
+		Items:       []character.InventoryAddition{{Item: &dnd5e.InventoryItem{ID: "EQUIPMENT_TORCH", Quantity: 1}}},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *EquipmentOrchestratorTestSuite) TestAddToInventory_InternalCallerCapsQuantity() {
+	s.charData.Equipment = nil
+	s.expectGet(nil)
+	s.mockExtClient.EXPECT().GetEquipmentData(s.ctx, "EQUIPMENT_TORCH").Return(&external.EquipmentData{
+		ID: "EQUIPMENT_TORCH",
+	}, nil)
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Len(input.CharacterData.Equipment, 5)
+	})
+
+	output, err := s.orchestrator.AddToInventory(s.ctx, &character.AddToInventoryInput{
+		CharacterID: "char-1",
+		Internal:    true,
+		Items: []character.InventoryAddition{
+			{Item: &dnd5e.InventoryItem{ID: "EQUIPMENT_TORCH", Quantity: 5}},
+			{Item: &dnd5e.InventoryItem{ID: "EQUIPMENT_ARROW", Quantity: 1_000_000}},
+		},
+	})
+
+	s.Require().NoError(err)
+	s.False(output.Success)
+	s.Len(output.Errors, 1)
+}
+
+func TestEquipmentOrchestratorTestSuite(t *testing.T) {
+	suite.Run(t, new(EquipmentOrchestratorTestSuite))
+}
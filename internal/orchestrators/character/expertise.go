@@ -0,0 +1,92 @@
+package character
+
+import (
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+const (
+	// ChoiceExpertise is the draft choice category for Expertise selections.
+	// Selections are made in SkillSelection.
+	ChoiceExpertise shared.ChoiceCategory = "expertise"
+
+	// ExpertiseThievesTools can be selected for Expertise in place of a skill
+	ExpertiseThievesTools constants.Skill = "thieves-tools"
+
+	thievesToolsName = "Thieves' Tools"
+)
+
+// expertiseAllowance returns how many Expertise selections a class has at a level. Bards
+// also gain Expertise, but not until level 3, which drafts never reach.
+func expertiseAllowance(classID constants.Class, level int) int {
+	if classID != constants.ClassRogue {
+		return 0
+	}
+	// Rogues choose two at level 1 and two more at level 6
+	if level >= 6 {
+		return 4
+	}
+	return 2
+}
+
+// applyExpertise validates the draft's Expertise selections and raises the chosen skills to
+// Expertise. Classes with Expertise must make every selection. It must run after all skill
+// and tool proficiencies are applied, since Expertise can only be chosen for a proficiency
+// the character already has. Tool Expertise is returned
+// separately because the toolkit character data only tracks skill proficiency levels.
+func applyExpertise(characterData *toolkitchar.Data, choices []toolkitchar.ChoiceData) ([]string, error) {
+	var selections []constants.Skill
+	for _, choice := range choices {
+		if choice.Category == ChoiceExpertise {
+			selections = append(selections, choice.SkillSelection...)
+		}
+	}
+
+	allowed := expertiseAllowance(characterData.ClassID, characterData.Level)
+	if len(selections) > allowed {
+		return nil, errors.InvalidArgumentf("%s can choose %d expertise at level %d, got %d",
+			characterData.ClassID, allowed, characterData.Level, len(selections))
+	}
+	if len(selections) < allowed {
+		return nil, errors.InvalidArgumentf("draft is incomplete: %s must choose %d expertise, got %d",
+			characterData.ClassID, allowed, len(selections))
+	}
+	if len(selections) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[constants.Skill]bool, len(selections))
+	var toolExpertise []string
+	for _, selection := range selections {
+		if seen[selection] {
+			return nil, errors.InvalidArgumentf("expertise in %s chosen more than once", selection)
+		}
+		seen[selection] = true
+
+		if selection == ExpertiseThievesTools {
+			if !dnd5e.HasTool(characterData.Proficiencies.Tools, thievesToolsName) {
+				return nil, errors.InvalidArgument("expertise requires proficiency with thieves' tools")
+			}
+			toolExpertise = append(toolExpertise, thievesToolsName)
+			continue
+		}
+
+		if selection.Ability() == "" {
+			return nil, errors.InvalidArgumentf("invalid expertise skill: %s", selection)
+		}
+		if characterData.Skills[selection] < shared.Proficient {
+			return nil, errors.InvalidArgumentf("expertise requires proficiency in %s", selection)
+		}
+	}
+
+	for _, selection := range selections {
+		if selection != ExpertiseThievesTools {
+			characterData.Skills[selection] = shared.Expertise
+		}
+	}
+
+	return toolExpertise, nil
+}
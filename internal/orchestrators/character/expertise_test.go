@@ -0,0 +1,186 @@
+package character_test
+
+import (
+	"context"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	charrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	draftrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/class"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/race"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+// setupRogueDraft mocks a halfling rogue draft proficient in Stealth, Acrobatics and thieves' tools
+func (s *FinalizeDraftOrchestratorTestSuite) setupRogueDraft(classID constants.Class, expertise ...constants.Skill) {
+	s.mockIDGen.EXPECT().Generate().Return("char-rogue")
+
+	draft := &toolkitchar.DraftData{
+		ID:          "draft-rogue",
+		PlayerID:    "player-rogue",
+		Name:        "Lightfoot",
+		RaceChoice:  toolkitchar.RaceChoice{RaceID: constants.RaceHalfling},
+		ClassChoice: toolkitchar.ClassChoice{ClassID: classID},
+		AbilityScoreChoice: shared.AbilityScores{
+			constants.STR: 8,
+			constants.DEX: 17,
+			constants.CON: 12,
+			constants.INT: 13,
+			constants.WIS: 12,
+			constants.CHA: 10,
+		},
+		Choices: []toolkitchar.ChoiceData{
+			{
+				Category:       shared.ChoiceSkills,
+				Source:         shared.SourceClass,
+				ChoiceID:       "rogue_skills",
+				SkillSelection: []constants.Skill{constants.SkillStealth, constants.SkillAcrobatics},
+			},
+			{
+				Category:       character.ChoiceExpertise,
+				Source:         shared.SourceClass,
+				ChoiceID:       "rogue_expertise",
+				SkillSelection: expertise,
+			},
+		},
+	}
+
+	s.mockDraftRepo.EXPECT().
+		Get(gomock.Any(), draftrepo.GetInput{ID: draft.ID}).
+		Return(&draftrepo.GetOutput{Draft: draft}, nil)
+	s.mockExtClient.EXPECT().
+		GetRaceData(gomock.Any(), string(constants.RaceHalfling)).
+		Return(&external.RaceDataOutput{
+			RaceData: &race.Data{ID: constants.RaceHalfling, Speed: 25, Size: "Small"},
+		}, nil)
+	s.mockExtClient.EXPECT().
+		GetClassData(gomock.Any(), string(classID)).
+		Return(&external.ClassDataOutput{
+			ClassData: &class.Data{
+				ID:                classID,
+				HitDice:           8,
+				SavingThrows:      []constants.Ability{constants.DEX, constants.INT},
+				ToolProficiencies: []string{"Thieves' Tools"},
+			},
+		}, nil)
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_RogueExpertise() {
+	s.setupRogueDraft(constants.ClassRogue, constants.SkillStealth, character.ExpertiseThievesTools)
+
+	s.mockCharRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input charrepo.CreateInput) (*charrepo.CreateOutput, error) {
+			s.Equal(shared.Expertise, input.CharacterData.Skills[constants.SkillStealth])
+			s.Equal(shared.Proficient, input.CharacterData.Skills[constants.SkillAcrobatics])
+			s.Contains(input.CharacterData.Proficiencies.Tools, "Thieves' Tools")
+			s.Require().NotNil(input.Sheet)
+			s.Equal([]string{"Thieves' Tools"}, input.Sheet.ToolExpertise)
+			return &charrepo.CreateOutput{CharacterData: input.CharacterData, Sheet: input.Sheet}, nil
+		})
+	s.mockDraftRepo.EXPECT().
+		Delete(gomock.Any(), draftrepo.DeleteInput{ID: "draft-rogue"}).
+		Return(&draftrepo.DeleteOutput{}, nil)
+
+	output, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-rogue"})
+
+	s.Require().NoError(err)
+	s.Equal(shared.Expertise, output.Character.Skills[constants.SkillStealth])
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_ExpertiseRequiresProficiency() {
+	s.setupRogueDraft(constants.ClassRogue, constants.SkillStealth, constants.SkillArcana)
+
+	output, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-rogue"})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+	s.Contains(err.Error(), "arcana")
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_ExpertiseNotGrantedByClass() {
+	// Bards do not gain Expertise until level 3, which drafts never reach
+	s.setupRogueDraft(constants.ClassBard, constants.SkillStealth)
+
+	output, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-rogue"})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_RequiresExpertise() {
+	s.setupRogueDraft(constants.ClassRogue)
+
+	output, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-rogue"})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+	s.Contains(err.Error(), "draft is incomplete")
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_TooFewExpertiseChoices() {
+	s.setupRogueDraft(constants.ClassRogue, constants.SkillStealth)
+
+	output, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-rogue"})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestUpdateClass_BardHasNoExpertiseChoice() {
+	s.mockDraftRepo.EXPECT().
+		Get(gomock.Any(), draftrepo.GetInput{ID: "draft-bard"}).
+		Return(&draftrepo.GetOutput{Draft: &toolkitchar.DraftData{ID: "draft-bard"}}, nil)
+	s.mockDraftRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input draftrepo.UpdateInput) (*draftrepo.UpdateOutput, error) {
+			return &draftrepo.UpdateOutput{Draft: input.Draft}, nil
+		})
+
+	output, err := s.orchestrator.UpdateClass(s.ctx, &character.UpdateClassInput{
+		DraftID: "draft-bard",
+		ClassID: constants.ClassBard,
+	})
+
+	s.Require().NoError(err)
+	for _, choice := range output.Draft.Choices {
+		s.NotEqual(character.ChoiceExpertise, choice.Category)
+	}
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_TooManyExpertiseChoices() {
+	s.setupRogueDraft(constants.ClassRogue,
+		constants.SkillStealth, constants.SkillAcrobatics, character.ExpertiseThievesTools)
+
+	output, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-rogue"})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestUpdateClass_RogueAddsExpertiseChoice() {
+	s.mockDraftRepo.EXPECT().
+		Get(gomock.Any(), draftrepo.GetInput{ID: "draft-rogue"}).
+		Return(&draftrepo.GetOutput{Draft: &toolkitchar.DraftData{ID: "draft-rogue"}}, nil)
+	s.mockDraftRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input draftrepo.UpdateInput) (*draftrepo.UpdateOutput, error) {
+			return &draftrepo.UpdateOutput{Draft: input.Draft}, nil
+		})
+
+	output, err := s.orchestrator.UpdateClass(s.ctx, &character.UpdateClassInput{
+		DraftID: "draft-rogue",
+		ClassID: constants.ClassRogue,
+	})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.Draft.Choices, 1)
+	s.Equal(character.ChoiceExpertise, output.Draft.Choices[0].Category)
+	s.Equal("rogue_expertise", output.Draft.Choices[0].ChoiceID)
+}
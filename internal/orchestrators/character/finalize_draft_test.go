@@ -13,6 +13,7 @@ import (
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
 	dicemock "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice/mock"
 	idgenmock "github.com/KirkDiggler/rpg-api/internal/pkg/idgen/mock"
+	campaignmock "github.com/KirkDiggler/rpg-api/internal/repositories/campaign/mock"
 	charrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
 	charmock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
 	draftrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft"
@@ -51,6 +52,7 @@ func (s *FinalizeDraftOrchestratorTestSuite) SetupTest() {
 	cfg := &character.Config{
 		CharacterRepo:      s.mockCharRepo,
 		CharacterDraftRepo: s.mockDraftRepo,
+		CampaignRepo:       campaignmock.NewMockRepository(s.ctrl),
 		ExternalClient:     s.mockExtClient,
 		DiceService:        s.mockDiceService,
 		IDGenerator:        s.mockIDGen,
@@ -92,6 +94,7 @@ func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_Success() {
 			constants.CHA: 8,
 		},
 		Choices: []toolkitchar.ChoiceData{
+			fightingStyleChoice(character.FightingStyleDefense),
 			{
 				Category:       shared.ChoiceSkills,
 				Source:         shared.SourceClass,
@@ -238,6 +241,7 @@ func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_SuccessWithoutBac
 			constants.CHA: 8,
 		},
 		Choices: []toolkitchar.ChoiceData{
+			fightingStyleChoice(character.FightingStyleDefense),
 			{
 				Category:       shared.ChoiceSkills,
 				Source:         shared.SourceClass,
@@ -482,6 +486,7 @@ func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_DraftDeleteFails(
 			constants.WIS: 12,
 			constants.CHA: 8,
 		},
+		Choices: []toolkitchar.ChoiceData{fightingStyleChoice(character.FightingStyleDefense)},
 	}
 
 	// Mock the Get call
@@ -698,6 +703,7 @@ func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_HillDwarfWithHPBo
 			constants.WIS: 15,
 			constants.CHA: 14,
 		},
+		Choices: []toolkitchar.ChoiceData{subclassChoice(constants.ClassCleric, character.SubclassLifeDomain)},
 	}
 
 	// Mock the Get call
@@ -817,6 +823,7 @@ func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_RacialSkillConfli
 			constants.CHA: 8,
 		},
 		Choices: []toolkitchar.ChoiceData{
+			fightingStyleChoice(character.FightingStyleDefense),
 			{
 				Category:       shared.ChoiceSkills,
 				Source:         shared.SourceClass,
@@ -1224,6 +1231,7 @@ func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_WarlockPactMagic(
 			constants.CON: 14,
 			constants.CHA: 16,
 		},
+		Choices: []toolkitchar.ChoiceData{subclassChoice(constants.ClassWarlock, character.SubclassFiend)},
 	}
 
 	// Mock draft retrieval
@@ -12,6 +12,7 @@ import (
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
 	dicemock "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice/mock"
 	idgenmock "github.com/KirkDiggler/rpg-api/internal/pkg/idgen/mock"
+	campaignmock "github.com/KirkDiggler/rpg-api/internal/repositories/campaign/mock"
 	charrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
 	charmock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
 	draftrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft"
@@ -49,6 +50,7 @@ func (s *FinalizeGapsTestSuite) SetupTest() {
 	cfg := &character.Config{
 		CharacterRepo:      s.mockCharRepo,
 		CharacterDraftRepo: s.mockDraftRepo,
+		CampaignRepo:       campaignmock.NewMockRepository(s.ctrl),
 		ExternalClient:     s.mockExtClient,
 		DiceService:        s.mockDiceService,
 		IDGenerator:        s.mockIDGen,
@@ -93,6 +95,7 @@ func (s *FinalizeGapsTestSuite) TestGaps_BackgroundData() {
 			constants.CHA: 10,
 		},
 		Choices: []toolkitchar.ChoiceData{
+			fightingStyleChoice(character.FightingStyleDefense),
 			{
 				Category: shared.ChoiceSkills,
 				Source:   shared.SourceBackground,
@@ -456,6 +459,7 @@ func (s *FinalizeGapsTestSuite) TestGaps_ToolProficiencies() {
 			constants.CHA: 8,
 		},
 		Choices: []toolkitchar.ChoiceData{
+			fightingStyleChoice(character.FightingStyleDefense),
 			{
 				// TODO: ChoiceToolProficiency doesn't exist yet
 				// Category: shared.ChoiceToolProficiency,
@@ -57,6 +57,51 @@ func (mr *MockServiceMockRecorder) AddToInventory(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToInventory", reflect.TypeOf((*MockService)(nil).AddToInventory), ctx, input)
 }
 
+// AttuneItem mocks base method.
+func (m *MockService) AttuneItem(ctx context.Context, input *character.AttuneItemInput) (*character.AttuneItemOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttuneItem", ctx, input)
+	ret0, _ := ret[0].(*character.AttuneItemOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttuneItem indicates an expected call of AttuneItem.
+func (mr *MockServiceMockRecorder) AttuneItem(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttuneItem", reflect.TypeOf((*MockService)(nil).AttuneItem), ctx, input)
+}
+
+// CanCastSpell mocks base method.
+func (m *MockService) CanCastSpell(ctx context.Context, input *character.CanCastSpellInput) (*character.CanCastSpellOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CanCastSpell", ctx, input)
+	ret0, _ := ret[0].(*character.CanCastSpellOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CanCastSpell indicates an expected call of CanCastSpell.
+func (mr *MockServiceMockRecorder) CanCastSpell(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanCastSpell", reflect.TypeOf((*MockService)(nil).CanCastSpell), ctx, input)
+}
+
+// CopySpellToSpellbook mocks base method.
+func (m *MockService) CopySpellToSpellbook(ctx context.Context, input *character.CopySpellToSpellbookInput) (*character.CopySpellToSpellbookOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopySpellToSpellbook", ctx, input)
+	ret0, _ := ret[0].(*character.CopySpellToSpellbookOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopySpellToSpellbook indicates an expected call of CopySpellToSpellbook.
+func (mr *MockServiceMockRecorder) CopySpellToSpellbook(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopySpellToSpellbook", reflect.TypeOf((*MockService)(nil).CopySpellToSpellbook), ctx, input)
+}
+
 // CreateDraft mocks base method.
 func (m *MockService) CreateDraft(ctx context.Context, input *character.CreateDraftInput) (*character.CreateDraftOutput, error) {
 	m.ctrl.T.Helper()
@@ -162,6 +207,21 @@ func (mr *MockServiceMockRecorder) GetCharacter(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCharacter", reflect.TypeOf((*MockService)(nil).GetCharacter), ctx, input)
 }
 
+// GetCharacterAtTime mocks base method.
+func (m *MockService) GetCharacterAtTime(ctx context.Context, input *character.GetCharacterAtTimeInput) (*character.GetCharacterAtTimeOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCharacterAtTime", ctx, input)
+	ret0, _ := ret[0].(*character.GetCharacterAtTimeOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCharacterAtTime indicates an expected call of GetCharacterAtTime.
+func (mr *MockServiceMockRecorder) GetCharacterAtTime(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCharacterAtTime", reflect.TypeOf((*MockService)(nil).GetCharacterAtTime), ctx, input)
+}
+
 // GetCharacterInventory mocks base method.
 func (m *MockService) GetCharacterInventory(ctx context.Context, input *character.GetCharacterInventoryInput) (*character.GetCharacterInventoryOutput, error) {
 	m.ctrl.T.Helper()
@@ -177,6 +237,21 @@ func (mr *MockServiceMockRecorder) GetCharacterInventory(ctx, input any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCharacterInventory", reflect.TypeOf((*MockService)(nil).GetCharacterInventory), ctx, input)
 }
 
+// GetCharacterTimeline mocks base method.
+func (m *MockService) GetCharacterTimeline(ctx context.Context, input *character.GetCharacterTimelineInput) (*character.GetCharacterTimelineOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCharacterTimeline", ctx, input)
+	ret0, _ := ret[0].(*character.GetCharacterTimelineOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCharacterTimeline indicates an expected call of GetCharacterTimeline.
+func (mr *MockServiceMockRecorder) GetCharacterTimeline(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCharacterTimeline", reflect.TypeOf((*MockService)(nil).GetCharacterTimeline), ctx, input)
+}
+
 // GetClassDetails mocks base method.
 func (m *MockService) GetClassDetails(ctx context.Context, input *character.GetClassDetailsInput) (*character.GetClassDetailsOutput, error) {
 	m.ctrl.T.Helper()
@@ -252,6 +327,66 @@ func (mr *MockServiceMockRecorder) GetRaceDetails(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRaceDetails", reflect.TypeOf((*MockService)(nil).GetRaceDetails), ctx, input)
 }
 
+// HardDeleteCharacter mocks base method.
+func (m *MockService) HardDeleteCharacter(ctx context.Context, input *character.HardDeleteCharacterInput) (*character.HardDeleteCharacterOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HardDeleteCharacter", ctx, input)
+	ret0, _ := ret[0].(*character.HardDeleteCharacterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HardDeleteCharacter indicates an expected call of HardDeleteCharacter.
+func (mr *MockServiceMockRecorder) HardDeleteCharacter(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HardDeleteCharacter", reflect.TypeOf((*MockService)(nil).HardDeleteCharacter), ctx, input)
+}
+
+// JoinSession mocks base method.
+func (m *MockService) JoinSession(ctx context.Context, input *character.JoinSessionInput) (*character.JoinSessionOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinSession", ctx, input)
+	ret0, _ := ret[0].(*character.JoinSessionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JoinSession indicates an expected call of JoinSession.
+func (mr *MockServiceMockRecorder) JoinSession(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinSession", reflect.TypeOf((*MockService)(nil).JoinSession), ctx, input)
+}
+
+// LearnSpells mocks base method.
+func (m *MockService) LearnSpells(ctx context.Context, input *character.LearnSpellsInput) (*character.LearnSpellsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LearnSpells", ctx, input)
+	ret0, _ := ret[0].(*character.LearnSpellsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LearnSpells indicates an expected call of LearnSpells.
+func (mr *MockServiceMockRecorder) LearnSpells(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LearnSpells", reflect.TypeOf((*MockService)(nil).LearnSpells), ctx, input)
+}
+
+// LeaveSession mocks base method.
+func (m *MockService) LeaveSession(ctx context.Context, input *character.LeaveSessionInput) (*character.LeaveSessionOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaveSession", ctx, input)
+	ret0, _ := ret[0].(*character.LeaveSessionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LeaveSession indicates an expected call of LeaveSession.
+func (mr *MockServiceMockRecorder) LeaveSession(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveSession", reflect.TypeOf((*MockService)(nil).LeaveSession), ctx, input)
+}
+
 // ListBackgrounds mocks base method.
 func (m *MockService) ListBackgrounds(ctx context.Context, input *character.ListBackgroundsInput) (*character.ListBackgroundsOutput, error) {
 	m.ctrl.T.Helper()
@@ -312,6 +447,21 @@ func (mr *MockServiceMockRecorder) ListClasses(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClasses", reflect.TypeOf((*MockService)(nil).ListClasses), ctx, input)
 }
 
+// ListDeletedCharacters mocks base method.
+func (m *MockService) ListDeletedCharacters(ctx context.Context, input *character.ListDeletedCharactersInput) (*character.ListDeletedCharactersOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedCharacters", ctx, input)
+	ret0, _ := ret[0].(*character.ListDeletedCharactersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedCharacters indicates an expected call of ListDeletedCharacters.
+func (mr *MockServiceMockRecorder) ListDeletedCharacters(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedCharacters", reflect.TypeOf((*MockService)(nil).ListDeletedCharacters), ctx, input)
+}
+
 // ListDrafts mocks base method.
 func (m *MockService) ListDrafts(ctx context.Context, input *character.ListDraftsInput) (*character.ListDraftsOutput, error) {
 	m.ctrl.T.Helper()
@@ -357,6 +507,21 @@ func (mr *MockServiceMockRecorder) ListRaces(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRaces", reflect.TypeOf((*MockService)(nil).ListRaces), ctx, input)
 }
 
+// ListSessionCharacters mocks base method.
+func (m *MockService) ListSessionCharacters(ctx context.Context, input *character.ListSessionCharactersInput) (*character.ListSessionCharactersOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessionCharacters", ctx, input)
+	ret0, _ := ret[0].(*character.ListSessionCharactersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessionCharacters indicates an expected call of ListSessionCharacters.
+func (mr *MockServiceMockRecorder) ListSessionCharacters(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessionCharacters", reflect.TypeOf((*MockService)(nil).ListSessionCharacters), ctx, input)
+}
+
 // ListSpellsByLevel mocks base method.
 func (m *MockService) ListSpellsByLevel(ctx context.Context, input *character.ListSpellsByLevelInput) (*character.ListSpellsByLevelOutput, error) {
 	m.ctrl.T.Helper()
@@ -372,6 +537,51 @@ func (mr *MockServiceMockRecorder) ListSpellsByLevel(ctx, input any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSpellsByLevel", reflect.TypeOf((*MockService)(nil).ListSpellsByLevel), ctx, input)
 }
 
+// LongRest mocks base method.
+func (m *MockService) LongRest(ctx context.Context, input *character.LongRestInput) (*character.LongRestOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LongRest", ctx, input)
+	ret0, _ := ret[0].(*character.LongRestOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LongRest indicates an expected call of LongRest.
+func (mr *MockServiceMockRecorder) LongRest(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LongRest", reflect.TypeOf((*MockService)(nil).LongRest), ctx, input)
+}
+
+// PrepareSpells mocks base method.
+func (m *MockService) PrepareSpells(ctx context.Context, input *character.PrepareSpellsInput) (*character.PrepareSpellsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrepareSpells", ctx, input)
+	ret0, _ := ret[0].(*character.PrepareSpellsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PrepareSpells indicates an expected call of PrepareSpells.
+func (mr *MockServiceMockRecorder) PrepareSpells(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareSpells", reflect.TypeOf((*MockService)(nil).PrepareSpells), ctx, input)
+}
+
+// PurgeDeletedCharacters mocks base method.
+func (m *MockService) PurgeDeletedCharacters(ctx context.Context, input *character.PurgeDeletedCharactersInput) (*character.PurgeDeletedCharactersOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedCharacters", ctx, input)
+	ret0, _ := ret[0].(*character.PurgeDeletedCharactersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedCharacters indicates an expected call of PurgeDeletedCharacters.
+func (mr *MockServiceMockRecorder) PurgeDeletedCharacters(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedCharacters", reflect.TypeOf((*MockService)(nil).PurgeDeletedCharacters), ctx, input)
+}
+
 // RemoveFromInventory mocks base method.
 func (m *MockService) RemoveFromInventory(ctx context.Context, input *character.RemoveFromInventoryInput) (*character.RemoveFromInventoryOutput, error) {
 	m.ctrl.T.Helper()
@@ -387,6 +597,51 @@ func (mr *MockServiceMockRecorder) RemoveFromInventory(ctx, input any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromInventory", reflect.TypeOf((*MockService)(nil).RemoveFromInventory), ctx, input)
 }
 
+// RequestCharacterTransfer mocks base method.
+func (m *MockService) RequestCharacterTransfer(ctx context.Context, input *character.RequestCharacterTransferInput) (*character.RequestCharacterTransferOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestCharacterTransfer", ctx, input)
+	ret0, _ := ret[0].(*character.RequestCharacterTransferOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestCharacterTransfer indicates an expected call of RequestCharacterTransfer.
+func (mr *MockServiceMockRecorder) RequestCharacterTransfer(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestCharacterTransfer", reflect.TypeOf((*MockService)(nil).RequestCharacterTransfer), ctx, input)
+}
+
+// RespondToCharacterTransfer mocks base method.
+func (m *MockService) RespondToCharacterTransfer(ctx context.Context, input *character.RespondToCharacterTransferInput) (*character.RespondToCharacterTransferOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondToCharacterTransfer", ctx, input)
+	ret0, _ := ret[0].(*character.RespondToCharacterTransferOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondToCharacterTransfer indicates an expected call of RespondToCharacterTransfer.
+func (mr *MockServiceMockRecorder) RespondToCharacterTransfer(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToCharacterTransfer", reflect.TypeOf((*MockService)(nil).RespondToCharacterTransfer), ctx, input)
+}
+
+// RestoreCharacter mocks base method.
+func (m *MockService) RestoreCharacter(ctx context.Context, input *character.RestoreCharacterInput) (*character.RestoreCharacterOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreCharacter", ctx, input)
+	ret0, _ := ret[0].(*character.RestoreCharacterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreCharacter indicates an expected call of RestoreCharacter.
+func (mr *MockServiceMockRecorder) RestoreCharacter(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreCharacter", reflect.TypeOf((*MockService)(nil).RestoreCharacter), ctx, input)
+}
+
 // RollAbilityScores mocks base method.
 func (m *MockService) RollAbilityScores(ctx context.Context, input *character.RollAbilityScoresInput) (*character.RollAbilityScoresOutput, error) {
 	m.ctrl.T.Helper()
@@ -402,6 +657,36 @@ func (mr *MockServiceMockRecorder) RollAbilityScores(ctx, input any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollAbilityScores", reflect.TypeOf((*MockService)(nil).RollAbilityScores), ctx, input)
 }
 
+// ShareCharacter mocks base method.
+func (m *MockService) ShareCharacter(ctx context.Context, input *character.ShareCharacterInput) (*character.ShareCharacterOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareCharacter", ctx, input)
+	ret0, _ := ret[0].(*character.ShareCharacterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShareCharacter indicates an expected call of ShareCharacter.
+func (mr *MockServiceMockRecorder) ShareCharacter(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareCharacter", reflect.TypeOf((*MockService)(nil).ShareCharacter), ctx, input)
+}
+
+// UnattuneItem mocks base method.
+func (m *MockService) UnattuneItem(ctx context.Context, input *character.UnattuneItemInput) (*character.UnattuneItemOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnattuneItem", ctx, input)
+	ret0, _ := ret[0].(*character.UnattuneItemOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnattuneItem indicates an expected call of UnattuneItem.
+func (mr *MockServiceMockRecorder) UnattuneItem(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnattuneItem", reflect.TypeOf((*MockService)(nil).UnattuneItem), ctx, input)
+}
+
 // UnequipItem mocks base method.
 func (m *MockService) UnequipItem(ctx context.Context, input *character.UnequipItemInput) (*character.UnequipItemOutput, error) {
 	m.ctrl.T.Helper()
@@ -417,6 +702,21 @@ func (mr *MockServiceMockRecorder) UnequipItem(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnequipItem", reflect.TypeOf((*MockService)(nil).UnequipItem), ctx, input)
 }
 
+// UnshareCharacter mocks base method.
+func (m *MockService) UnshareCharacter(ctx context.Context, input *character.UnshareCharacterInput) (*character.UnshareCharacterOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnshareCharacter", ctx, input)
+	ret0, _ := ret[0].(*character.UnshareCharacterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnshareCharacter indicates an expected call of UnshareCharacter.
+func (mr *MockServiceMockRecorder) UnshareCharacter(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnshareCharacter", reflect.TypeOf((*MockService)(nil).UnshareCharacter), ctx, input)
+}
+
 // UpdateAbilityScores mocks base method.
 func (m *MockService) UpdateAbilityScores(ctx context.Context, input *character.UpdateAbilityScoresInput) (*character.UpdateAbilityScoresOutput, error) {
 	m.ctrl.T.Helper()
@@ -447,6 +747,21 @@ func (mr *MockServiceMockRecorder) UpdateBackground(ctx, input any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBackground", reflect.TypeOf((*MockService)(nil).UpdateBackground), ctx, input)
 }
 
+// UpdateCharacterDetails mocks base method.
+func (m *MockService) UpdateCharacterDetails(ctx context.Context, input *character.UpdateCharacterDetailsInput) (*character.UpdateCharacterDetailsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCharacterDetails", ctx, input)
+	ret0, _ := ret[0].(*character.UpdateCharacterDetailsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCharacterDetails indicates an expected call of UpdateCharacterDetails.
+func (mr *MockServiceMockRecorder) UpdateCharacterDetails(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCharacterDetails", reflect.TypeOf((*MockService)(nil).UpdateCharacterDetails), ctx, input)
+}
+
 // UpdateChoices mocks base method.
 func (m *MockService) UpdateChoices(ctx context.Context, input *character.UpdateChoicesInput) (*character.UpdateChoicesOutput, error) {
 	m.ctrl.T.Helper()
@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
 	"github.com/KirkDiggler/rpg-api/internal/errors"
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/dice"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/clock"
 	"github.com/KirkDiggler/rpg-api/internal/pkg/idgen"
+	campaignrepo "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
 	"github.com/KirkDiggler/rpg-api/internal/repositories/character"
 	draftrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft"
 	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
@@ -23,10 +27,16 @@ import (
 type Config struct {
 	CharacterRepo      character.Repository
 	CharacterDraftRepo draftrepo.Repository
+	CampaignRepo       campaignrepo.Repository // Game sessions belong to campaigns
 	ExternalClient     external.Client
 	DiceService        dice.Service
 	IDGenerator        idgen.Generator
 	DraftIDGenerator   idgen.Generator
+
+	// Optional
+	Clock                     clock.Clock   // Defaults to the real clock
+	DeletedCharacterRetention time.Duration // How long deleted characters can be restored, defaults to 30 days
+	SingleActiveSession       bool          // Characters may only be in one session at a time
 }
 
 // Validate ensures all required dependencies are present
@@ -37,6 +47,9 @@ func (c *Config) Validate() error {
 	if c.CharacterDraftRepo == nil {
 		return errors.InvalidArgument("character draft repository is required")
 	}
+	if c.CampaignRepo == nil {
+		return errors.InvalidArgument("campaign repository is required")
+	}
 	if c.ExternalClient == nil {
 		return errors.InvalidArgument("external client is required")
 	}
@@ -49,6 +62,9 @@ func (c *Config) Validate() error {
 	if c.DraftIDGenerator == nil {
 		return errors.InvalidArgument("draft ID generator is required")
 	}
+	if c.DeletedCharacterRetention < 0 {
+		return errors.InvalidArgument("deleted character retention cannot be negative")
+	}
 	return nil
 }
 
@@ -56,10 +72,14 @@ func (c *Config) Validate() error {
 type Orchestrator struct {
 	charRepo       character.Repository
 	draftRepo      draftrepo.Repository
+	campaignRepo   campaignrepo.Repository
 	externalClient external.Client
 	diceService    dice.Service
 	idGen          idgen.Generator
 	draftIDGen     idgen.Generator
+	clock          clock.Clock
+	retention      time.Duration
+	singleSession  bool
 }
 
 // New creates a new character orchestrator
@@ -68,13 +88,26 @@ func New(cfg *Config) (*Orchestrator, error) {
 		return nil, err
 	}
 
+	c := cfg.Clock
+	if c == nil {
+		c = clock.New()
+	}
+	retention := cfg.DeletedCharacterRetention
+	if retention == 0 {
+		retention = defaultDeletedCharacterRetention
+	}
+
 	return &Orchestrator{
 		charRepo:       cfg.CharacterRepo,
 		draftRepo:      cfg.CharacterDraftRepo,
+		campaignRepo:   cfg.CampaignRepo,
 		externalClient: cfg.ExternalClient,
 		diceService:    cfg.DiceService,
 		idGen:          cfg.IDGenerator,
 		draftIDGen:     cfg.DraftIDGenerator,
+		clock:          c,
+		retention:      retention,
+		singleSession:  cfg.SingleActiveSession,
 	}, nil
 }
 
@@ -109,6 +142,16 @@ func mapSkillNameToConstant(skillName string) (constants.Skill, bool) {
 	return skillConst, exists
 }
 
+// hasChoiceCategory reports whether any of the choices belong to a category
+func hasChoiceCategory(choices []toolkitchar.ChoiceData, category shared.ChoiceCategory) bool {
+	for _, choice := range choices {
+		if choice.Category == category {
+			return true
+		}
+	}
+	return false
+}
+
 // contains checks if a string slice contains a specific string
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -404,6 +447,17 @@ func (o *Orchestrator) UpdateClass(ctx context.Context, input *UpdateClassInput)
 		nonClassChoices = append(nonClassChoices, cantripChoice, spellChoice)
 	}
 
+	// Subclass, fighting style and expertise choices made at level 1
+	if err := validateLevelOneFeatureChoices(input.ClassID, input.Choices); err != nil {
+		return nil, err
+	}
+	for _, featureChoice := range levelOneFeatureChoices(input.ClassID, chosenSubclass(input.Choices)) {
+		if !hasChoiceCategory(input.Choices, featureChoice.Category) {
+			nonClassChoices = append(nonClassChoices, featureChoice)
+		}
+	}
+	draft.ClassChoice.SubclassID = chosenSubclass(input.Choices)
+
 	// Add new class choices if provided
 	if len(input.Choices) > 0 {
 		// Ensure all new choices have the class source set
@@ -763,6 +817,10 @@ func (o *Orchestrator) FinalizeDraft(ctx context.Context, input *FinalizeDraftIn
 	// Armor proficiencies from class
 	characterData.Proficiencies.Armor = classDataOutput.ClassData.ArmorProficiencies
 
+	// Tool proficiencies from class
+	characterData.Proficiencies.Tools = append(characterData.Proficiencies.Tools,
+		classDataOutput.ClassData.ToolProficiencies...)
+
 	// Tool proficiencies from background
 	// TODO: Add tool proficiencies when they are available in BackgroundData
 	// Current BackgroundData structure doesn't include tool proficiencies
@@ -827,6 +885,18 @@ func (o *Orchestrator) FinalizeDraft(ctx context.Context, input *FinalizeDraftIn
 		}
 	}
 
+	// Expertise can only be chosen for proficiencies gathered above
+	toolExpertise, err := applyExpertise(characterData, draft.Choices)
+	if err != nil {
+		return nil, err
+	}
+
+	// Level 1 subclass, dragon ancestor and fighting style choices must all be made
+	features, err := applyLevelOneFeatureChoices(characterData, draft.Choices)
+	if err != nil {
+		return nil, err
+	}
+
 	// Handle subrace bonuses
 	if draft.RaceChoice.SubraceID == constants.SubraceHillDwarf {
 		// Hill Dwarf gets +1 HP per level
@@ -890,9 +960,26 @@ func (o *Orchestrator) FinalizeDraft(ctx context.Context, input *FinalizeDraftIn
 			Used: 0,
 		}
 	}
+	// Wizards start with the spells chosen during creation written in their spellbook
+	var spellbook []string
+	if characterData.ClassID == constants.ClassWizard {
+		spellbook = selectedSpells(characterData, shared.ChoiceSpells)
+	}
+
+	var sheet *dnd5e.CharacterSheet
+	if len(toolExpertise) > 0 || len(features.alwaysPrepared) > 0 || len(spellbook) > 0 || features.dragonAncestor != "" {
+		sheet = &dnd5e.CharacterSheet{
+			ToolExpertise:        toolExpertise,
+			AlwaysPreparedSpells: features.alwaysPrepared,
+			Spellbook:            spellbook,
+			DragonAncestor:       features.dragonAncestor,
+		}
+	}
+
 	// Save the character
 	createCharOutput, err := o.charRepo.Create(ctx, character.CreateInput{
 		CharacterData: characterData,
+		Sheet:         sheet,
 	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create character from draft %s", input.DraftID)
@@ -936,45 +1023,74 @@ func (o *Orchestrator) GetCharacter(ctx context.Context, input *GetCharacterInpu
 		}
 		return nil, errors.Wrapf(err, "failed to get character %s", input.CharacterID)
 	}
-
-	return &GetCharacterOutput{
-		Character: getOutput.CharacterData,
-	}, nil
-}
-
-func (o *Orchestrator) ListCharacters(ctx context.Context, input *ListCharactersInput) (*ListCharactersOutput, error) {
-	// List characters from repository by player ID
-	listOutput, err := o.charRepo.ListByPlayerID(ctx, character.ListByPlayerIDInput{
-		PlayerID: input.PlayerID,
-	})
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to list characters")
+	if !input.Internal {
+		allowed, err := o.canViewCharacter(ctx, &viewer{playerID: input.ViewerID, sessionID: input.SessionID},
+			getOutput.CharacterData, getOutput.Sheet)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, errors.PermissionDeniedf("character %s is not shared with player %q", input.CharacterID, input.ViewerID)
+		}
 	}
 
-	return &ListCharactersOutput{
-		Characters: listOutput.Characters,
-	}, nil
+	output := &GetCharacterOutput{
+		Character:      getOutput.CharacterData,
+		Sheet:          getOutput.Sheet,
+		DerivedStats:   o.deriveStats(ctx, getOutput.CharacterData, getOutput.Sheet),
+		AttackProfiles: o.attackProfiles(ctx, getOutput.CharacterData, getOutput.Sheet),
+	}
+	if getOutput.Sheet != nil {
+		output.Details = getOutput.Sheet.Details
+	}
+	return output, nil
 }
 
-func (o *Orchestrator) DeleteCharacter(ctx context.Context, input *DeleteCharacterInput) (*DeleteCharacterOutput, error) {
-	// Validate input
-	if input.CharacterID == "" {
-		return nil, errors.InvalidArgument("character ID is required")
+func (o *Orchestrator) ListCharacters(ctx context.Context, input *ListCharactersInput) (*ListCharactersOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	// A request with no viewer sees nothing
+	if input.ViewerID == "" {
+		return &ListCharactersOutput{Characters: []*toolkitchar.Data{}}, nil
+	}
+
+	// The repository leaves out characters the viewer may not see, so pages come back full
+	// and the total only counts what the viewer can read. Characters shared with a session
+	// are only let in once the viewer is known to belong to it.
+	listInput := character.ListInput{
+		PlayerID:   input.PlayerID,
+		SessionID:  input.SessionID,
+		ClassID:    input.ClassID,
+		RaceID:     input.RaceID,
+		MinLevel:   input.MinLevel,
+		MaxLevel:   input.MaxLevel,
+		SortBy:     input.SortBy,
+		Descending: input.SortDescending,
+		PageSize:   input.PageSize,
+		PageToken:  input.PageToken,
+		ViewerID:   input.ViewerID,
+	}
+	if input.SessionID != "" {
+		session, err := o.memberSession(ctx, input.ViewerID, input.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		if session != nil {
+			listInput.ViewerSessionID = input.SessionID
+		}
 	}
 
-	// Delete character from repository
-	_, err := o.charRepo.Delete(ctx, character.DeleteInput{
-		ID: input.CharacterID,
-	})
+	listOutput, err := o.charRepo.List(ctx, listInput)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			return nil, errors.NotFoundf("character %s not found", input.CharacterID)
-		}
-		return nil, errors.Wrapf(err, "failed to delete character %s", input.CharacterID)
+		return nil, errors.Wrapf(err, "failed to list characters")
 	}
 
-	return &DeleteCharacterOutput{
-		Message: fmt.Sprintf("Character %s deleted successfully", input.CharacterID),
+	return &ListCharactersOutput{
+		Characters:    listOutput.Characters,
+		NextPageToken: listOutput.NextPageToken,
+		TotalSize:     listOutput.TotalSize,
 	}, nil
 }
 
@@ -1200,18 +1316,6 @@ func (o *Orchestrator) GetCharacterInventory(ctx context.Context, input *GetChar
 	return nil, errors.Unimplemented("not implemented")
 }
 
-func (o *Orchestrator) EquipItem(ctx context.Context, input *EquipItemInput) (*EquipItemOutput, error) {
-	return nil, errors.Unimplemented("not implemented")
-}
-
-func (o *Orchestrator) UnequipItem(ctx context.Context, input *UnequipItemInput) (*UnequipItemOutput, error) {
-	return nil, errors.Unimplemented("not implemented")
-}
-
-func (o *Orchestrator) AddToInventory(ctx context.Context, input *AddToInventoryInput) (*AddToInventoryOutput, error) {
-	return nil, errors.Unimplemented("not implemented")
-}
-
 func (o *Orchestrator) RemoveFromInventory(ctx context.Context, input *RemoveFromInventoryInput) (*RemoveFromInventoryOutput, error) {
 	return nil, errors.Unimplemented("not implemented")
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/dice"
 	dicemock "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice/mock"
 	idgenmock "github.com/KirkDiggler/rpg-api/internal/pkg/idgen/mock"
+	campaignmock "github.com/KirkDiggler/rpg-api/internal/repositories/campaign/mock"
 	charactermock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
 	draftrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft"
 	draftrepomock "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft/mock"
@@ -47,6 +48,7 @@ func (s *OrchestratorRollAbilityScoresTestSuite) SetupTest() {
 
 	orchestrator, err := character.New(&character.Config{
 		CharacterDraftRepo: s.mockDraft,
+		CampaignRepo:       campaignmock.NewMockRepository(s.ctrl),
 		DiceService:        s.mockDice,
 		CharacterRepo:      s.mockChar,
 		ExternalClient:     s.mockExternal,
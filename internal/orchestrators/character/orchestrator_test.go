@@ -13,6 +13,7 @@ import (
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
 	dicemock "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice/mock"
 	idgenmock "github.com/KirkDiggler/rpg-api/internal/pkg/idgen/mock"
+	campaignmock "github.com/KirkDiggler/rpg-api/internal/repositories/campaign/mock"
 	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
 	characterrepomock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
 	draftrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft"
@@ -55,6 +56,7 @@ func (s *OrchestratorTestSuite) SetupTest() {
 	orchestrator, err := character.New(&character.Config{
 		CharacterRepo:      s.mockCharRepo,
 		CharacterDraftRepo: s.mockDraftRepo,
+		CampaignRepo:       campaignmock.NewMockRepository(s.ctrl),
 		ExternalClient:     s.mockExternal,
 		DiceService:        s.mockDiceService,
 		IDGenerator:        s.mockIDGenerator,
@@ -1033,9 +1035,10 @@ func (s *OrchestratorTestSuite) TestGetCharacter_Success() {
 		Get(ctx, characterrepo.GetInput{ID: characterID}).
 		Return(&characterrepo.GetOutput{CharacterData: mockCharacter}, nil)
 
-	// Call orchestrator
+	// Call orchestrator as the owner
 	input := &character.GetCharacterInput{
 		CharacterID: characterID,
+		ViewerID:    s.testPlayerID,
 	}
 	output, err := s.orchestrator.GetCharacter(ctx, input)
 
@@ -0,0 +1,309 @@
+package character
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+// preparedCasterAbilities maps classes that prepare spells each day to their spellcasting ability
+var preparedCasterAbilities = map[constants.Class]constants.Ability{
+	constants.ClassCleric:  constants.WIS,
+	constants.ClassDruid:   constants.WIS,
+	constants.ClassPaladin: constants.CHA,
+	constants.ClassWizard:  constants.INT,
+}
+
+// PrepareSpells replaces the character's prepared spell list. The list can be set once
+// after each long rest; always prepared spells such as domain spells are not counted.
+func (o *Orchestrator) PrepareSpells(ctx context.Context, input *PrepareSpellsInput) (*PrepareSpellsOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument("player ID is required")
+	}
+
+	getOutput, err := o.getOwnedCharacter(ctx, input.CharacterID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	charData := getOutput.CharacterData
+	sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+
+	if _, ok := preparedCasterAbilities[charData.ClassID]; !ok {
+		return nil, errors.FailedPreconditionf("%s does not prepare spells", charData.ClassID)
+	}
+	if sheet.PreparedSinceLongRest {
+		return nil, errors.FailedPrecondition("prepared spells can only be changed after a long rest")
+	}
+	highestSlot := highestSpellSlotLevel(charData)
+	if highestSlot == 0 {
+		return nil, errors.FailedPreconditionf("%s has no spell slots at level %d", charData.ClassID, charData.Level)
+	}
+
+	// Always prepared spells are already on the list and do not use up a preparation
+	var prepared []string
+	for _, spellID := range input.SpellIDs {
+		if slices.Contains(prepared, spellID) {
+			return nil, errors.InvalidArgumentf("spell %s prepared more than once", spellID)
+		}
+		if !slices.Contains(sheet.AlwaysPreparedSpells, spellID) {
+			prepared = append(prepared, spellID)
+		}
+	}
+
+	maxPrepared := maxPreparedSpells(charData)
+	if int32(len(prepared)) > maxPrepared { // nolint:gosec // spell lists are small
+		return nil, errors.InvalidArgumentf("%s can prepare %d spells, got %d",
+			charData.ClassID, maxPrepared, len(prepared))
+	}
+
+	if len(prepared) > 0 {
+		var classSpells map[string]int32
+		classSpells, err = o.classSpellLevels(ctx, charData.ClassID)
+		if err != nil {
+			return nil, err
+		}
+		spellbook := wizardSpellbook(charData, sheet)
+		for _, spellID := range prepared {
+			level, ok := classSpells[spellID]
+			if !ok {
+				return nil, errors.InvalidArgumentf("%s is not on the %s spell list", spellID, charData.ClassID)
+			}
+			if level == 0 {
+				return nil, errors.InvalidArgumentf("%s is a cantrip and is not prepared", spellID)
+			}
+			if int(level) > highestSlot {
+				return nil, errors.InvalidArgumentf("%s is level %d but the highest spell slot is level %d",
+					spellID, level, highestSlot)
+			}
+			if charData.ClassID == constants.ClassWizard && !slices.Contains(spellbook, spellID) {
+				return nil, errors.InvalidArgumentf("%s is not in the spellbook", spellID)
+			}
+		}
+	}
+
+	sheet.PreparedSpells = prepared
+	sheet.PreparedSinceLongRest = true
+
+	updateOutput, err := o.charRepo.Update(ctx, character.UpdateInput{
+		CharacterData: charData,
+		Sheet:         sheet,
+		EventType:     dnd5e.CharacterEventSpellsPrepared,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to prepare spells")
+	}
+
+	slog.InfoContext(ctx, "prepared spells",
+		"character_id", input.CharacterID,
+		"prepared_count", len(prepared),
+		"max_prepared", maxPrepared)
+
+	return &PrepareSpellsOutput{
+		Character:            updateOutput.CharacterData,
+		PreparedSpells:       sheet.PreparedSpells,
+		AlwaysPreparedSpells: sheet.AlwaysPreparedSpells,
+		MaxPrepared:          maxPrepared,
+	}, nil
+}
+
+// CanCastSpell checks that the character knows or has prepared a spell and has a spell slot
+// to cast it with. It does not spend the slot.
+func (o *Orchestrator) CanCastSpell(ctx context.Context, input *CanCastSpellInput) (*CanCastSpellOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.SpellID == "" {
+		return nil, errors.InvalidArgument("spell ID is required")
+	}
+	if input.SlotLevel < 0 || input.SlotLevel > 9 {
+		return nil, errors.InvalidArgumentf("invalid slot level: %d", input.SlotLevel)
+	}
+
+	getOutput, err := o.getCharacterWithSheet(ctx, input.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+	charData := getOutput.CharacterData
+	sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+
+	spellData, err := o.externalClient.GetSpellData(ctx, input.SpellID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get spell %s", input.SpellID)
+	}
+
+	output := &CanCastSpellOutput{
+		SpellData: spellData,
+		SlotLevel: input.SlotLevel,
+	}
+	if output.SlotLevel == 0 {
+		output.SlotLevel = spellData.Level
+	}
+
+	if reason := castingRestriction(charData, sheet, input.SpellID, spellData.Level, output.SlotLevel); reason != "" {
+		output.Reason = reason
+		return output, nil
+	}
+
+	output.CanCast = true
+	return output, nil
+}
+
+// LongRest restores hit points, spell slots and long rest resources, removes one level of
+// exhaustion, and lets a prepared caster choose a new list of prepared spells
+func (o *Orchestrator) LongRest(ctx context.Context, input *LongRestInput) (*LongRestOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument("player ID is required")
+	}
+
+	getOutput, err := o.getOwnedCharacter(ctx, input.CharacterID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	charData := getOutput.CharacterData
+	sheet := getOutput.Sheet
+
+	charData.HitPoints = charData.MaxHitPoints
+	for level, slot := range charData.SpellSlots {
+		slot.Used = 0
+		charData.SpellSlots[level] = slot
+	}
+	for resourceType, resource := range charData.ClassResources {
+		if resource.Resets == shared.LongRest || resource.Resets == shared.ShortRest {
+			resource.Current = resource.Max
+			charData.ClassResources[resourceType] = resource
+		}
+	}
+	if charData.Exhaustion > 0 {
+		charData.Exhaustion--
+	}
+	if sheet != nil {
+		sheet.PreparedSinceLongRest = false
+	}
+
+	updateOutput, err := o.charRepo.Update(ctx, character.UpdateInput{
+		CharacterData: charData,
+		Sheet:         sheet,
+		EventType:     dnd5e.CharacterEventLongRest,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to finish long rest")
+	}
+
+	slog.InfoContext(ctx, "finished long rest", "character_id", input.CharacterID)
+
+	return &LongRestOutput{Character: updateOutput.CharacterData}, nil
+}
+
+// castingRestriction returns why a spell cannot be cast, or an empty string when it can
+func castingRestriction(
+	charData *toolkitchar.Data,
+	sheet *dnd5e.CharacterSheet,
+	spellID string,
+	spellLevel, slotLevel int32,
+) string {
+	if spellLevel == 0 {
+		if !slices.Contains(selectedSpells(charData, shared.ChoiceCantrips), spellID) {
+			return fmt.Sprintf("%s is not a known cantrip", spellID)
+		}
+		return ""
+	}
+
+	if _, ok := preparedCasterAbilities[charData.ClassID]; ok {
+		if !slices.Contains(sheet.PreparedSpells, spellID) && !slices.Contains(sheet.AlwaysPreparedSpells, spellID) {
+			return fmt.Sprintf("%s is not prepared", spellID)
+		}
+	} else if !slices.Contains(selectedSpells(charData, shared.ChoiceSpells), spellID) {
+		return fmt.Sprintf("%s is not a known spell", spellID)
+	}
+
+	if slotLevel < spellLevel {
+		return fmt.Sprintf("%s cannot be cast with a level %d slot", spellID, slotLevel)
+	}
+	slot, ok := charData.SpellSlots[int(slotLevel)]
+	if !ok || slot.Max == 0 {
+		return fmt.Sprintf("no level %d spell slots", slotLevel)
+	}
+	if slot.Used >= slot.Max {
+		return fmt.Sprintf("no level %d spell slots remaining", slotLevel)
+	}
+	return ""
+}
+
+// maxPreparedSpells is the spellcasting ability modifier plus class level (half level for
+// paladins), minimum one
+func maxPreparedSpells(charData *toolkitchar.Data) int32 {
+	level := charData.Level
+	if charData.ClassID == constants.ClassPaladin {
+		level /= 2
+	}
+	ability := preparedCasterAbilities[charData.ClassID]
+	// nolint:gosec // character levels are 1-20
+	result := dnd5e.AbilityModifier(charData.AbilityScores[ability]) + int32(level)
+	if result < 1 {
+		return 1
+	}
+	return result
+}
+
+// highestSpellSlotLevel returns the highest spell level the character has slots for
+func highestSpellSlotLevel(charData *toolkitchar.Data) int {
+	highest := 0
+	for level, slot := range charData.SpellSlots {
+		if slot.Max > 0 && level > highest {
+			highest = level
+		}
+	}
+	return highest
+}
+
+// classSpellLevels returns the level of every spell on a class's spell list, keyed by spell ID
+func (o *Orchestrator) classSpellLevels(ctx context.Context, classID constants.Class) (map[string]int32, error) {
+	spells, err := o.externalClient.ListAvailableSpells(ctx, &external.ListSpellsInput{ClassID: string(classID)})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list %s spells", classID)
+	}
+	levels := make(map[string]int32, len(spells))
+	for _, spell := range spells {
+		if spell != nil {
+			levels[spell.ID] = spell.Level
+		}
+	}
+	return levels, nil
+}
+
+// selectedSpells returns the spells or cantrips chosen during character creation
+func selectedSpells(charData *toolkitchar.Data, category shared.ChoiceCategory) []string {
+	var result []string
+	for _, choice := range charData.Choices {
+		if choice.Category != category {
+			continue
+		}
+		result = append(result, choice.SpellSelection...)
+		result = append(result, choice.CantripSelection...)
+	}
+	return result
+}
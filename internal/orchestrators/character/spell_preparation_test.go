@@ -0,0 +1,327 @@
+package character_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	extmock "github.com/KirkDiggler/rpg-api/internal/clients/external/mock"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	dicemock "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice/mock"
+	campaignmock "github.com/KirkDiggler/rpg-api/internal/repositories/campaign/mock"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	charmock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
+	draftmock "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft/mock"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+type SpellPreparationTestSuite struct {
+	suite.Suite
+	ctrl          *gomock.Controller
+	orchestrator  *character.Orchestrator
+	mockCharRepo  *charmock.MockRepository
+	mockExtClient *extmock.MockClient
+	ctx           context.Context
+
+	cleric *toolkitchar.Data
+	wizard *toolkitchar.Data
+}
+
+func (s *SpellPreparationTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockCharRepo = charmock.NewMockRepository(s.ctrl)
+	s.mockExtClient = extmock.NewMockClient(s.ctrl)
+	s.ctx = context.Background()
+
+	orch, err := character.New(&character.Config{
+		CharacterRepo:      s.mockCharRepo,
+		CharacterDraftRepo: draftmock.NewMockRepository(s.ctrl),
+		CampaignRepo:       campaignmock.NewMockRepository(s.ctrl),
+		ExternalClient:     s.mockExtClient,
+		DiceService:        dicemock.NewMockService(s.ctrl),
+		IDGenerator:        &mockIDGenerator{},
+		DraftIDGenerator:   &mockIDGenerator{},
+	})
+	s.Require().NoError(err)
+	s.orchestrator = orch
+
+	s.cleric = &toolkitchar.Data{
+		ID:            "cleric-1",
+		PlayerID:      "player-1",
+		Level:         1,
+		ClassID:       constants.ClassCleric,
+		SubclassID:    character.SubclassLifeDomain,
+		AbilityScores: shared.AbilityScores{constants.WIS: 14},
+		SpellSlots:    map[int]toolkitchar.SlotInfo{1: {Max: 2}},
+		Choices: []toolkitchar.ChoiceData{{
+			Category:         shared.ChoiceCantrips,
+			Source:           shared.SourceClass,
+			CantripSelection: []string{"SPELL_SACRED_FLAME"},
+		}},
+	}
+	s.wizard = &toolkitchar.Data{
+		ID:            "wizard-1",
+		PlayerID:      "player-1",
+		Level:         1,
+		ClassID:       constants.ClassWizard,
+		AbilityScores: shared.AbilityScores{constants.INT: 16},
+		SpellSlots:    map[int]toolkitchar.SlotInfo{1: {Max: 2}},
+		Choices: []toolkitchar.ChoiceData{{
+			Category:       shared.ChoiceSpells,
+			Source:         shared.SourceClass,
+			SpellSelection: []string{"SPELL_MAGIC_MISSILE", "SPELL_SHIELD", "SPELL_SLEEP"},
+		}},
+	}
+}
+
+func (s *SpellPreparationTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *SpellPreparationTestSuite) expectGet(charData *toolkitchar.Data, sheet *dnd5e.CharacterSheet) {
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: charData.ID}).
+		Return(&characterrepo.GetOutput{CharacterData: charData, Sheet: sheet}, nil)
+}
+
+func (s *SpellPreparationTestSuite) expectUpdate(check func(input characterrepo.UpdateInput)) {
+	s.mockCharRepo.EXPECT().
+		Update(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+			check(input)
+			return &characterrepo.UpdateOutput{CharacterData: input.CharacterData, Sheet: input.Sheet}, nil
+		})
+}
+
+func (s *SpellPreparationTestSuite) expectClassSpells(classID constants.Class, spells ...*external.SpellData) {
+	s.mockExtClient.EXPECT().
+		ListAvailableSpells(s.ctx, &external.ListSpellsInput{ClassID: string(classID)}).
+		Return(spells, nil)
+}
+
+func (s *SpellPreparationTestSuite) TestPrepareSpells_DomainSpellsNotCounted() {
+	s.expectGet(s.cleric, &dnd5e.CharacterSheet{AlwaysPreparedSpells: []string{"SPELL_BLESS", "SPELL_CURE_WOUNDS"}})
+	s.expectClassSpells(constants.ClassCleric,
+		&external.SpellData{ID: "SPELL_BLESS", Level: 1},
+		&external.SpellData{ID: "SPELL_GUIDING_BOLT", Level: 1},
+		&external.SpellData{ID: "SPELL_HEALING_WORD", Level: 1},
+		&external.SpellData{ID: "SPELL_SHIELD_OF_FAITH", Level: 1},
+	)
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Equal([]string{"SPELL_GUIDING_BOLT", "SPELL_HEALING_WORD", "SPELL_SHIELD_OF_FAITH"},
+			input.Sheet.PreparedSpells)
+		s.True(input.Sheet.PreparedSinceLongRest)
+	})
+
+	// WIS +2 and level 1 allows three, Bless is a domain spell
+	output, err := s.orchestrator.PrepareSpells(s.ctx, &character.PrepareSpellsInput{
+		CharacterID: "cleric-1",
+		PlayerID:    "player-1",
+		SpellIDs:    []string{"SPELL_BLESS", "SPELL_GUIDING_BOLT", "SPELL_HEALING_WORD", "SPELL_SHIELD_OF_FAITH"},
+	})
+
+	s.Require().NoError(err)
+	s.Equal(int32(3), output.MaxPrepared)
+	s.Len(output.PreparedSpells, 3)
+}
+
+func (s *SpellPreparationTestSuite) TestPrepareSpells_TooMany() {
+	s.expectGet(s.cleric, nil)
+
+	output, err := s.orchestrator.PrepareSpells(s.ctx, &character.PrepareSpellsInput{
+		CharacterID: "cleric-1",
+		PlayerID:    "player-1",
+		SpellIDs:    []string{"SPELL_BLESS", "SPELL_GUIDING_BOLT", "SPELL_HEALING_WORD", "SPELL_SHIELD_OF_FAITH"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *SpellPreparationTestSuite) TestPrepareSpells_NotOnClassList() {
+	s.expectGet(s.cleric, nil)
+	s.expectClassSpells(constants.ClassCleric, &external.SpellData{ID: "SPELL_BLESS", Level: 1})
+
+	output, err := s.orchestrator.PrepareSpells(s.ctx, &character.PrepareSpellsInput{
+		CharacterID: "cleric-1",
+		PlayerID:    "player-1",
+		SpellIDs:    []string{"SPELL_MAGIC_MISSILE"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+	s.Contains(err.Error(), "cleric spell list")
+}
+
+func (s *SpellPreparationTestSuite) TestPrepareSpells_SlotLevelTooLow() {
+	s.expectGet(s.cleric, nil)
+	s.expectClassSpells(constants.ClassCleric, &external.SpellData{ID: "SPELL_AID", Level: 2})
+
+	output, err := s.orchestrator.PrepareSpells(s.ctx, &character.PrepareSpellsInput{
+		CharacterID: "cleric-1",
+		PlayerID:    "player-1",
+		SpellIDs:    []string{"SPELL_AID"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *SpellPreparationTestSuite) TestPrepareSpells_WizardRequiresSpellbook() {
+	s.expectGet(s.wizard, nil)
+	s.expectClassSpells(constants.ClassWizard,
+		&external.SpellData{ID: "SPELL_MAGIC_MISSILE", Level: 1},
+		&external.SpellData{ID: "SPELL_BURNING_HANDS", Level: 1},
+	)
+
+	output, err := s.orchestrator.PrepareSpells(s.ctx, &character.PrepareSpellsInput{
+		CharacterID: "wizard-1",
+		PlayerID:    "player-1",
+		SpellIDs:    []string{"SPELL_MAGIC_MISSILE", "SPELL_BURNING_HANDS"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+	s.Contains(err.Error(), "spellbook")
+}
+
+func (s *SpellPreparationTestSuite) TestPrepareSpells_LockedUntilLongRest() {
+	s.expectGet(s.cleric, &dnd5e.CharacterSheet{PreparedSinceLongRest: true})
+
+	output, err := s.orchestrator.PrepareSpells(s.ctx, &character.PrepareSpellsInput{
+		CharacterID: "cleric-1",
+		PlayerID:    "player-1",
+		SpellIDs:    []string{"SPELL_GUIDING_BOLT"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *SpellPreparationTestSuite) TestPrepareSpells_KnownCaster() {
+	s.wizard.ClassID = constants.ClassSorcerer
+	s.expectGet(s.wizard, nil)
+
+	output, err := s.orchestrator.PrepareSpells(s.ctx, &character.PrepareSpellsInput{
+		CharacterID: "wizard-1",
+		PlayerID:    "player-1",
+		SpellIDs:    []string{"SPELL_MAGIC_MISSILE"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *SpellPreparationTestSuite) TestLongRest_UnlocksPreparationAndRestores() {
+	s.cleric.HitPoints = 3
+	s.cleric.MaxHitPoints = 10
+	s.cleric.Exhaustion = 2
+	s.cleric.SpellSlots[1] = toolkitchar.SlotInfo{Max: 2, Used: 2}
+	s.cleric.ClassResources = map[shared.ClassResourceType]toolkitchar.ResourceData{
+		shared.ClassResourceChannelDivinity: {Max: 1, Current: 0, Resets: shared.ShortRest},
+	}
+	s.expectGet(s.cleric, &dnd5e.CharacterSheet{
+		PreparedSpells:        []string{"SPELL_GUIDING_BOLT"},
+		PreparedSinceLongRest: true,
+	})
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Equal(10, input.CharacterData.HitPoints)
+		s.Equal(1, input.CharacterData.Exhaustion)
+		s.Equal(0, input.CharacterData.SpellSlots[1].Used)
+		s.Equal(1, input.CharacterData.ClassResources[shared.ClassResourceChannelDivinity].Current)
+		s.False(input.Sheet.PreparedSinceLongRest)
+		s.Equal([]string{"SPELL_GUIDING_BOLT"}, input.Sheet.PreparedSpells)
+	})
+
+	_, err := s.orchestrator.LongRest(s.ctx, &character.LongRestInput{CharacterID: "cleric-1", PlayerID: "player-1"})
+
+	s.Require().NoError(err)
+}
+
+func (s *SpellPreparationTestSuite) TestCanCastSpell() {
+	sheet := &dnd5e.CharacterSheet{
+		AlwaysPreparedSpells: []string{"SPELL_BLESS"},
+		PreparedSpells:       []string{"SPELL_GUIDING_BOLT"},
+	}
+
+	testCases := []struct {
+		name      string
+		spell     *external.SpellData
+		slotLevel int32
+		slotsUsed int
+		canCast   bool
+		reason    string
+	}{
+		{name: "prepared spell", spell: &external.SpellData{ID: "SPELL_GUIDING_BOLT", Level: 1}, canCast: true},
+		{name: "domain spell", spell: &external.SpellData{ID: "SPELL_BLESS", Level: 1}, canCast: true},
+		{name: "known cantrip", spell: &external.SpellData{ID: "SPELL_SACRED_FLAME", Level: 0}, slotsUsed: 2, canCast: true},
+		{
+			name:   "unprepared spell",
+			spell:  &external.SpellData{ID: "SPELL_HEALING_WORD", Level: 1},
+			reason: "SPELL_HEALING_WORD is not prepared",
+		},
+		{
+			name:      "no slots remaining",
+			spell:     &external.SpellData{ID: "SPELL_GUIDING_BOLT", Level: 1},
+			slotsUsed: 2,
+			reason:    "no level 1 spell slots remaining",
+		},
+		{
+			name:      "upcast without slot",
+			spell:     &external.SpellData{ID: "SPELL_GUIDING_BOLT", Level: 1},
+			slotLevel: 2,
+			reason:    "no level 2 spell slots",
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.cleric.SpellSlots[1] = toolkitchar.SlotInfo{Max: 2, Used: tc.slotsUsed}
+			s.expectGet(s.cleric, sheet)
+			s.mockExtClient.EXPECT().GetSpellData(s.ctx, tc.spell.ID).Return(tc.spell, nil)
+
+			output, err := s.orchestrator.CanCastSpell(s.ctx, &character.CanCastSpellInput{
+				CharacterID: "cleric-1",
+				SpellID:     tc.spell.ID,
+				SlotLevel:   tc.slotLevel,
+			})
+
+			s.Require().NoError(err)
+			s.Equal(tc.canCast, output.CanCast)
+			s.Equal(tc.reason, output.Reason)
+		})
+	}
+}
+
+func TestSpellPreparationTestSuite(t *testing.T) {
+	suite.Run(t, new(SpellPreparationTestSuite))
+}
+
+func (s *SpellPreparationTestSuite) TestPrepareSpells_NotOwner() {
+	s.expectGet(s.cleric, nil)
+
+	output, err := s.orchestrator.PrepareSpells(s.ctx, &character.PrepareSpellsInput{
+		CharacterID: "cleric-1",
+		PlayerID:    "player-2",
+		SpellIDs:    []string{"SPELL_GUIDING_BOLT"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *SpellPreparationTestSuite) TestLongRest_NotOwner() {
+	s.expectGet(s.cleric, nil)
+
+	output, err := s.orchestrator.LongRest(s.ctx, &character.LongRestInput{CharacterID: "cleric-1", PlayerID: "player-2"})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
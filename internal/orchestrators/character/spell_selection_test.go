@@ -10,6 +10,7 @@ import (
 	extmock "github.com/KirkDiggler/rpg-api/internal/clients/external/mock"
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
 	dicemock "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice/mock"
+	campaignmock "github.com/KirkDiggler/rpg-api/internal/repositories/campaign/mock"
 	charmock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
 	draftrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft"
 	draftmock "github.com/KirkDiggler/rpg-api/internal/repositories/character_draft/mock"
@@ -41,6 +42,7 @@ func (s *SpellSelectionOrchestratorTestSuite) SetupTest() {
 	cfg := &character.Config{
 		CharacterRepo:      s.mockCharRepo,
 		CharacterDraftRepo: s.mockDraftRepo,
+		CampaignRepo:       campaignmock.NewMockRepository(s.ctrl),
 		ExternalClient:     s.mockExtClient,
 		DiceService:        s.mockDiceService,
 		IDGenerator:        &mockIDGenerator{},
@@ -195,13 +197,17 @@ func (s *SpellSelectionOrchestratorTestSuite) TestUpdateClass_ChangingClassClear
 
 	// Verify old wizard choices were removed
 	s.Require().NotNil(savedDraft)
-	s.Require().Len(savedDraft.Choices, 1, "Should only have race choice left")
+	s.Require().Len(savedDraft.Choices, 2, "Should only have race choice and fighting style left")
 
-	// Only race choice should remain
+	// Race choice should remain
 	choice := savedDraft.Choices[0]
 	s.Equal(shared.SourceRace, choice.Source)
 	s.Equal(shared.ChoiceSkills, choice.Category)
 	s.Equal("elf_skills", choice.ChoiceID)
+
+	// Fighters choose a fighting style at level 1
+	s.Equal(shared.ChoiceFightingStyle, savedDraft.Choices[1].Category)
+	s.Equal("fighter_fighting_style", savedDraft.Choices[1].ChoiceID)
 }
 
 func (s *SpellSelectionOrchestratorTestSuite) TestUpdateClass_ClericOnlyGetsCantrips() {
@@ -241,14 +247,17 @@ func (s *SpellSelectionOrchestratorTestSuite) TestUpdateClass_ClericOnlyGetsCant
 	s.Require().NotNil(output)
 	s.Equal(constants.ClassCleric, output.Draft.ClassChoice.ClassID)
 
-	// Verify only cantrip choice was added (clerics prepare spells)
+	// Verify no spell choice was added (clerics prepare spells)
 	s.Require().NotNil(savedDraft)
-	s.Require().Len(savedDraft.Choices, 1, "Cleric should only have cantrip choice")
+	s.Require().Len(savedDraft.Choices, 2, "Cleric should only have cantrip and domain choices")
 
 	choice := savedDraft.Choices[0]
 	s.Equal(shared.SourceClass, choice.Source)
 	s.Equal(shared.ChoiceCantrips, choice.Category)
 	s.Equal("cleric_cantrips", choice.ChoiceID)
+
+	s.Equal(character.ChoiceSubclass, savedDraft.Choices[1].Category)
+	s.Equal("cleric_subclass", savedDraft.Choices[1].ChoiceID)
 }
 
 // mockIDGenerator is a simple mock for testing
@@ -0,0 +1,220 @@
+package character
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+const (
+	// startingSpellbookSpells is the number of 1st level spells a wizard starts with
+	startingSpellbookSpells = 6
+	// spellbookSpellsPerLevel is the number of free spells a wizard adds on each level up
+	spellbookSpellsPerLevel = 2
+	// copyGoldPerSpellLevel is the cost in gold of copying a spell into a spellbook
+	copyGoldPerSpellLevel = 50
+	// copyHoursPerSpellLevel is the time in hours it takes to copy a spell into a spellbook
+	copyHoursPerSpellLevel = 2
+)
+
+// LearnSpells adds the free spells a wizard writes into their spellbook when they gain a level
+func (o *Orchestrator) LearnSpells(ctx context.Context, input *LearnSpellsInput) (*LearnSpellsOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument("player ID is required")
+	}
+	if len(input.SpellIDs) == 0 {
+		return nil, errors.InvalidArgument("at least one spell ID is required")
+	}
+
+	getOutput, err := o.getOwnedCharacter(ctx, input.CharacterID, input.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	charData := getOutput.CharacterData
+	sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+
+	if charData.ClassID != constants.ClassWizard {
+		return nil, errors.FailedPreconditionf("%s does not keep a spellbook", charData.ClassID)
+	}
+
+	spellbook := wizardSpellbook(charData, sheet)
+	remaining := freeSpellbookSpells(charData.Level) - (len(spellbook) - len(sheet.CopiedSpells))
+	if len(input.SpellIDs) > remaining {
+		return nil, errors.InvalidArgumentf("wizard can learn %d more spells at level %d, got %d",
+			max(remaining, 0), charData.Level, len(input.SpellIDs))
+	}
+
+	err = o.validateSpellbookAdditions(ctx, charData, spellbook, input.SpellIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet.Spellbook = append(slices.Clone(spellbook), input.SpellIDs...)
+
+	updateOutput, err := o.charRepo.Update(ctx, character.UpdateInput{
+		CharacterData: charData,
+		Sheet:         sheet,
+		EventType:     dnd5e.CharacterEventSpellbookChanged,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to learn spells")
+	}
+
+	slog.InfoContext(ctx, "learned spells",
+		"character_id", input.CharacterID,
+		"spell_ids", input.SpellIDs,
+		"spellbook_size", len(sheet.Spellbook))
+
+	return &LearnSpellsOutput{
+		Character:           updateOutput.CharacterData,
+		Spellbook:           sheet.Spellbook,
+		FreeSpellsRemaining: int32(remaining - len(input.SpellIDs)), // nolint:gosec // spell counts are small
+	}, nil
+}
+
+// CopySpellToSpellbook copies a wizard spell from a spell scroll the character carries into the
+// spellbook. Copying costs 50 gp and 2 hours per spell level; the gold is taken from the
+// character's wallet and the scroll is used up.
+func (o *Orchestrator) CopySpellToSpellbook(
+	ctx context.Context,
+	input *CopySpellToSpellbookInput,
+) (*CopySpellToSpellbookOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument("player ID is required")
+	}
+	if input.SpellID == "" {
+		return nil, errors.InvalidArgument("spell ID is required")
+	}
+
+	var spellData *external.SpellData
+	var costGold int32
+	// Scroll, wallet and spellbook are written together so a failed copy never charges the
+	// character, and a concurrent change makes the copy start over rather than charge twice
+	updateOutput, err := o.updateAtRevision(ctx, input.CharacterID, dnd5e.CharacterEventSpellbookChanged,
+		func(charData *toolkitchar.Data, sheet *dnd5e.CharacterSheet) error {
+			if charData.PlayerID != input.PlayerID {
+				return errors.PermissionDeniedf("character %s is not owned by player %s",
+					input.CharacterID, input.PlayerID)
+			}
+			if charData.ClassID != constants.ClassWizard {
+				return errors.FailedPreconditionf("%s does not keep a spellbook", charData.ClassID)
+			}
+
+			spellbook := wizardSpellbook(charData, sheet)
+			err := o.validateSpellbookAdditions(ctx, charData, spellbook, []string{input.SpellID})
+			if err != nil {
+				return err
+			}
+
+			equipment, removed := dnd5e.RemoveItems(charData.Equipment, dnd5e.SpellScrollItemID(input.SpellID), 1)
+			if removed == 0 {
+				return errors.FailedPreconditionf("character %s has no spell scroll of %s", input.CharacterID, input.SpellID)
+			}
+
+			if spellData == nil {
+				spellData, err = o.externalClient.GetSpellData(ctx, input.SpellID)
+				if err != nil {
+					return errors.Wrapf(err, "failed to get spell %s", input.SpellID)
+				}
+			}
+
+			costGold = spellData.Level * copyGoldPerSpellLevel
+			wallet, ok := sheet.Wallet.Spend(int64(costGold) * dnd5e.CopperPerGold)
+			if !ok {
+				return errors.FailedPreconditionf(
+					"insufficient funds: need %d gp, have %d cp", costGold, sheet.Wallet.TotalCopper())
+			}
+
+			charData.Equipment = equipment
+			sheet.Wallet = wallet
+			sheet.Spellbook = append(slices.Clone(spellbook), input.SpellID)
+			sheet.CopiedSpells = append(sheet.CopiedSpells, input.SpellID)
+			return nil
+		})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to copy spell into spellbook")
+	}
+
+	slog.InfoContext(ctx, "copied spell into spellbook",
+		"character_id", input.CharacterID,
+		"spell_id", input.SpellID,
+		"cost_gp", costGold)
+
+	return &CopySpellToSpellbookOutput{
+		Character: updateOutput.CharacterData,
+		Spellbook: updateOutput.Sheet.Spellbook,
+		Wallet:    updateOutput.Sheet.Wallet,
+		CostGold:  costGold,
+		Hours:     spellData.Level * copyHoursPerSpellLevel,
+	}, nil
+}
+
+// validateSpellbookAdditions checks new spellbook spells are wizard spells of a level the
+// wizard has slots for and are not already in the book
+func (o *Orchestrator) validateSpellbookAdditions(
+	ctx context.Context,
+	charData *toolkitchar.Data,
+	spellbook []string,
+	spellIDs []string,
+) error {
+	classSpells, err := o.classSpellLevels(ctx, constants.ClassWizard)
+	if err != nil {
+		return err
+	}
+	highestSlot := highestSpellSlotLevel(charData)
+
+	for i, spellID := range spellIDs {
+		if slices.Contains(spellbook, spellID) || slices.Contains(spellIDs[:i], spellID) {
+			return errors.InvalidArgumentf("%s is already in the spellbook", spellID)
+		}
+		level, ok := classSpells[spellID]
+		if !ok {
+			return errors.InvalidArgumentf("%s is not on the wizard spell list", spellID)
+		}
+		if level == 0 {
+			return errors.InvalidArgumentf("%s is a cantrip and is not written in a spellbook", spellID)
+		}
+		if int(level) > highestSlot {
+			return errors.InvalidArgumentf("%s is level %d but the highest spell slot is level %d",
+				spellID, level, highestSlot)
+		}
+	}
+	return nil
+}
+
+// wizardSpellbook returns the spells in a wizard's spellbook. Characters created before the
+// spellbook was tracked fall back to the spells chosen during creation.
+func wizardSpellbook(charData *toolkitchar.Data, sheet *dnd5e.CharacterSheet) []string {
+	if sheet != nil && len(sheet.Spellbook) > 0 {
+		return sheet.Spellbook
+	}
+	return selectedSpells(charData, shared.ChoiceSpells)
+}
+
+// freeSpellbookSpells is the number of spells a wizard can add without paying to copy them
+func freeSpellbookSpells(level int) int {
+	if level < 1 {
+		level = 1
+	}
+	return startingSpellbookSpells + (level-1)*spellbookSpellsPerLevel
+}
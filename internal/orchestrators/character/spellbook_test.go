@@ -0,0 +1,295 @@
+package character_test
+
+import (
+	"context"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+var startingSpellbook = []string{
+	"SPELL_MAGIC_MISSILE", "SPELL_SHIELD", "SPELL_SLEEP",
+	"SPELL_DETECT_MAGIC", "SPELL_MAGE_ARMOR", "SPELL_FIND_FAMILIAR",
+}
+
+func (s *SpellPreparationTestSuite) expectWizardSpells() {
+	s.expectClassSpells(constants.ClassWizard,
+		&external.SpellData{ID: "SPELL_MAGIC_MISSILE", Level: 1},
+		&external.SpellData{ID: "SPELL_BURNING_HANDS", Level: 1},
+		&external.SpellData{ID: "SPELL_THUNDERWAVE", Level: 1},
+		&external.SpellData{ID: "SPELL_FEATHER_FALL", Level: 1},
+		&external.SpellData{ID: "SPELL_MISTY_STEP", Level: 2},
+		&external.SpellData{ID: "SPELL_FIRE_BOLT", Level: 0},
+	)
+}
+
+func (s *SpellPreparationTestSuite) TestLearnSpells_TwoPerLevel() {
+	s.wizard.Level = 2
+	s.expectGet(s.wizard, &dnd5e.CharacterSheet{Spellbook: startingSpellbook})
+	s.expectWizardSpells()
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Len(input.Sheet.Spellbook, 8)
+		s.Contains(input.Sheet.Spellbook, "SPELL_THUNDERWAVE")
+	})
+
+	output, err := s.orchestrator.LearnSpells(s.ctx, &character.LearnSpellsInput{
+		CharacterID: "wizard-1",
+		PlayerID:    "player-1",
+		SpellIDs:    []string{"SPELL_BURNING_HANDS", "SPELL_THUNDERWAVE"},
+	})
+
+	s.Require().NoError(err)
+	s.Equal(int32(0), output.FreeSpellsRemaining)
+}
+
+func (s *SpellPreparationTestSuite) TestLearnSpells_CopiedSpellsAreNotFree() {
+	s.wizard.Level = 2
+	s.expectGet(s.wizard, &dnd5e.CharacterSheet{
+		Spellbook:    append(append([]string{}, startingSpellbook...), "SPELL_FEATHER_FALL"),
+		CopiedSpells: []string{"SPELL_FEATHER_FALL"},
+	})
+
+	output, err := s.orchestrator.LearnSpells(s.ctx, &character.LearnSpellsInput{
+		CharacterID: "wizard-1",
+		PlayerID:    "player-1",
+		SpellIDs:    []string{"SPELL_BURNING_HANDS", "SPELL_THUNDERWAVE", "SPELL_MISTY_STEP"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+	s.Contains(err.Error(), "learn 2 more spells")
+}
+
+func (s *SpellPreparationTestSuite) TestLearnSpells_SpellLevelTooHigh() {
+	s.wizard.Level = 2
+	s.expectGet(s.wizard, &dnd5e.CharacterSheet{Spellbook: startingSpellbook})
+	s.expectWizardSpells()
+
+	output, err := s.orchestrator.LearnSpells(s.ctx, &character.LearnSpellsInput{
+		CharacterID: "wizard-1",
+		PlayerID:    "player-1",
+		SpellIDs:    []string{"SPELL_MISTY_STEP"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *SpellPreparationTestSuite) TestLearnSpells_NotAWizard() {
+	s.expectGet(s.cleric, nil)
+
+	output, err := s.orchestrator.LearnSpells(s.ctx, &character.LearnSpellsInput{
+		CharacterID: "cleric-1",
+		PlayerID:    "player-1",
+		SpellIDs:    []string{"SPELL_BLESS"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *SpellPreparationTestSuite) TestCopySpellToSpellbook_ChargesWallet() {
+	s.wizard.Equipment = []string{"dagger", dnd5e.SpellScrollItemID("SPELL_FEATHER_FALL")}
+	s.expectGet(s.wizard, &dnd5e.CharacterSheet{
+		Spellbook: startingSpellbook,
+		Wallet:    &dnd5e.Wallet{Gold: 60, Silver: 5},
+	})
+	s.expectWizardSpells()
+	s.mockExtClient.EXPECT().
+		GetSpellData(s.ctx, "SPELL_FEATHER_FALL").
+		Return(&external.SpellData{ID: "SPELL_FEATHER_FALL", Level: 1}, nil)
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Contains(input.Sheet.Spellbook, "SPELL_FEATHER_FALL")
+		s.Equal([]string{"SPELL_FEATHER_FALL"}, input.Sheet.CopiedSpells)
+		s.Equal(&dnd5e.Wallet{Gold: 10, Silver: 5}, input.Sheet.Wallet)
+		s.Equal([]string{"dagger"}, input.CharacterData.Equipment, "the scroll is used up")
+	})
+
+	output, err := s.orchestrator.CopySpellToSpellbook(s.ctx, &character.CopySpellToSpellbookInput{
+		CharacterID: "wizard-1",
+		PlayerID:    "player-1",
+		SpellID:     "SPELL_FEATHER_FALL",
+	})
+
+	s.Require().NoError(err)
+	s.Equal(int32(50), output.CostGold)
+	s.Equal(int32(2), output.Hours)
+	s.Len(output.Spellbook, 7)
+}
+
+func (s *SpellPreparationTestSuite) TestCopySpellToSpellbook_ConcurrentCopyChargesOnce() {
+	scroll := dnd5e.SpellScrollItemID("SPELL_FEATHER_FALL")
+	before := *s.wizard
+	before.Equipment = []string{scroll}
+	after := *s.wizard
+	gomock.InOrder(
+		s.mockCharRepo.EXPECT().
+			Get(s.ctx, characterrepo.GetInput{ID: "wizard-1"}).
+			Return(&characterrepo.GetOutput{
+				CharacterData: &before,
+				Sheet:         &dnd5e.CharacterSheet{Spellbook: startingSpellbook, Wallet: &dnd5e.Wallet{Gold: 60}},
+				Revision:      7,
+			}, nil),
+		s.mockCharRepo.EXPECT().
+			Update(s.ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+				s.Require().NotNil(input.ExpectedRevision)
+				s.Equal(int64(7), *input.ExpectedRevision)
+				return nil, errors.Aborted("character changed")
+			}),
+		// The other copy already used the scroll and paid for it
+		s.mockCharRepo.EXPECT().
+			Get(s.ctx, characterrepo.GetInput{ID: "wizard-1"}).
+			Return(&characterrepo.GetOutput{
+				CharacterData: &after,
+				Sheet: &dnd5e.CharacterSheet{
+					Spellbook:    append(append([]string{}, startingSpellbook...), "SPELL_FEATHER_FALL"),
+					CopiedSpells: []string{"SPELL_FEATHER_FALL"},
+					Wallet:       &dnd5e.Wallet{Gold: 10},
+				},
+				Revision: 8,
+			}, nil),
+	)
+	s.expectWizardSpells()
+	s.expectWizardSpells()
+	s.mockExtClient.EXPECT().
+		GetSpellData(s.ctx, "SPELL_FEATHER_FALL").
+		Return(&external.SpellData{ID: "SPELL_FEATHER_FALL", Level: 1}, nil)
+
+	output, err := s.orchestrator.CopySpellToSpellbook(s.ctx, &character.CopySpellToSpellbookInput{
+		CharacterID: "wizard-1",
+		PlayerID:    "player-1",
+		SpellID:     "SPELL_FEATHER_FALL",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *SpellPreparationTestSuite) TestCopySpellToSpellbook_InsufficientFunds() {
+	s.wizard.Equipment = []string{dnd5e.SpellScrollItemID("SPELL_FEATHER_FALL")}
+	s.expectGet(s.wizard, &dnd5e.CharacterSheet{
+		Spellbook: startingSpellbook,
+		Wallet:    &dnd5e.Wallet{Gold: 49, Silver: 9},
+	})
+	s.expectWizardSpells()
+	s.mockExtClient.EXPECT().
+		GetSpellData(s.ctx, "SPELL_FEATHER_FALL").
+		Return(&external.SpellData{ID: "SPELL_FEATHER_FALL", Level: 1}, nil)
+
+	output, err := s.orchestrator.CopySpellToSpellbook(s.ctx, &character.CopySpellToSpellbookInput{
+		CharacterID: "wizard-1",
+		PlayerID:    "player-1",
+		SpellID:     "SPELL_FEATHER_FALL",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *SpellPreparationTestSuite) TestCopySpellToSpellbook_NoScroll() {
+	s.wizard.Equipment = []string{dnd5e.SpellScrollItemID("SPELL_THUNDERWAVE")}
+	s.expectGet(s.wizard, &dnd5e.CharacterSheet{
+		Spellbook: startingSpellbook,
+		Wallet:    &dnd5e.Wallet{Gold: 60},
+	})
+	s.expectWizardSpells()
+
+	output, err := s.orchestrator.CopySpellToSpellbook(s.ctx, &character.CopySpellToSpellbookInput{
+		CharacterID: "wizard-1",
+		PlayerID:    "player-1",
+		SpellID:     "SPELL_FEATHER_FALL",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+	s.Contains(err.Error(), "no spell scroll")
+}
+
+func (s *SpellPreparationTestSuite) TestCopySpellToSpellbook_AlreadyInSpellbook() {
+	s.expectGet(s.wizard, &dnd5e.CharacterSheet{Spellbook: startingSpellbook})
+	s.expectWizardSpells()
+
+	output, err := s.orchestrator.CopySpellToSpellbook(s.ctx, &character.CopySpellToSpellbookInput{
+		CharacterID: "wizard-1",
+		PlayerID:    "player-1",
+		SpellID:     "SPELL_MAGIC_MISSILE",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *SpellPreparationTestSuite) TestPrepareSpells_FromCopiedSpell() {
+	s.expectGet(s.wizard, &dnd5e.CharacterSheet{
+		Spellbook:    append(append([]string{}, startingSpellbook...), "SPELL_FEATHER_FALL"),
+		CopiedSpells: []string{"SPELL_FEATHER_FALL"},
+	})
+	s.expectWizardSpells()
+	s.expectUpdate(func(input characterrepo.UpdateInput) {
+		s.Equal([]string{"SPELL_FEATHER_FALL", "SPELL_MAGIC_MISSILE"}, input.Sheet.PreparedSpells)
+	})
+
+	_, err := s.orchestrator.PrepareSpells(s.ctx, &character.PrepareSpellsInput{
+		CharacterID: "wizard-1",
+		PlayerID:    "player-1",
+		SpellIDs:    []string{"SPELL_FEATHER_FALL", "SPELL_MAGIC_MISSILE"},
+	})
+
+	s.Require().NoError(err)
+}
+
+func (s *FinalizeDraftOrchestratorTestSuite) TestFinalizeDraft_WizardStartsSpellbook() {
+	s.setupClassDraft(constants.ClassWizard, 6, toolkitchar.ChoiceData{
+		Category:       shared.ChoiceSpells,
+		Source:         shared.SourceClass,
+		ChoiceID:       "wizard_spells",
+		SpellSelection: startingSpellbook,
+	})
+	s.expectCreateAndDelete(func(input characterrepo.CreateInput) {
+		s.Require().NotNil(input.Sheet)
+		s.Equal(startingSpellbook, input.Sheet.Spellbook)
+	})
+
+	_, err := s.orchestrator.FinalizeDraft(s.ctx, &character.FinalizeDraftInput{DraftID: "draft-wizard"})
+
+	s.Require().NoError(err)
+}
+
+func (s *SpellPreparationTestSuite) TestLearnSpells_NotOwner() {
+	s.expectGet(s.wizard, &dnd5e.CharacterSheet{Spellbook: startingSpellbook})
+
+	output, err := s.orchestrator.LearnSpells(s.ctx, &character.LearnSpellsInput{
+		CharacterID: "wizard-1",
+		PlayerID:    "player-2",
+		SpellIDs:    []string{"SPELL_FEATHER_FALL"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
+
+func (s *SpellPreparationTestSuite) TestCopySpellToSpellbook_NotOwner() {
+	s.wizard.Equipment = []string{dnd5e.SpellScrollItemID("SPELL_FEATHER_FALL")}
+	s.expectGet(s.wizard, &dnd5e.CharacterSheet{
+		Spellbook: startingSpellbook,
+		Wallet:    &dnd5e.Wallet{Gold: 60},
+	})
+
+	output, err := s.orchestrator.CopySpellToSpellbook(s.ctx, &character.CopySpellToSpellbookInput{
+		CharacterID: "wizard-1",
+		PlayerID:    "player-2",
+		SpellID:     "SPELL_FEATHER_FALL",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsPermissionDenied(err))
+}
@@ -40,6 +40,26 @@ type Service interface {
 	GetCharacter(ctx context.Context, input *GetCharacterInput) (*GetCharacterOutput, error)
 	ListCharacters(ctx context.Context, input *ListCharactersInput) (*ListCharactersOutput, error)
 	DeleteCharacter(ctx context.Context, input *DeleteCharacterInput) (*DeleteCharacterOutput, error)
+	RestoreCharacter(ctx context.Context, input *RestoreCharacterInput) (*RestoreCharacterOutput, error)
+	ListDeletedCharacters(ctx context.Context, input *ListDeletedCharactersInput) (*ListDeletedCharactersOutput, error)
+	HardDeleteCharacter(ctx context.Context, input *HardDeleteCharacterInput) (*HardDeleteCharacterOutput, error)
+	PurgeDeletedCharacters(ctx context.Context, input *PurgeDeletedCharactersInput) (*PurgeDeletedCharactersOutput, error)
+	UpdateCharacterDetails(ctx context.Context, input *UpdateCharacterDetailsInput) (*UpdateCharacterDetailsOutput, error)
+
+	// Sharing and ownership
+	ShareCharacter(ctx context.Context, input *ShareCharacterInput) (*ShareCharacterOutput, error)
+	UnshareCharacter(ctx context.Context, input *UnshareCharacterInput) (*UnshareCharacterOutput, error)
+	RequestCharacterTransfer(ctx context.Context, input *RequestCharacterTransferInput) (*RequestCharacterTransferOutput, error)
+	RespondToCharacterTransfer(ctx context.Context, input *RespondToCharacterTransferInput) (*RespondToCharacterTransferOutput, error)
+
+	// Game sessions
+	JoinSession(ctx context.Context, input *JoinSessionInput) (*JoinSessionOutput, error)
+	LeaveSession(ctx context.Context, input *LeaveSessionInput) (*LeaveSessionOutput, error)
+	ListSessionCharacters(ctx context.Context, input *ListSessionCharactersInput) (*ListSessionCharactersOutput, error)
+
+	// Character history
+	GetCharacterTimeline(ctx context.Context, input *GetCharacterTimelineInput) (*GetCharacterTimelineOutput, error)
+	GetCharacterAtTime(ctx context.Context, input *GetCharacterAtTimeInput) (*GetCharacterAtTimeOutput, error)
 
 	// Data loading for UI
 	ListRaces(ctx context.Context, input *ListRacesInput) (*ListRacesOutput, error)
@@ -65,6 +85,19 @@ type Service interface {
 	UnequipItem(ctx context.Context, input *UnequipItemInput) (*UnequipItemOutput, error)
 	AddToInventory(ctx context.Context, input *AddToInventoryInput) (*AddToInventoryOutput, error)
 	RemoveFromInventory(ctx context.Context, input *RemoveFromInventoryInput) (*RemoveFromInventoryOutput, error)
+
+	// Magic item attunement
+	AttuneItem(ctx context.Context, input *AttuneItemInput) (*AttuneItemOutput, error)
+	UnattuneItem(ctx context.Context, input *UnattuneItemInput) (*UnattuneItemOutput, error)
+
+	// Spellcasting
+	PrepareSpells(ctx context.Context, input *PrepareSpellsInput) (*PrepareSpellsOutput, error)
+	CanCastSpell(ctx context.Context, input *CanCastSpellInput) (*CanCastSpellOutput, error)
+	LongRest(ctx context.Context, input *LongRestInput) (*LongRestOutput, error)
+
+	// Wizard spellbook
+	LearnSpells(ctx context.Context, input *LearnSpellsInput) (*LearnSpellsOutput, error)
+	CopySpellToSpellbook(ctx context.Context, input *CopySpellToSpellbookInput) (*CopySpellToSpellbookOutput, error)
 }
 
 // Draft lifecycle types
@@ -252,19 +285,122 @@ type FinalizeDraftOutput struct {
 // GetCharacterInput defines the request for getting a character
 type GetCharacterInput struct {
 	CharacterID string
+	ViewerID    string // The player reading; only the owner and players it is shared with may view
+	SessionID   string // Optional, the session the viewer is viewing from, for session shares
+
+	// Internal is set by other orchestrators reading the character on the server's behalf,
+	// such as an encounter loading combat stats. It skips the viewer check.
+	Internal bool
 }
 
 // GetCharacterOutput defines the response for getting a character
 type GetCharacterOutput struct {
+	Character      *character.Data
+	Sheet          *dnd5e.CharacterSheet
+	DerivedStats   *DerivedStats
+	AttackProfiles []*AttackProfile
+	Details        *dnd5e.CharacterDetails
+}
+
+// UpdateCharacterDetailsInput defines the request for editing a character's descriptive details.
+// Nil fields are left unchanged and empty strings clear a field; name cannot be cleared.
+type UpdateCharacterDetailsInput struct {
+	CharacterID       string
+	Name              *string
+	Alignment         *string
+	Appearance        *string
+	Backstory         *string
+	PersonalityTraits *string
+	Ideals            *string
+	Bonds             *string
+	Flaws             *string
+	Notes             *string
+}
+
+// UpdateCharacterDetailsOutput defines the response for editing a character's descriptive details
+type UpdateCharacterDetailsOutput struct {
+	Character *character.Data
+	Details   *dnd5e.CharacterDetails
+}
+
+// GetCharacterTimelineInput defines the request for reading a character's change history
+type GetCharacterTimelineInput struct {
+	CharacterID string
+	ViewerID    string    // The player reading; only the owner and players it is shared with may view
+	SessionID   string    // Optional, the session the viewer is viewing from, for session shares
+	Since       time.Time // Optional, events at or after this time
+	Until       time.Time // Optional, events at or before this time
+}
+
+// GetCharacterTimelineOutput defines the response for reading a character's change history
+type GetCharacterTimelineOutput struct {
+	Events []*dnd5e.CharacterEvent // Oldest first
+}
+
+// GetCharacterAtTimeInput defines the request for rebuilding a character as it was at a point in time
+type GetCharacterAtTimeInput struct {
+	CharacterID string
+	ViewerID    string // The player reading; only the owner and players it is shared with may view
+	SessionID   string // Optional, the session the viewer is viewing from, for session shares
+	At          time.Time
+}
+
+// GetCharacterAtTimeOutput defines the response for rebuilding a character as it was at a point in time
+type GetCharacterAtTimeOutput struct {
 	Character *character.Data
+	Sheet     *dnd5e.CharacterSheet
+	LastEvent *dnd5e.CharacterEvent // The most recent change included in the rebuilt state
+}
+
+// DerivedStats holds values calculated from the character, their equipment and magic items
+type DerivedStats struct {
+	ArmorClass       int32
+	ProficiencyBonus int32
+	SavingThrowBonus int32 // magic item bonus added to every saving throw
+}
+
+// Attack types reported on an AttackProfile
+const (
+	AttackTypeMelee  = "melee"
+	AttackTypeRanged = "ranged"
+)
+
+// AttackProfile describes an attack the character can make with an equipped weapon
+type AttackProfile struct {
+	WeaponID   string
+	WeaponName string
+	Slot       string
+	AttackType string // AttackTypeMelee or AttackTypeRanged
+	Ability    constants.Ability
+	Proficient bool
+
+	AttackBonus int32
+	DamageDice  string
+	DamageBonus int32
+	DamageType  string
+
+	ReachFeet   int32 // melee reach, 0 for ranged weapons
+	NormalRange int32 // ranged or thrown normal range in feet, 0 when it cannot be thrown or fired
+	LongRange   int32 // long range in feet, attacks beyond normal range have disadvantage
+	Properties  []string
 }
 
 // ListCharactersInput defines the request for listing characters
 type ListCharactersInput struct {
 	PageSize  int32
 	PageToken string
-	SessionID string // Optional filter
+	SessionID string // Optional filter, also the session the viewer is viewing from
 	PlayerID  string // Optional filter
+	ViewerID  string // The player listing; other players' characters are only listed if shared with them
+
+	// Optional filters
+	ClassID  constants.Class
+	RaceID   constants.Race
+	MinLevel int
+	MaxLevel int
+
+	SortBy         string // name, level, created_at or updated_at; defaults to created_at
+	SortDescending bool
 }
 
 // ListCharactersOutput defines the response for listing characters
@@ -282,6 +418,144 @@ type DeleteCharacterInput struct {
 // DeleteCharacterOutput defines the response for deleting a character
 type DeleteCharacterOutput struct {
 	Message string
+	PurgeAt time.Time // When the character will be permanently deleted unless restored
+}
+
+// ShareCharacterInput defines the request for sharing a character read-only
+type ShareCharacterInput struct {
+	CharacterID string
+	PlayerID    string   // Must be the character's owner
+	PlayerIDs   []string // Players to share with
+	SessionIDs  []string // Sessions to share with
+}
+
+// ShareCharacterOutput defines the response for sharing a character
+type ShareCharacterOutput struct {
+	SharedWithPlayers  []string
+	SharedWithSessions []string
+}
+
+// UnshareCharacterInput defines the request for revoking read-only access to a character
+type UnshareCharacterInput struct {
+	CharacterID string
+	PlayerID    string   // Must be the character's owner
+	PlayerIDs   []string // Players to stop sharing with
+	SessionIDs  []string // Sessions to stop sharing with
+}
+
+// UnshareCharacterOutput defines the response for revoking access to a character
+type UnshareCharacterOutput struct {
+	SharedWithPlayers  []string
+	SharedWithSessions []string
+}
+
+// RequestCharacterTransferInput defines the request for offering a character to another player
+type RequestCharacterTransferInput struct {
+	CharacterID string
+	PlayerID    string // Must be the character's owner
+	ToPlayerID  string
+}
+
+// RequestCharacterTransferOutput defines the response for offering a character to another player
+type RequestCharacterTransferOutput struct {
+	Transfer *dnd5e.CharacterTransfer
+}
+
+// RespondToCharacterTransferInput defines the request for answering a pending transfer.
+// The recipient may accept or decline; the owner may only decline, which cancels the offer.
+type RespondToCharacterTransferInput struct {
+	CharacterID string
+	PlayerID    string
+	Accept      bool
+}
+
+// RespondToCharacterTransferOutput defines the response for answering a pending transfer
+type RespondToCharacterTransferOutput struct {
+	Character   *character.Data
+	Transferred bool
+}
+
+// JoinSessionInput defines the request for adding a character to a game session
+type JoinSessionInput struct {
+	CharacterID string
+	PlayerID    string // Must be the character's owner
+	SessionID   string
+}
+
+// JoinSessionOutput defines the response for adding a character to a game session
+type JoinSessionOutput struct {
+	SessionIDs []string // Every session the character is now in
+}
+
+// LeaveSessionInput defines the request for removing a character from a game session
+type LeaveSessionInput struct {
+	CharacterID string
+	PlayerID    string // Must be the character's owner
+	SessionID   string
+}
+
+// LeaveSessionOutput defines the response for removing a character from a game session
+type LeaveSessionOutput struct {
+	SessionIDs []string // The sessions the character is still in
+}
+
+// ListSessionCharactersInput defines the request for loading a session's party
+type ListSessionCharactersInput struct {
+	SessionID string
+	ViewerID  string // The player loading the party; only characters they may see are returned
+}
+
+// ListSessionCharactersOutput defines the response for loading a session's party
+type ListSessionCharactersOutput struct {
+	Characters []*character.Data // Ordered by name
+}
+
+// RestoreCharacterInput defines the request for restoring a deleted character
+type RestoreCharacterInput struct {
+	CharacterID string
+	PlayerID    string // Must own the character, taken from actor.ID by the caller
+}
+
+// RestoreCharacterOutput defines the response for restoring a deleted character
+type RestoreCharacterOutput struct {
+	Character *character.Data
+}
+
+// ListDeletedCharactersInput defines the request for listing a player's deleted characters
+type ListDeletedCharactersInput struct {
+	PlayerID string
+}
+
+// ListDeletedCharactersOutput defines the response for listing deleted characters
+type ListDeletedCharactersOutput struct {
+	Characters []*DeletedCharacter
+}
+
+// DeletedCharacter is a soft deleted character that can still be restored
+type DeletedCharacter struct {
+	Character *character.Data
+	DeletedAt time.Time
+	PurgeAt   time.Time
+}
+
+// HardDeleteCharacterInput defines the request for permanently deleting a character
+type HardDeleteCharacterInput struct {
+	CharacterID  string
+	PlayerID     string // Must own the character, taken from actor.ID by the caller
+	Confirmation string // Must match the character's name
+}
+
+// HardDeleteCharacterOutput defines the response for permanently deleting a character
+type HardDeleteCharacterOutput struct {
+	Message string
+}
+
+// PurgeDeletedCharactersInput defines the request for permanently deleting expired characters
+type PurgeDeletedCharactersInput struct{}
+
+// PurgeDeletedCharactersOutput defines the response for purging deleted characters
+type PurgeDeletedCharactersOutput struct {
+	PurgedCharacterIDs []string
 }
 
 // Data loading types for character creation UI
@@ -552,6 +826,7 @@ type GetCharacterInventoryOutput struct {
 // EquipItemInput defines the request for equipping an item
 type EquipItemInput struct {
 	CharacterID string
+	PlayerID    string // Must own the character
 	ItemID      string
 	Slot        string
 }
@@ -566,6 +841,7 @@ type EquipItemOutput struct {
 // UnequipItemInput defines the request for unequipping an item
 type UnequipItemInput struct {
 	CharacterID string
+	PlayerID    string // Must own the character
 	Slot        string
 }
 
@@ -575,10 +851,112 @@ type UnequipItemOutput struct {
 	Character *character.Data
 }
 
+// AttuneItemInput defines the request for attuning to a magic item
+type AttuneItemInput struct {
+	CharacterID string
+	PlayerID    string // Must own the character
+	ItemID      string
+}
+
+// AttuneItemOutput defines the response for attuning to a magic item
+type AttuneItemOutput struct {
+	Character    *character.Data
+	AttunedItems []string
+}
+
+// UnattuneItemInput defines the request for ending attunement to a magic item
+type UnattuneItemInput struct {
+	CharacterID string
+	PlayerID    string // Must own the character
+	ItemID      string
+}
+
+// UnattuneItemOutput defines the response for ending attunement to a magic item
+type UnattuneItemOutput struct {
+	Character    *character.Data
+	AttunedItems []string
+}
+
+// PrepareSpellsInput defines the request for setting a prepared caster's spell list
+type PrepareSpellsInput struct {
+	CharacterID string
+	PlayerID    string // Must own the character
+	SpellIDs    []string
+}
+
+// PrepareSpellsOutput defines the response for setting a prepared caster's spell list
+type PrepareSpellsOutput struct {
+	Character            *character.Data
+	PreparedSpells       []string
+	AlwaysPreparedSpells []string
+	MaxPrepared          int32
+}
+
+// CanCastSpellInput defines the request for checking whether a character can cast a spell
+type CanCastSpellInput struct {
+	CharacterID string
+	SpellID     string
+	SlotLevel   int32 // Optional, defaults to the spell's level
+}
+
+// CanCastSpellOutput defines the response for checking whether a character can cast a spell
+type CanCastSpellOutput struct {
+	CanCast   bool
+	Reason    string // Why the spell cannot be cast, empty when it can
+	SpellData *external.SpellData
+	SlotLevel int32
+}
+
+// LongRestInput defines the request for a character finishing a long rest
+type LongRestInput struct {
+	CharacterID string
+	PlayerID    string // Must own the character
+}
+
+// LongRestOutput defines the response for a character finishing a long rest
+type LongRestOutput struct {
+	Character *character.Data
+}
+
+// LearnSpellsInput defines the request for adding the free spells a wizard gains on level up
+type LearnSpellsInput struct {
+	CharacterID string
+	PlayerID    string // Must own the character
+	SpellIDs    []string
+}
+
+// LearnSpellsOutput defines the response for adding the free spells a wizard gains on level up
+type LearnSpellsOutput struct {
+	Character           *character.Data
+	Spellbook           []string
+	FreeSpellsRemaining int32
+}
+
+// CopySpellToSpellbookInput defines the request for copying a spell found on a scroll
+type CopySpellToSpellbookInput struct {
+	CharacterID string
+	PlayerID    string // Must own the character
+	SpellID     string
+}
+
+// CopySpellToSpellbookOutput defines the response for copying a spell found on a scroll
+type CopySpellToSpellbookOutput struct {
+	Character *character.Data
+	Spellbook []string
+	Wallet    *dnd5e.Wallet
+	CostGold  int32
+	Hours     int32
+}
+
 // AddToInventoryInput defines the request for adding item to inventory
 type AddToInventoryInput struct {
 	CharacterID string
+	PlayerID    string // The DM granting the items; must own a campaign the character is in
 	Items       []InventoryAddition
+
+	// Internal is set by other orchestrators granting items on the server's behalf,
+	// such as loot or quest rewards. It skips the DM check.
+	Internal bool
 }
 
 // AddToInventoryOutput defines the response for adding item to inventory
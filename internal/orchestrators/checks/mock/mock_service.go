@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/KirkDiggler/rpg-api/internal/orchestrators/checks (interfaces: Service)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/mock_service.go -package=checksmock github.com/KirkDiggler/rpg-api/internal/orchestrators/checks Service
+//
+
+// Package checksmock is a generated GoMock package.
+package checksmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+
+	checks "github.com/KirkDiggler/rpg-api/internal/orchestrators/checks"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// GetPassiveScores mocks base method.
+func (m *MockService) GetPassiveScores(ctx context.Context, input *checks.GetPassiveScoresInput) (*checks.GetPassiveScoresOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPassiveScores", ctx, input)
+	ret0, _ := ret[0].(*checks.GetPassiveScoresOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPassiveScores indicates an expected call of GetPassiveScores.
+func (mr *MockServiceMockRecorder) GetPassiveScores(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPassiveScores", reflect.TypeOf((*MockService)(nil).GetPassiveScores), ctx, input)
+}
+
+// RollCheck mocks base method.
+func (m *MockService) RollCheck(ctx context.Context, input *checks.RollCheckInput) (*checks.RollCheckOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollCheck", ctx, input)
+	ret0, _ := ret[0].(*checks.RollCheckOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollCheck indicates an expected call of RollCheck.
+func (mr *MockServiceMockRecorder) RollCheck(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollCheck", reflect.TypeOf((*MockService)(nil).RollCheck), ctx, input)
+}
+
+// RollContestedCheck mocks base method.
+func (m *MockService) RollContestedCheck(ctx context.Context, input *checks.RollContestedCheckInput) (*checks.RollContestedCheckOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollContestedCheck", ctx, input)
+	ret0, _ := ret[0].(*checks.RollContestedCheckOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollContestedCheck indicates an expected call of RollContestedCheck.
+func (mr *MockServiceMockRecorder) RollContestedCheck(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollContestedCheck", reflect.TypeOf((*MockService)(nil).RollContestedCheck), ctx, input)
+}
+
+// RollGroupCheck mocks base method.
+func (m *MockService) RollGroupCheck(ctx context.Context, input *checks.RollGroupCheckInput) (*checks.RollGroupCheckOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollGroupCheck", ctx, input)
+	ret0, _ := ret[0].(*checks.RollGroupCheckOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollGroupCheck indicates an expected call of RollGroupCheck.
+func (mr *MockServiceMockRecorder) RollGroupCheck(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollGroupCheck", reflect.TypeOf((*MockService)(nil).RollGroupCheck), ctx, input)
+}
@@ -0,0 +1,519 @@
+// Package checks implements ability checks, skill checks and saving throws for saved characters
+package checks
+
+//go:generate mockgen -destination=mock/mock_service.go -package=checksmock github.com/KirkDiggler/rpg-api/internal/orchestrators/checks Service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/dice"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/conditions"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+const (
+	// ContextChecks is the dice session context check rolls are recorded under
+	ContextChecks = "checks"
+
+	// passiveBase is the base of a passive score, before modifiers
+	passiveBase = 10
+
+	// passiveAdvantageBonus is added to (or subtracted from) a passive score for advantage (or disadvantage)
+	passiveAdvantageBonus = 5
+
+	sourceSituational = "situational"
+)
+
+// Service defines the interface for check operations
+type Service interface {
+	// RollCheck rolls a single ability check, skill check or saving throw
+	RollCheck(ctx context.Context, input *RollCheckInput) (*RollCheckOutput, error)
+
+	// RollGroupCheck rolls the same check for several characters against one DC
+	RollGroupCheck(ctx context.Context, input *RollGroupCheckInput) (*RollGroupCheckOutput, error)
+
+	// RollContestedCheck rolls a check for two characters and compares the totals
+	RollContestedCheck(ctx context.Context, input *RollContestedCheckInput) (*RollContestedCheckOutput, error)
+
+	// GetPassiveScores returns passive Perception, Investigation and Insight
+	GetPassiveScores(ctx context.Context, input *GetPassiveScoresInput) (*GetPassiveScoresOutput, error)
+}
+
+// Config holds the dependencies for the checks orchestrator
+type Config struct {
+	// CharacterService loads characters with their sheet and derived stats, so checks use
+	// the same magic item bonuses as the character sheet
+	CharacterService character.Service
+	DiceService      dice.Service
+}
+
+// Validate ensures all required dependencies are provided
+func (c *Config) Validate() error {
+	vb := errors.NewValidationBuilder()
+
+	if c.CharacterService == nil {
+		vb.RequiredField("CharacterService")
+	}
+	if c.DiceService == nil {
+		vb.RequiredField("DiceService")
+	}
+
+	return vb.Build()
+}
+
+type orchestrator struct {
+	charService character.Service
+	diceService dice.Service
+}
+
+// NewOrchestrator creates a new checks orchestrator with the provided dependencies
+func NewOrchestrator(cfg *Config) (Service, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid config")
+	}
+
+	return &orchestrator{
+		charService: cfg.CharacterService,
+		diceService: cfg.DiceService,
+	}, nil
+}
+
+// RollCheck rolls a single ability check, skill check or saving throw
+func (o *orchestrator) RollCheck(ctx context.Context, input *RollCheckInput) (*RollCheckOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if err := validateCheck(input.Check); err != nil {
+		return nil, err
+	}
+	if input.DC < 0 {
+		return nil, errors.InvalidArgument("DC cannot be negative")
+	}
+
+	subject, err := o.getCharacter(ctx, input.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newCheckResult(subject, input.Check)
+	if input.Advantage {
+		result.AdvantageSources = append(result.AdvantageSources, sourceSituational)
+	}
+	if input.Disadvantage {
+		result.DisadvantageSources = append(result.DisadvantageSources, sourceSituational)
+	}
+
+	if err = o.roll(ctx, result, input.DC); err != nil {
+		return nil, err
+	}
+
+	return &RollCheckOutput{Result: result}, nil
+}
+
+// RollGroupCheck rolls the same check for several characters against one DC
+func (o *orchestrator) RollGroupCheck(ctx context.Context, input *RollGroupCheckInput) (*RollGroupCheckOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if len(input.CharacterIDs) == 0 {
+		return nil, errors.InvalidArgument("at least one character ID is required")
+	}
+	if input.DC <= 0 {
+		return nil, errors.InvalidArgument("group checks require a DC")
+	}
+	if err := validateCheck(input.Check); err != nil {
+		return nil, err
+	}
+
+	output := &RollGroupCheckOutput{
+		Results: make([]*CheckResult, 0, len(input.CharacterIDs)),
+	}
+
+	for _, characterID := range input.CharacterIDs {
+		if characterID == "" {
+			return nil, errors.InvalidArgument("character IDs cannot be empty")
+		}
+
+		subject, err := o.getCharacter(ctx, characterID)
+		if err != nil {
+			return nil, err
+		}
+
+		result := newCheckResult(subject, input.Check)
+		if err = o.roll(ctx, result, input.DC); err != nil {
+			return nil, err
+		}
+
+		output.Results = append(output.Results, result)
+		if result.Success {
+			output.Successes++
+		}
+	}
+
+	// nolint:gosec // group sizes are small
+	output.Success = output.Successes*2 >= int32(len(output.Results))
+
+	slog.InfoContext(ctx, "rolled group check",
+		"check", describeCheck(input.Check),
+		"dc", input.DC,
+		"successes", output.Successes,
+		"members", len(output.Results),
+		"success", output.Success)
+
+	return output, nil
+}
+
+// RollContestedCheck rolls a check for two characters and compares the totals
+func (o *orchestrator) RollContestedCheck(
+	ctx context.Context,
+	input *RollContestedCheckInput,
+) (*RollContestedCheckOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.InitiatorID == "" {
+		return nil, errors.InvalidArgument("initiator ID is required")
+	}
+	if input.DefenderID == "" {
+		return nil, errors.InvalidArgument("defender ID is required")
+	}
+	if input.InitiatorID == input.DefenderID {
+		return nil, errors.InvalidArgument("a character cannot contest themselves")
+	}
+	if len(input.DefenderChecks) == 0 {
+		return nil, errors.InvalidArgument("at least one defender check is required")
+	}
+	if err := validateCheck(input.InitiatorCheck); err != nil {
+		return nil, err
+	}
+	for _, check := range input.DefenderChecks {
+		if err := validateCheck(check); err != nil {
+			return nil, err
+		}
+	}
+
+	initiatorSubject, err := o.getCharacter(ctx, input.InitiatorID)
+	if err != nil {
+		return nil, err
+	}
+	defenderSubject, err := o.getCharacter(ctx, input.DefenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	initiator := newCheckResult(initiatorSubject, input.InitiatorCheck)
+	if err = o.roll(ctx, initiator, 0); err != nil {
+		return nil, err
+	}
+
+	// The defender resists with whichever allowed check suits them best
+	var defender *CheckResult
+	for _, check := range input.DefenderChecks {
+		candidate := newCheckResult(defenderSubject, check)
+		if defender == nil || betterOption(candidate, defender) {
+			defender = candidate
+		}
+	}
+	if err = o.roll(ctx, defender, 0); err != nil {
+		return nil, err
+	}
+
+	output := &RollContestedCheckOutput{
+		Initiator:     initiator,
+		Defender:      defender,
+		InitiatorWins: !initiator.AutoFailed && (defender.AutoFailed || initiator.Total > defender.Total),
+	}
+	initiator.Success = output.InitiatorWins
+	defender.Success = !output.InitiatorWins
+
+	slog.InfoContext(ctx, "rolled contested check",
+		"initiator_id", input.InitiatorID,
+		"initiator_check", describeCheck(initiator.Check),
+		"initiator_total", initiator.Total,
+		"defender_id", input.DefenderID,
+		"defender_check", describeCheck(defender.Check),
+		"defender_total", defender.Total,
+		"initiator_wins", output.InitiatorWins)
+
+	return output, nil
+}
+
+// GetPassiveScores returns passive Perception, Investigation and Insight
+func (o *orchestrator) GetPassiveScores(
+	ctx context.Context,
+	input *GetPassiveScoresInput,
+) (*GetPassiveScoresOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+
+	subject, err := o.getCharacter(ctx, input.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetPassiveScoresOutput{
+		Perception:    passiveScore(subject, constants.SkillPerception),
+		Investigation: passiveScore(subject, constants.SkillInvestigation),
+		Insight:       passiveScore(subject, constants.SkillInsight),
+	}, nil
+}
+
+// roll rolls the d20s for a prepared result and fills in the totals
+func (o *orchestrator) roll(ctx context.Context, result *CheckResult, dc int32) error {
+	result.DC = dc
+	result.Advantage = len(result.AdvantageSources) > 0
+	result.Disadvantage = len(result.DisadvantageSources) > 0
+
+	if result.AutoFailed {
+		result.Success = false
+		return nil
+	}
+
+	// Advantage and disadvantage cancel out no matter how many sources each has
+	notation := "1d20"
+	if result.Advantage != result.Disadvantage {
+		notation = "2d20"
+	}
+
+	rollOutput, err := o.diceService.RollDice(ctx, &dice.RollDiceInput{
+		EntityID:    result.CharacterID,
+		Context:     ContextChecks,
+		Notation:    notation,
+		Description: describeCheck(result.Check),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to roll %s", describeCheck(result.Check))
+	}
+	if rollOutput.Roll == nil || len(rollOutput.Roll.Dice) == 0 {
+		return errors.Internal("dice roll returned no dice")
+	}
+
+	result.Rolls = rollOutput.Roll.Dice
+	result.Roll = result.Rolls[0]
+	for _, die := range result.Rolls[1:] {
+		if result.Advantage && !result.Disadvantage {
+			result.Roll = max(result.Roll, die)
+		} else if result.Disadvantage && !result.Advantage {
+			result.Roll = min(result.Roll, die)
+		}
+	}
+
+	result.Total = result.Roll + result.Modifier
+	if dc > 0 {
+		result.Success = result.Total >= dc
+	}
+
+	return nil
+}
+
+// subject is a character making a check, with the sheet and derived stats the check draws on
+type subject struct {
+	data    *toolkitchar.Data
+	sheet   *dnd5e.CharacterSheet
+	derived *character.DerivedStats
+}
+
+// getCharacter loads a saved character with its sheet and derived stats, mapping not found errors
+func (o *orchestrator) getCharacter(ctx context.Context, characterID string) (*subject, error) {
+	getOutput, err := o.charService.GetCharacter(ctx, &character.GetCharacterInput{
+		CharacterID: characterID,
+		Internal:    true,
+	})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NotFoundf("character %s not found", characterID)
+		}
+		return nil, errors.Wrapf(err, "failed to get character %s", characterID)
+	}
+	return &subject{
+		data:    getOutput.Character,
+		sheet:   dnd5e.SheetOrEmpty(getOutput.Sheet),
+		derived: getOutput.DerivedStats,
+	}, nil
+}
+
+// newCheckResult calculates the modifier and condition effects for a check before it is rolled
+func newCheckResult(subj *subject, check Check) *CheckResult {
+	charData := subj.data
+	ability := check.Ability
+	if check.Type == CheckTypeSkill && ability == "" {
+		ability = check.Skill.Ability()
+	}
+
+	result := &CheckResult{
+		CharacterID:     charData.ID,
+		Check:           check,
+		Ability:         ability,
+		AbilityModifier: dnd5e.AbilityModifier(charData.AbilityScores[ability]),
+	}
+
+	profBonus := dnd5e.ProficiencyBonus(charData.Level)
+	switch check.Type {
+	case CheckTypeSkill:
+		switch level := charData.Skills[check.Skill]; {
+		case level >= shared.Expertise:
+			result.ProficiencyBonus = profBonus * 2
+			result.Expertise = true
+		case level >= shared.Proficient:
+			result.ProficiencyBonus = profBonus
+		}
+	case CheckTypeTool:
+		switch {
+		case dnd5e.HasTool(subj.sheet.ToolExpertise, check.Tool):
+			result.ProficiencyBonus = profBonus * 2
+			result.Expertise = true
+		case dnd5e.HasTool(charData.Proficiencies.Tools, check.Tool):
+			result.ProficiencyBonus = profBonus
+		}
+	case CheckTypeSavingThrow:
+		if charData.SavingThrows[ability] >= shared.Proficient {
+			result.ProficiencyBonus = profBonus
+		}
+		if subj.derived != nil {
+			result.ItemBonus = subj.derived.SavingThrowBonus
+		}
+	}
+	result.Modifier = result.AbilityModifier + result.ProficiencyBonus + result.ItemBonus
+
+	applyConditions(charData, result)
+
+	return result
+}
+
+// applyConditions records advantage, disadvantage and automatic failures from the character's conditions
+func applyConditions(charData *toolkitchar.Data, result *CheckResult) {
+	exhaustion := exhaustionLevel(charData)
+
+	if result.Check.Type == CheckTypeSavingThrow {
+		if exhaustion >= 3 {
+			result.DisadvantageSources = append(result.DisadvantageSources, "exhaustion")
+		}
+		physical := result.Ability == constants.STR || result.Ability == constants.DEX
+
+		for _, condition := range charData.Conditions {
+			switch condition.Type {
+			case conditions.Restrained:
+				if result.Ability == constants.DEX {
+					result.DisadvantageSources = append(result.DisadvantageSources, string(condition.Type))
+				}
+			case conditions.Paralyzed, conditions.Stunned, conditions.Unconscious, conditions.Petrified:
+				if physical {
+					result.AutoFailed = true
+				}
+			}
+		}
+		return
+	}
+
+	// Ability checks, including skill checks
+	if exhaustion >= 1 {
+		result.DisadvantageSources = append(result.DisadvantageSources, "exhaustion")
+	}
+	for _, condition := range charData.Conditions {
+		switch condition.Type {
+		case conditions.Poisoned, conditions.Frightened:
+			result.DisadvantageSources = append(result.DisadvantageSources, string(condition.Type))
+		}
+	}
+}
+
+// exhaustionLevel returns the highest exhaustion level from the counter or exhaustion conditions
+func exhaustionLevel(charData *toolkitchar.Data) int {
+	level := charData.Exhaustion
+	for _, condition := range charData.Conditions {
+		suffix, ok := strings.CutPrefix(string(condition.Type), "exhaustion_")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(suffix); err == nil {
+			level = max(level, n)
+		}
+	}
+	return level
+}
+
+// passiveScore is 10 + the skill modifier, +5 with advantage or -5 with disadvantage
+func passiveScore(subj *subject, skill constants.Skill) int32 {
+	result := newCheckResult(subj, Check{Type: CheckTypeSkill, Skill: skill})
+
+	score := passiveBase + result.Modifier
+	hasAdvantage := len(result.AdvantageSources) > 0
+	hasDisadvantage := len(result.DisadvantageSources) > 0
+	if hasAdvantage && !hasDisadvantage {
+		score += passiveAdvantageBonus
+	} else if hasDisadvantage && !hasAdvantage {
+		score -= passiveAdvantageBonus
+	}
+	return score
+}
+
+// betterOption reports whether a defender check option is preferable to the current choice
+func betterOption(candidate, current *CheckResult) bool {
+	if candidate.AutoFailed != current.AutoFailed {
+		return current.AutoFailed
+	}
+	return candidate.Modifier > current.Modifier
+}
+
+func validateCheck(check Check) error {
+	switch check.Type {
+	case CheckTypeSkill:
+		if check.Skill.Ability() == "" {
+			return errors.InvalidArgumentf("invalid skill: %s", check.Skill)
+		}
+		if check.Ability != "" && !validAbility(check.Ability) {
+			return errors.InvalidArgumentf("invalid ability: %s", check.Ability)
+		}
+	case CheckTypeTool:
+		if check.Tool == "" {
+			return errors.InvalidArgument("tool is required for tool checks")
+		}
+		if !validAbility(check.Ability) {
+			return errors.InvalidArgumentf("invalid ability: %s", check.Ability)
+		}
+	case CheckTypeAbility, CheckTypeSavingThrow:
+		if !validAbility(check.Ability) {
+			return errors.InvalidArgumentf("invalid ability: %s", check.Ability)
+		}
+	default:
+		return errors.InvalidArgumentf("invalid check type: %s", check.Type)
+	}
+	return nil
+}
+
+func validAbility(ability constants.Ability) bool {
+	switch ability {
+	case constants.STR, constants.DEX, constants.CON, constants.INT, constants.WIS, constants.CHA:
+		return true
+	}
+	return false
+}
+
+func describeCheck(check Check) string {
+	switch check.Type {
+	case CheckTypeSkill:
+		return fmt.Sprintf("%s check", check.Skill)
+	case CheckTypeTool:
+		return fmt.Sprintf("%s (%s) check", check.Ability, check.Tool)
+	case CheckTypeSavingThrow:
+		return fmt.Sprintf("%s saving throw", check.Ability)
+	default:
+		return fmt.Sprintf("%s check", check.Ability)
+	}
+}
@@ -0,0 +1,350 @@
+package checks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	charactermock "github.com/KirkDiggler/rpg-api/internal/orchestrators/character/mock"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/checks"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/dice"
+	dicemock "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice/mock"
+	dicesession "github.com/KirkDiggler/rpg-api/internal/repositories/dice_session"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/conditions"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+type OrchestratorTestSuite struct {
+	suite.Suite
+	ctrl         *gomock.Controller
+	mockCharSvc  *charactermock.MockService
+	mockDice     *dicemock.MockService
+	orchestrator checks.Service
+	ctx          context.Context
+
+	rogue   *toolkitchar.Data
+	fighter *toolkitchar.Data
+}
+
+func (s *OrchestratorTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockCharSvc = charactermock.NewMockService(s.ctrl)
+	s.mockDice = dicemock.NewMockService(s.ctrl)
+	s.ctx = context.Background()
+
+	orchestrator, err := checks.NewOrchestrator(&checks.Config{
+		CharacterService: s.mockCharSvc,
+		DiceService:      s.mockDice,
+	})
+	s.Require().NoError(err)
+	s.orchestrator = orchestrator
+
+	s.rogue = &toolkitchar.Data{
+		ID:    "rogue-1",
+		Level: 1,
+		AbilityScores: shared.AbilityScores{
+			constants.STR: 10,
+			constants.DEX: 16,
+			constants.CON: 12,
+			constants.INT: 13,
+			constants.WIS: 12,
+			constants.CHA: 9,
+		},
+		Skills: map[constants.Skill]shared.ProficiencyLevel{
+			constants.SkillStealth:    shared.Expertise,
+			constants.SkillAcrobatics: shared.Proficient,
+			constants.SkillPerception: shared.Proficient,
+		},
+		SavingThrows: map[constants.Ability]shared.ProficiencyLevel{
+			constants.DEX: shared.Proficient,
+			constants.INT: shared.Proficient,
+		},
+	}
+	s.fighter = &toolkitchar.Data{
+		ID:    "fighter-1",
+		Level: 5,
+		AbilityScores: shared.AbilityScores{
+			constants.STR: 17,
+			constants.DEX: 12,
+			constants.CON: 14,
+			constants.INT: 8,
+			constants.WIS: 10,
+			constants.CHA: 10,
+		},
+		Skills: map[constants.Skill]shared.ProficiencyLevel{
+			constants.SkillAthletics: shared.Proficient,
+		},
+		SavingThrows: map[constants.Ability]shared.ProficiencyLevel{
+			constants.STR: shared.Proficient,
+			constants.CON: shared.Proficient,
+		},
+	}
+}
+
+func (s *OrchestratorTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *OrchestratorTestSuite) expectCharacter(charData *toolkitchar.Data) {
+	s.expectCharacterOutput(&character.GetCharacterOutput{Character: charData})
+}
+
+func (s *OrchestratorTestSuite) expectCharacterOutput(output *character.GetCharacterOutput) {
+	s.mockCharSvc.EXPECT().
+		GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: output.Character.ID, Internal: true}).
+		Return(output, nil)
+}
+
+func (s *OrchestratorTestSuite) expectRoll(entityID, notation string, rolled ...int32) {
+	s.mockDice.EXPECT().
+		RollDice(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *dice.RollDiceInput) (*dice.RollDiceOutput, error) {
+			s.Equal(entityID, input.EntityID)
+			s.Equal(checks.ContextChecks, input.Context)
+			s.Equal(notation, input.Notation)
+			return &dice.RollDiceOutput{Roll: &dicesession.DiceRoll{Notation: notation, Dice: rolled}}, nil
+		})
+}
+
+func (s *OrchestratorTestSuite) TestRollCheck_ExpertiseDoublesProficiency() {
+	s.expectCharacter(s.rogue)
+	s.expectRoll("rogue-1", "1d20", 8)
+
+	output, err := s.orchestrator.RollCheck(s.ctx, &checks.RollCheckInput{
+		CharacterID: "rogue-1",
+		Check:       checks.Check{Type: checks.CheckTypeSkill, Skill: constants.SkillStealth},
+		DC:          15,
+	})
+
+	s.Require().NoError(err)
+	result := output.Result
+	s.Equal(constants.DEX, result.Ability)
+	s.Equal(int32(3), result.AbilityModifier)
+	s.Equal(int32(4), result.ProficiencyBonus)
+	s.True(result.Expertise)
+	s.Equal(int32(15), result.Total)
+	s.True(result.Success)
+}
+
+func (s *OrchestratorTestSuite) TestRollCheck_PoisonedRollsWithDisadvantage() {
+	s.rogue.Conditions = []conditions.Condition{{Type: conditions.Poisoned}}
+	s.expectCharacter(s.rogue)
+	s.expectRoll("rogue-1", "2d20", 17, 6)
+
+	output, err := s.orchestrator.RollCheck(s.ctx, &checks.RollCheckInput{
+		CharacterID: "rogue-1",
+		Check:       checks.Check{Type: checks.CheckTypeAbility, Ability: constants.INT},
+		DC:          10,
+	})
+
+	s.Require().NoError(err)
+	result := output.Result
+	s.True(result.Disadvantage)
+	s.Equal([]string{"poisoned"}, result.DisadvantageSources)
+	s.Equal([]int32{17, 6}, result.Rolls)
+	s.Equal(int32(6), result.Roll)
+	s.Equal(int32(0), result.ProficiencyBonus)
+	s.Equal(int32(7), result.Total)
+	s.False(result.Success)
+}
+
+func (s *OrchestratorTestSuite) TestRollCheck_AdvantageCancelsDisadvantage() {
+	s.rogue.Exhaustion = 1
+	s.expectCharacter(s.rogue)
+	s.expectRoll("rogue-1", "1d20", 11)
+
+	output, err := s.orchestrator.RollCheck(s.ctx, &checks.RollCheckInput{
+		CharacterID: "rogue-1",
+		Check:       checks.Check{Type: checks.CheckTypeSkill, Skill: constants.SkillAcrobatics},
+		Advantage:   true,
+	})
+
+	s.Require().NoError(err)
+	s.True(output.Result.Advantage)
+	s.True(output.Result.Disadvantage)
+	s.Equal(int32(16), output.Result.Total)
+}
+
+func (s *OrchestratorTestSuite) TestRollCheck_ParalyzedFailsDexSave() {
+	s.rogue.Conditions = []conditions.Condition{{Type: conditions.Paralyzed}}
+	s.expectCharacter(s.rogue)
+
+	output, err := s.orchestrator.RollCheck(s.ctx, &checks.RollCheckInput{
+		CharacterID: "rogue-1",
+		Check:       checks.Check{Type: checks.CheckTypeSavingThrow, Ability: constants.DEX},
+		DC:          5,
+	})
+
+	s.Require().NoError(err)
+	s.True(output.Result.AutoFailed)
+	s.False(output.Result.Success)
+	s.Empty(output.Result.Rolls)
+}
+
+func (s *OrchestratorTestSuite) TestRollCheck_RestrainedDexSave() {
+	s.rogue.Conditions = []conditions.Condition{{Type: conditions.Restrained}}
+	s.expectCharacter(s.rogue)
+	s.expectRoll("rogue-1", "2d20", 4, 14)
+
+	output, err := s.orchestrator.RollCheck(s.ctx, &checks.RollCheckInput{
+		CharacterID: "rogue-1",
+		Check:       checks.Check{Type: checks.CheckTypeSavingThrow, Ability: constants.DEX},
+		DC:          12,
+	})
+
+	s.Require().NoError(err)
+	// 4 + 3 DEX + 2 proficiency
+	s.Equal(int32(9), output.Result.Total)
+	s.False(output.Result.Success)
+}
+
+func (s *OrchestratorTestSuite) TestRollCheck_SaveIncludesMagicItemBonus() {
+	s.expectCharacterOutput(&character.GetCharacterOutput{
+		Character:    s.rogue,
+		DerivedStats: &character.DerivedStats{SavingThrowBonus: 1},
+	})
+	s.expectRoll("rogue-1", "1d20", 9)
+
+	output, err := s.orchestrator.RollCheck(s.ctx, &checks.RollCheckInput{
+		CharacterID: "rogue-1",
+		Check:       checks.Check{Type: checks.CheckTypeSavingThrow, Ability: constants.WIS},
+		DC:          11,
+	})
+
+	s.Require().NoError(err)
+	// 9 + 1 WIS + 1 Cloak of Protection
+	s.Equal(int32(1), output.Result.ItemBonus)
+	s.Equal(int32(11), output.Result.Total)
+	s.True(output.Result.Success)
+}
+
+func (s *OrchestratorTestSuite) TestRollCheck_ToolExpertiseDoublesProficiency() {
+	s.rogue.Proficiencies.Tools = []string{"Thieves' Tools"}
+	s.expectCharacterOutput(&character.GetCharacterOutput{
+		Character: s.rogue,
+		Sheet:     &dnd5e.CharacterSheet{ToolExpertise: []string{"Thieves' Tools"}},
+	})
+	s.expectRoll("rogue-1", "1d20", 5)
+
+	output, err := s.orchestrator.RollCheck(s.ctx, &checks.RollCheckInput{
+		CharacterID: "rogue-1",
+		Check:       checks.Check{Type: checks.CheckTypeTool, Ability: constants.DEX, Tool: "thieves tools"},
+		DC:          12,
+	})
+
+	s.Require().NoError(err)
+	s.True(output.Result.Expertise)
+	// 5 + 3 DEX + 4 doubled proficiency
+	s.Equal(int32(12), output.Result.Total)
+}
+
+func (s *OrchestratorTestSuite) TestRollCheck_ToolWithoutProficiency() {
+	s.expectCharacter(s.fighter)
+	s.expectRoll("fighter-1", "1d20", 10)
+
+	output, err := s.orchestrator.RollCheck(s.ctx, &checks.RollCheckInput{
+		CharacterID: "fighter-1",
+		Check:       checks.Check{Type: checks.CheckTypeTool, Ability: constants.DEX, Tool: "Thieves' Tools"},
+	})
+
+	s.Require().NoError(err)
+	s.Equal(int32(0), output.Result.ProficiencyBonus)
+	s.Equal(int32(11), output.Result.Total)
+}
+
+func (s *OrchestratorTestSuite) TestRollCheck_InvalidCheck() {
+	output, err := s.orchestrator.RollCheck(s.ctx, &checks.RollCheckInput{
+		CharacterID: "rogue-1",
+		Check:       checks.Check{Type: checks.CheckTypeSkill, Skill: "basket-weaving"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *OrchestratorTestSuite) TestRollCheck_CharacterNotFound() {
+	s.mockCharSvc.EXPECT().
+		GetCharacter(s.ctx, &character.GetCharacterInput{CharacterID: "missing", Internal: true}).
+		Return(nil, errors.NotFound("character not found"))
+
+	output, err := s.orchestrator.RollCheck(s.ctx, &checks.RollCheckInput{
+		CharacterID: "missing",
+		Check:       checks.Check{Type: checks.CheckTypeAbility, Ability: constants.STR},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsNotFound(err))
+}
+
+func (s *OrchestratorTestSuite) TestRollGroupCheck_HalfSucceeding() {
+	s.expectCharacter(s.rogue)
+	s.expectRoll("rogue-1", "1d20", 12)
+	s.expectCharacter(s.fighter)
+	s.expectRoll("fighter-1", "1d20", 3)
+
+	output, err := s.orchestrator.RollGroupCheck(s.ctx, &checks.RollGroupCheckInput{
+		CharacterIDs: []string{"rogue-1", "fighter-1"},
+		Check:        checks.Check{Type: checks.CheckTypeSkill, Skill: constants.SkillStealth},
+		DC:           12,
+	})
+
+	s.Require().NoError(err)
+	s.Len(output.Results, 2)
+	s.Equal(int32(1), output.Successes)
+	s.True(output.Success)
+}
+
+func (s *OrchestratorTestSuite) TestRollContestedCheck_GrappleDefenderUsesBestSkill() {
+	s.expectCharacter(s.fighter)
+	s.expectCharacter(s.rogue)
+	s.expectRoll("fighter-1", "1d20", 10)
+	s.expectRoll("rogue-1", "1d20", 11)
+
+	output, err := s.orchestrator.RollContestedCheck(s.ctx, &checks.RollContestedCheckInput{
+		InitiatorID:    "fighter-1",
+		InitiatorCheck: checks.GrappleCheck,
+		DefenderID:     "rogue-1",
+		DefenderChecks: checks.GrappleEscapeChecks,
+	})
+
+	s.Require().NoError(err)
+	// 10 + 3 STR + 3 proficiency against 11 + 3 DEX + 2 proficiency
+	s.Equal(int32(16), output.Initiator.Total)
+	s.Equal(constants.SkillAcrobatics, output.Defender.Check.Skill)
+	s.Equal(int32(16), output.Defender.Total)
+	// Ties keep the status quo
+	s.False(output.InitiatorWins)
+	s.True(output.Defender.Success)
+}
+
+func (s *OrchestratorTestSuite) TestGetPassiveScores() {
+	s.expectCharacter(s.rogue)
+
+	output, err := s.orchestrator.GetPassiveScores(s.ctx, &checks.GetPassiveScoresInput{CharacterID: "rogue-1"})
+
+	s.Require().NoError(err)
+	s.Equal(int32(13), output.Perception)
+	s.Equal(int32(11), output.Investigation)
+	s.Equal(int32(11), output.Insight)
+}
+
+func (s *OrchestratorTestSuite) TestGetPassiveScores_PoisonedIsMinusFive() {
+	s.rogue.Conditions = []conditions.Condition{{Type: conditions.Poisoned}}
+	s.expectCharacter(s.rogue)
+
+	output, err := s.orchestrator.GetPassiveScores(s.ctx, &checks.GetPassiveScoresInput{CharacterID: "rogue-1"})
+
+	s.Require().NoError(err)
+	s.Equal(int32(8), output.Perception)
+}
+
+func TestOrchestratorTestSuite(t *testing.T) {
+	suite.Run(t, new(OrchestratorTestSuite))
+}
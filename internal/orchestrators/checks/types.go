@@ -0,0 +1,126 @@
+package checks
+
+import (
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+)
+
+// CheckType identifies what kind of d20 roll is being made
+type CheckType string
+
+// Check types supported by the checks orchestrator
+const (
+	CheckTypeAbility     CheckType = "ability"
+	CheckTypeSkill       CheckType = "skill"
+	CheckTypeSavingThrow CheckType = "saving_throw"
+	CheckTypeTool        CheckType = "tool"
+)
+
+// Check describes a single roll a character can be asked to make
+type Check struct {
+	Type CheckType
+	// Ability is required for ability checks and saving throws. For skill checks it
+	// overrides the skill's default ability, e.g. Strength (Intimidation).
+	Ability constants.Ability
+	// Skill is required for skill checks
+	Skill constants.Skill
+	// Tool is required for tool checks, e.g. "Thieves' Tools". The ability comes from the task.
+	Tool string
+}
+
+// Grapple contest checks: the grappler rolls Athletics and the target picks Athletics or Acrobatics
+var (
+	GrappleCheck = Check{Type: CheckTypeSkill, Skill: constants.SkillAthletics}
+
+	GrappleEscapeChecks = []Check{
+		{Type: CheckTypeSkill, Skill: constants.SkillAthletics},
+		{Type: CheckTypeSkill, Skill: constants.SkillAcrobatics},
+	}
+)
+
+// CheckResult holds the outcome of a check with the full breakdown of the roll
+type CheckResult struct {
+	CharacterID string
+	Check       Check
+	Ability     constants.Ability
+
+	Rolls []int32 // every d20 rolled, two with advantage or disadvantage
+	Roll  int32   // the d20 that was kept
+
+	AbilityModifier  int32
+	ProficiencyBonus int32 // proficiency applied, doubled for expertise
+	Expertise        bool
+	ItemBonus        int32 // passive magic item bonus, e.g. a Cloak of Protection on saving throws
+	Modifier         int32 // AbilityModifier + ProficiencyBonus + ItemBonus
+	Total            int32 // Roll + Modifier
+
+	Advantage           bool
+	Disadvantage        bool
+	AdvantageSources    []string
+	DisadvantageSources []string
+
+	// DC is zero when the roll was not made against a DC
+	DC      int32
+	Success bool
+	// AutoFailed is set when a condition makes the check fail without a roll
+	AutoFailed bool
+}
+
+// RollCheckInput defines the request for rolling a single check
+type RollCheckInput struct {
+	CharacterID string
+	Check       Check
+	DC          int32
+
+	// Situational advantage or disadvantage granted by the caller
+	Advantage    bool
+	Disadvantage bool
+}
+
+// RollCheckOutput defines the response for rolling a single check
+type RollCheckOutput struct {
+	Result *CheckResult
+}
+
+// RollGroupCheckInput defines the request for a group check
+type RollGroupCheckInput struct {
+	CharacterIDs []string
+	Check        Check
+	DC           int32
+}
+
+// RollGroupCheckOutput defines the response for a group check.
+// The group succeeds when at least half of its members succeed.
+type RollGroupCheckOutput struct {
+	Results   []*CheckResult
+	Successes int32
+	Success   bool
+}
+
+// RollContestedCheckInput defines the request for a contested check
+type RollContestedCheckInput struct {
+	InitiatorID    string
+	InitiatorCheck Check
+	DefenderID     string
+	// DefenderChecks lists the checks the defender may resist with, the best modifier is used
+	DefenderChecks []Check
+}
+
+// RollContestedCheckOutput defines the response for a contested check.
+// A tie leaves the situation unchanged, so the initiator only wins with a higher total.
+type RollContestedCheckOutput struct {
+	Initiator     *CheckResult
+	Defender      *CheckResult
+	InitiatorWins bool
+}
+
+// GetPassiveScoresInput defines the request for a character's passive scores
+type GetPassiveScoresInput struct {
+	CharacterID string
+}
+
+// GetPassiveScoresOutput defines the response for a character's passive scores
+type GetPassiveScoresOutput struct {
+	Perception    int32
+	Investigation int32
+	Insight       int32
+}
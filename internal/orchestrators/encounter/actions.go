@@ -0,0 +1,284 @@
+package encounter
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/initiative"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+// Resources a combatant can spend. The action, bonus action and object interaction
+// belong to its turn; the reaction lasts from the start of one of its turns to the next.
+const (
+	CostAction            = "action"
+	CostBonusAction       = "bonus_action"
+	CostReaction          = "reaction"
+	CostObjectInteraction = "object_interaction"
+)
+
+// Actions the encounter records the cost of but does not resolve
+const (
+	ActionCastSpell = "cast_spell"
+	ActionUseItem   = "use_item"
+	ActionInteract  = "interact" // Drawing a weapon, opening a door and the like
+
+	// ActionDisengage keeps the rest of the turn's movement from provoking opportunity
+	// attacks. It is recorded and applied by the encounter.
+	ActionDisengage = "disengage"
+)
+
+// cunningActionLevel is the rogue level that lets a rogue disengage as a bonus action
+const cunningActionLevel = 2
+
+// defaultCosts is what each action costs. A spell named in the request costs its casting time.
+var defaultCosts = map[string]string{
+	ActionCastSpell: CostAction,
+	ActionUseItem:   CostAction,
+	ActionInteract:  CostObjectInteraction,
+	ActionDisengage: CostAction,
+}
+
+// UseAction spends a resource on a spell, an item, an object interaction or Disengage.
+// Only a reaction can be spent outside the entity's own turn.
+func (o *orchestrator) UseAction(ctx context.Context, input *UseActionInput) (*UseActionOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("encounter_id", input.EncounterID, vb)
+	errors.ValidateRequired("entity_id", input.EntityID, vb)
+	errors.ValidateEnum("action", input.Action, []string{ActionCastSpell, ActionUseItem, ActionInteract, ActionDisengage}, vb)
+	if input.Cost != "" {
+		errors.ValidateEnum("cost", input.Cost,
+			[]string{CostAction, CostBonusAction, CostReaction, CostObjectInteraction}, vb)
+	}
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+
+	return retryOnConflict(func() (*UseActionOutput, error) {
+		return o.useAction(ctx, input)
+	})
+}
+
+// useAction is UseAction against a fresh read of the encounter
+func (o *orchestrator) useAction(ctx context.Context, input *UseActionInput) (*UseActionOutput, error) {
+	getOutput, err := o.repo.Get(ctx, &encounters.GetInput{EncounterID: input.EncounterID})
+	if err != nil {
+		return nil, err
+	}
+	data := getOutput.Data
+	if err := requireNoPendingAction(data); err != nil {
+		return nil, err
+	}
+	if data.RoomData == nil || data.InitiativeData == nil {
+		return nil, errors.FailedPreconditionf("encounter %s has no room or initiative", input.EncounterID)
+	}
+
+	placement, ok := data.RoomData.Entities[input.EntityID]
+	if !ok {
+		return nil, errors.NotFoundf("entity %s is not in encounter %s", input.EntityID, input.EncounterID)
+	}
+
+	// Stats loaded here are only read; the attack that first needs them stores them
+	combatant, err := o.combatant(ctx, maps.Clone(data.Combatants), placement)
+	if err != nil {
+		return nil, err
+	}
+	if combatant.HitPoints <= 0 {
+		return nil, errors.FailedPreconditionf("%s is down and cannot act", nameOf(combatant))
+	}
+	if condition := incapacitatedBy(combatant); condition != "" {
+		return nil, errors.FailedPreconditionf("%s is %s and cannot act", nameOf(combatant), condition)
+	}
+
+	cost, err := o.costOf(ctx, input, placement)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := initiative.LoadFromData(*data.InitiativeData)
+	current := tracker.Current()
+	onTurn := current != nil && current.GetID() == input.EntityID
+	update := &encounters.UpdateInput{EncounterID: input.EncounterID}
+	output := &UseActionOutput{EntityID: input.EntityID, Action: input.Action, Cost: cost}
+
+	if cost == CostReaction {
+		update.ReactionsUsed, err = spendReaction(data, input.EntityID)
+		if err != nil {
+			return nil, err
+		}
+		data.ReactionsUsed = update.ReactionsUsed
+	} else {
+		if !onTurn {
+			return nil, errors.FailedPreconditionf("it is not %s's turn", input.EntityID)
+		}
+		update.Turn = turnFor(data, input.EntityID, tracker.Round())
+		if err := spend(update.Turn, cost); err != nil {
+			return nil, err
+		}
+		if input.Action == ActionDisengage {
+			update.Turn.Disengaged = true
+		}
+		data.Turn = update.Turn
+	}
+
+	// Written at the revision read, so a concurrent action cannot spend the same resource
+	if err := o.update(ctx, data, update); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "entity used an action",
+		"encounter_id", input.EncounterID,
+		"entity_id", input.EntityID,
+		"action", input.Action,
+		"cost", cost,
+		"description", input.Description)
+
+	if onTurn {
+		speed, err := o.speedOf(ctx, data, input.EntityID, placement.EntityType)
+		if err != nil {
+			return nil, err
+		}
+		output.Turn = turnState(data, input.EntityID, tracker.Round(), speed)
+	}
+	return output, nil
+}
+
+// costOf works out what an action costs. A spell costs its casting time and everything
+// else its usual cost. The request may ask to pay differently only where the rules allow:
+// a second object interaction takes the action, and a rogue's Cunning Action lets it
+// disengage as a bonus action.
+func (o *orchestrator) costOf(ctx context.Context, input *UseActionInput, placement spatial.EntityPlacement) (string, error) {
+	cost := defaultCosts[input.Action]
+	if input.Action == ActionCastSpell && input.SpellID != "" {
+		spellData, err := o.externalClient.GetSpellData(ctx, input.SpellID)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get spell %s", input.SpellID)
+		}
+		cost = castingTimeCost(spellData.CastingTime)
+		if cost == "" {
+			return "", errors.FailedPreconditionf("%s takes %s to cast and cannot be cast in combat",
+				spellData.Name, spellData.CastingTime)
+		}
+	}
+	if input.Cost == "" || input.Cost == cost {
+		return cost, nil
+	}
+
+	switch {
+	case input.Action == ActionInteract && input.Cost == CostAction:
+		return input.Cost, nil
+	case input.Action == ActionDisengage && input.Cost == CostBonusAction:
+		hasCunningAction, err := o.hasCunningAction(ctx, placement)
+		if err != nil {
+			return "", err
+		}
+		if !hasCunningAction {
+			return "", errors.FailedPreconditionf("%s needs Cunning Action to disengage as a bonus action", input.EntityID)
+		}
+		return input.Cost, nil
+	case input.Action == ActionCastSpell && input.SpellID == "":
+		return "", errors.InvalidArgumentf("a spell cast with a %s needs its spell_id", input.Cost)
+	}
+	return "", errors.InvalidArgumentf("%s costs a %s, not a %s", input.Action, cost, input.Cost)
+}
+
+// castingTimeCost maps a spell's casting time to the resource it spends, or "" for
+// spells that take longer than a turn
+func castingTimeCost(castingTime string) string {
+	switch strings.ToLower(strings.TrimSpace(castingTime)) {
+	case "1 action":
+		return CostAction
+	case "1 bonus action":
+		return CostBonusAction
+	}
+	if strings.HasPrefix(strings.ToLower(castingTime), "1 reaction") {
+		return CostReaction
+	}
+	return ""
+}
+
+// hasCunningAction reports whether an entity is a rogue of high enough level for Cunning Action
+func (o *orchestrator) hasCunningAction(ctx context.Context, placement spatial.EntityPlacement) (bool, error) {
+	if placement.EntityType != entityTypeCharacter {
+		return false, nil
+	}
+	charOutput, err := o.charRepo.Get(ctx, characterrepo.GetInput{ID: placement.EntityID})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get character %s", placement.EntityID)
+	}
+	charData := charOutput.CharacterData
+	return charData.ClassID == constants.ClassRogue && charData.Level >= cunningActionLevel, nil
+}
+
+// turnFor returns a copy of what an entity has spent this turn. Anything stored for an
+// earlier turn no longer applies, so the entity starts over.
+func turnFor(data *encounters.EncounterData, entityID string, round int) *encounters.TurnData {
+	if data.Turn != nil && data.Turn.EntityID == entityID && data.Turn.Round == round {
+		turn := *data.Turn
+		return &turn
+	}
+	return &encounters.TurnData{EntityID: entityID, Round: round}
+}
+
+// spend marks a turn resource spent, failing if it already is
+func spend(turn *encounters.TurnData, cost string) error {
+	switch cost {
+	case CostAction:
+		if turn.ActionUsed {
+			return errors.FailedPreconditionf("%s has no action left this turn", turn.EntityID)
+		}
+		turn.ActionUsed = true
+	case CostBonusAction:
+		if turn.BonusActionUsed {
+			return errors.FailedPreconditionf("%s has no bonus action left this turn", turn.EntityID)
+		}
+		turn.BonusActionUsed = true
+	case CostObjectInteraction:
+		if turn.ObjectInteractionUsed {
+			return errors.FailedPreconditionf(
+				"%s has already interacted with an object this turn; another interaction takes an action", turn.EntityID)
+		}
+		turn.ObjectInteractionUsed = true
+	default:
+		return errors.Internalf("unknown turn resource %q", cost)
+	}
+	return nil
+}
+
+// spendReaction returns the spent reactions with the entity's added, failing if it has
+// already reacted since its turn last started
+func spendReaction(data *encounters.EncounterData, entityID string) ([]string, error) {
+	if slices.Contains(data.ReactionsUsed, entityID) {
+		return nil, errors.FailedPreconditionf("%s has no reaction left until its next turn", entityID)
+	}
+	return append(slices.Clone(data.ReactionsUsed), entityID), nil
+}
+
+// turnState describes what an entity has left of its turn
+func turnState(data *encounters.EncounterData, entityID string, round, speed int) *TurnState {
+	turn := turnFor(data, entityID, round)
+	return &TurnState{
+		EntityID:                   entityID,
+		Speed:                      speed,
+		MovementUsed:               turn.MovementUsed,
+		MovementRemaining:          max(0, speed-turn.MovementUsed),
+		ActionAvailable:            !turn.ActionUsed,
+		BonusActionAvailable:       !turn.BonusActionUsed,
+		ReactionAvailable:          !slices.Contains(data.ReactionsUsed, entityID),
+		ObjectInteractionAvailable: !turn.ObjectInteractionUsed,
+	}
+}
@@ -0,0 +1,277 @@
+package encounter_test
+
+import (
+	"context"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+func (s *OrchestratorTestSuite) useAction(entityID, action, cost string) (*encounter.UseActionOutput, error) {
+	return s.orchestrator.UseAction(context.Background(), &encounter.UseActionInput{
+		EncounterID: "enc-1",
+		EntityID:    entityID,
+		Action:      action,
+		Cost:        cost,
+	})
+}
+
+// castSpell casts a spell, which costs whatever its casting time says
+func (s *OrchestratorTestSuite) castSpell(entityID, spellID string) (*encounter.UseActionOutput, error) {
+	return s.orchestrator.UseAction(context.Background(), &encounter.UseActionInput{
+		EncounterID: "enc-1",
+		EntityID:    entityID,
+		Action:      encounter.ActionCastSpell,
+		SpellID:     spellID,
+	})
+}
+
+// castReaction spends an entity's reaction casting Shield
+func (s *OrchestratorTestSuite) castReaction(entityID string) (*encounter.UseActionOutput, error) {
+	s.expectSpell(encounter.SpellShield, "Shield", "1 reaction, which you take when you are hit by an attack")
+	return s.castSpell(entityID, encounter.SpellShield)
+}
+
+func (s *OrchestratorTestSuite) expectSpell(spellID, name, castingTime string) {
+	s.mockExternalClient.EXPECT().
+		GetSpellData(gomock.Any(), spellID).
+		Return(&external.SpellData{ID: spellID, Name: name, CastingTime: castingTime}, nil).
+		AnyTimes()
+}
+
+func (s *OrchestratorTestSuite) nextTurn() {
+	_, err := s.orchestrator.NextTurn(context.Background(), &encounter.NextTurnInput{EncounterID: "enc-1"})
+	s.Require().NoError(err)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_SpendsTheAction() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 1}, newOrc(15))
+	s.expectHero()
+	s.expectAttackRoll("hero", "1d20", 2)
+
+	_, err := s.attack("hero", "orc")
+	s.Require().NoError(err)
+
+	output, err := s.attack("hero", "orc")
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err), "got %v", err)
+	s.ErrorContains(err, "no action left")
+
+	_, err = s.useAction("hero", encounter.ActionCastSpell, "")
+	s.True(errors.IsFailedPrecondition(err), "casting a spell takes the same action")
+}
+
+func (s *OrchestratorTestSuite) TestUseAction_ConcurrentActionsSpendItOnce() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 5, Y: 0}, newOrc(15))
+	s.expectHero()
+	s.expectSpell("SPELL_FIRE_BOLT", "Fire Bolt", "1 action")
+	// A second request takes the action after this one has checked it is available
+	s.raceReads(1, func() {
+		_, err := s.useAction("hero", encounter.ActionDisengage, "")
+		s.Require().NoError(err)
+	})
+
+	output, err := s.castSpell("hero", "SPELL_FIRE_BOLT")
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err), "got %v", err)
+	s.ErrorContains(err, "no action left")
+}
+
+func (s *OrchestratorTestSuite) TestUpdate_SameRevisionAppliesOnce() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 5, Y: 0}, newOrc(15))
+	getOutput, err := s.repo.Get(context.Background(), &encounters.GetInput{EncounterID: "enc-1"})
+	s.Require().NoError(err)
+	revision := getOutput.Data.Revision
+
+	_, err = s.repo.Update(context.Background(), &encounters.UpdateInput{
+		EncounterID:      "enc-1",
+		Turn:             &encounters.TurnData{EntityID: "hero", Round: 1, ActionUsed: true},
+		ExpectedRevision: &revision,
+	})
+	s.Require().NoError(err)
+
+	_, err = s.repo.Update(context.Background(), &encounters.UpdateInput{
+		EncounterID:      "enc-1",
+		Turn:             &encounters.TurnData{EntityID: "hero", Round: 1, BonusActionUsed: true},
+		ExpectedRevision: &revision,
+	})
+	s.True(errors.IsAborted(err), "got %v", err)
+
+	getOutput, err = s.repo.Get(context.Background(), &encounters.GetInput{EncounterID: "enc-1"})
+	s.Require().NoError(err)
+	s.Equal(revision+1, getOutput.Data.Revision)
+	s.Equal(&encounters.TurnData{EntityID: "hero", Round: 1, ActionUsed: true}, getOutput.Data.Turn)
+}
+
+func (s *OrchestratorTestSuite) TestMove_KeepsWhatWasSpent() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 1}, newOrc(15))
+	s.expectHero()
+	s.expectAttackRoll("hero", "1d20", 2)
+	_, err := s.attack("hero", "orc")
+	s.Require().NoError(err)
+
+	moved, err := s.move("hero", 0, 2)
+	s.Require().NoError(err)
+	s.Require().NotNil(moved.Turn)
+	s.False(moved.Turn.ActionAvailable, "the move reports what the mover has left")
+	s.True(moved.Turn.ReactionAvailable)
+
+	state, err := s.orchestrator.GetCombatState(context.Background(), &encounter.GetCombatStateInput{EncounterID: "enc-1", Internal: true})
+	s.Require().NoError(err)
+	s.Equal(10, state.Turn.MovementUsed)
+	s.False(state.Turn.ActionAvailable, "moving after attacking does not restore the action")
+	s.True(state.Turn.BonusActionAvailable)
+}
+
+func (s *OrchestratorTestSuite) TestUseAction_BonusActionAndObjectInteraction() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 5, Y: 0}, newOrc(15))
+	s.expectHero()
+	s.expectSpell("SPELL_HEALING_WORD", "Healing Word", "1 bonus action")
+
+	output, err := s.castSpell("hero", "SPELL_HEALING_WORD")
+	s.Require().NoError(err)
+	s.Equal(encounter.CostBonusAction, output.Cost)
+	s.Require().NotNil(output.Turn)
+	s.True(output.Turn.ActionAvailable)
+	s.False(output.Turn.BonusActionAvailable)
+
+	_, err = s.castSpell("hero", "SPELL_HEALING_WORD")
+	s.True(errors.IsFailedPrecondition(err), "got %v", err)
+
+	output, err = s.useAction("hero", encounter.ActionInteract, "")
+	s.Require().NoError(err)
+	s.Equal(encounter.CostObjectInteraction, output.Cost)
+
+	_, err = s.useAction("hero", encounter.ActionInteract, "")
+	s.True(errors.IsFailedPrecondition(err), "only one interaction is free")
+
+	output, err = s.useAction("hero", encounter.ActionInteract, encounter.CostAction)
+	s.Require().NoError(err, "a second interaction takes the action")
+	s.False(output.Turn.ActionAvailable)
+}
+
+func (s *OrchestratorTestSuite) TestUseAction_ReactionOutsideTurn() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 5, Y: 0}, newOrc(15))
+
+	_, err := s.useAction("orc", encounter.ActionUseItem, "")
+	s.True(errors.IsFailedPrecondition(err), "only a reaction can be spent outside the orc's turn")
+
+	output, err := s.castReaction("orc")
+	s.Require().NoError(err)
+	s.Nil(output.Turn)
+
+	_, err = s.castReaction("orc")
+	s.True(errors.IsFailedPrecondition(err), "one reaction per round")
+
+	s.nextTurn()
+
+	_, err = s.castReaction("orc")
+	s.Require().NoError(err, "the reaction comes back when the orc's turn starts")
+}
+
+func (s *OrchestratorTestSuite) TestNextTurn_ResetsTheTurn() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 1}, newOrc(15))
+	s.expectHero()
+	s.expectAttackRoll("hero", "1d20", 2)
+	_, err := s.attack("hero", "orc")
+	s.Require().NoError(err)
+	_, err = s.castReaction("hero")
+	s.Require().NoError(err)
+
+	s.nextTurn()
+	state, err := s.orchestrator.GetCombatState(context.Background(), &encounter.GetCombatStateInput{EncounterID: "enc-1", Internal: true})
+	s.Require().NoError(err)
+	s.Equal("orc", state.Turn.EntityID)
+	s.True(state.Turn.ActionAvailable)
+
+	s.nextTurn()
+	state, err = s.orchestrator.GetCombatState(context.Background(), &encounter.GetCombatStateInput{EncounterID: "enc-1", Internal: true})
+	s.Require().NoError(err)
+	s.Equal(&encounter.TurnState{
+		EntityID:                   "hero",
+		Speed:                      30,
+		MovementRemaining:          30,
+		ActionAvailable:            true,
+		BonusActionAvailable:       true,
+		ReactionAvailable:          true,
+		ObjectInteractionAvailable: true,
+	}, state.Turn)
+}
+
+func (s *OrchestratorTestSuite) TestUseAction_CostComesFromTheRules() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 5, Y: 0}, newOrc(15))
+	s.expectHero()
+	s.expectSpell("SPELL_FIRE_BOLT", "Fire Bolt", "1 action")
+	s.expectSpell("SPELL_ALARM", "Alarm", "1 minute")
+
+	_, err := s.useAction("hero", encounter.ActionUseItem, encounter.CostBonusAction)
+	s.True(errors.IsInvalidArgument(err), "drinking a potion takes the action, got %v", err)
+
+	_, err = s.useAction("hero", encounter.ActionCastSpell, encounter.CostBonusAction)
+	s.True(errors.IsInvalidArgument(err), "an unnamed spell takes the action, got %v", err)
+
+	_, err = s.orchestrator.UseAction(context.Background(), &encounter.UseActionInput{
+		EncounterID: "enc-1",
+		EntityID:    "hero",
+		Action:      encounter.ActionCastSpell,
+		SpellID:     "SPELL_FIRE_BOLT",
+		Cost:        encounter.CostBonusAction,
+	})
+	s.True(errors.IsInvalidArgument(err), "got %v", err)
+
+	_, err = s.castSpell("hero", "SPELL_ALARM")
+	s.True(errors.IsFailedPrecondition(err), "got %v", err)
+
+	_, err = s.useAction("hero", encounter.ActionDisengage, encounter.CostBonusAction)
+	s.True(errors.IsFailedPrecondition(err), "only a rogue can disengage as a bonus action, got %v", err)
+
+	output, err := s.castSpell("hero", "SPELL_FIRE_BOLT")
+	s.Require().NoError(err)
+	s.Equal(encounter.CostAction, output.Cost)
+}
+
+func (s *OrchestratorTestSuite) TestUseAction_CunningAction() {
+	s.saveOrcTurnEncounter(&toolkitchar.Data{ID: "hero", ClassID: constants.ClassRogue, Level: 2}, nil)
+	s.expectHero()
+	s.nextTurn()
+
+	output, err := s.useAction("hero", encounter.ActionDisengage, encounter.CostBonusAction)
+	s.Require().NoError(err)
+	s.Equal(encounter.CostBonusAction, output.Cost)
+	s.True(output.Turn.ActionAvailable)
+}
+
+func (s *OrchestratorTestSuite) TestUseAction_NotWhileDown() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 5, Y: 0}, newOrc(0))
+
+	_, err := s.castReaction("orc")
+	s.True(errors.IsFailedPrecondition(err), "got %v", err)
+	s.ErrorContains(err, "down")
+
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 5, Y: 0}, newOrc(15, "stunned"))
+	_, err = s.castReaction("orc")
+	s.True(errors.IsFailedPrecondition(err), "got %v", err)
+	s.ErrorContains(err, "stunned")
+}
+
+func (s *OrchestratorTestSuite) TestUseAction_InvalidInput() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 5, Y: 0}, newOrc(15))
+
+	_, err := s.useAction("hero", "dance", "")
+	s.True(errors.IsInvalidArgument(err), "got %v", err)
+
+	_, err = s.useAction("hero", encounter.ActionCastSpell, "ritual")
+	s.True(errors.IsInvalidArgument(err), "got %v", err)
+
+	_, err = s.useAction("wizard", encounter.ActionCastSpell, "")
+	s.True(errors.IsNotFound(err), "got %v", err)
+}
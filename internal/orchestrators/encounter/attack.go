@@ -0,0 +1,733 @@
+package encounter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/dice"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/conditions"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+const (
+	// ContextAttacks is the dice session context attack and damage rolls are recorded under
+	ContextAttacks = "attacks"
+
+	// UnarmedStrikeID is the weapon ID of the attack a character makes without a weapon
+	UnarmedStrikeID = "unarmed-strike"
+
+	// defaultArmorClass is used for characters whose armor class could not be derived
+	defaultArmorClass = 10
+
+	entityTypeMonster = "monster"
+
+	// maxCharacterWriteAttempts bounds how often saving a character's hit points or spell
+	// slots starts over when the character changes underneath it
+	maxCharacterWriteAttempts = 3
+)
+
+// Attack types reported on an AttackResult
+const (
+	AttackTypeMelee  = character.AttackTypeMelee
+	AttackTypeRanged = character.AttackTypeRanged
+)
+
+// attackOption is a weapon or monster attack, in the shape the resolver needs
+type attackOption struct {
+	id          string
+	name        string
+	bonus       int32
+	damageDice  string
+	damageBonus int32
+	damageType  string
+	reachFeet   int
+	normalRange int
+	longRange   int
+}
+
+// Attack resolves an attack by the active entity: the target must be within reach or range,
+// the d20 is rolled with advantage or disadvantage from conditions and range, and damage
+// from a hit comes off the target's hit points, which a character keeps after the encounter.
+// A hit the target can answer with Shield or Uncanny Dodge is answered at once, or with
+// WaitForReactions pauses before damage until it responds.
+func (o *orchestrator) Attack(ctx context.Context, input *AttackInput) (*AttackOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("encounter_id", input.EncounterID, vb)
+	errors.ValidateRequired("attacker_id", input.AttackerID, vb)
+	errors.ValidateRequired("target_id", input.TargetID, vb)
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+	if input.AttackerID == input.TargetID {
+		return nil, errors.InvalidArgument("an entity cannot attack itself")
+	}
+
+	return retryOnConflict(func() (*AttackOutput, error) {
+		return o.attack(ctx, input)
+	})
+}
+
+// attack is Attack against a fresh read of the encounter
+func (o *orchestrator) attack(ctx context.Context, input *AttackInput) (*AttackOutput, error) {
+	getOutput, err := o.repo.Get(ctx, &encounters.GetInput{EncounterID: input.EncounterID})
+	if err != nil {
+		return nil, err
+	}
+	data := getOutput.Data
+	if err := requireNoPendingAction(data); err != nil {
+		return nil, err
+	}
+	st, err := loadActionState(data, input.AttackerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entityID := range []string{input.AttackerID, input.TargetID} {
+		if _, ok := data.RoomData.Entities[entityID]; !ok {
+			return nil, errors.NotFoundf("entity %s is not in encounter %s", entityID, input.EncounterID)
+		}
+	}
+	if st.currentTurn() != input.AttackerID {
+		return nil, errors.FailedPreconditionf("it is not %s's turn", input.AttackerID)
+	}
+	if err := spend(st.turn, CostAction); err != nil {
+		return nil, err
+	}
+
+	st.wait = input.WaitForReactions
+	result, err := o.makeAttack(ctx, st, input.AttackerID, input.TargetID, input.WeaponID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := o.save(ctx, st); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "attack resolved",
+		"encounter_id", input.EncounterID,
+		"attacker_id", input.AttackerID,
+		"target_id", input.TargetID,
+		"hit", result.Hit,
+		"damage", result.Damage,
+		"waiting_on_reactions", st.pending != nil)
+
+	return &AttackOutput{
+		Result:         result,
+		RoomData:       data.RoomData,
+		InitiativeData: data.InitiativeData,
+		Initiative:     data.Initiative,
+		CurrentTurn:    st.currentTurn(),
+		Reactions:      st.reactions,
+		Pending:        st.pending,
+	}, nil
+}
+
+// attackRoll is an attack between choosing the target and applying damage
+type attackRoll struct {
+	attacker     *encounters.CombatantData
+	target       *encounters.CombatantData
+	option       *attackOption
+	result       *AttackResult
+	autoCritical bool
+}
+
+// makeAttack rolls an attack and applies its damage. A hit the target can react to is
+// answered by autoResponse, or, when st.wait is set, left in st.pending with damage still
+// to come.
+func (o *orchestrator) makeAttack(ctx context.Context, st *actionState, attackerID, targetID, weaponID string) (*AttackResult, error) {
+	atk, err := o.prepareAttack(ctx, st, attackerID, targetID, weaponID)
+	if err != nil {
+		return nil, err
+	}
+	result := atk.result
+	if err := o.rollAttack(ctx, result, atk.option, atk.autoCritical); err != nil {
+		return nil, err
+	}
+
+	taken := ""
+	if result.Hit {
+		offers, err := o.hitReactions(ctx, st, atk)
+		if err != nil {
+			return nil, err
+		}
+		if len(offers) > 0 && st.wait {
+			result.TargetHitPoints = int32(atk.target.HitPoints) // nolint:gosec // bounded by max hit points
+			st.pending = &encounters.PendingActionData{
+				Type:     encounters.PendingActionAttack,
+				EntityID: attackerID,
+				Attack: &encounters.PendingAttackData{
+					TargetID:            targetID,
+					WeaponID:            atk.option.id,
+					Rolls:               result.Rolls,
+					Roll:                result.Roll,
+					Critical:            result.Critical,
+					AdvantageSources:    result.AdvantageSources,
+					DisadvantageSources: result.DisadvantageSources,
+					Steps:               slices.Clone(result.Steps),
+				},
+				Reactions: offers,
+			}
+			return result, nil
+		}
+
+		// The target has one reaction, so it takes the first one the policy accepts
+		for _, offer := range offers {
+			response := autoResponse(offer.Reaction)
+			if taken != "" {
+				response = encounters.ReactionDeclined
+			}
+			if response == encounters.ReactionAccepted {
+				taken = offer.Reaction
+				st.reactionsUsed = append(st.reactionsUsed, targetID)
+			}
+			st.reactions = append(st.reactions, &ReactionResult{
+				EntityID: targetID,
+				Reaction: offer.Reaction,
+				Response: response,
+			})
+		}
+	}
+
+	if err := o.finishAttack(ctx, st, atk, taken); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// prepareAttack checks that an attack can be made and works out everything about it
+// except the rolls
+func (o *orchestrator) prepareAttack(ctx context.Context, st *actionState, attackerID, targetID, weaponID string) (*attackRoll, error) {
+	attackerPlacement, ok := st.data.RoomData.Entities[attackerID]
+	if !ok {
+		return nil, errors.NotFoundf("entity %s is not in encounter %s", attackerID, st.data.ID)
+	}
+	targetPlacement, ok := st.data.RoomData.Entities[targetID]
+	if !ok {
+		return nil, errors.NotFoundf("entity %s is not in encounter %s", targetID, st.data.ID)
+	}
+
+	attacker, err := o.combatant(ctx, st.combatants, attackerPlacement)
+	if err != nil {
+		return nil, err
+	}
+	target, err := o.combatant(ctx, st.combatants, targetPlacement)
+	if err != nil {
+		return nil, err
+	}
+	if attacker.HitPoints <= 0 {
+		return nil, errors.FailedPreconditionf("%s is down and cannot attack", nameOf(attacker))
+	}
+	if condition := incapacitatedBy(attacker); condition != "" {
+		return nil, errors.FailedPreconditionf("%s is %s and cannot attack", nameOf(attacker), condition)
+	}
+	if target.HitPoints <= 0 {
+		return nil, errors.FailedPreconditionf("%s is already down", nameOf(target))
+	}
+
+	option, err := o.attackOption(ctx, attacker, weaponID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AttackResult{
+		AttackerID:   attackerID,
+		TargetID:     targetID,
+		WeaponID:     option.id,
+		WeaponName:   option.name,
+		DistanceFeet: st.feetBetween(attackerPlacement.Position, targetPlacement.Position),
+		AttackBonus:  option.bonus,
+		TargetAC:     int32(armorClassOf(target)), // nolint:gosec // armor class is small
+	}
+	if err := applyRange(result, option); err != nil {
+		return nil, err
+	}
+	result.Steps = append(result.Steps, fmt.Sprintf("%s attacks %s with %s (%s, %d ft)",
+		nameOf(attacker), nameOf(target), option.name, result.AttackType, result.DistanceFeet))
+
+	autoCritical := applyAttackConditions(result, attacker, target)
+	if result.AttackType == AttackTypeRanged && hostileAdjacent(st.data.RoomData, st.grid, attackerPlacement, st.combatants) {
+		result.DisadvantageSources = append(result.DisadvantageSources, "hostile within 5 ft")
+	}
+
+	return &attackRoll{
+		attacker:     attacker,
+		target:       target,
+		option:       option,
+		result:       result,
+		autoCritical: autoCritical,
+	}, nil
+}
+
+// finishAttack applies the target's reaction, rolls damage for a hit and takes it off the
+// target, removing a defeated monster from the room and the initiative order
+func (o *orchestrator) finishAttack(ctx context.Context, st *actionState, atk *attackRoll, reaction string) error {
+	result := atk.result
+
+	// Changes to the target go on a copy
+	target := new(encounters.CombatantData)
+	*target = *atk.target
+	if reaction == ReactionShield {
+		if err := o.castShield(ctx, st, result, target); err != nil {
+			return err
+		}
+	}
+
+	if result.Hit {
+		if err := o.rollDamage(ctx, result, atk.option); err != nil {
+			return err
+		}
+		var step string
+		result.Damage, step = adjustDamage(target, result.Damage, result.DamageType)
+		if step != "" {
+			result.Steps = append(result.Steps, step)
+		}
+		if reaction == ReactionUncannyDodge {
+			result.Damage /= 2
+			result.Steps = append(result.Steps, fmt.Sprintf("%s uses Uncanny Dodge: damage halved to %d",
+				nameOf(target), result.Damage))
+		}
+	}
+
+	target.HitPoints = max(0, target.HitPoints-int(result.Damage))
+	st.combatants[target.EntityID] = target
+	if target.EntityType == entityTypeCharacter && target.HitPoints != atk.target.HitPoints &&
+		!slices.Contains(st.hurt, target.EntityID) {
+		st.hurt = append(st.hurt, target.EntityID)
+	}
+	result.TargetHitPoints = int32(target.HitPoints) // nolint:gosec // bounded by max hit points
+	if result.Hit {
+		result.Steps = append(result.Steps, fmt.Sprintf("%s has %d/%d hit points",
+			nameOf(target), target.HitPoints, target.MaxHitPoints))
+	}
+
+	if result.Hit && target.HitPoints == 0 {
+		result.TargetDefeated = true
+		if target.EntityType == entityTypeCharacter {
+			// Characters fall unconscious and stay on the field
+			if !slices.Contains(target.Conditions, string(conditions.Unconscious)) {
+				target.Conditions = append(slices.Clone(target.Conditions), string(conditions.Unconscious))
+			}
+			result.Steps = append(result.Steps, fmt.Sprintf("%s falls unconscious", nameOf(target)))
+		} else {
+			delete(st.data.RoomData.Entities, target.EntityID)
+			if err := st.tracker.Remove(target.EntityID); err != nil {
+				slog.WarnContext(ctx, "defeated entity was not in the initiative order",
+					"encounter_id", st.data.ID,
+					"entity_id", target.EntityID)
+			}
+			result.Steps = append(result.Steps, fmt.Sprintf("%s is defeated", nameOf(target)))
+		}
+	}
+	return nil
+}
+
+// saveHitPoints writes a character's hit points from the encounter back to the character,
+// so damage taken in combat lasts beyond it. The write is made at the revision read and
+// starts over if the character was saved in between.
+func (o *orchestrator) saveHitPoints(ctx context.Context, combatant *encounters.CombatantData) error {
+	for range maxCharacterWriteAttempts {
+		getOutput, err := o.charRepo.Get(ctx, characterrepo.GetInput{ID: combatant.EntityID})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get character %s", combatant.EntityID)
+		}
+		if getOutput.CharacterData.HitPoints == combatant.HitPoints {
+			return nil
+		}
+
+		getOutput.CharacterData.HitPoints = combatant.HitPoints
+		_, err = o.charRepo.Update(ctx, characterrepo.UpdateInput{
+			CharacterData:    getOutput.CharacterData,
+			EventType:        dnd5e.CharacterEventHitPointsChanged,
+			ExpectedRevision: &getOutput.Revision,
+		})
+		if errors.IsAborted(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to save hit points of character %s", combatant.EntityID)
+		}
+		return nil
+	}
+	return errors.Abortedf("character %s kept changing, try again", combatant.EntityID)
+}
+
+// combatant returns an entity's combat stats. A character without stats yet has them
+// loaded from its sheet and added to combatants.
+func (o *orchestrator) combatant(
+	ctx context.Context,
+	combatants map[string]*encounters.CombatantData,
+	placement spatial.EntityPlacement,
+) (*encounters.CombatantData, error) {
+	if combatant, ok := combatants[placement.EntityID]; ok {
+		return combatant, nil
+	}
+	if placement.EntityType != entityTypeCharacter || o.charService == nil {
+		return nil, errors.FailedPreconditionf("%s has no combat stats", placement.EntityID)
+	}
+
+	charOutput, err := o.charService.GetCharacter(ctx, &character.GetCharacterInput{
+		CharacterID: placement.EntityID,
+		Internal:    true,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get character %s", placement.EntityID)
+	}
+
+	combatant := &encounters.CombatantData{
+		EntityID:     placement.EntityID,
+		EntityType:   entityTypeCharacter,
+		Name:         charOutput.Character.Name,
+		ArmorClass:   defaultArmorClass,
+		HitPoints:    charOutput.Character.HitPoints,
+		MaxHitPoints: charOutput.Character.MaxHitPoints,
+	}
+	if charOutput.DerivedStats != nil {
+		combatant.ArmorClass = int(charOutput.DerivedStats.ArmorClass)
+	}
+	for _, condition := range charOutput.Character.Conditions {
+		combatant.Conditions = append(combatant.Conditions, string(condition.Type))
+	}
+
+	combatants[placement.EntityID] = combatant
+	return combatant, nil
+}
+
+// attackOptions lists the attacks an entity can make. Monsters use the attacks from their
+// stat block; characters use their equipped weapons, then an unarmed strike.
+func (o *orchestrator) attackOptions(ctx context.Context, attacker *encounters.CombatantData) ([]*attackOption, error) {
+	if attacker.EntityType != entityTypeCharacter {
+		options := make([]*attackOption, 0, len(attacker.Attacks))
+		for _, attack := range attacker.Attacks {
+			options = append(options, &attackOption{
+				id:          attack.ID,
+				name:        attack.Name,
+				bonus:       int32(attack.AttackBonus), // nolint:gosec // small stat block value
+				damageDice:  attack.DamageDice,
+				damageBonus: int32(attack.DamageBonus), // nolint:gosec // small stat block value
+				damageType:  attack.DamageType,
+				reachFeet:   attack.ReachFeet,
+				normalRange: attack.NormalRange,
+				longRange:   attack.LongRange,
+			})
+		}
+		return options, nil
+	}
+
+	if o.charService == nil {
+		return nil, errors.FailedPrecondition("character attacks require the character service")
+	}
+	charOutput, err := o.charService.GetCharacter(ctx, &character.GetCharacterInput{
+		CharacterID: attacker.EntityID,
+		Internal:    true,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get character %s", attacker.EntityID)
+	}
+
+	options := make([]*attackOption, 0, len(charOutput.AttackProfiles)+1)
+	for _, profile := range charOutput.AttackProfiles {
+		options = append(options, &attackOption{
+			id:          profile.WeaponID,
+			name:        profile.WeaponName,
+			bonus:       profile.AttackBonus,
+			damageDice:  profile.DamageDice,
+			damageBonus: profile.DamageBonus,
+			damageType:  profile.DamageType,
+			reachFeet:   int(profile.ReachFeet),
+			normalRange: int(profile.NormalRange),
+			longRange:   int(profile.LongRange),
+		})
+	}
+
+	// Unarmed strike: proficient, 1 + STR bludgeoning damage
+	strength := int32(charOutput.Character.AbilityScores.Modifier(constants.STR)) // nolint:gosec // small modifier
+	var proficiency int32
+	if charOutput.DerivedStats != nil {
+		proficiency = charOutput.DerivedStats.ProficiencyBonus
+	}
+	options = append(options, &attackOption{
+		id:          UnarmedStrikeID,
+		name:        "Unarmed Strike",
+		bonus:       strength + proficiency,
+		damageBonus: 1 + strength,
+		damageType:  "bludgeoning",
+		reachFeet:   feetPerCell,
+	})
+	return options, nil
+}
+
+// attackOption picks the attack to make: the one asked for, or else the first available.
+// A character with no weapon equipped makes an unarmed strike.
+func (o *orchestrator) attackOption(
+	ctx context.Context,
+	attacker *encounters.CombatantData,
+	weaponID string,
+) (*attackOption, error) {
+	options, err := o.attackOptions(ctx, attacker)
+	if err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		if weaponID == "" || option.id == weaponID {
+			return option, nil
+		}
+	}
+
+	switch {
+	case attacker.EntityType == entityTypeCharacter:
+		return nil, errors.NotFoundf("%s has no weapon %s equipped", nameOf(attacker), weaponID)
+	case weaponID != "":
+		return nil, errors.NotFoundf("%s has no attack %s", nameOf(attacker), weaponID)
+	default:
+		return nil, errors.FailedPreconditionf("%s has no attacks", nameOf(attacker))
+	}
+}
+
+// applyRange sets the attack type from the distance to the target. A target within reach
+// is attacked in melee; otherwise a ranged or thrown attack is made, with disadvantage
+// beyond normal range.
+func applyRange(result *AttackResult, option *attackOption) error {
+	longRange := max(option.longRange, option.normalRange)
+
+	switch {
+	case option.reachFeet > 0 && result.DistanceFeet <= option.reachFeet:
+		result.AttackType = AttackTypeMelee
+	case longRange > 0 && result.DistanceFeet <= longRange:
+		result.AttackType = AttackTypeRanged
+		if result.DistanceFeet > option.normalRange {
+			result.DisadvantageSources = append(result.DisadvantageSources, "long range")
+		}
+	default:
+		return errors.FailedPreconditionf("target is %d ft away, out of range of %s", result.DistanceFeet, option.name)
+	}
+	return nil
+}
+
+// applyAttackConditions adds advantage and disadvantage from the attacker's and target's
+// conditions. It reports whether a hit becomes a critical hit, which happens when the
+// target is paralyzed or unconscious and the attacker is within 5 feet.
+func applyAttackConditions(result *AttackResult, attacker, target *encounters.CombatantData) bool {
+	for _, condition := range attacker.Conditions {
+		switch conditions.ConditionType(condition) {
+		case conditions.Blinded, conditions.Frightened, conditions.Poisoned, conditions.Prone, conditions.Restrained:
+			result.DisadvantageSources = append(result.DisadvantageSources, "attacker "+condition)
+		case conditions.Invisible:
+			result.AdvantageSources = append(result.AdvantageSources, "attacker "+condition)
+		}
+	}
+
+	withinFiveFeet := result.DistanceFeet <= feetPerCell
+	autoCritical := false
+	for _, condition := range target.Conditions {
+		switch conditions.ConditionType(condition) {
+		case conditions.Blinded, conditions.Petrified, conditions.Restrained, conditions.Stunned:
+			result.AdvantageSources = append(result.AdvantageSources, "target "+condition)
+		case conditions.Paralyzed, conditions.Unconscious:
+			result.AdvantageSources = append(result.AdvantageSources, "target "+condition)
+			autoCritical = autoCritical || withinFiveFeet
+		case conditions.Prone:
+			if withinFiveFeet {
+				result.AdvantageSources = append(result.AdvantageSources, "target "+condition)
+			} else {
+				result.DisadvantageSources = append(result.DisadvantageSources, "target "+condition)
+			}
+		case conditions.Invisible:
+			result.DisadvantageSources = append(result.DisadvantageSources, "target "+condition)
+		}
+	}
+	return autoCritical
+}
+
+// rollAttack rolls the d20 and decides whether the attack hits. A natural 1 always misses
+// and a natural 20 always hits as a critical hit.
+func (o *orchestrator) rollAttack(ctx context.Context, result *AttackResult, option *attackOption, autoCritical bool) error {
+	result.Advantage = len(result.AdvantageSources) > 0
+	result.Disadvantage = len(result.DisadvantageSources) > 0
+	if len(result.AdvantageSources) > 0 {
+		result.Steps = append(result.Steps, "Advantage: "+strings.Join(result.AdvantageSources, ", "))
+	}
+	if len(result.DisadvantageSources) > 0 {
+		result.Steps = append(result.Steps, "Disadvantage: "+strings.Join(result.DisadvantageSources, ", "))
+	}
+
+	// Advantage and disadvantage cancel out no matter how many sources each has
+	notation := "1d20"
+	if result.Advantage != result.Disadvantage {
+		notation = "2d20"
+	}
+
+	rollOutput, err := o.diceService.RollDice(ctx, &dice.RollDiceInput{
+		EntityID:    result.AttackerID,
+		Context:     ContextAttacks,
+		Notation:    notation,
+		Description: option.name + " attack",
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to roll %s attack", option.name)
+	}
+	if rollOutput.Roll == nil || len(rollOutput.Roll.Dice) == 0 {
+		return errors.Internal("dice roll returned no dice")
+	}
+
+	result.Rolls = rollOutput.Roll.Dice
+	result.Roll = result.Rolls[0]
+	for _, die := range result.Rolls[1:] {
+		if result.Advantage && !result.Disadvantage {
+			result.Roll = max(result.Roll, die)
+		} else if result.Disadvantage && !result.Advantage {
+			result.Roll = min(result.Roll, die)
+		}
+	}
+	result.Total = result.Roll + result.AttackBonus
+	result.Steps = append(result.Steps, fmt.Sprintf("Rolled %s: %s, kept %d", notation, joinRolls(result.Rolls), result.Roll))
+
+	switch result.Roll {
+	case 1:
+		result.Hit = false
+		result.Steps = append(result.Steps, "Natural 1: automatic miss")
+	case 20:
+		result.Hit = true
+		result.Critical = true
+		result.Steps = append(result.Steps, "Natural 20: critical hit")
+	default:
+		result.Hit = result.Total >= result.TargetAC
+		outcome := "miss"
+		if result.Hit {
+			outcome = "hit"
+		}
+		result.Steps = append(result.Steps, fmt.Sprintf("%d %+d = %d against AC %d: %s",
+			result.Roll, result.AttackBonus, result.Total, result.TargetAC, outcome))
+		if result.Hit && autoCritical {
+			result.Critical = true
+			result.Steps = append(result.Steps, "Hit against a helpless target within 5 ft: critical hit")
+		}
+	}
+	return nil
+}
+
+// rollDamage rolls damage for a hit, doubling the damage dice on a critical hit
+func (o *orchestrator) rollDamage(ctx context.Context, result *AttackResult, option *attackOption) error {
+	result.DamageDice = option.damageDice
+	result.DamageBonus = option.damageBonus
+	result.DamageType = option.damageType
+	if result.Critical && option.damageDice != "" {
+		doubled, err := doubleDice(option.damageDice)
+		if err != nil {
+			return err
+		}
+		result.DamageDice = doubled
+	}
+
+	var rolled int32
+	if result.DamageDice != "" {
+		rollOutput, err := o.diceService.RollDice(ctx, &dice.RollDiceInput{
+			EntityID:    result.AttackerID,
+			Context:     ContextAttacks,
+			Notation:    result.DamageDice,
+			Description: option.name + " damage",
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to roll %s damage", option.name)
+		}
+		if rollOutput.Roll == nil {
+			return errors.Internal("dice roll returned no dice")
+		}
+		result.DamageRolls = rollOutput.Roll.Dice
+		for _, die := range result.DamageRolls {
+			rolled += die
+		}
+	}
+
+	result.Damage = max(0, rolled+result.DamageBonus)
+	if result.DamageDice != "" {
+		result.Steps = append(result.Steps, fmt.Sprintf("Damage %s: %s %+d = %d %s",
+			result.DamageDice, joinRolls(result.DamageRolls), result.DamageBonus, result.Damage, result.DamageType))
+	} else {
+		result.Steps = append(result.Steps, fmt.Sprintf("Damage: %d %s", result.Damage, result.DamageType))
+	}
+	return nil
+}
+
+// doubleDice doubles the number of dice in an "NdM" notation
+func doubleDice(notation string) (string, error) {
+	count, sides, ok := strings.Cut(notation, "d")
+	if !ok {
+		return "", errors.Internalf("invalid damage dice %q", notation)
+	}
+	n := 1
+	if count != "" {
+		var err error
+		n, err = strconv.Atoi(count)
+		if err != nil {
+			return "", errors.Internalf("invalid damage dice %q", notation)
+		}
+	}
+	return fmt.Sprintf("%dd%s", n*2, sides), nil
+}
+
+// hostileAdjacent reports whether a conscious enemy of the attacker is within 5 feet
+func hostileAdjacent(
+	room *spatial.RoomData,
+	grid spatial.Grid,
+	attacker spatial.EntityPlacement,
+	combatants map[string]*encounters.CombatantData,
+) bool {
+	for id, placement := range room.Entities {
+		if placement.EntityType == attacker.EntityType {
+			continue
+		}
+		if placement.EntityType != entityTypeCharacter && placement.EntityType != entityTypeMonster {
+			continue
+		}
+		if grid.Distance(attacker.Position, placement.Position) > 1 {
+			continue
+		}
+		if combatant, ok := combatants[id]; ok && (combatant.HitPoints <= 0 || incapacitatedBy(combatant) != "") {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// incapacitatedBy returns the condition that keeps a combatant from taking actions, if any
+func incapacitatedBy(combatant *encounters.CombatantData) string {
+	for _, condition := range combatant.Conditions {
+		switch conditions.ConditionType(condition) {
+		case conditions.Incapacitated, conditions.Paralyzed, conditions.Petrified,
+			conditions.Stunned, conditions.Unconscious:
+			return condition
+		}
+	}
+	return ""
+}
+
+func nameOf(combatant *encounters.CombatantData) string {
+	if combatant.Name != "" {
+		return combatant.Name
+	}
+	return combatant.EntityID
+}
+
+func joinRolls(rolls []int32) string {
+	parts := make([]string, len(rolls))
+	for i, roll := range rolls {
+		parts[i] = strconv.Itoa(int(roll))
+	}
+	return strings.Join(parts, ", ")
+}
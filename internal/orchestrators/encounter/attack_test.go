@@ -0,0 +1,307 @@
+package encounter_test
+
+import (
+	"context"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/dice"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	dicesession "github.com/KirkDiggler/rpg-api/internal/repositories/dice_session"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/initiative"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+// saveAttackEncounter stores a 30x10 square room with the hero and an orc. The orc's
+// stat block is stored with the encounter; the hero's comes from its character sheet.
+func (s *OrchestratorTestSuite) saveAttackEncounter(heroPos, orcPos spatial.Position, orc *encounters.CombatantData) {
+	_, err := s.repo.Save(context.Background(), &encounters.SaveInput{
+		EncounterID: "enc-1",
+		RoomData: &spatial.RoomData{
+			ID:       "room-1",
+			Type:     "dungeon",
+			Width:    30,
+			Height:   10,
+			GridType: spatial.GridTypeSquare,
+			Entities: map[string]spatial.EntityPlacement{
+				"hero": {EntityID: "hero", EntityType: "character", Position: heroPos},
+				"orc":  {EntityID: "orc", EntityType: "monster", Position: orcPos},
+			},
+		},
+		InitiativeData: &initiative.TrackerData{
+			Order: []initiative.EntityData{
+				{ID: "hero", Type: "character"},
+				{ID: "orc", Type: "monster"},
+			},
+			Current: 0,
+			Round:   1,
+		},
+		Combatants: map[string]*encounters.CombatantData{"orc": orc},
+	})
+	s.Require().NoError(err)
+	s.expectCharacterData(&toolkitchar.Data{ID: "hero"}, nil)
+}
+
+// expectHitPointsSaved expects the hero's hit points to be written back to the character
+func (s *OrchestratorTestSuite) expectHitPointsSaved(hitPoints int) {
+	s.mockCharRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+			s.Equal(dnd5e.CharacterEventHitPointsChanged, input.EventType)
+			s.Equal("hero", input.CharacterData.ID)
+			s.Equal(hitPoints, input.CharacterData.HitPoints)
+			s.Nil(input.Sheet, "the sheet is left as it is")
+			s.NotNil(input.ExpectedRevision)
+			return &characterrepo.UpdateOutput{CharacterData: input.CharacterData}, nil
+		})
+}
+
+func newOrc(hitPoints int, conditions ...string) *encounters.CombatantData {
+	return &encounters.CombatantData{
+		EntityID:     "orc",
+		EntityType:   "monster",
+		Name:         "Orc",
+		ArmorClass:   13,
+		HitPoints:    hitPoints,
+		MaxHitPoints: 15,
+		Conditions:   conditions,
+		Attacks: []*encounters.AttackData{
+			{ID: "javelin", Name: "Javelin", AttackBonus: 5, DamageDice: "1d6", DamageBonus: 3, DamageType: "piercing",
+				ReachFeet: 5, NormalRange: 30, LongRange: 120},
+		},
+	}
+}
+
+func (s *OrchestratorTestSuite) expectHero() {
+	hero := &toolkitchar.Data{ID: "hero", Name: "Hero", HitPoints: 12, MaxHitPoints: 12}
+	s.mockCharacterService.EXPECT().
+		GetCharacter(gomock.Any(), &character.GetCharacterInput{CharacterID: "hero", Internal: true}).
+		Return(&character.GetCharacterOutput{
+			Character:    hero,
+			DerivedStats: &character.DerivedStats{ArmorClass: 16, ProficiencyBonus: 2},
+			AttackProfiles: []*character.AttackProfile{
+				{
+					WeaponID:    "longsword",
+					WeaponName:  "Longsword",
+					AttackType:  character.AttackTypeMelee,
+					AttackBonus: 5,
+					DamageDice:  "1d8",
+					DamageBonus: 3,
+					DamageType:  "slashing",
+					ReachFeet:   5,
+				},
+			},
+		}, nil).
+		AnyTimes()
+}
+
+func (s *OrchestratorTestSuite) expectAttackRoll(entityID, notation string, rolled ...int32) {
+	s.mockDiceService.EXPECT().
+		RollDice(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *dice.RollDiceInput) (*dice.RollDiceOutput, error) {
+			s.Equal(entityID, input.EntityID)
+			s.Equal(encounter.ContextAttacks, input.Context)
+			s.Equal(notation, input.Notation)
+			return &dice.RollDiceOutput{Roll: &dicesession.DiceRoll{Notation: notation, Dice: rolled}}, nil
+		})
+}
+
+func (s *OrchestratorTestSuite) attack(attackerID, targetID string) (*encounter.AttackOutput, error) {
+	return s.orchestrator.Attack(context.Background(), &encounter.AttackInput{
+		EncounterID: "enc-1",
+		AttackerID:  attackerID,
+		TargetID:    targetID,
+	})
+}
+
+func (s *OrchestratorTestSuite) TestAttack_HitAppliesDamage() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 1}, newOrc(15))
+	s.expectHero()
+	s.expectAttackRoll("hero", "1d20", 11)
+	s.expectAttackRoll("hero", "1d8", 6)
+
+	output, err := s.attack("hero", "orc")
+
+	s.Require().NoError(err)
+	result := output.Result
+	s.Equal("longsword", result.WeaponID)
+	s.Equal(encounter.AttackTypeMelee, result.AttackType)
+	s.Equal(5, result.DistanceFeet)
+	s.Equal(int32(16), result.Total)
+	s.Equal(int32(13), result.TargetAC)
+	s.True(result.Hit)
+	s.False(result.Critical)
+	s.Equal(int32(9), result.Damage)
+	s.Equal(int32(6), result.TargetHitPoints)
+	s.NotEmpty(result.Steps)
+
+	// The damage stays with the encounter
+	getOutput, err := s.repo.Get(context.Background(), &encounters.GetInput{EncounterID: "enc-1"})
+	s.Require().NoError(err)
+	s.Equal(6, getOutput.Data.Combatants["orc"].HitPoints)
+	s.Equal(12, getOutput.Data.Combatants["hero"].HitPoints)
+	s.Equal(16, getOutput.Data.Combatants["hero"].ArmorClass)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_MissBelowArmorClass() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 0}, newOrc(15))
+	s.expectHero()
+	s.expectAttackRoll("hero", "1d20", 7)
+
+	output, err := s.attack("hero", "orc")
+
+	s.Require().NoError(err)
+	s.False(output.Result.Hit)
+	s.Zero(output.Result.Damage)
+	s.Equal(int32(15), output.Result.TargetHitPoints)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_NaturalTwentyDoublesDamageDice() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 0}, newOrc(15))
+	s.expectHero()
+	s.expectAttackRoll("hero", "1d20", 20)
+	s.expectAttackRoll("hero", "2d8", 2, 3)
+
+	output, err := s.attack("hero", "orc")
+
+	s.Require().NoError(err)
+	s.True(output.Result.Hit)
+	s.True(output.Result.Critical)
+	s.Equal("2d8", output.Result.DamageDice)
+	s.Equal(int32(8), output.Result.Damage)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_NaturalOneAlwaysMisses() {
+	orc := newOrc(15)
+	orc.ArmorClass = 1
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 0}, orc)
+	s.expectHero()
+	s.expectAttackRoll("hero", "1d20", 1)
+
+	output, err := s.attack("hero", "orc")
+
+	s.Require().NoError(err)
+	s.False(output.Result.Hit)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_TargetConditionGrantsAdvantage() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 0}, newOrc(15, "prone"))
+	s.expectHero()
+	s.expectAttackRoll("hero", "2d20", 4, 12)
+	s.expectAttackRoll("hero", "1d8", 1)
+
+	output, err := s.attack("hero", "orc")
+
+	s.Require().NoError(err)
+	s.True(output.Result.Advantage)
+	s.Equal([]string{"target prone"}, output.Result.AdvantageSources)
+	s.Equal(int32(12), output.Result.Roll)
+	s.True(output.Result.Hit)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_AdvantageAndDisadvantageCancel() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 0}, newOrc(15, "restrained"))
+	s.expectHero()
+	s.expectAttackRoll("hero", "1d20", 5)
+
+	// The hero was poisoned earlier in the encounter
+	getOutput, err := s.repo.Get(context.Background(), &encounters.GetInput{EncounterID: "enc-1"})
+	s.Require().NoError(err)
+	combatants := getOutput.Data.Combatants
+	combatants["hero"] = &encounters.CombatantData{
+		EntityID: "hero", EntityType: "character", ArmorClass: 16, HitPoints: 12, MaxHitPoints: 12,
+		Conditions: []string{"poisoned"},
+	}
+	_, err = s.repo.Update(context.Background(), &encounters.UpdateInput{EncounterID: "enc-1", Combatants: combatants})
+	s.Require().NoError(err)
+
+	output, err := s.attack("hero", "orc")
+
+	s.Require().NoError(err)
+	s.True(output.Result.Advantage)
+	s.True(output.Result.Disadvantage)
+	s.Equal([]int32{5}, output.Result.Rolls)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_UnconsciousTargetTakesCriticalHit() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 0}, newOrc(15, "unconscious"))
+	s.expectHero()
+	s.expectAttackRoll("hero", "2d20", 9, 3)
+	s.expectAttackRoll("hero", "2d8", 1, 1)
+
+	output, err := s.attack("hero", "orc")
+
+	s.Require().NoError(err)
+	s.True(output.Result.Critical)
+	s.Equal(int32(5), output.Result.Damage)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_LongRangeHasDisadvantage() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 10, Y: 0}, newOrc(15))
+	s.expectHero()
+	_, err := s.orchestrator.NextTurn(context.Background(), &encounter.NextTurnInput{EncounterID: "enc-1"})
+	s.Require().NoError(err)
+	s.expectAttackRoll("orc", "2d20", 18, 6)
+
+	output, err := s.attack("orc", "hero")
+
+	s.Require().NoError(err)
+	result := output.Result
+	s.Equal(encounter.AttackTypeRanged, result.AttackType)
+	s.Equal(50, result.DistanceFeet)
+	s.Equal([]string{"long range"}, result.DisadvantageSources)
+	s.Equal(int32(6), result.Roll)
+	s.Equal(int32(16), result.TargetAC)
+	s.False(result.Hit)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_DefeatedMonsterLeavesTheField() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 0}, newOrc(4))
+	s.expectHero()
+	s.expectAttackRoll("hero", "1d20", 15)
+	s.expectAttackRoll("hero", "1d8", 3)
+
+	output, err := s.attack("hero", "orc")
+
+	s.Require().NoError(err)
+	s.True(output.Result.TargetDefeated)
+	s.Zero(output.Result.TargetHitPoints)
+	s.NotContains(output.RoomData.Entities, "orc")
+	s.Len(output.InitiativeData.Order, 1)
+	s.Equal("hero", output.CurrentTurn)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_OutOfReach() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 3, Y: 0}, newOrc(15))
+	s.expectHero()
+
+	output, err := s.attack("hero", "orc")
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *OrchestratorTestSuite) TestAttack_NotYourTurn() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 0}, newOrc(15))
+
+	output, err := s.attack("orc", "hero")
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *OrchestratorTestSuite) TestAttack_RequiresTarget() {
+	output, err := s.orchestrator.Attack(context.Background(), &encounter.AttackInput{
+		EncounterID: "enc-1",
+		AttackerID:  "hero",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
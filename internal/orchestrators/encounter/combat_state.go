@@ -0,0 +1,199 @@
+package encounter
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	campaignrepo "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/initiative"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+// GetCombatState returns everything a client needs to rebuild an encounter: the room, the
+// turn order, every combatant's hit points and conditions, and what the active combatant
+// has left this turn
+func (o *orchestrator) GetCombatState(ctx context.Context, input *GetCombatStateInput) (*GetCombatStateOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("encounter_id", input.EncounterID, vb)
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+
+	getOutput, err := o.repo.Get(ctx, &encounters.GetInput{EncounterID: input.EncounterID})
+	if err != nil {
+		return nil, err
+	}
+	data := getOutput.Data
+
+	showMonsterStats := input.Internal
+	if !showMonsterStats {
+		showMonsterStats, err = o.canSeeMonsterStats(ctx, data, input.ViewerID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	output := &GetCombatStateOutput{
+		EncounterID:    data.ID,
+		RoomData:       data.RoomData,
+		InitiativeData: data.InitiativeData,
+		Initiative:     data.Initiative,
+		Pending:        data.PendingAction,
+	}
+
+	var tracker *initiative.Tracker
+	if data.InitiativeData != nil {
+		tracker = initiative.LoadFromData(*data.InitiativeData)
+		output.Round = tracker.Round()
+		if current := tracker.Current(); current != nil {
+			output.CurrentTurn = current.GetID()
+		}
+	}
+
+	// Stats are looked up on a copy; characters loaded for the view are not stored
+	combatants := make(map[string]*encounters.CombatantData, len(data.Combatants))
+	maps.Copy(combatants, data.Combatants)
+
+	for _, entityID := range combatantIDs(data) {
+		var state *CombatantState
+		state, err = o.combatantState(ctx, data, combatants, entityID, showMonsterStats)
+		if err != nil {
+			return nil, err
+		}
+		output.Combatants = append(output.Combatants, state)
+	}
+
+	if output.CurrentTurn != "" {
+		entityType := initiativeType(data, output.CurrentTurn)
+		if placement, ok := roomPlacement(data.RoomData, output.CurrentTurn); ok {
+			entityType = placement.EntityType
+		}
+		var speed int
+		speed, err = o.speedOf(ctx, data, output.CurrentTurn, entityType)
+		if err != nil {
+			return nil, err
+		}
+		output.Turn = turnState(data, output.CurrentTurn, tracker.Round(), speed)
+	}
+
+	return output, nil
+}
+
+// canSeeMonsterStats reports whether a viewer may see monster hit points and armor class.
+// Only the DM of the encounter's campaign may; a request with no viewer may not.
+func (o *orchestrator) canSeeMonsterStats(ctx context.Context, data *encounters.EncounterData, viewerID string) (bool, error) {
+	if viewerID == "" || data.CampaignID == "" || o.campaignRepo == nil {
+		return false, nil
+	}
+
+	campaignOutput, err := o.campaignRepo.Get(ctx, campaignrepo.GetInput{ID: data.CampaignID})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get campaign %s", data.CampaignID)
+	}
+
+	for _, member := range campaignOutput.Campaign.Members {
+		if member.PlayerID == viewerID {
+			return member.Role == campaignrepo.RoleDM, nil
+		}
+	}
+	return false, nil
+}
+
+// combatantState builds the view of one combatant. Characters without stored stats are
+// loaded from their sheet when possible; a combatant without stats is still listed with
+// its position.
+func (o *orchestrator) combatantState(
+	ctx context.Context,
+	data *encounters.EncounterData,
+	combatants map[string]*encounters.CombatantData,
+	entityID string,
+	showMonsterStats bool,
+) (*CombatantState, error) {
+	placement, inRoom := roomPlacement(data.RoomData, entityID)
+	if !inRoom {
+		placement = spatial.EntityPlacement{EntityID: entityID, EntityType: initiativeType(data, entityID)}
+	}
+	state := &CombatantState{
+		EntityID:   entityID,
+		EntityType: placement.EntityType,
+		Position:   placement.Position,
+	}
+
+	combatant, err := o.combatant(ctx, combatants, placement)
+	if err != nil {
+		if !errors.IsFailedPrecondition(err) && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		slog.WarnContext(ctx, "combatant has no combat stats",
+			"encounter_id", data.ID,
+			"entity_id", entityID,
+			"error", err)
+		return state, nil
+	}
+
+	state.Name = combatant.Name
+	state.Conditions = combatant.Conditions
+	if combatant.EntityType != entityTypeCharacter && !showMonsterStats {
+		state.StatsHidden = true
+		return state, nil
+	}
+	state.ArmorClass = armorClassOf(combatant)
+	state.HitPoints = combatant.HitPoints
+	state.MaxHitPoints = combatant.MaxHitPoints
+	return state, nil
+}
+
+// combatantIDs returns the entities taking part in the fight: the initiative order, then
+// any characters, monsters or entities with stats placed in the room but not in the order
+func combatantIDs(data *encounters.EncounterData) []string {
+	var ids []string
+	if data.InitiativeData != nil {
+		for _, entity := range data.InitiativeData.Order {
+			ids = append(ids, entity.ID)
+		}
+	}
+
+	var others []string
+	if data.RoomData != nil {
+		for id, placement := range data.RoomData.Entities {
+			_, hasStats := data.Combatants[id]
+			isCombatant := placement.EntityType == entityTypeCharacter || placement.EntityType == entityTypeMonster
+			if (hasStats || isCombatant) && !slices.Contains(ids, id) {
+				others = append(others, id)
+			}
+		}
+	}
+	slices.Sort(others)
+
+	return append(ids, others...)
+}
+
+func roomPlacement(room *spatial.RoomData, entityID string) (spatial.EntityPlacement, bool) {
+	if room == nil {
+		return spatial.EntityPlacement{}, false
+	}
+	placement, ok := room.Entities[entityID]
+	return placement, ok
+}
+
+func initiativeType(data *encounters.EncounterData, entityID string) string {
+	if data.InitiativeData != nil {
+		for _, entity := range data.InitiativeData.Order {
+			if entity.ID == entityID {
+				return entity.Type
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,158 @@
+package encounter_test
+
+import (
+	"context"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/idgen"
+	campaignrepo "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
+	campaignrepomock "github.com/KirkDiggler/rpg-api/internal/repositories/campaign/mock"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/initiative"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+func (s *OrchestratorTestSuite) TestGetCombatState() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 5, Y: 0}, newOrc(9, "prone"))
+	s.expectHero()
+	_, err := s.move("hero", 2, 0)
+	s.Require().NoError(err)
+
+	output, err := s.orchestrator.GetCombatState(context.Background(), &encounter.GetCombatStateInput{
+		EncounterID: "enc-1",
+		Internal:    true,
+	})
+
+	s.Require().NoError(err)
+	s.Equal("hero", output.CurrentTurn)
+	s.Equal(1, output.Round)
+	s.Equal(spatial.Position{X: 2, Y: 0}, output.RoomData.Entities["hero"].Position)
+	s.Equal(&encounter.TurnState{
+		EntityID:                   "hero",
+		Speed:                      30,
+		MovementUsed:               10,
+		MovementRemaining:          20,
+		ActionAvailable:            true,
+		BonusActionAvailable:       true,
+		ReactionAvailable:          true,
+		ObjectInteractionAvailable: true,
+	}, output.Turn)
+
+	s.Require().Len(output.Combatants, 2)
+	hero, orc := output.Combatants[0], output.Combatants[1]
+	s.Equal("Hero", hero.Name)
+	s.Equal(spatial.Position{X: 2, Y: 0}, hero.Position)
+	s.Equal(16, hero.ArmorClass)
+	s.Equal(12, hero.HitPoints)
+	s.Equal("orc", orc.EntityID)
+	s.False(orc.StatsHidden)
+	s.Equal(9, orc.HitPoints)
+	s.Equal(15, orc.MaxHitPoints)
+	s.Equal([]string{"prone"}, orc.Conditions)
+}
+
+func (s *OrchestratorTestSuite) TestGetCombatState_HidesMonsterStatsFromPlayers() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 5, Y: 0}, newOrc(9, "prone"))
+	s.expectHero()
+
+	output, err := s.orchestrator.GetCombatState(context.Background(), &encounter.GetCombatStateInput{
+		EncounterID: "enc-1",
+		ViewerID:    "player-1",
+	})
+
+	s.Require().NoError(err)
+	hero, orc := output.Combatants[0], output.Combatants[1]
+	s.Equal(12, hero.HitPoints)
+	s.True(orc.StatsHidden)
+	s.Zero(orc.HitPoints)
+	s.Zero(orc.ArmorClass)
+	s.Equal([]string{"prone"}, orc.Conditions, "conditions are visible on the table")
+}
+
+func (s *OrchestratorTestSuite) TestGetCombatState_NoViewerSeesNoMonsterStats() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 5, Y: 0}, newOrc(9))
+	s.expectHero()
+
+	output, err := s.orchestrator.GetCombatState(context.Background(), &encounter.GetCombatStateInput{
+		EncounterID: "enc-1",
+	})
+
+	s.Require().NoError(err)
+	orc := output.Combatants[1]
+	s.True(orc.StatsHidden)
+	s.Zero(orc.HitPoints)
+}
+
+func (s *OrchestratorTestSuite) TestGetCombatState_CampaignDMSeesMonsterStats() {
+	ctrl := gomock.NewController(s.T())
+	mockCampaignRepo := campaignrepomock.NewMockRepository(ctrl)
+	orchestrator, err := encounter.NewOrchestrator(&encounter.Config{
+		IDGenerator:    idgen.NewSequential("test"),
+		Repository:     s.repo,
+		DiceService:    s.mockDiceService,
+		CharacterRepo:  s.mockCharRepo,
+		ExternalClient: s.mockExternalClient,
+		CampaignRepo:   mockCampaignRepo,
+	})
+	s.Require().NoError(err)
+
+	_, err = s.repo.Save(context.Background(), &encounters.SaveInput{
+		EncounterID: "enc-1",
+		CampaignID:  "camp-1",
+		RoomData: &spatial.RoomData{
+			ID:       "room-1",
+			Width:    10,
+			Height:   10,
+			GridType: spatial.GridTypeSquare,
+			Entities: map[string]spatial.EntityPlacement{
+				"orc": {EntityID: "orc", EntityType: "monster", Position: spatial.Position{X: 5, Y: 0}},
+			},
+		},
+		InitiativeData: &initiative.TrackerData{
+			Order: []initiative.EntityData{{ID: "orc", Type: "monster"}},
+			Round: 1,
+		},
+		Combatants: map[string]*encounters.CombatantData{"orc": newOrc(9)},
+	})
+	s.Require().NoError(err)
+	mockCampaignRepo.EXPECT().
+		Get(gomock.Any(), campaignrepo.GetInput{ID: "camp-1"}).
+		Return(&campaignrepo.GetOutput{Campaign: &campaignrepo.Campaign{
+			ID: "camp-1",
+			Members: []*campaignrepo.Member{
+				{PlayerID: "dm-1", Role: campaignrepo.RoleDM},
+				{PlayerID: "player-1", Role: campaignrepo.RolePlayer},
+			},
+		}}, nil)
+
+	output, err := orchestrator.GetCombatState(context.Background(), &encounter.GetCombatStateInput{
+		EncounterID: "enc-1",
+		ViewerID:    "dm-1",
+	})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.Combatants, 1)
+	s.False(output.Combatants[0].StatsHidden)
+	s.Equal(9, output.Combatants[0].HitPoints)
+	s.Equal(&encounter.TurnState{
+		EntityID:                   "orc",
+		Speed:                      30,
+		MovementRemaining:          30,
+		ActionAvailable:            true,
+		BonusActionAvailable:       true,
+		ReactionAvailable:          true,
+		ObjectInteractionAvailable: true,
+	}, output.Turn)
+}
+
+func (s *OrchestratorTestSuite) TestGetCombatState_NotFound() {
+	output, err := s.orchestrator.GetCombatState(context.Background(), &encounter.GetCombatStateInput{
+		EncounterID: "missing",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsNotFound(err))
+}
@@ -0,0 +1,336 @@
+package encounter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/initiative"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+const (
+	// maxGridSize caps each room dimension, in cells
+	maxGridSize = 100
+
+	// maxMonstersPerGroup caps the count of a single monster group
+	maxMonstersPerGroup = 50
+
+	defaultRoomType = "encounter"
+
+	entityTypeObstacle = "obstacle"
+)
+
+// obstacle is a placed entity that blocks movement, and optionally line of sight
+type obstacle struct {
+	id                string
+	blocksLineOfSight bool
+}
+
+func (e *obstacle) GetID() string           { return e.id }
+func (e *obstacle) GetType() string         { return entityTypeObstacle }
+func (e *obstacle) GetSize() int            { return 1 }
+func (e *obstacle) BlocksMovement() bool    { return true }
+func (e *obstacle) BlocksLineOfSight() bool { return e.blocksLineOfSight }
+
+// CreateEncounter builds a room on the requested grid, places the characters, monsters
+// and obstacles, rolls initiative and saves the encounter
+func (o *orchestrator) CreateEncounter(ctx context.Context, input *CreateEncounterInput) (*CreateEncounterOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	if err := validateCreateEncounter(input); err != nil {
+		return nil, err
+	}
+
+	grid, err := gridForRoom(&spatial.RoomData{GridType: input.GridType, Width: input.Width, Height: input.Height})
+	if err != nil {
+		return nil, err
+	}
+
+	// Obstacles and explicit spawn positions are claimed before any zone is filled, so a
+	// zone never hands out a cell another entity was asked to start in
+	occupied := make(map[spatial.Position]bool)
+	for i, obs := range input.Obstacles {
+		if err = claimCell(grid, occupied, obs.Position, fmt.Sprintf("obstacles[%d]", i)); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, pos := range input.DifficultTerrain {
+		if !isCell(grid, pos) {
+			return nil, errors.InvalidArgumentf("difficult_terrain[%d] (%v, %v) is not a cell in the room", i, pos.X, pos.Y)
+		}
+	}
+
+	monsterCount := 0
+	for _, group := range input.Monsters {
+		monsterCount += group.Count
+	}
+
+	characterCells, err := claimPositions(grid, occupied, input.CharacterSpawn, len(input.CharacterIDs), "character_spawn")
+	if err != nil {
+		return nil, err
+	}
+	monsterCells, err := claimPositions(grid, occupied, input.MonsterSpawn, monsterCount, "monster_spawn")
+	if err != nil {
+		return nil, err
+	}
+	characterCells, err = fillZone(grid, occupied, input.CharacterSpawn, characterCells, len(input.CharacterIDs), "character_spawn")
+	if err != nil {
+		return nil, err
+	}
+	monsterCells, err = fillZone(grid, occupied, input.MonsterSpawn, monsterCells, monsterCount, "monster_spawn")
+	if err != nil {
+		return nil, err
+	}
+
+	// Stat blocks are looked up once per group, before anything is generated
+	statBlocks := make(map[string]*external.MonsterData, len(input.Monsters))
+	for _, group := range input.Monsters {
+		if _, ok := statBlocks[group.MonsterID]; ok {
+			continue
+		}
+		var statBlock *external.MonsterData
+		statBlock, err = o.externalClient.GetMonsterData(ctx, group.MonsterID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get monster %s", group.MonsterID)
+		}
+		statBlocks[group.MonsterID] = statBlock
+	}
+
+	encounterID := o.idGen.Generate()
+
+	slog.InfoContext(ctx, "encounter creation requested",
+		"encounter_id", encounterID,
+		"grid_type", input.GridType,
+		"character_count", len(input.CharacterIDs),
+		"monster_count", monsterCount,
+	)
+
+	roomType := input.RoomType
+	if roomType == "" {
+		roomType = defaultRoomType
+	}
+	room := spatial.NewBasicRoom(spatial.BasicRoomConfig{
+		ID:   o.idGen.Generate(),
+		Type: roomType,
+		Grid: grid,
+	})
+
+	participants := make([]*initiativeParticipant, 0, len(input.CharacterIDs)+monsterCount)
+	for i, characterID := range input.CharacterIDs {
+		var participant *initiativeParticipant
+		participant, err = o.characterInitiative(ctx, characterID)
+		if err != nil {
+			return nil, err
+		}
+		if err = room.PlaceEntity(participant.entity, characterCells[i]); err != nil {
+			return nil, errors.Wrapf(err, "failed to place character %s", characterID)
+		}
+		participants = append(participants, participant)
+	}
+
+	combatants := make(map[string]*encounters.CombatantData, monsterCount)
+	for _, group := range input.Monsters {
+		for n := 1; n <= group.Count; n++ {
+			var monster *encounters.CombatantData
+			monster, err = o.monsterCombatant(ctx, statBlocks[group.MonsterID], o.idGen.Generate(), input.RollHitPoints)
+			if err != nil {
+				return nil, err
+			}
+			if group.Count > 1 {
+				monster.Name = fmt.Sprintf("%s %d", monster.Name, n)
+			}
+			participant := monsterInitiative(monster)
+			if err = room.PlaceEntity(participant.entity, monsterCells[len(combatants)]); err != nil {
+				return nil, errors.Wrapf(err, "failed to place monster %s", monster.EntityID)
+			}
+			combatants[monster.EntityID] = monster
+			participants = append(participants, participant)
+		}
+	}
+
+	for _, obs := range input.Obstacles {
+		entity := &obstacle{id: o.idGen.Generate(), blocksLineOfSight: obs.BlocksLineOfSight}
+		if err = room.PlaceEntity(entity, obs.Position); err != nil {
+			return nil, errors.Wrapf(err, "failed to place obstacle %s", entity.id)
+		}
+	}
+
+	order, initiativeEntries, err := o.rollInitiative(ctx, participants)
+	if err != nil {
+		return nil, err
+	}
+	tracker := initiative.New(order)
+
+	currentTurn := ""
+	if current := tracker.Current(); current != nil {
+		currentTurn = current.GetID()
+	}
+
+	roomData := room.ToData()
+	trackerData := tracker.ToData()
+
+	_, err = o.repo.Save(ctx, &encounters.SaveInput{
+		EncounterID:      encounterID,
+		CampaignID:       input.CampaignID,
+		SessionID:        input.SessionID,
+		RoomData:         &roomData,
+		InitiativeData:   &trackerData,
+		Initiative:       initiativeEntries,
+		DifficultTerrain: input.DifficultTerrain,
+		Combatants:       combatants,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to save encounter")
+	}
+
+	return &CreateEncounterOutput{
+		EncounterID:    encounterID,
+		RoomData:       &roomData,
+		InitiativeData: &trackerData,
+		Initiative:     initiativeEntries,
+		Combatants:     combatants,
+		CurrentTurn:    currentTurn,
+	}, nil
+}
+
+func validateCreateEncounter(input *CreateEncounterInput) error {
+	vb := errors.NewValidationBuilder()
+
+	errors.ValidateEnum("grid_type", input.GridType, []string{spatial.GridTypeHex, spatial.GridTypeSquare}, vb)
+	errors.ValidateRange("width", input.Width, 1, maxGridSize, vb)
+	errors.ValidateRange("height", input.Height, 1, maxGridSize, vb)
+
+	if len(input.CharacterIDs) == 0 && len(input.Monsters) == 0 {
+		vb.Field("character_ids", "at least one character or monster is required")
+	}
+	seen := make(map[string]bool, len(input.CharacterIDs))
+	for i, characterID := range input.CharacterIDs {
+		errors.ValidateRequired(fmt.Sprintf("character_ids[%d]", i), characterID, vb)
+		if seen[characterID] {
+			vb.Fieldf(fmt.Sprintf("character_ids[%d]", i), "duplicate character %s", characterID)
+		}
+		seen[characterID] = true
+	}
+	if len(input.CharacterIDs) > 0 {
+		validateSpawnArea("character_spawn", input.CharacterSpawn, vb)
+	}
+
+	for i, group := range input.Monsters {
+		field := fmt.Sprintf("monsters[%d]", i)
+		if group == nil {
+			vb.RequiredField(field)
+			continue
+		}
+		errors.ValidateRequired(field+".monster_id", group.MonsterID, vb)
+		errors.ValidateRange(field+".count", group.Count, 1, maxMonstersPerGroup, vb)
+	}
+	if len(input.Monsters) > 0 {
+		validateSpawnArea("monster_spawn", input.MonsterSpawn, vb)
+	}
+
+	for i, obs := range input.Obstacles {
+		if obs == nil {
+			vb.RequiredField(fmt.Sprintf("obstacles[%d]", i))
+		}
+	}
+
+	return vb.Build()
+}
+
+func validateSpawnArea(field string, area *SpawnArea, vb *errors.ValidationBuilder) {
+	if area == nil || (len(area.Positions) == 0 && area.Zone == nil) {
+		vb.Field(field, "positions or a zone is required")
+	}
+}
+
+// claimPositions claims a side's explicit spawn positions, up to count of them
+func claimPositions(
+	grid spatial.Grid,
+	occupied map[spatial.Position]bool,
+	area *SpawnArea,
+	count int,
+	field string,
+) ([]spatial.Position, error) {
+	if area == nil || count == 0 {
+		return nil, nil
+	}
+
+	cells := make([]spatial.Position, 0, count)
+	for i, pos := range area.Positions {
+		if len(cells) == count {
+			break
+		}
+		if err := claimCell(grid, occupied, pos, fmt.Sprintf("%s.positions[%d]", field, i)); err != nil {
+			return nil, err
+		}
+		cells = append(cells, pos)
+	}
+	return cells, nil
+}
+
+// fillZone claims free cells of a side's spawn zone, row by row, until the side has count
+func fillZone(
+	grid spatial.Grid,
+	occupied map[spatial.Position]bool,
+	area *SpawnArea,
+	cells []spatial.Position,
+	count int,
+	field string,
+) ([]spatial.Position, error) {
+	if len(cells) == count {
+		return cells, nil
+	}
+	if area == nil || area.Zone == nil {
+		return nil, errors.InvalidArgumentf("%s has %d positions for %d entities", field, len(cells), count)
+	}
+
+	zone := area.Zone
+	minX, maxX := math.Min(zone.From.X, zone.To.X), math.Max(zone.From.X, zone.To.X)
+	minY, maxY := math.Min(zone.From.Y, zone.To.Y), math.Max(zone.From.Y, zone.To.Y)
+	for _, corner := range []spatial.Position{{X: minX, Y: minY}, {X: maxX, Y: maxY}} {
+		if !isCell(grid, corner) {
+			return nil, errors.InvalidArgumentf("%s zone corner (%v, %v) is not a cell in the room", field, corner.X, corner.Y)
+		}
+	}
+
+	for y := minY; y <= maxY && len(cells) < count; y++ {
+		for x := minX; x <= maxX && len(cells) < count; x++ {
+			pos := spatial.Position{X: x, Y: y}
+			if occupied[pos] {
+				continue
+			}
+			occupied[pos] = true
+			cells = append(cells, pos)
+		}
+	}
+
+	if len(cells) < count {
+		return nil, errors.InvalidArgumentf("%s has room for %d of %d entities", field, len(cells), count)
+	}
+	return cells, nil
+}
+
+// claimCell marks a cell as taken, rejecting cells outside the room or already taken
+func claimCell(grid spatial.Grid, occupied map[spatial.Position]bool, pos spatial.Position, field string) error {
+	if !isCell(grid, pos) {
+		return errors.InvalidArgumentf("%s (%v, %v) is not a cell in the room", field, pos.X, pos.Y)
+	}
+	if occupied[pos] {
+		return errors.InvalidArgumentf("%s (%v, %v) is already taken", field, pos.X, pos.Y)
+	}
+	occupied[pos] = true
+	return nil
+}
+
+// isCell reports whether a position is a whole cell inside the grid
+func isCell(grid spatial.Grid, pos spatial.Position) bool {
+	return grid.IsValidPosition(pos) && pos.X == math.Trunc(pos.X) && pos.Y == math.Trunc(pos.Y)
+}
@@ -0,0 +1,169 @@
+package encounter_test
+
+import (
+	"context"
+
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+func (s *OrchestratorTestSuite) TestCreateEncounter() {
+	s.expectCharacters("hero", "mage")
+	s.expectInitiativeRolls(20, 15, 10, 5)
+
+	output, err := s.orchestrator.CreateEncounter(context.Background(), &encounter.CreateEncounterInput{
+		CampaignID:   "camp-1",
+		GridType:     spatial.GridTypeSquare,
+		Width:        8,
+		Height:       6,
+		CharacterIDs: []string{"hero", "mage"},
+		CharacterSpawn: &encounter.SpawnArea{
+			Positions: []spatial.Position{{X: 0, Y: 0}},
+			Zone:      &encounter.SpawnZone{From: spatial.Position{X: 0, Y: 0}, To: spatial.Position{X: 1, Y: 5}},
+		},
+		Monsters: []*encounter.MonsterGroup{{MonsterID: "goblin", Count: 2}},
+		MonsterSpawn: &encounter.SpawnArea{
+			Zone: &encounter.SpawnZone{From: spatial.Position{X: 7, Y: 5}, To: spatial.Position{X: 6, Y: 0}},
+		},
+		Obstacles:        []*encounter.Obstacle{{Position: spatial.Position{X: 6, Y: 0}, BlocksLineOfSight: true}},
+		DifficultTerrain: []spatial.Position{{X: 3, Y: 3}},
+	})
+
+	s.Require().NoError(err)
+	s.Equal("test_1", output.EncounterID)
+	s.Equal(spatial.GridTypeSquare, output.RoomData.GridType)
+	s.Equal(8, output.RoomData.Width)
+	s.Equal(6, output.RoomData.Height)
+	s.Equal("encounter", output.RoomData.Type)
+
+	entities := output.RoomData.Entities
+	s.Equal(spatial.Position{X: 0, Y: 0}, entities["hero"].Position)
+	s.Equal(spatial.Position{X: 1, Y: 0}, entities["mage"].Position, "the zone skips the hero's cell")
+	s.Equal(spatial.Position{X: 7, Y: 0}, entities["test_3"].Position, "the zone skips the obstacle")
+	s.Equal(spatial.Position{X: 6, Y: 1}, entities["test_4"].Position)
+	s.Equal(spatial.EntityPlacement{
+		EntityID:          "test_5",
+		EntityType:        "obstacle",
+		Position:          spatial.Position{X: 6, Y: 0},
+		Size:              1,
+		BlocksMovement:    true,
+		BlocksLineOfSight: true,
+	}, entities["test_5"])
+
+	s.Require().Len(output.Combatants, 2)
+	s.Equal("Goblin 1", output.Combatants["test_3"].Name)
+	s.Equal("Goblin 2", output.Combatants["test_4"].Name)
+	s.Equal(7, output.Combatants["test_4"].HitPoints)
+
+	s.Len(output.InitiativeData.Order, 4, "obstacles do not roll initiative")
+	s.Equal("hero", output.CurrentTurn)
+
+	stored, err := s.repo.Get(context.Background(), &encounters.GetInput{EncounterID: output.EncounterID})
+	s.Require().NoError(err)
+	s.Equal("camp-1", stored.Data.CampaignID)
+	s.Equal([]spatial.Position{{X: 3, Y: 3}}, stored.Data.DifficultTerrain)
+	s.Equal(output.Combatants, stored.Data.Combatants)
+}
+
+func (s *OrchestratorTestSuite) TestCreateEncounter_ObstaclesBlockMovement() {
+	s.expectCharacters("hero")
+	s.expectInitiativeRolls(20)
+
+	output, err := s.orchestrator.CreateEncounter(context.Background(), &encounter.CreateEncounterInput{
+		GridType:       spatial.GridTypeSquare,
+		Width:          3,
+		Height:         1,
+		CharacterIDs:   []string{"hero"},
+		CharacterSpawn: &encounter.SpawnArea{Positions: []spatial.Position{{X: 0, Y: 0}}},
+		Obstacles:      []*encounter.Obstacle{{Position: spatial.Position{X: 1, Y: 0}}},
+	})
+	s.Require().NoError(err)
+
+	_, err = s.orchestrator.Move(context.Background(), &encounter.MoveInput{
+		EncounterID: output.EncounterID,
+		EntityID:    "hero",
+		Target:      spatial.Position{X: 2, Y: 0},
+	})
+
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *OrchestratorTestSuite) TestCreateEncounter_InvalidInput() {
+	valid := func() *encounter.CreateEncounterInput {
+		return &encounter.CreateEncounterInput{
+			GridType:     spatial.GridTypeHex,
+			Width:        5,
+			Height:       5,
+			CharacterIDs: []string{"hero"},
+			CharacterSpawn: &encounter.SpawnArea{
+				Zone: &encounter.SpawnZone{From: spatial.Position{X: 0, Y: 0}, To: spatial.Position{X: 0, Y: 1}},
+			},
+			Monsters:     []*encounter.MonsterGroup{{MonsterID: "goblin", Count: 1}},
+			MonsterSpawn: &encounter.SpawnArea{Positions: []spatial.Position{{X: 4, Y: 4}}},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(input *encounter.CreateEncounterInput)
+	}{
+		{
+			name:   "gridless grid",
+			modify: func(input *encounter.CreateEncounterInput) { input.GridType = spatial.GridTypeGridless },
+		},
+		{
+			name:   "zero width",
+			modify: func(input *encounter.CreateEncounterInput) { input.Width = 0 },
+		},
+		{
+			name:   "missing monster ID",
+			modify: func(input *encounter.CreateEncounterInput) { input.Monsters[0].MonsterID = "" },
+		},
+		{
+			name:   "no monster count",
+			modify: func(input *encounter.CreateEncounterInput) { input.Monsters[0].Count = 0 },
+		},
+		{
+			name:   "missing character spawn",
+			modify: func(input *encounter.CreateEncounterInput) { input.CharacterSpawn = nil },
+		},
+		{
+			name: "spawn outside the room",
+			modify: func(input *encounter.CreateEncounterInput) {
+				input.MonsterSpawn.Positions = []spatial.Position{{X: 5, Y: 0}}
+			},
+		},
+		{
+			name: "spawn on an obstacle",
+			modify: func(input *encounter.CreateEncounterInput) {
+				input.Obstacles = []*encounter.Obstacle{{Position: spatial.Position{X: 4, Y: 4}}}
+			},
+		},
+		{
+			name: "zone too small",
+			modify: func(input *encounter.CreateEncounterInput) {
+				input.CharacterIDs = []string{"hero", "mage", "rogue"}
+			},
+		},
+		{
+			name: "difficult terrain outside the room",
+			modify: func(input *encounter.CreateEncounterInput) {
+				input.DifficultTerrain = []spatial.Position{{X: -1, Y: 0}}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			input := valid()
+			tc.modify(input)
+
+			output, err := s.orchestrator.CreateEncounter(context.Background(), input)
+
+			s.Nil(output)
+			s.True(errors.IsInvalidArgument(err), "got %v", err)
+		})
+	}
+}
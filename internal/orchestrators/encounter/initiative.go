@@ -0,0 +1,158 @@
+package encounter
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/dice"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	"github.com/KirkDiggler/rpg-toolkit/core"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+const (
+	// ContextInitiative is the dice session context initiative rolls are recorded under
+	ContextInitiative = "initiative"
+
+	// defaultAbilityScore is used for ability scores a stat block or sheet leaves out
+	defaultAbilityScore = 10
+)
+
+// initiativeParticipant is an entity about to roll initiative
+type initiativeParticipant struct {
+	entity         core.Entity
+	dexterityScore int
+	bonuses        []*encounters.InitiativeBonusData
+}
+
+// characterInitiative reads a character's DEX score and initiative bonuses. Characters
+// that aren't stored, such as the placeholder IDs the dev client starts with, roll with
+// DEX 10 like they move at the default speed.
+func (o *orchestrator) characterInitiative(ctx context.Context, characterID string) (*initiativeParticipant, error) {
+	participant := &initiativeParticipant{
+		entity:         &simpleEntity{id: characterID, entityType: entityTypeCharacter},
+		dexterityScore: defaultAbilityScore,
+	}
+	charOutput, err := o.charRepo.Get(ctx, characterrepo.GetInput{ID: characterID})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return participant, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get character %s", characterID)
+	}
+	charData := charOutput.CharacterData
+
+	participant.dexterityScore = abilityScore(charData.AbilityScores, constants.DEX)
+	// Initiative is a DEX check, so Jack of All Trades adds half proficiency from bard level 2
+	if charData.ClassID == constants.ClassBard && charData.Level >= 2 {
+		participant.bonuses = append(participant.bonuses, &encounters.InitiativeBonusData{
+			Source: "Jack of All Trades",
+			Value:  int(dnd5e.ProficiencyBonus(charData.Level)) / 2,
+		})
+	}
+	return participant, nil
+}
+
+// monsterInitiative reads a monster's DEX score from its stat block
+func monsterInitiative(combatant *encounters.CombatantData) *initiativeParticipant {
+	return &initiativeParticipant{
+		entity:         &simpleEntity{id: combatant.EntityID, entityType: combatant.EntityType},
+		dexterityScore: abilityScore(combatant.AbilityScores, constants.DEX),
+	}
+}
+
+// rollInitiative rolls a d20 for every participant and returns them in turn order with
+// the breakdown of each roll. Ties go to the higher DEX score; participants still tied
+// roll off once, and any tie after that is settled by entity ID so the order is stable.
+func (o *orchestrator) rollInitiative(
+	ctx context.Context,
+	participants []*initiativeParticipant,
+) ([]core.Entity, []*encounters.InitiativeEntryData, error) {
+	entities := make(map[string]core.Entity, len(participants))
+	entries := make([]*encounters.InitiativeEntryData, 0, len(participants))
+	for _, participant := range participants {
+		entityID := participant.entity.GetID()
+		roll, err := o.rollD20(ctx, entityID, "Initiative")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		entry := &encounters.InitiativeEntryData{
+			EntityID:          entityID,
+			Roll:              roll,
+			DexterityModifier: int(dnd5e.AbilityModifier(participant.dexterityScore)),
+			Bonuses:           participant.bonuses,
+			DexterityScore:    participant.dexterityScore,
+		}
+		entry.Modifier = entry.DexterityModifier
+		for _, bonus := range participant.bonuses {
+			entry.Modifier += bonus.Value
+		}
+		entry.Total = entry.Roll + entry.Modifier
+
+		entities[entityID] = participant.entity
+		entries = append(entries, entry)
+	}
+
+	for _, entry := range entries {
+		if entry.RollOff != 0 {
+			continue
+		}
+		tied := slices.ContainsFunc(entries, func(other *encounters.InitiativeEntryData) bool {
+			return other != entry && other.Total == entry.Total && other.DexterityScore == entry.DexterityScore
+		})
+		if !tied {
+			continue
+		}
+		roll, err := o.rollD20(ctx, entry.EntityID, "Initiative roll-off")
+		if err != nil {
+			return nil, nil, err
+		}
+		entry.RollOff = roll
+	}
+
+	ordered := slices.Clone(entries)
+	slices.SortStableFunc(ordered, func(a, b *encounters.InitiativeEntryData) int {
+		return cmp.Or(
+			cmp.Compare(b.Total, a.Total),
+			cmp.Compare(b.DexterityScore, a.DexterityScore),
+			cmp.Compare(b.RollOff, a.RollOff),
+			cmp.Compare(a.EntityID, b.EntityID),
+		)
+	})
+
+	order := make([]core.Entity, len(ordered))
+	for i, entry := range ordered {
+		order[i] = entities[entry.EntityID]
+	}
+	return order, ordered, nil
+}
+
+// rollD20 rolls a single d20 through the dice service
+func (o *orchestrator) rollD20(ctx context.Context, entityID, description string) (int, error) {
+	rollOutput, err := o.diceService.RollDice(ctx, &dice.RollDiceInput{
+		EntityID:    entityID,
+		Context:     ContextInitiative,
+		Notation:    "1d20",
+		Description: description,
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to roll initiative for %s", entityID)
+	}
+	if rollOutput.Roll == nil || len(rollOutput.Roll.Dice) == 0 {
+		return 0, errors.Internal("dice roll returned no dice")
+	}
+	return int(rollOutput.Roll.Dice[0]), nil
+}
+
+func abilityScore(scores shared.AbilityScores, ability constants.Ability) int {
+	if score, ok := scores[ability]; ok {
+		return score
+	}
+	return defaultAbilityScore
+}
@@ -0,0 +1,124 @@
+package encounter_test
+
+import (
+	"context"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+func (s *OrchestratorTestSuite) TestDungeonStart_InitiativeUsesCharacterModifiers() {
+	s.expectCharacterData(&toolkitchar.Data{
+		ID:            "rogue",
+		Level:         3,
+		ClassID:       constants.ClassRogue,
+		AbilityScores: shared.AbilityScores{constants.DEX: 16},
+	}, nil)
+	s.expectCharacterData(&toolkitchar.Data{
+		ID:            "bard",
+		Level:         5,
+		ClassID:       constants.ClassBard,
+		AbilityScores: shared.AbilityScores{constants.DEX: 12},
+	}, nil)
+	s.expectCharacterData(&toolkitchar.Data{
+		ID:            "sentry",
+		Level:         4,
+		ClassID:       constants.ClassFighter,
+		AbilityScores: shared.AbilityScores{constants.DEX: 9},
+	}, nil)
+	// rogue, bard, sentry, then the goblin
+	s.expectInitiativeRolls(10, 10, 10, 10)
+
+	output, err := s.orchestrator.DungeonStart(context.Background(), &encounter.DungeonStartInput{
+		CharacterIDs: []string{"rogue", "bard", "sentry"},
+	})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.Initiative, 4)
+	rogue, goblin, bard, sentry := output.Initiative[0], output.Initiative[1], output.Initiative[2], output.Initiative[3]
+
+	s.Equal("rogue", rogue.EntityID)
+	s.Equal(3, rogue.Modifier)
+	s.Equal(13, rogue.Total)
+
+	// The goblin and the bard tie on 12; the goblin's higher DEX goes first
+	s.Equal(12, goblin.Total)
+	s.Equal(14, goblin.DexterityScore)
+	s.Equal("bard", bard.EntityID)
+	s.Equal([]*encounters.InitiativeBonusData{{Source: "Jack of All Trades", Value: 1}}, bard.Bonuses)
+	s.Equal(12, bard.Total)
+	s.Zero(bard.RollOff)
+
+	s.Equal("sentry", sentry.EntityID)
+	s.Equal(-1, sentry.DexterityModifier)
+	s.Empty(sentry.Bonuses)
+	s.Equal(9, sentry.Total)
+
+	s.Equal("monster", output.InitiativeData.Order[1].Type)
+	s.Equal("rogue", output.CurrentTurn)
+
+	// The breakdown is stored with the encounter
+	turnOrder, err := s.orchestrator.GetTurnOrder(context.Background(), &encounter.GetTurnOrderInput{
+		EncounterID: output.EncounterID,
+	})
+	s.Require().NoError(err)
+	s.Equal(output.Initiative, turnOrder.Initiative)
+}
+
+func (s *OrchestratorTestSuite) TestDungeonStart_InitiativeRollOffBreaksFullTies() {
+	s.expectCharacters("first", "second")
+	// first, second, the goblin, then the roll-off between first and second
+	s.expectInitiativeRolls(10, 10, 1, 3, 17)
+
+	output, err := s.orchestrator.DungeonStart(context.Background(), &encounter.DungeonStartInput{
+		CharacterIDs: []string{"first", "second"},
+	})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.Initiative, 3)
+	s.Equal("second", output.Initiative[0].EntityID)
+	s.Equal(17, output.Initiative[0].RollOff)
+	s.Equal("first", output.Initiative[1].EntityID)
+	s.Equal(3, output.Initiative[1].RollOff)
+	s.Zero(output.Initiative[2].RollOff)
+}
+
+func (s *OrchestratorTestSuite) TestDungeonStart_UnknownCharacterRollsWithDefaultDexterity() {
+	s.mockCharRepo.EXPECT().
+		Get(gomock.Any(), characterrepo.GetInput{ID: "char-1"}).
+		Return(nil, errors.NotFound("character not found")).
+		AnyTimes()
+	s.expectInitiativeRolls(15, 1)
+
+	output, err := s.orchestrator.DungeonStart(context.Background(), &encounter.DungeonStartInput{
+		CharacterIDs: []string{"char-1"},
+	})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.Initiative, 2)
+	s.Equal("char-1", output.Initiative[0].EntityID)
+	s.Equal(10, output.Initiative[0].DexterityScore)
+	s.Zero(output.Initiative[0].Modifier)
+	s.Empty(output.Initiative[0].Bonuses)
+	s.Equal(15, output.Initiative[0].Total)
+}
+
+func (s *OrchestratorTestSuite) TestDungeonStart_CharacterLookupFails() {
+	s.mockCharRepo.EXPECT().
+		Get(gomock.Any(), characterrepo.GetInput{ID: "hero"}).
+		Return(nil, errors.Internal("redis down"))
+
+	output, err := s.orchestrator.DungeonStart(context.Background(), &encounter.DungeonStartInput{
+		CharacterIDs: []string{"hero"},
+	})
+
+	s.Nil(output)
+	s.Error(err)
+}
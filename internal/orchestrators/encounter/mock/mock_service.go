@@ -42,6 +42,36 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// Attack mocks base method.
+func (m *MockService) Attack(ctx context.Context, input *encounter.AttackInput) (*encounter.AttackOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attack", ctx, input)
+	ret0, _ := ret[0].(*encounter.AttackOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Attack indicates an expected call of Attack.
+func (mr *MockServiceMockRecorder) Attack(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attack", reflect.TypeOf((*MockService)(nil).Attack), ctx, input)
+}
+
+// CreateEncounter mocks base method.
+func (m *MockService) CreateEncounter(ctx context.Context, input *encounter.CreateEncounterInput) (*encounter.CreateEncounterOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEncounter", ctx, input)
+	ret0, _ := ret[0].(*encounter.CreateEncounterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEncounter indicates an expected call of CreateEncounter.
+func (mr *MockServiceMockRecorder) CreateEncounter(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEncounter", reflect.TypeOf((*MockService)(nil).CreateEncounter), ctx, input)
+}
+
 // DungeonStart mocks base method.
 func (m *MockService) DungeonStart(ctx context.Context, input *encounter.DungeonStartInput) (*encounter.DungeonStartOutput, error) {
 	m.ctrl.T.Helper()
@@ -57,6 +87,21 @@ func (mr *MockServiceMockRecorder) DungeonStart(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DungeonStart", reflect.TypeOf((*MockService)(nil).DungeonStart), ctx, input)
 }
 
+// GetCombatState mocks base method.
+func (m *MockService) GetCombatState(ctx context.Context, input *encounter.GetCombatStateInput) (*encounter.GetCombatStateOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCombatState", ctx, input)
+	ret0, _ := ret[0].(*encounter.GetCombatStateOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCombatState indicates an expected call of GetCombatState.
+func (mr *MockServiceMockRecorder) GetCombatState(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCombatState", reflect.TypeOf((*MockService)(nil).GetCombatState), ctx, input)
+}
+
 // GetTurnOrder mocks base method.
 func (m *MockService) GetTurnOrder(ctx context.Context, input *encounter.GetTurnOrderInput) (*encounter.GetTurnOrderOutput, error) {
 	m.ctrl.T.Helper()
@@ -72,6 +117,21 @@ func (mr *MockServiceMockRecorder) GetTurnOrder(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTurnOrder", reflect.TypeOf((*MockService)(nil).GetTurnOrder), ctx, input)
 }
 
+// Move mocks base method.
+func (m *MockService) Move(ctx context.Context, input *encounter.MoveInput) (*encounter.MoveOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, input)
+	ret0, _ := ret[0].(*encounter.MoveOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockServiceMockRecorder) Move(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockService)(nil).Move), ctx, input)
+}
+
 // NextTurn mocks base method.
 func (m *MockService) NextTurn(ctx context.Context, input *encounter.NextTurnInput) (*encounter.NextTurnOutput, error) {
 	m.ctrl.T.Helper()
@@ -86,3 +146,48 @@ func (mr *MockServiceMockRecorder) NextTurn(ctx, input any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextTurn", reflect.TypeOf((*MockService)(nil).NextTurn), ctx, input)
 }
+
+// RespondToReaction mocks base method.
+func (m *MockService) RespondToReaction(ctx context.Context, input *encounter.RespondToReactionInput) (*encounter.RespondToReactionOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondToReaction", ctx, input)
+	ret0, _ := ret[0].(*encounter.RespondToReactionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondToReaction indicates an expected call of RespondToReaction.
+func (mr *MockServiceMockRecorder) RespondToReaction(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToReaction", reflect.TypeOf((*MockService)(nil).RespondToReaction), ctx, input)
+}
+
+// ResumeAction mocks base method.
+func (m *MockService) ResumeAction(ctx context.Context, input *encounter.ResumeActionInput) (*encounter.ResumeActionOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeAction", ctx, input)
+	ret0, _ := ret[0].(*encounter.ResumeActionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeAction indicates an expected call of ResumeAction.
+func (mr *MockServiceMockRecorder) ResumeAction(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeAction", reflect.TypeOf((*MockService)(nil).ResumeAction), ctx, input)
+}
+
+// UseAction mocks base method.
+func (m *MockService) UseAction(ctx context.Context, input *encounter.UseActionInput) (*encounter.UseActionOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseAction", ctx, input)
+	ret0, _ := ret[0].(*encounter.UseActionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseAction indicates an expected call of UseAction.
+func (mr *MockServiceMockRecorder) UseAction(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseAction", reflect.TypeOf((*MockService)(nil).UseAction), ctx, input)
+}
@@ -0,0 +1,166 @@
+package encounter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/dice"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
+)
+
+// ContextHitPoints is the dice session context rolled monster hit points are recorded under
+const ContextHitPoints = "hit_points"
+
+// damageDicePattern splits SRD damage such as "1d6+2" or "2d8 - 1" into dice and bonus
+var damageDicePattern = regexp.MustCompile(`^(\d+d\d+)\s*(?:([+-])\s*(\d+))?$`)
+
+// monsterCombatant builds a monster's combat stats from its stat block, with either the
+// average hit points or hit points rolled from its hit dice
+func (o *orchestrator) monsterCombatant(
+	ctx context.Context,
+	statBlock *external.MonsterData,
+	entityID string,
+	rollHitPoints bool,
+) (*encounters.CombatantData, error) {
+	monster := &encounters.CombatantData{
+		EntityID:              entityID,
+		EntityType:            entityTypeMonster,
+		Name:                  statBlock.Name,
+		ArmorClass:            int(statBlock.ArmorClass),
+		HitPoints:             int(statBlock.HitPoints),
+		AbilityScores:         shared.AbilityScores{},
+		DamageResistances:     statBlock.DamageResistances,
+		DamageImmunities:      statBlock.DamageImmunities,
+		DamageVulnerabilities: statBlock.DamageVulnerabilities,
+	}
+	for ability, score := range statBlock.AbilityScores {
+		monster.AbilityScores[constants.Ability(ability)] = int(score)
+	}
+	if statBlock.Speed != nil {
+		monster.Speed = int(statBlock.Speed.Walk)
+	}
+
+	if rollHitPoints && statBlock.HitDice != "" {
+		hitPoints, err := o.rollHitPoints(ctx, entityID, statBlock.HitDice, abilityScore(monster.AbilityScores, constants.CON))
+		if err != nil {
+			return nil, err
+		}
+		monster.HitPoints = hitPoints
+	}
+	monster.MaxHitPoints = monster.HitPoints
+
+	for _, action := range statBlock.Actions {
+		attack, ok := monsterAttack(action)
+		if !ok {
+			continue
+		}
+		monster.Attacks = append(monster.Attacks, attack)
+	}
+
+	return monster, nil
+}
+
+// rollHitPoints rolls hit dice such as "2d6", adding the CON modifier once per die.
+// A monster always has at least 1 hit point.
+func (o *orchestrator) rollHitPoints(ctx context.Context, entityID, hitDice string, constitution int) (int, error) {
+	count, _, ok := strings.Cut(hitDice, "d")
+	dieCount, err := strconv.Atoi(count)
+	if !ok || err != nil {
+		return 0, errors.Internalf("invalid hit dice %q", hitDice)
+	}
+
+	rollOutput, err := o.diceService.RollDice(ctx, &dice.RollDiceInput{
+		EntityID:    entityID,
+		Context:     ContextHitPoints,
+		Notation:    hitDice,
+		Description: "Hit points",
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to roll hit points for %s", entityID)
+	}
+	if rollOutput.Roll == nil {
+		return 0, errors.Internal("dice roll returned no dice")
+	}
+
+	total := dieCount * int(dnd5e.AbilityModifier(constitution))
+	for _, die := range rollOutput.Roll.Dice {
+		total += int(die)
+	}
+	return max(1, total), nil
+}
+
+// monsterAttack converts a stat block action to an attack. Actions without an attack
+// roll, reach or range, such as Multiattack, are not attacks. Only the first damage
+// component is used; riders such as "plus 1d6 fire" are not applied.
+func monsterAttack(action *external.MonsterActionData) (*encounters.AttackData, bool) {
+	if len(action.Damage) == 0 || (action.ReachFeet == 0 && action.Range == nil) {
+		return nil, false
+	}
+
+	damage := action.Damage[0]
+	matches := damageDicePattern.FindStringSubmatch(damage.DamageDice)
+	if matches == nil {
+		slog.Warn("unsupported monster damage dice", "action", action.Name, "damage_dice", damage.DamageDice)
+		return nil, false
+	}
+
+	attack := &encounters.AttackData{
+		ID:          attackID(action.Name),
+		Name:        action.Name,
+		AttackBonus: int(action.AttackBonus),
+		DamageDice:  matches[1],
+		DamageType:  damage.DamageType,
+		ReachFeet:   int(action.ReachFeet),
+	}
+	if matches[3] != "" {
+		bonus, _ := strconv.Atoi(matches[3])
+		if matches[2] == "-" {
+			bonus = -bonus
+		}
+		attack.DamageBonus = bonus
+	}
+	if action.Range != nil {
+		attack.NormalRange = action.Range.Normal
+		attack.LongRange = action.Range.Long
+	}
+	return attack, true
+}
+
+// attackID turns an action name such as "Light Crossbow" into "light-crossbow"
+func attackID(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "-")
+}
+
+// adjustDamage applies a target's immunity, resistance or vulnerability to a damage type
+func adjustDamage(target *encounters.CombatantData, damage int32, damageType string) (int32, string) {
+	has := func(types []string) bool {
+		for _, t := range types {
+			if strings.EqualFold(t, damageType) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case damageType == "":
+		return damage, ""
+	case has(target.DamageImmunities):
+		return 0, fmt.Sprintf("%s is immune to %s damage", nameOf(target), damageType)
+	case has(target.DamageResistances):
+		return damage / 2, fmt.Sprintf("%s resists %s damage: %d", nameOf(target), damageType, damage/2)
+	case has(target.DamageVulnerabilities):
+		return damage * 2, fmt.Sprintf("%s is vulnerable to %s damage: %d", nameOf(target), damageType, damage*2)
+	default:
+		return damage, ""
+	}
+}
@@ -0,0 +1,144 @@
+package encounter_test
+
+import (
+	"context"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/dice"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
+	dicesession "github.com/KirkDiggler/rpg-api/internal/repositories/dice_session"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+// createMonsterEncounter creates a square room with the given monsters and no characters
+func (s *OrchestratorTestSuite) createMonsterEncounter(
+	rollHitPoints bool,
+	groups ...*encounter.MonsterGroup,
+) (*encounter.CreateEncounterOutput, error) {
+	return s.orchestrator.CreateEncounter(context.Background(), &encounter.CreateEncounterInput{
+		GridType:      spatial.GridTypeSquare,
+		Width:         5,
+		Height:        5,
+		Monsters:      groups,
+		MonsterSpawn:  &encounter.SpawnArea{Zone: &encounter.SpawnZone{To: spatial.Position{X: 4, Y: 4}}},
+		RollHitPoints: rollHitPoints,
+	})
+}
+
+func (s *OrchestratorTestSuite) TestCreateEncounter_MonstersUseSRDStatBlocks() {
+	s.mockExternalClient.EXPECT().
+		GetMonsterData(gomock.Any(), "skeleton").
+		Return(&external.MonsterData{
+			ID:                    "skeleton",
+			Name:                  "Skeleton",
+			ArmorClass:            13,
+			HitPoints:             13,
+			HitDice:               "2d8",
+			Speed:                 &external.MonsterSpeedData{Walk: 30},
+			AbilityScores:         map[string]int32{"dex": 14, "con": 15},
+			DamageVulnerabilities: []string{"bludgeoning"},
+			DamageImmunities:      []string{"poison"},
+			Actions: []*external.MonsterActionData{
+				{Name: "Multiattack", Description: "The skeleton makes two attacks."},
+				{
+					Name:        "Shortsword",
+					AttackBonus: 4,
+					Damage:      []*external.DamageData{{DamageDice: "1d6+2", DamageType: "piercing"}},
+					ReachFeet:   5,
+				},
+				{
+					Name:        "Shortbow",
+					AttackBonus: 4,
+					Damage:      []*external.DamageData{{DamageDice: "1d6+2", DamageType: "piercing"}},
+					Range:       &external.WeaponRangeData{Normal: 80, Long: 320},
+				},
+			},
+		}, nil)
+	s.expectInitiativeRolls()
+
+	output, err := s.createMonsterEncounter(false, &encounter.MonsterGroup{MonsterID: "skeleton", Count: 1})
+
+	s.Require().NoError(err)
+	skeleton := output.Combatants["test_3"]
+	s.Require().NotNil(skeleton)
+	s.Equal("Skeleton", skeleton.Name)
+	s.Equal(13, skeleton.ArmorClass)
+	s.Equal(13, skeleton.HitPoints, "average hit points")
+	s.Equal(13, skeleton.MaxHitPoints)
+	s.Equal(30, skeleton.Speed)
+	s.Equal(14, skeleton.AbilityScores[constants.DEX])
+	s.Equal([]string{"bludgeoning"}, skeleton.DamageVulnerabilities)
+	s.Equal([]string{"poison"}, skeleton.DamageImmunities)
+	s.Equal([]*encounters.AttackData{
+		{ID: "shortsword", Name: "Shortsword", AttackBonus: 4, DamageDice: "1d6", DamageBonus: 2, DamageType: "piercing", ReachFeet: 5},
+		{ID: "shortbow", Name: "Shortbow", AttackBonus: 4, DamageDice: "1d6", DamageBonus: 2, DamageType: "piercing",
+			NormalRange: 80, LongRange: 320},
+	}, skeleton.Attacks, "Multiattack is not an attack")
+	s.Equal(2, output.Initiative[0].DexterityModifier)
+}
+
+func (s *OrchestratorTestSuite) TestCreateEncounter_RollsMonsterHitPoints() {
+	s.mockDiceService.EXPECT().
+		RollDice(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *dice.RollDiceInput) (*dice.RollDiceOutput, error) {
+			s.Equal(encounter.ContextHitPoints, input.Context)
+			s.Equal("2d6", input.Notation)
+			return &dice.RollDiceOutput{Roll: &dicesession.DiceRoll{Notation: input.Notation, Dice: []int32{6, 5}}}, nil
+		})
+	s.expectInitiativeRolls()
+
+	output, err := s.createMonsterEncounter(true, &encounter.MonsterGroup{MonsterID: "goblin", Count: 1})
+
+	s.Require().NoError(err)
+	goblin := output.Combatants["test_3"]
+	s.Equal(11, goblin.HitPoints)
+	s.Equal(11, goblin.MaxHitPoints)
+}
+
+func (s *OrchestratorTestSuite) TestCreateEncounter_MonsterLookupFails() {
+	s.mockExternalClient.EXPECT().
+		GetMonsterData(gomock.Any(), "tarrasque").
+		Return(nil, errors.Internal("unexpected status code: 404"))
+
+	output, err := s.createMonsterEncounter(false, &encounter.MonsterGroup{MonsterID: "tarrasque", Count: 1})
+
+	s.Nil(output)
+	s.ErrorContains(err, "failed to get monster tarrasque")
+}
+
+func (s *OrchestratorTestSuite) TestAttack_ResistanceHalvesDamage() {
+	orc := newOrc(15)
+	orc.DamageResistances = []string{"slashing"}
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 1}, orc)
+	s.expectHero()
+	s.expectAttackRoll("hero", "1d20", 11)
+	s.expectAttackRoll("hero", "1d8", 6)
+
+	output, err := s.attack("hero", "orc")
+
+	s.Require().NoError(err)
+	s.Equal(int32(4), output.Result.Damage, "9 slashing halved, rounded down")
+	s.Equal(int32(11), output.Result.TargetHitPoints)
+	s.Contains(output.Result.Steps, "Orc resists slashing damage: 4")
+}
+
+func (s *OrchestratorTestSuite) TestAttack_ImmunityPreventsDamage() {
+	orc := newOrc(15)
+	orc.DamageImmunities = []string{"slashing"}
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 1}, orc)
+	s.expectHero()
+	s.expectAttackRoll("hero", "1d20", 11)
+	s.expectAttackRoll("hero", "1d8", 6)
+
+	output, err := s.attack("hero", "orc")
+
+	s.Require().NoError(err)
+	s.True(output.Result.Hit)
+	s.Zero(output.Result.Damage)
+	s.Equal(int32(15), output.Result.TargetHitPoints)
+}
@@ -0,0 +1,292 @@
+package encounter
+
+import (
+	"container/heap"
+	"context"
+	"log/slog"
+	"math"
+	"slices"
+
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+const (
+	// feetPerCell is the distance covered by moving one grid cell
+	feetPerCell = 5
+
+	// defaultSpeed is used for entities without a known speed
+	defaultSpeed = 30
+
+	entityTypeCharacter = "character"
+)
+
+// MetaKeyMovementError is the error metadata key holding one of the MovementError values,
+// so callers can tell a rejected move from a failed request
+const MetaKeyMovementError = "movement_error"
+
+// Reasons a move can be rejected
+const (
+	MovementErrorInvalidPosition      = "invalid_position"
+	MovementErrorOutOfBounds          = "out_of_bounds"
+	MovementErrorPositionOccupied     = "position_occupied"
+	MovementErrorPathBlocked          = "path_blocked"
+	MovementErrorInsufficientMovement = "insufficient_movement"
+	MovementErrorNotYourTurn          = "not_your_turn"
+)
+
+// Move moves an entity along the cheapest open path to the target, charging the
+// movement against what it has left this turn. Entering difficult terrain costs double.
+// Leaving an enemy's reach gives it an opportunity attack, which can stop the move early.
+func (o *orchestrator) Move(ctx context.Context, input *MoveInput) (*MoveOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("encounter_id", input.EncounterID, vb)
+	errors.ValidateRequired("entity_id", input.EntityID, vb)
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+
+	return retryOnConflict(func() (*MoveOutput, error) {
+		return o.move(ctx, input)
+	})
+}
+
+// move is Move against a fresh read of the encounter
+func (o *orchestrator) move(ctx context.Context, input *MoveInput) (*MoveOutput, error) {
+	getOutput, err := o.repo.Get(ctx, &encounters.GetInput{EncounterID: input.EncounterID})
+	if err != nil {
+		return nil, err
+	}
+	data := getOutput.Data
+	if err := requireNoPendingAction(data); err != nil {
+		return nil, err
+	}
+	st, err := loadActionState(data, input.EntityID)
+	if err != nil {
+		return nil, err
+	}
+
+	placement, ok := data.RoomData.Entities[input.EntityID]
+	if !ok {
+		return nil, errors.NotFoundf("entity %s is not in encounter %s", input.EntityID, input.EncounterID)
+	}
+
+	if st.currentTurn() != input.EntityID {
+		return nil, errors.FailedPreconditionf("it is not %s's turn", input.EntityID).
+			WithMeta(MetaKeyMovementError, MovementErrorNotYourTurn)
+	}
+
+	target := input.Target
+	if !st.grid.IsValidPosition(target) {
+		return nil, errors.InvalidArgumentf("position (%v, %v) is outside the room", target.X, target.Y).
+			WithMeta(MetaKeyMovementError, MovementErrorOutOfBounds)
+	}
+	if target.X != math.Trunc(target.X) || target.Y != math.Trunc(target.Y) || target.Equals(placement.Position) {
+		return nil, errors.InvalidArgumentf("position (%v, %v) is not a cell the entity can move to", target.X, target.Y).
+			WithMeta(MetaKeyMovementError, MovementErrorInvalidPosition)
+	}
+
+	occupied := occupiedCells(data.RoomData, input.EntityID)
+	if occupied[target] {
+		return nil, errors.FailedPreconditionf("position (%v, %v) is occupied", target.X, target.Y).
+			WithMeta(MetaKeyMovementError, MovementErrorPositionOccupied)
+	}
+
+	difficult := make(map[spatial.Position]bool, len(data.DifficultTerrain))
+	for _, pos := range data.DifficultTerrain {
+		difficult[pos] = true
+	}
+
+	path, cost, found := findPath(st.grid, placement.Position, target, occupied, difficult)
+	if !found {
+		return nil, errors.FailedPreconditionf("no open path to (%v, %v)", target.X, target.Y).
+			WithMeta(MetaKeyMovementError, MovementErrorPathBlocked)
+	}
+
+	speed, err := o.speedOf(ctx, data, input.EntityID, placement.EntityType)
+	if err != nil {
+		return nil, err
+	}
+
+	if st.turn.MovementUsed+cost > speed {
+		return nil, errors.FailedPreconditionf("moving costs %d ft but only %d ft remain", cost, speed-st.turn.MovementUsed).
+			WithMeta(MetaKeyMovementError, MovementErrorInsufficientMovement)
+	}
+
+	st.wait = input.WaitForReactions
+	entered, spent, err := o.walk(ctx, st, input.EntityID, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := o.save(ctx, st); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "entity moved",
+		"encounter_id", input.EncounterID,
+		"entity_id", input.EntityID,
+		"cost", spent,
+		"movement_remaining", speed-st.turn.MovementUsed,
+		"waiting_on_reactions", st.pending != nil)
+
+	return &MoveOutput{
+		Path:              entered,
+		MovementCost:      spent,
+		MovementUsed:      st.turn.MovementUsed,
+		MovementRemaining: speed - st.turn.MovementUsed,
+		Speed:             speed,
+		RoomData:          data.RoomData,
+		InitiativeData:    data.InitiativeData,
+		Initiative:        data.Initiative,
+		CurrentTurn:       st.currentTurn(),
+		Turn:              turnState(data, input.EntityID, st.tracker.Round(), speed),
+		Interrupted:       st.pending == nil && len(entered) < len(path),
+		Reactions:         st.reactions,
+		Pending:           st.pending,
+	}, nil
+}
+
+// speedOf looks up an entity's walking speed. Characters use their stored speed and
+// monsters the speed from their stat block; anything else, or a character the repository
+// doesn't know, moves at the default speed.
+func (o *orchestrator) speedOf(ctx context.Context, data *encounters.EncounterData, entityID, entityType string) (int, error) {
+	if entityType != entityTypeCharacter {
+		if combatant, ok := data.Combatants[entityID]; ok && combatant.Speed > 0 {
+			return combatant.Speed, nil
+		}
+		return defaultSpeed, nil
+	}
+
+	charOutput, err := o.charRepo.Get(ctx, characterrepo.GetInput{ID: entityID})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return defaultSpeed, nil
+		}
+		return 0, errors.Wrapf(err, "failed to get character %s", entityID)
+	}
+	if charOutput.CharacterData.Speed <= 0 {
+		return defaultSpeed, nil
+	}
+	return charOutput.CharacterData.Speed, nil
+}
+
+// gridForRoom rebuilds the grid a room was created with
+func gridForRoom(room *spatial.RoomData) (spatial.Grid, error) {
+	switch room.GridType {
+	case spatial.GridTypeHex:
+		return spatial.NewHexGrid(spatial.HexGridConfig{
+			Width:     float64(room.Width),
+			Height:    float64(room.Height),
+			PointyTop: true, // Encounters always use pointy-top hexes (D&D 5e standard)
+		}), nil
+	case spatial.GridTypeSquare:
+		return spatial.NewSquareGrid(spatial.SquareGridConfig{
+			Width:  float64(room.Width),
+			Height: float64(room.Height),
+		}), nil
+	default:
+		return nil, errors.FailedPreconditionf("movement is not supported on %q grids", room.GridType)
+	}
+}
+
+// occupiedCells returns the cells other entities stand in
+func occupiedCells(room *spatial.RoomData, moverID string) map[spatial.Position]bool {
+	occupied := make(map[spatial.Position]bool, len(room.Entities))
+	for id, placement := range room.Entities {
+		if id != moverID {
+			occupied[placement.Position] = true
+		}
+	}
+	return occupied
+}
+
+// findPath finds the cheapest path from start to target that avoids occupied cells.
+// It returns the cells entered, in order, and the cost in feet.
+func findPath(
+	grid spatial.Grid,
+	start, target spatial.Position,
+	occupied, difficult map[spatial.Position]bool,
+) ([]spatial.Position, int, bool) {
+	costs := map[spatial.Position]int{start: 0}
+	previous := make(map[spatial.Position]spatial.Position)
+	queue := &pathQueue{}
+	heap.Push(queue, &pathNode{pos: start})
+
+	for queue.Len() > 0 {
+		node := heap.Pop(queue).(*pathNode)
+		if node.cost > costs[node.pos] {
+			continue // A cheaper route to this cell was already expanded
+		}
+		if node.pos.Equals(target) {
+			break
+		}
+
+		for _, next := range grid.GetNeighbors(node.pos) {
+			if occupied[next] {
+				continue
+			}
+			step := feetPerCell
+			if difficult[next] {
+				step *= 2
+			}
+			cost := node.cost + step
+			if known, seen := costs[next]; seen && known <= cost {
+				continue
+			}
+			costs[next] = cost
+			previous[next] = node.pos
+			queue.seq++
+			heap.Push(queue, &pathNode{pos: next, cost: cost, seq: queue.seq})
+		}
+	}
+
+	cost, reached := costs[target]
+	if !reached {
+		return nil, 0, false
+	}
+
+	var path []spatial.Position
+	for pos := target; !pos.Equals(start); pos = previous[pos] {
+		path = append(path, pos)
+	}
+	slices.Reverse(path)
+	return path, cost, true
+}
+
+type pathNode struct {
+	pos  spatial.Position
+	cost int
+	seq  int // Breaks cost ties in insertion order so paths are deterministic
+}
+
+// pathQueue is a min-heap of path nodes ordered by cost
+type pathQueue struct {
+	nodes []*pathNode
+	seq   int
+}
+
+func (q *pathQueue) Len() int { return len(q.nodes) }
+
+func (q *pathQueue) Less(i, j int) bool {
+	if q.nodes[i].cost != q.nodes[j].cost {
+		return q.nodes[i].cost < q.nodes[j].cost
+	}
+	return q.nodes[i].seq < q.nodes[j].seq
+}
+
+func (q *pathQueue) Swap(i, j int) { q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i] }
+
+func (q *pathQueue) Push(x any) { q.nodes = append(q.nodes, x.(*pathNode)) }
+
+func (q *pathQueue) Pop() any {
+	last := q.nodes[len(q.nodes)-1]
+	q.nodes = q.nodes[:len(q.nodes)-1]
+	return last
+}
@@ -0,0 +1,234 @@
+package encounter_test
+
+import (
+	"context"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/idgen"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	characterrepomock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/initiative"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+// saveMovementEncounter stores a 10x10 hex room with the hero's turn first
+func (s *OrchestratorTestSuite) saveMovementEncounter(
+	heroPos spatial.Position,
+	others map[string]spatial.Position,
+	difficult []spatial.Position,
+) {
+	entities := map[string]spatial.EntityPlacement{
+		"hero": {EntityID: "hero", EntityType: "character", Position: heroPos},
+		"orc":  {EntityID: "orc", EntityType: "monster", Position: spatial.Position{X: 9, Y: 9}},
+	}
+	for id, pos := range others {
+		entities[id] = spatial.EntityPlacement{EntityID: id, EntityType: "object", Position: pos, BlocksMovement: true}
+	}
+
+	_, err := s.repo.Save(context.Background(), &encounters.SaveInput{
+		EncounterID: "enc-1",
+		RoomData: &spatial.RoomData{
+			ID:       "room-1",
+			Type:     "dungeon",
+			Width:    10,
+			Height:   10,
+			GridType: spatial.GridTypeHex,
+			Entities: entities,
+		},
+		InitiativeData: &initiative.TrackerData{
+			Order: []initiative.EntityData{
+				{ID: "hero", Type: "character"},
+				{ID: "orc", Type: "monster"},
+			},
+			Current: 0,
+			Round:   1,
+		},
+		DifficultTerrain: difficult,
+	})
+	s.Require().NoError(err)
+	s.expectCharacterData(&toolkitchar.Data{ID: "hero"}, nil)
+}
+
+func (s *OrchestratorTestSuite) move(entityID string, x, y float64) (*encounter.MoveOutput, error) {
+	return s.orchestrator.Move(context.Background(), &encounter.MoveInput{
+		EncounterID: "enc-1",
+		EntityID:    entityID,
+		Target:      spatial.Position{X: x, Y: y},
+	})
+}
+
+func (s *OrchestratorTestSuite) requireMovementError(err error, reason string) {
+	s.Require().Error(err)
+	s.Equal(reason, errors.GetMeta(err)[encounter.MetaKeyMovementError])
+}
+
+func (s *OrchestratorTestSuite) TestMove() {
+	s.saveMovementEncounter(spatial.Position{X: 2, Y: 3}, nil, nil)
+
+	output, err := s.move("hero", 4, 3)
+
+	s.Require().NoError(err)
+	s.Equal([]spatial.Position{{X: 3, Y: 3}, {X: 4, Y: 3}}, output.Path)
+	s.Equal(10, output.MovementCost)
+	s.Equal(20, output.MovementRemaining)
+	s.Equal(spatial.Position{X: 4, Y: 3}, output.RoomData.Entities["hero"].Position)
+
+	// The new position and spent movement are stored
+	getOutput, err := s.repo.Get(context.Background(), &encounters.GetInput{EncounterID: "enc-1"})
+	s.Require().NoError(err)
+	s.Equal(spatial.Position{X: 4, Y: 3}, getOutput.Data.RoomData.Entities["hero"].Position)
+	s.Equal(&encounters.TurnData{EntityID: "hero", Round: 1, MovementUsed: 10}, getOutput.Data.Turn)
+}
+
+func (s *OrchestratorTestSuite) TestMove_DifficultTerrainCostsDouble() {
+	s.saveMovementEncounter(spatial.Position{X: 2, Y: 3}, nil, []spatial.Position{{X: 3, Y: 3}})
+
+	output, err := s.move("hero", 3, 3)
+
+	s.Require().NoError(err)
+	s.Equal(10, output.MovementCost)
+	s.Equal(20, output.MovementRemaining)
+}
+
+func (s *OrchestratorTestSuite) TestMove_PathsAroundOccupiedCells() {
+	s.saveMovementEncounter(spatial.Position{X: 2, Y: 3}, map[string]spatial.Position{
+		"pillar": {X: 3, Y: 3},
+	}, nil)
+
+	output, err := s.move("hero", 4, 3)
+
+	s.Require().NoError(err)
+	s.NotContains(output.Path, spatial.Position{X: 3, Y: 3})
+	s.Equal(spatial.Position{X: 4, Y: 3}, output.Path[len(output.Path)-1])
+	s.Equal(5*len(output.Path), output.MovementCost)
+}
+
+func (s *OrchestratorTestSuite) TestMove_SpendsBudgetAcrossMoves() {
+	s.saveMovementEncounter(spatial.Position{X: 0, Y: 3}, nil, nil)
+
+	_, err := s.move("hero", 4, 3)
+	s.Require().NoError(err)
+
+	output, err := s.move("hero", 6, 3)
+	s.Require().NoError(err)
+	s.Equal(0, output.MovementRemaining)
+
+	_, err = s.move("hero", 7, 3)
+	s.requireMovementError(err, encounter.MovementErrorInsufficientMovement)
+}
+
+func (s *OrchestratorTestSuite) TestMove_InsufficientMovement() {
+	s.saveMovementEncounter(spatial.Position{X: 0, Y: 3}, nil, nil)
+
+	output, err := s.move("hero", 7, 3)
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+	s.requireMovementError(err, encounter.MovementErrorInsufficientMovement)
+}
+
+func (s *OrchestratorTestSuite) TestMove_RecheckedAfterAConcurrentMove() {
+	s.saveMovementEncounter(spatial.Position{X: 0, Y: 3}, nil, nil)
+	// Another request spends most of the hero's movement after this one reads the encounter
+	s.raceReads(1, func() {
+		_, err := s.repo.Update(context.Background(), &encounters.UpdateInput{
+			EncounterID: "enc-1",
+			Turn:        &encounters.TurnData{EntityID: "hero", Round: 1, MovementUsed: 25},
+		})
+		s.Require().NoError(err)
+	})
+
+	output, err := s.move("hero", 2, 3)
+
+	s.Nil(output)
+	s.requireMovementError(err, encounter.MovementErrorInsufficientMovement)
+}
+
+func (s *OrchestratorTestSuite) TestMove_GivesUpWhenTheEncounterKeepsChanging() {
+	s.saveMovementEncounter(spatial.Position{X: 0, Y: 3}, nil, nil)
+	s.raceReads(10, func() {
+		_, err := s.repo.Update(context.Background(), &encounters.UpdateInput{EncounterID: "enc-1"})
+		s.Require().NoError(err)
+	})
+
+	output, err := s.move("hero", 2, 3)
+
+	s.Nil(output)
+	s.True(errors.IsAborted(err), "got %v", err)
+}
+
+func (s *OrchestratorTestSuite) TestMove_NotYourTurn() {
+	s.saveMovementEncounter(spatial.Position{X: 2, Y: 3}, nil, nil)
+
+	output, err := s.move("orc", 8, 9)
+
+	s.Nil(output)
+	s.requireMovementError(err, encounter.MovementErrorNotYourTurn)
+}
+
+func (s *OrchestratorTestSuite) TestMove_TargetOccupied() {
+	s.saveMovementEncounter(spatial.Position{X: 2, Y: 3}, map[string]spatial.Position{
+		"crate": {X: 3, Y: 3},
+	}, nil)
+
+	output, err := s.move("hero", 3, 3)
+
+	s.Nil(output)
+	s.requireMovementError(err, encounter.MovementErrorPositionOccupied)
+}
+
+func (s *OrchestratorTestSuite) TestMove_OutOfBounds() {
+	s.saveMovementEncounter(spatial.Position{X: 2, Y: 3}, nil, nil)
+
+	output, err := s.move("hero", 10, 3)
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+	s.requireMovementError(err, encounter.MovementErrorOutOfBounds)
+}
+
+func (s *OrchestratorTestSuite) TestMove_PathBlocked() {
+	// Wall the hero in on every side
+	grid := spatial.NewHexGrid(spatial.HexGridConfig{Width: 10, Height: 10, PointyTop: true})
+	walls := make(map[string]spatial.Position)
+	for i, pos := range grid.GetNeighbors(spatial.Position{X: 0, Y: 0}) {
+		walls[string(rune('a'+i))] = pos
+	}
+	s.saveMovementEncounter(spatial.Position{X: 0, Y: 0}, walls, nil)
+
+	output, err := s.move("hero", 5, 5)
+
+	s.Nil(output)
+	s.requireMovementError(err, encounter.MovementErrorPathBlocked)
+}
+
+func (s *OrchestratorTestSuite) TestMove_UsesCharacterSpeed() {
+	ctrl := gomock.NewController(s.T())
+	mockCharRepo := characterrepomock.NewMockRepository(ctrl)
+	orchestrator, err := encounter.NewOrchestrator(&encounter.Config{
+		IDGenerator:    idgen.NewSequential("test"),
+		Repository:     s.repo,
+		DiceService:    s.mockDiceService,
+		CharacterRepo:  mockCharRepo,
+		ExternalClient: s.mockExternalClient,
+	})
+	s.Require().NoError(err)
+	mockCharRepo.EXPECT().
+		Get(gomock.Any(), characterrepo.GetInput{ID: "hero"}).
+		Return(&characterrepo.GetOutput{CharacterData: &toolkitchar.Data{ID: "hero", Speed: 25}}, nil)
+	s.saveMovementEncounter(spatial.Position{X: 0, Y: 3}, nil, nil)
+
+	output, err := orchestrator.Move(context.Background(), &encounter.MoveInput{
+		EncounterID: "enc-1",
+		EntityID:    "hero",
+		Target:      spatial.Position{X: 6, Y: 3},
+	})
+
+	s.Nil(output)
+	s.requireMovementError(err, encounter.MovementErrorInsufficientMovement)
+}
@@ -6,17 +6,27 @@ package encounter
 import (
 	"context"
 	"log/slog"
+	"maps"
+	"time"
 
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
 	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/character"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/dice"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/clock"
 	"github.com/KirkDiggler/rpg-api/internal/pkg/idgen"
+	campaignrepo "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
 	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
-	"github.com/KirkDiggler/rpg-toolkit/core"
 	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/initiative"
 	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
 )
 
 // Service defines the interface for encounter operations
 type Service interface {
+	// CreateEncounter creates an encounter from a grid, spawn areas, monsters and obstacles
+	CreateEncounter(ctx context.Context, input *CreateEncounterInput) (*CreateEncounterOutput, error)
+
 	// DungeonStart creates a simple dungeon encounter for testing
 	DungeonStart(ctx context.Context, input *DungeonStartInput) (*DungeonStartOutput, error)
 
@@ -25,12 +35,53 @@ type Service interface {
 
 	// GetTurnOrder returns the current turn order
 	GetTurnOrder(ctx context.Context, input *GetTurnOrderInput) (*GetTurnOrderOutput, error)
+
+	// Move moves the active entity to a new position within its movement budget
+	Move(ctx context.Context, input *MoveInput) (*MoveOutput, error)
+
+	// Attack resolves an attack by the active entity against another entity
+	Attack(ctx context.Context, input *AttackInput) (*AttackOutput, error)
+
+	// UseAction spends an action, bonus action, reaction or object interaction on a spell
+	// or item the encounter does not resolve itself
+	UseAction(ctx context.Context, input *UseActionInput) (*UseActionOutput, error)
+
+	// RespondToReaction accepts or declines a reaction a combatant was offered; the paused
+	// action resumes once every reaction is answered
+	RespondToReaction(ctx context.Context, input *RespondToReactionInput) (*RespondToReactionOutput, error)
+
+	// ResumeAction resumes a paused action once its unanswered reactions are past their deadline
+	ResumeAction(ctx context.Context, input *ResumeActionInput) (*ResumeActionOutput, error)
+
+	// GetCombatState returns the full state of an encounter, for clients reconnecting mid-fight
+	GetCombatState(ctx context.Context, input *GetCombatStateInput) (*GetCombatStateOutput, error)
 }
 
 // Config holds the dependencies for the encounter orchestrator
 type Config struct {
 	IDGenerator idgen.Generator
 	Repository  encounters.Repository
+	DiceService dice.Service
+
+	// CharacterRepo supplies characters' initiative modifiers and speed
+	CharacterRepo characterrepo.Repository
+
+	// ExternalClient supplies monster stat blocks
+	ExternalClient external.Client
+
+	// CharacterService is optional; when set, characters can attack and be attacked
+	// using the weapons, armor class and hit points on their sheets
+	CharacterService character.Service
+
+	// CampaignRepo is optional; when set, the DM of an encounter's campaign can see monster stats
+	CampaignRepo campaignrepo.Repository
+
+	// Clock is optional; it sets reaction deadlines and defaults to the system clock
+	Clock clock.Clock
+
+	// ReactionTimeout is optional; it is how long combatants have to answer a reaction
+	// and defaults to 30 seconds
+	ReactionTimeout time.Duration
 }
 
 // Validate ensures all required dependencies are provided
@@ -45,12 +96,32 @@ func (c *Config) Validate() error {
 		vb.RequiredField("Repository")
 	}
 
+	if c.DiceService == nil {
+		vb.RequiredField("DiceService")
+	}
+
+	if c.CharacterRepo == nil {
+		vb.RequiredField("CharacterRepo")
+	}
+
+	if c.ExternalClient == nil {
+		vb.RequiredField("ExternalClient")
+	}
+
 	return vb.Build()
 }
 
 type orchestrator struct {
-	idGen idgen.Generator
-	repo  encounters.Repository
+	idGen          idgen.Generator
+	repo           encounters.Repository
+	diceService    dice.Service
+	charRepo       characterrepo.Repository
+	externalClient external.Client
+	charService    character.Service
+	campaignRepo   campaignrepo.Repository
+
+	clock           clock.Clock
+	reactionTimeout time.Duration
 }
 
 // simpleEntity implements core.Entity for demo purposes
@@ -73,125 +144,60 @@ func NewOrchestrator(cfg *Config) (Service, error) {
 		return nil, errors.Wrap(err, "invalid config")
 	}
 
+	clk := cfg.Clock
+	if clk == nil {
+		clk = clock.New()
+	}
+	reactionTimeout := cfg.ReactionTimeout
+	if reactionTimeout <= 0 {
+		reactionTimeout = defaultReactionTimeout
+	}
+
 	return &orchestrator{
-		idGen: cfg.IDGenerator,
-		repo:  cfg.Repository,
+		idGen:          cfg.IDGenerator,
+		repo:           cfg.Repository,
+		diceService:    cfg.DiceService,
+		charRepo:       cfg.CharacterRepo,
+		externalClient: cfg.ExternalClient,
+		charService:    cfg.CharacterService,
+		campaignRepo:   cfg.CampaignRepo,
+
+		clock:           clk,
+		reactionTimeout: reactionTimeout,
 	}, nil
 }
 
-// DungeonStart creates a simple dungeon encounter for testing
+// DungeonStart creates a simple dungeon encounter for testing: a 10x10 hex room with the
+// characters lined up from (2, 3) and a goblin across the room
 func (o *orchestrator) DungeonStart(ctx context.Context, input *DungeonStartInput) (*DungeonStartOutput, error) {
 	if input == nil {
 		return nil, errors.InvalidArgument("input is required")
 	}
 
-	// Generate unique encounter ID
-	encounterID := o.idGen.Generate()
-
-	slog.Info("Dungeon encounter creation requested",
-		"encounter_id", encounterID,
-		"character_count", len(input.CharacterIDs),
-	)
-
-	// Create a 10x10 hex grid room with pointy-top orientation (D&D 5e standard)
-	hexGrid := spatial.NewHexGrid(spatial.HexGridConfig{
-		Width:     10,   // width in hex units
-		Height:    10,   // height in hex units
-		PointyTop: true, // pointy-top orientation for D&D 5e
-	})
-
-	// Create room with the hex grid
-	room := spatial.NewBasicRoom(spatial.BasicRoomConfig{
-		ID:   o.idGen.Generate(),
-		Type: "dungeon",
-		Grid: hexGrid,
-	})
-
-	// Add character placements - spread them out in the starting area
-	for i, characterID := range input.CharacterIDs {
-		entityPos := spatial.Position{
-			X: float64(2 + i), // Spread characters horizontally
-			Y: 3.0,            // Starting row
-		}
-
-		// Create a simple entity for the character
-		charEntity := &simpleEntity{
-			id:         characterID,
-			entityType: "character",
-		}
-
-		if err := room.PlaceEntity(charEntity, entityPos); err != nil {
-			slog.Warn("Failed to place character entity",
-				"character_id", characterID,
-				"position", entityPos,
-				"error", err,
-			)
-		}
-	}
-
-	// Add a demo monster for the encounter
-	monsterPos := spatial.Position{
-		X: 7.0, // Opposite side from characters
-		Y: 6.0,
-	}
-	monsterID := o.idGen.Generate()
-	monsterEntity := &simpleEntity{
-		id:         monsterID,
-		entityType: "monster",
-	}
-	if err := room.PlaceEntity(monsterEntity, monsterPos); err != nil {
-		slog.Warn("Failed to place monster entity",
-			"monster_id", monsterID,
-			"position", monsterPos,
-			"error", err,
-		)
-	}
-
-	// Create initiative order - characters and monsters
-	entities := make(map[core.Entity]int)
-
-	// Add characters with default DEX modifier (for demo, using 0)
-	for _, characterID := range input.CharacterIDs {
-		charEntity := &simpleEntity{
-			id:         characterID,
-			entityType: "character",
-		}
-		entities[charEntity] = 0 // TODO(#206): Get actual DEX modifier from character service
-	}
-
-	// Add the monster
-	entities[monsterEntity] = 2 // TODO(#206): Make monster DEX modifier configurable
-
-	// Roll initiative and create tracker
-	order := initiative.RollForOrder(entities, nil) // nil uses default dice roller
-	tracker := initiative.New(order)
-
-	// Get current turn
-	current := tracker.Current()
-	currentTurn := ""
-	if current != nil {
-		currentTurn = current.GetID()
-	}
-
-	// Convert to response data
-	roomData := room.ToData()
-	trackerData := tracker.ToData()
-
-	// Save encounter to repository
-	_, err := o.repo.Save(ctx, &encounters.SaveInput{
-		EncounterID:    encounterID,
-		RoomData:       &roomData,
-		InitiativeData: &trackerData,
+	createOutput, err := o.CreateEncounter(ctx, &CreateEncounterInput{
+		RoomType:     "dungeon",
+		GridType:     spatial.GridTypeHex,
+		Width:        10,
+		Height:       10,
+		CharacterIDs: input.CharacterIDs,
+		CharacterSpawn: &SpawnArea{
+			Zone: &SpawnZone{From: spatial.Position{X: 2, Y: 3}, To: spatial.Position{X: 9, Y: 9}},
+		},
+		Monsters: []*MonsterGroup{{MonsterID: "goblin", Count: 1}},
+		MonsterSpawn: &SpawnArea{
+			Positions: []spatial.Position{{X: 7, Y: 6}},
+		},
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to save encounter")
+		return nil, err
 	}
 
 	return &DungeonStartOutput{
-		EncounterID:    encounterID,
-		RoomData:       &roomData,
-		InitiativeData: &trackerData,
-		CurrentTurn:    currentTurn,
+		EncounterID:    createOutput.EncounterID,
+		RoomData:       createOutput.RoomData,
+		InitiativeData: createOutput.InitiativeData,
+		Initiative:     createOutput.Initiative,
+		CurrentTurn:    createOutput.CurrentTurn,
 	}, nil
 }
 
@@ -201,6 +207,13 @@ func (o *orchestrator) NextTurn(ctx context.Context, input *NextTurnInput) (*Nex
 		return nil, errors.InvalidArgument("input is required")
 	}
 
+	return retryOnConflict(func() (*NextTurnOutput, error) {
+		return o.nextTurn(ctx, input)
+	})
+}
+
+// nextTurn is NextTurn against a fresh read of the encounter
+func (o *orchestrator) nextTurn(ctx context.Context, input *NextTurnInput) (*NextTurnOutput, error) {
 	// Get encounter from repository
 	getOutput, err := o.repo.Get(ctx, &encounters.GetInput{
 		EncounterID: input.EncounterID,
@@ -208,6 +221,9 @@ func (o *orchestrator) NextTurn(ctx context.Context, input *NextTurnInput) (*Nex
 	if err != nil {
 		return nil, err
 	}
+	if err := requireNoPendingAction(getOutput.Data); err != nil {
+		return nil, err
+	}
 
 	// Recreate tracker from stored data
 	tracker := initiative.LoadFromData(*getOutput.Data.InitiativeData)
@@ -219,14 +235,34 @@ func (o *orchestrator) NextTurn(ctx context.Context, input *NextTurnInput) (*Nex
 		currentTurn = next.GetID()
 	}
 
+	// The new combatant starts its turn with everything available and gets its reaction back
+	reactionsUsed := make([]string, 0, len(getOutput.Data.ReactionsUsed))
+	for _, entityID := range getOutput.Data.ReactionsUsed {
+		if entityID != currentTurn {
+			reactionsUsed = append(reactionsUsed, entityID)
+		}
+	}
+
+	// Effects that last until the start of the combatant's next turn, such as Shield, end
+	var combatants map[string]*encounters.CombatantData
+	if combatant, ok := getOutput.Data.Combatants[currentTurn]; ok && combatant.Shielded {
+		combatants = maps.Clone(getOutput.Data.Combatants)
+		unshielded := *combatant
+		unshielded.Shielded = false
+		combatants[currentTurn] = &unshielded
+	}
+
 	// Update stored initiative data
 	updatedData := tracker.ToData()
-	_, err = o.repo.Update(ctx, &encounters.UpdateInput{
+	err = o.update(ctx, getOutput.Data, &encounters.UpdateInput{
 		EncounterID:    input.EncounterID,
 		InitiativeData: &updatedData,
+		Turn:           &encounters.TurnData{EntityID: currentTurn, Round: tracker.Round()},
+		ReactionsUsed:  reactionsUsed,
+		Combatants:     combatants,
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to update encounter")
+		return nil, err
 	}
 
 	slog.Info("Advanced turn",
@@ -265,6 +301,7 @@ func (o *orchestrator) GetTurnOrder(ctx context.Context, input *GetTurnOrderInpu
 
 	return &GetTurnOrderOutput{
 		InitiativeData: getOutput.Data.InitiativeData,
+		Initiative:     getOutput.Data.Initiative,
 		CurrentTurn:    currentTurn,
 	}, nil
 }
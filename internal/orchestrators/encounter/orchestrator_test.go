@@ -5,36 +5,178 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
-
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	externalmock "github.com/KirkDiggler/rpg-api/internal/clients/external/mock"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	charactermock "github.com/KirkDiggler/rpg-api/internal/orchestrators/character/mock"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/dice"
+	dicemock "github.com/KirkDiggler/rpg-api/internal/orchestrators/dice/mock"
 	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
 	"github.com/KirkDiggler/rpg-api/internal/pkg/idgen"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	characterrepomock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
+	dicesession "github.com/KirkDiggler/rpg-api/internal/repositories/dice_session"
 	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
 )
 
 type OrchestratorTestSuite struct {
 	suite.Suite
 	orchestrator encounter.Service
 	idGen        idgen.Generator
+	repo         *encounters.InMemoryRepository
+
+	ctrl                 *gomock.Controller
+	mockDiceService      *dicemock.MockService
+	mockCharacterService *charactermock.MockService
+	mockCharRepo         *characterrepomock.MockRepository
+	mockExternalClient   *externalmock.MockClient
 }
 
 func (s *OrchestratorTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockDiceService = dicemock.NewMockService(s.ctrl)
+	s.mockCharacterService = charactermock.NewMockService(s.ctrl)
+	s.mockCharRepo = characterrepomock.NewMockRepository(s.ctrl)
+	s.mockExternalClient = externalmock.NewMockClient(s.ctrl)
 	s.idGen = idgen.NewSequential("test")
+	s.repo = encounters.NewInMemory()
 
 	cfg := &encounter.Config{
-		IDGenerator: s.idGen,
-		Repository:  encounters.NewInMemory(),
+		IDGenerator:      s.idGen,
+		Repository:       s.repo,
+		DiceService:      s.mockDiceService,
+		CharacterRepo:    s.mockCharRepo,
+		ExternalClient:   s.mockExternalClient,
+		CharacterService: s.mockCharacterService,
 	}
 
+	// Encounters with monsters use the SRD goblin unless a test asks for another monster
+	s.mockExternalClient.EXPECT().
+		GetMonsterData(gomock.Any(), "goblin").
+		Return(goblinStatBlock(), nil).
+		AnyTimes()
+
 	var err error
 	s.orchestrator, err = encounter.NewOrchestrator(cfg)
 	s.Require().NoError(err)
 }
 
+// racingRepository runs a concurrent write right after an encounter is read, the way
+// another server replica could, for the given number of reads
+type racingRepository struct {
+	*encounters.InMemoryRepository
+	races int
+	race  func()
+}
+
+func (r *racingRepository) Get(ctx context.Context, input *encounters.GetInput) (*encounters.GetOutput, error) {
+	output, err := r.InMemoryRepository.Get(ctx, input)
+	if r.races > 0 {
+		r.races--
+		r.race()
+	}
+	return output, err
+}
+
+// raceReads rebuilds the orchestrator so race runs after each of the next races reads
+func (s *OrchestratorTestSuite) raceReads(races int, race func()) {
+	var err error
+	s.orchestrator, err = encounter.NewOrchestrator(&encounter.Config{
+		IDGenerator:      s.idGen,
+		Repository:       &racingRepository{InMemoryRepository: s.repo, races: races, race: race},
+		DiceService:      s.mockDiceService,
+		CharacterRepo:    s.mockCharRepo,
+		ExternalClient:   s.mockExternalClient,
+		CharacterService: s.mockCharacterService,
+	})
+	s.Require().NoError(err)
+}
+
+// goblinStatBlock is the SRD goblin as the external client returns it
+func goblinStatBlock() *external.MonsterData {
+	return &external.MonsterData{
+		ID:         "goblin",
+		Name:       "Goblin",
+		ArmorClass: 15,
+		HitPoints:  7,
+		HitDice:    "2d6",
+		Speed:      &external.MonsterSpeedData{Walk: 30},
+		AbilityScores: map[string]int32{
+			"str": 8, "dex": 14, "con": 10, "int": 10, "wis": 8, "cha": 8,
+		},
+		Actions: []*external.MonsterActionData{
+			{
+				Name:        "Scimitar",
+				AttackBonus: 4,
+				Damage:      []*external.DamageData{{DamageDice: "1d6+2", DamageType: "slashing"}},
+				ReachFeet:   5,
+			},
+			{
+				Name:        "Shortbow",
+				AttackBonus: 4,
+				Damage:      []*external.DamageData{{DamageDice: "1d6+2", DamageType: "piercing"}},
+				Range:       &external.WeaponRangeData{Normal: 80, Long: 320},
+			},
+		},
+		ChallengeRating: 0.25,
+		XP:              50,
+	}
+}
+
+func (s *OrchestratorTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// expectCharacters stores average characters with the given IDs
+func (s *OrchestratorTestSuite) expectCharacters(characterIDs ...string) {
+	for _, characterID := range characterIDs {
+		s.expectCharacterData(&toolkitchar.Data{
+			ID:            characterID,
+			Level:         1,
+			AbilityScores: shared.AbilityScores{constants.DEX: 10},
+		}, nil)
+	}
+}
+
+func (s *OrchestratorTestSuite) expectCharacterData(charData *toolkitchar.Data, sheet *dnd5e.CharacterSheet) {
+	s.mockCharRepo.EXPECT().
+		Get(gomock.Any(), characterrepo.GetInput{ID: charData.ID}).
+		Return(&characterrepo.GetOutput{CharacterData: charData, Sheet: sheet}, nil).
+		AnyTimes()
+}
+
+// expectInitiativeRolls returns the given d20 rolls in order, then counts up from 1
+func (s *OrchestratorTestSuite) expectInitiativeRolls(rolls ...int32) {
+	next := int32(0)
+	s.mockDiceService.EXPECT().
+		RollDice(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *dice.RollDiceInput) (*dice.RollDiceOutput, error) {
+			s.Equal(encounter.ContextInitiative, input.Context)
+			s.Equal("1d20", input.Notation)
+			var roll int32
+			if len(rolls) > 0 {
+				roll, rolls = rolls[0], rolls[1:]
+			} else {
+				next++
+				roll = next
+			}
+			return &dice.RollDiceOutput{Roll: &dicesession.DiceRoll{Notation: input.Notation, Dice: []int32{roll}}}, nil
+		}).
+		AnyTimes()
+}
+
 func (s *OrchestratorTestSuite) TestDungeonStart_WithInitiative() {
 	// Arrange
 	input := &encounter.DungeonStartInput{
 		CharacterIDs: []string{"fighter-123", "wizard-456", "rogue-789"},
 	}
+	s.expectCharacters(input.CharacterIDs...)
+	s.expectInitiativeRolls()
 
 	// Act
 	output, err := s.orchestrator.DungeonStart(context.Background(), input)
@@ -71,6 +213,8 @@ func (s *OrchestratorTestSuite) TestNextTurn() {
 	startInput := &encounter.DungeonStartInput{
 		CharacterIDs: []string{"fighter-123", "wizard-456"},
 	}
+	s.expectCharacters(startInput.CharacterIDs...)
+	s.expectInitiativeRolls()
 
 	startOutput, err := s.orchestrator.DungeonStart(context.Background(), startInput)
 	s.Require().NoError(err)
@@ -106,6 +250,8 @@ func (s *OrchestratorTestSuite) TestGetTurnOrder() {
 	startInput := &encounter.DungeonStartInput{
 		CharacterIDs: []string{"fighter-123"},
 	}
+	s.expectCharacters(startInput.CharacterIDs...)
+	s.expectInitiativeRolls()
 
 	startOutput, err := s.orchestrator.DungeonStart(context.Background(), startInput)
 	s.Require().NoError(err)
@@ -0,0 +1,864 @@
+package encounter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/conditions"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/initiative"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+// Reactions a combatant can be offered
+const (
+	ReactionOpportunityAttack = "opportunity_attack" // An enemy is leaving its reach
+	ReactionShield            = "shield"             // It was hit and can cast Shield
+	ReactionUncannyDodge      = "uncanny_dodge"      // It was hit and is a rogue of level 5 or higher
+)
+
+const (
+	// SpellShield is the spell ID a character needs prepared or known to be offered Shield
+	SpellShield = "SPELL_SHIELD"
+
+	shieldACBonus     = 5
+	uncannyDodgeLevel = 5
+
+	// defaultReactionTimeout is how long combatants have to answer a reaction
+	defaultReactionTimeout = 30 * time.Second
+
+	// maxReachFeet is the longest melee reach in the SRD, the tarrasque's tail. Enemies
+	// farther than this from every cell of a move cannot make an opportunity attack.
+	maxReachFeet = 20
+
+	// maxActionAttempts bounds how often an action starts over when the encounter is
+	// written by someone else while it is resolved
+	maxActionAttempts = 3
+
+	// metaKeyEncounterChanged marks the error from losing a write to the encounter
+	metaKeyEncounterChanged = "encounter_changed"
+)
+
+// actionState is an encounter while an action is resolved. Nothing is stored until the
+// action finishes or pauses for reactions.
+type actionState struct {
+	data          *encounters.EncounterData
+	tracker       *initiative.Tracker
+	grid          spatial.Grid
+	combatants    map[string]*encounters.CombatantData
+	reactionsUsed []string
+	turn          *encounters.TurnData          // What the acting entity has spent this turn
+	pending       *encounters.PendingActionData // Set when the action pauses
+
+	// wait is set when characters' reactions pause the action for their players to answer;
+	// otherwise, and always for monsters, reactions are resolved by autoResponse
+	wait bool
+	// reactions are the reactions resolved without pausing, in order
+	reactions []*ReactionResult
+	// hurt are the characters whose hit points changed, written back to them on save
+	hurt []string
+	// slotsSpent are the spell slots characters cast reactions with, spent on save
+	slotsSpent []spentSlot
+}
+
+// spentSlot is a spell slot a character cast a reaction with
+type spentSlot struct {
+	characterID string
+	level       int
+}
+
+// loadActionState prepares an encounter for resolving an action by the given entity
+func loadActionState(data *encounters.EncounterData, entityID string) (*actionState, error) {
+	if data.RoomData == nil || data.InitiativeData == nil {
+		return nil, errors.FailedPreconditionf("encounter %s has no room or initiative", data.ID)
+	}
+
+	grid, err := gridForRoom(data.RoomData)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := initiative.LoadFromData(*data.InitiativeData)
+	combatants := make(map[string]*encounters.CombatantData, len(data.Combatants)+2)
+	maps.Copy(combatants, data.Combatants)
+
+	return &actionState{
+		data:          data,
+		tracker:       tracker,
+		grid:          grid,
+		combatants:    combatants,
+		reactionsUsed: slices.Clone(data.ReactionsUsed),
+		turn:          turnFor(data, entityID, tracker.Round()),
+	}, nil
+}
+
+// save stores everything an action changed, including a pending action when it paused
+func (o *orchestrator) save(ctx context.Context, st *actionState) error {
+	trackerData := st.tracker.ToData()
+	st.data.InitiativeData = &trackerData
+	st.data.Turn = st.turn
+	st.data.ReactionsUsed = st.reactionsUsed
+
+	err := o.update(ctx, st.data, &encounters.UpdateInput{
+		EncounterID:        st.data.ID,
+		RoomData:           st.data.RoomData,
+		InitiativeData:     &trackerData,
+		Turn:               st.turn,
+		Combatants:         st.combatants,
+		ReactionsUsed:      append([]string{}, st.reactionsUsed...),
+		PendingAction:      st.pending,
+		ClearPendingAction: st.pending == nil,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Characters are only written once the encounter is, so an action that starts over
+	// never spends a slot or deals damage twice
+	for _, characterID := range st.hurt {
+		if err := o.saveHitPoints(ctx, st.combatants[characterID]); err != nil {
+			return err
+		}
+	}
+	st.hurt = nil
+	for _, slot := range st.slotsSpent {
+		if err := o.spendSpellSlot(ctx, slot); err != nil {
+			return err
+		}
+	}
+	st.slotsSpent = nil
+	return nil
+}
+
+// update writes an encounter at the revision it was read. Losing to a concurrent write is
+// marked so retryOnConflict starts the action over.
+func (o *orchestrator) update(ctx context.Context, data *encounters.EncounterData, input *encounters.UpdateInput) error {
+	input.ExpectedRevision = &data.Revision
+	_, err := o.repo.Update(ctx, input)
+	if errors.IsAborted(err) {
+		return errors.Wrapf(err, "encounter %s changed while the action was resolved", data.ID).
+			WithMeta(metaKeyEncounterChanged, true)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to update encounter")
+	}
+	return nil
+}
+
+// retryOnConflict runs an action until its write is not beaten by a concurrent write to
+// the encounter. Each attempt reads the encounter afresh, so the action is checked again
+// against what the other write left; the last attempt's conflict is returned as Aborted.
+func retryOnConflict[T any](run func() (T, error)) (T, error) {
+	for range maxActionAttempts - 1 {
+		output, err := run()
+		if !encounterChanged(err) {
+			return output, err
+		}
+	}
+	return run()
+}
+
+// encounterChanged reports whether an action failed because the encounter was written
+// while it was resolved. Conflicts writing a character after the encounter was saved are
+// not retried, since the action already happened.
+func encounterChanged(err error) bool {
+	changed, _ := errors.GetMeta(err)[metaKeyEncounterChanged].(bool)
+	return changed
+}
+
+// currentTurn returns the ID of whose turn it is, or "" when nobody's is
+func (st *actionState) currentTurn() string {
+	if current := st.tracker.Current(); current != nil {
+		return current.GetID()
+	}
+	return ""
+}
+
+// requireNoPendingAction fails while an action waits on reactions
+func requireNoPendingAction(data *encounters.EncounterData) error {
+	if data.PendingAction == nil {
+		return nil
+	}
+	return errors.FailedPreconditionf("encounter %s is waiting on reactions to %s's %s",
+		data.ID, data.PendingAction.EntityID, data.PendingAction.Type)
+}
+
+// autoResponse answers a reaction nobody will be asked about. Opportunity attacks and
+// Uncanny Dodge cost nothing but the reaction, so they are always taken; Shield spends a
+// spell slot, which is left to the player.
+func autoResponse(reaction string) string {
+	if reaction == ReactionShield {
+		return encounters.ReactionDeclined
+	}
+	return encounters.ReactionAccepted
+}
+
+// RespondToReaction records whether a combatant takes a reaction it was offered. Once
+// every reaction is answered the paused action resumes.
+func (o *orchestrator) RespondToReaction(ctx context.Context, input *RespondToReactionInput) (*RespondToReactionOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("encounter_id", input.EncounterID, vb)
+	errors.ValidateRequired("entity_id", input.EntityID, vb)
+	if input.Reaction != "" {
+		errors.ValidateEnum("reaction", input.Reaction,
+			[]string{ReactionOpportunityAttack, ReactionShield, ReactionUncannyDodge}, vb)
+	}
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+
+	return retryOnConflict(func() (*RespondToReactionOutput, error) {
+		return o.respondToReaction(ctx, input)
+	})
+}
+
+// respondToReaction is RespondToReaction against a fresh read of the encounter
+func (o *orchestrator) respondToReaction(ctx context.Context, input *RespondToReactionInput) (*RespondToReactionOutput, error) {
+	getOutput, err := o.repo.Get(ctx, &encounters.GetInput{EncounterID: input.EncounterID})
+	if err != nil {
+		return nil, err
+	}
+	data := getOutput.Data
+	if data.PendingAction == nil {
+		return nil, errors.FailedPreconditionf("encounter %s has no action waiting on reactions", input.EncounterID)
+	}
+
+	// Answer a copy so nothing changes unless it is stored
+	pending := *data.PendingAction
+	pending.Reactions = make([]*encounters.PendingReactionData, 0, len(data.PendingAction.Reactions))
+	var offers []*encounters.PendingReactionData
+	for _, reaction := range data.PendingAction.Reactions {
+		reaction := *reaction
+		pending.Reactions = append(pending.Reactions, &reaction)
+		if reaction.EntityID == input.EntityID && reaction.Response == "" &&
+			(input.Reaction == "" || reaction.Reaction == input.Reaction) {
+			offers = append(offers, pending.Reactions[len(pending.Reactions)-1])
+		}
+	}
+	if len(offers) == 0 {
+		return nil, errors.NotFoundf("%s has no pending reaction", input.EntityID)
+	}
+	if input.Accept && len(offers) > 1 {
+		return nil, errors.InvalidArgumentf("%s was offered %d reactions; choose one", input.EntityID, len(offers))
+	}
+
+	now := o.clock.Now()
+	for _, offer := range offers {
+		if now.After(offer.Deadline) {
+			return nil, errors.FailedPreconditionf("the chance for %s to react has passed", input.EntityID)
+		}
+		offer.Response = encounters.ReactionDeclined
+	}
+	if input.Accept {
+		// A combatant has one reaction, so taking one declines anything else it was offered
+		offers[0].Response = encounters.ReactionAccepted
+		for _, reaction := range pending.Reactions {
+			if reaction.EntityID == input.EntityID && reaction.Response == "" {
+				reaction.Response = encounters.ReactionDeclined
+			}
+		}
+	}
+
+	if !readyToResume(&pending, now) {
+		err = o.update(ctx, data, &encounters.UpdateInput{
+			EncounterID:   input.EncounterID,
+			PendingAction: &pending,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &RespondToReactionOutput{Pending: &pending}, nil
+	}
+
+	data.PendingAction = &pending
+	resolution, err := o.resume(ctx, data, now)
+	if err != nil {
+		return nil, err
+	}
+	return &RespondToReactionOutput{Resolution: resolution}, nil
+}
+
+// ResumeAction resumes a paused action once every reaction has been answered or has
+// passed its deadline. Unanswered reactions count as declined.
+func (o *orchestrator) ResumeAction(ctx context.Context, input *ResumeActionInput) (*ResumeActionOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	errors.ValidateRequired("encounter_id", input.EncounterID, vb)
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+
+	return retryOnConflict(func() (*ResumeActionOutput, error) {
+		return o.resumeAction(ctx, input)
+	})
+}
+
+// resumeAction is ResumeAction against a fresh read of the encounter
+func (o *orchestrator) resumeAction(ctx context.Context, input *ResumeActionInput) (*ResumeActionOutput, error) {
+	getOutput, err := o.repo.Get(ctx, &encounters.GetInput{EncounterID: input.EncounterID})
+	if err != nil {
+		return nil, err
+	}
+	data := getOutput.Data
+	if data.PendingAction == nil {
+		return nil, errors.FailedPreconditionf("encounter %s has no action waiting on reactions", input.EncounterID)
+	}
+
+	now := o.clock.Now()
+	if !readyToResume(data.PendingAction, now) {
+		return nil, errors.FailedPreconditionf("encounter %s is still waiting on reactions", input.EncounterID)
+	}
+
+	resolution, err := o.resume(ctx, data, now)
+	if err != nil {
+		return nil, err
+	}
+	return &ResumeActionOutput{Resolution: resolution}, nil
+}
+
+// readyToResume reports whether every reaction is answered or past its deadline
+func readyToResume(pending *encounters.PendingActionData, now time.Time) bool {
+	for _, reaction := range pending.Reactions {
+		if reaction.Response == "" && !now.After(reaction.Deadline) {
+			return false
+		}
+	}
+	return true
+}
+
+// resume takes the accepted reactions and finishes the paused action
+func (o *orchestrator) resume(ctx context.Context, data *encounters.EncounterData, now time.Time) (*ActionResolution, error) {
+	pending := data.PendingAction
+	for _, reaction := range pending.Reactions {
+		if reaction.Response == "" && now.After(reaction.Deadline) {
+			reaction.Response = encounters.ReactionExpired
+		}
+	}
+
+	st, err := loadActionState(data, pending.EntityID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only actions that wait on characters' reactions are ever paused
+	st.wait = true
+	resolution := &ActionResolution{}
+	switch pending.Type {
+	case encounters.PendingActionMove:
+		resolution.Move, err = o.resumeMove(ctx, st, pending, resolution)
+	case encounters.PendingActionAttack:
+		resolution.Attack, err = o.resumeAttack(ctx, st, pending, resolution)
+	default:
+		err = errors.Internalf("unknown pending action %q", pending.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resolution.Reactions = append(resolution.Reactions, st.reactions...)
+
+	if err := o.save(ctx, st); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "action resumed after reactions",
+		"encounter_id", data.ID,
+		"entity_id", pending.EntityID,
+		"action", pending.Type,
+		"paused_again", st.pending != nil)
+
+	if resolution.Move != nil {
+		resolution.Move.RoomData = st.data.RoomData
+		resolution.Move.InitiativeData = st.data.InitiativeData
+		resolution.Move.CurrentTurn = st.currentTurn()
+	}
+	if resolution.Attack != nil {
+		resolution.Attack.RoomData = st.data.RoomData
+		resolution.Attack.InitiativeData = st.data.InitiativeData
+		resolution.Attack.CurrentTurn = st.currentTurn()
+	}
+	return resolution, nil
+}
+
+// resumeMove makes the accepted opportunity attacks, then continues the move if the mover
+// is still standing
+func (o *orchestrator) resumeMove(
+	ctx context.Context,
+	st *actionState,
+	pending *encounters.PendingActionData,
+	resolution *ActionResolution,
+) (*MoveOutput, error) {
+	moverID := pending.EntityID
+	for _, reaction := range pending.Reactions {
+		reactionResult := &ReactionResult{
+			EntityID: reaction.EntityID,
+			Reaction: reaction.Reaction,
+			Response: reaction.Response,
+		}
+		resolution.Reactions = append(resolution.Reactions, reactionResult)
+		if reaction.Response != encounters.ReactionAccepted || isDown(st, moverID) {
+			continue
+		}
+
+		var err error
+		reactionResult.Attack, err = o.opportunityAttack(ctx, st, reaction.EntityID, moverID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	output := &MoveOutput{Initiative: st.data.Initiative}
+	if !isDown(st, moverID) {
+		var err error
+		output.Path, output.MovementCost, err = o.walk(ctx, st, moverID, pending.Move.Path, pending.Move.Offered)
+		if err != nil {
+			return nil, err
+		}
+	}
+	output.Pending = st.pending
+
+	placement, ok := st.data.RoomData.Entities[moverID]
+	if !ok {
+		return output, nil
+	}
+	speed, err := o.speedOf(ctx, st.data, moverID, placement.EntityType)
+	if err != nil {
+		return nil, err
+	}
+	output.Speed = speed
+	output.MovementUsed = st.turn.MovementUsed
+	output.MovementRemaining = max(0, speed-st.turn.MovementUsed)
+	return output, nil
+}
+
+// resumeAttack finishes an attack that hit, after the target's reaction
+func (o *orchestrator) resumeAttack(
+	ctx context.Context,
+	st *actionState,
+	pending *encounters.PendingActionData,
+	resolution *ActionResolution,
+) (*AttackOutput, error) {
+	stored := pending.Attack
+	atk, err := o.prepareAttack(ctx, st, pending.EntityID, stored.TargetID, stored.WeaponID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := atk.result
+	result.Rolls = stored.Rolls
+	result.Roll = stored.Roll
+	result.Total = stored.Roll + result.AttackBonus
+	result.Hit = true
+	result.Critical = stored.Critical
+	result.AdvantageSources = stored.AdvantageSources
+	result.DisadvantageSources = stored.DisadvantageSources
+	result.Advantage = len(stored.AdvantageSources) > 0
+	result.Disadvantage = len(stored.DisadvantageSources) > 0
+	result.Steps = stored.Steps
+
+	taken := ""
+	for _, reaction := range pending.Reactions {
+		resolution.Reactions = append(resolution.Reactions, &ReactionResult{
+			EntityID: reaction.EntityID,
+			Reaction: reaction.Reaction,
+			Response: reaction.Response,
+		})
+		if reaction.Response == encounters.ReactionAccepted {
+			taken = reaction.Reaction
+			st.reactionsUsed = append(st.reactionsUsed, reaction.EntityID)
+		}
+	}
+
+	if err := o.finishAttack(ctx, st, atk, taken); err != nil {
+		return nil, err
+	}
+	return &AttackOutput{Result: result, Initiative: st.data.Initiative}, nil
+}
+
+// walk moves an entity along a path one cell at a time, charging movement as it goes.
+// Leaving an enemy's reach, unless the mover disengaged, gives that enemy an opportunity
+// attack: monsters, and characters when nobody waits on them, make it at once, and a
+// mover it drops stops where it stands. Otherwise the move stops before the step and
+// waits for the characters to answer. Each enemy gets at most one chance per move.
+func (o *orchestrator) walk(
+	ctx context.Context,
+	st *actionState,
+	moverID string,
+	path []spatial.Position,
+	offered []string,
+) ([]spatial.Position, int, error) {
+	placement := st.data.RoomData.Entities[moverID]
+	difficult := make(map[spatial.Position]bool, len(st.data.DifficultTerrain))
+	for _, pos := range st.data.DifficultTerrain {
+		difficult[pos] = true
+	}
+
+	var threats []*threat
+	if !st.turn.Disengaged {
+		var err error
+		threats, err = o.threats(ctx, st, placement, path, offered)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var entered []spatial.Position
+	cost := 0
+	reacted := make(map[string]bool, len(threats))
+	for i, next := range path {
+		var offers []*encounters.PendingReactionData
+		for _, t := range threats {
+			leaving := st.feetBetween(placement.Position, t.position) <= t.reachFeet && st.feetBetween(next, t.position) > t.reachFeet
+			if !leaving || reacted[t.entityID] {
+				continue
+			}
+			reacted[t.entityID] = true
+
+			if st.wait && t.entityType == entityTypeCharacter {
+				offers = append(offers, &encounters.PendingReactionData{
+					EntityID: t.entityID,
+					Reaction: ReactionOpportunityAttack,
+					Deadline: o.clock.Now().Add(o.reactionTimeout),
+				})
+				continue
+			}
+
+			reaction := &ReactionResult{
+				EntityID: t.entityID,
+				Reaction: ReactionOpportunityAttack,
+				Response: autoResponse(ReactionOpportunityAttack),
+			}
+			var err error
+			reaction.Attack, err = o.opportunityAttack(ctx, st, t.entityID, moverID)
+			if err != nil {
+				return nil, 0, err
+			}
+			st.reactions = append(st.reactions, reaction)
+			if isDown(st, moverID) {
+				break
+			}
+		}
+		if isDown(st, moverID) {
+			break
+		}
+		if len(offers) > 0 {
+			moveOffered := slices.Clone(offered)
+			for _, offer := range offers {
+				moveOffered = append(moveOffered, offer.EntityID)
+			}
+			st.pending = &encounters.PendingActionData{
+				Type:      encounters.PendingActionMove,
+				EntityID:  moverID,
+				Move:      &encounters.PendingMoveData{Path: slices.Clone(path[i:]), Offered: moveOffered},
+				Reactions: offers,
+			}
+			break
+		}
+
+		step := feetPerCell
+		if difficult[next] {
+			step *= 2
+		}
+		placement.Position = next
+		entered = append(entered, next)
+		cost += step
+	}
+
+	// A defeated monster has already been taken out of the room
+	if _, ok := st.data.RoomData.Entities[moverID]; ok {
+		st.data.RoomData.Entities[moverID] = placement
+	}
+	st.turn.MovementUsed += cost
+	return entered, cost, nil
+}
+
+// opportunityAttack makes an accepted opportunity attack with the attacker's reaction. It
+// returns nil when the attacker can no longer make a melee attack.
+func (o *orchestrator) opportunityAttack(ctx context.Context, st *actionState, attackerID, moverID string) (*AttackResult, error) {
+	option, err := o.opportunityAttackOption(ctx, st, attackerID)
+	if err != nil || option == nil {
+		return nil, err
+	}
+
+	// A move cannot pause twice over, so the mover's own reactions are resolved at once
+	wait := st.wait
+	st.wait = false
+	result, err := o.makeAttack(ctx, st, attackerID, moverID, option.id)
+	st.wait = wait
+	if err != nil {
+		return nil, err
+	}
+	result.Steps = append([]string{"Opportunity attack"}, result.Steps...)
+	st.reactionsUsed = append(st.reactionsUsed, attackerID)
+	return result, nil
+}
+
+// threat is an enemy that could make an opportunity attack against a mover
+type threat struct {
+	entityID   string
+	entityType string
+	position   spatial.Position
+	reachFeet  int
+}
+
+// threats returns the enemies near a move that can still take a reaction and make a
+// melee attack
+func (o *orchestrator) threats(
+	ctx context.Context,
+	st *actionState,
+	mover spatial.EntityPlacement,
+	path []spatial.Position,
+	offered []string,
+) ([]*threat, error) {
+	var threats []*threat
+	for _, id := range slices.Sorted(maps.Keys(st.data.RoomData.Entities)) {
+		placement := st.data.RoomData.Entities[id]
+		if !hostile(mover, placement) || slices.Contains(offered, id) || slices.Contains(st.reactionsUsed, id) {
+			continue
+		}
+		near := st.feetBetween(mover.Position, placement.Position) <= maxReachFeet
+		for _, pos := range path {
+			near = near || st.feetBetween(pos, placement.Position) <= maxReachFeet
+		}
+		if !near {
+			continue
+		}
+
+		option, err := o.opportunityAttackOption(ctx, st, id)
+		if err != nil {
+			return nil, err
+		}
+		if option != nil {
+			threats = append(threats, &threat{
+				entityID:   id,
+				entityType: placement.EntityType,
+				position:   placement.Position,
+				reachFeet:  option.reachFeet,
+			})
+		}
+	}
+	return threats, nil
+}
+
+// opportunityAttackOption returns the melee attack with the longest reach an entity can
+// make now, or nil when it cannot make one
+func (o *orchestrator) opportunityAttackOption(ctx context.Context, st *actionState, entityID string) (*attackOption, error) {
+	placement, ok := st.data.RoomData.Entities[entityID]
+	if !ok {
+		return nil, nil
+	}
+	combatant, err := o.combatant(ctx, st.combatants, placement)
+	if err != nil {
+		if errors.IsFailedPrecondition(err) || errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if combatant.HitPoints <= 0 || incapacitatedBy(combatant) != "" {
+		return nil, nil
+	}
+
+	options, err := o.attackOptions(ctx, combatant)
+	if err != nil {
+		if errors.IsFailedPrecondition(err) || errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var best *attackOption
+	for _, option := range options {
+		if option.reachFeet > 0 && (best == nil || option.reachFeet > best.reachFeet) {
+			best = option
+		}
+	}
+	return best, nil
+}
+
+// hitReactions returns the reactions the target of a hit may take. Shield is offered only
+// when +5 AC would turn the hit into a miss and the target has a spell slot to cast it.
+func (o *orchestrator) hitReactions(ctx context.Context, st *actionState, atk *attackRoll) ([]*encounters.PendingReactionData, error) {
+	target := atk.target
+	if target.EntityType != entityTypeCharacter || target.HitPoints <= 0 || incapacitatedBy(target) != "" ||
+		slices.Contains(st.reactionsUsed, target.EntityID) {
+		return nil, nil
+	}
+
+	charOutput, err := o.charRepo.Get(ctx, characterrepo.GetInput{ID: target.EntityID})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get character %s", target.EntityID)
+	}
+	charData := charOutput.CharacterData
+
+	deadline := o.clock.Now().Add(o.reactionTimeout)
+	var offers []*encounters.PendingReactionData
+	result := atk.result
+	if result.Roll != 20 && result.Total < result.TargetAC+shieldACBonus && !target.Shielded &&
+		knowsSpell(charOutput, SpellShield) && lowestSpellSlot(charData.SpellSlots) > 0 {
+		offers = append(offers, &encounters.PendingReactionData{
+			EntityID: target.EntityID,
+			Reaction: ReactionShield,
+			Deadline: deadline,
+		})
+	}
+	// Uncanny Dodge needs the rogue to see the attacker
+	if charData.ClassID == constants.ClassRogue && charData.Level >= uncannyDodgeLevel &&
+		!slices.Contains(atk.attacker.Conditions, string(conditions.Invisible)) {
+		offers = append(offers, &encounters.PendingReactionData{
+			EntityID: target.EntityID,
+			Reaction: ReactionUncannyDodge,
+			Deadline: deadline,
+		})
+	}
+	return offers, nil
+}
+
+// castShield casts Shield with the target's lowest spell slot, raising its armor class until
+// the start of its next turn. The slot is spent when the action is saved. Without a slot left
+// the spell fails and nothing changes.
+func (o *orchestrator) castShield(
+	ctx context.Context,
+	st *actionState,
+	result *AttackResult,
+	target *encounters.CombatantData,
+) error {
+	charOutput, err := o.charRepo.Get(ctx, characterrepo.GetInput{ID: target.EntityID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get character %s", target.EntityID)
+	}
+	level := lowestSpellSlot(charOutput.CharacterData.SpellSlots)
+	if level == 0 {
+		result.Steps = append(result.Steps, fmt.Sprintf("%s has no spell slot left for Shield", nameOf(target)))
+		return nil
+	}
+	st.slotsSpent = append(st.slotsSpent, spentSlot{characterID: target.EntityID, level: level})
+
+	target.Shielded = true
+	result.TargetAC += shieldACBonus
+	if result.Roll != 20 && result.Total < result.TargetAC {
+		result.Hit = false
+		result.Critical = false
+		result.Steps = append(result.Steps, fmt.Sprintf("%s casts Shield with a level %d slot: AC %d, the attack misses",
+			nameOf(target), level, result.TargetAC))
+		return nil
+	}
+	result.Steps = append(result.Steps, fmt.Sprintf("%s casts Shield with a level %d slot: AC %d, the attack still hits",
+		nameOf(target), level, result.TargetAC))
+	return nil
+}
+
+// spendSpellSlot marks a spell slot used on the character. The write is made at the revision
+// read and starts over if the character was saved in between. A slot used up in the meantime
+// is taken from the lowest level left instead.
+func (o *orchestrator) spendSpellSlot(ctx context.Context, spent spentSlot) error {
+	for range maxCharacterWriteAttempts {
+		getOutput, err := o.charRepo.Get(ctx, characterrepo.GetInput{ID: spent.characterID})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get character %s", spent.characterID)
+		}
+		charData := getOutput.CharacterData
+
+		level := spent.level
+		if slot := charData.SpellSlots[level]; slot.Used >= slot.Max {
+			level = lowestSpellSlot(charData.SpellSlots)
+		}
+		if level == 0 {
+			slog.WarnContext(ctx, "character had no spell slot left to spend on a reaction",
+				"character_id", spent.characterID,
+				"level", spent.level)
+			return nil
+		}
+
+		slots := maps.Clone(charData.SpellSlots)
+		slot := slots[level]
+		slot.Used++
+		slots[level] = slot
+		charData.SpellSlots = slots
+		_, err = o.charRepo.Update(ctx, characterrepo.UpdateInput{
+			CharacterData:    charData,
+			EventType:        dnd5e.CharacterEventSpellSlotUsed,
+			ExpectedRevision: &getOutput.Revision,
+		})
+		if errors.IsAborted(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to spend a spell slot for %s", spent.characterID)
+		}
+		return nil
+	}
+	return errors.Abortedf("character %s kept changing, try again", spent.characterID)
+}
+
+// lowestSpellSlot returns the lowest level with a spell slot left, or 0 when none is
+func lowestSpellSlot(slots map[int]toolkitchar.SlotInfo) int {
+	lowest := 0
+	for level, slot := range slots {
+		if level > 0 && slot.Used < slot.Max && (lowest == 0 || level < lowest) {
+			lowest = level
+		}
+	}
+	return lowest
+}
+
+// armorClassOf returns a combatant's armor class, with Shield's bonus while it lasts
+func armorClassOf(combatant *encounters.CombatantData) int {
+	if combatant.Shielded {
+		return combatant.ArmorClass + shieldACBonus
+	}
+	return combatant.ArmorClass
+}
+
+// knowsSpell reports whether a character has a spell prepared or learned it at creation
+func knowsSpell(charOutput *characterrepo.GetOutput, spellID string) bool {
+	if sheet := charOutput.Sheet; sheet != nil &&
+		(slices.Contains(sheet.PreparedSpells, spellID) || slices.Contains(sheet.AlwaysPreparedSpells, spellID)) {
+		return true
+	}
+	for _, choice := range charOutput.CharacterData.Choices {
+		if slices.Contains(choice.SpellSelection, spellID) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostile reports whether two placements are on opposite sides of the fight
+func hostile(a, b spatial.EntityPlacement) bool {
+	isCombatant := func(p spatial.EntityPlacement) bool {
+		return p.EntityType == entityTypeCharacter || p.EntityType == entityTypeMonster
+	}
+	return a.EntityID != b.EntityID && a.EntityType != b.EntityType && isCombatant(a) && isCombatant(b)
+}
+
+// isDown reports whether an entity has been removed from the room or dropped to 0 hit points
+func isDown(st *actionState, entityID string) bool {
+	if _, ok := st.data.RoomData.Entities[entityID]; !ok {
+		return true
+	}
+	combatant, ok := st.combatants[entityID]
+	return ok && combatant.HitPoints <= 0
+}
+
+// feetBetween returns the distance between two cells in feet
+func (st *actionState) feetBetween(a, b spatial.Position) int {
+	return int(st.grid.Distance(a, b)) * feetPerCell
+}
@@ -0,0 +1,501 @@
+package encounter_test
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
+	mockclock "github.com/KirkDiggler/rpg-api/internal/pkg/clock/mock"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/initiative"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+// saveOrcTurnEncounter stores the hero next to an orc on the orc's turn, with the hero's
+// character data and sheet deciding which reactions it is offered
+func (s *OrchestratorTestSuite) saveOrcTurnEncounter(hero *toolkitchar.Data, sheet *dnd5e.CharacterSheet) {
+	_, err := s.repo.Save(context.Background(), &encounters.SaveInput{
+		EncounterID: "enc-1",
+		RoomData: &spatial.RoomData{
+			ID:       "room-1",
+			Type:     "dungeon",
+			Width:    30,
+			Height:   10,
+			GridType: spatial.GridTypeSquare,
+			Entities: map[string]spatial.EntityPlacement{
+				"hero": {EntityID: "hero", EntityType: "character", Position: spatial.Position{X: 0, Y: 0}},
+				"orc":  {EntityID: "orc", EntityType: "monster", Position: spatial.Position{X: 1, Y: 0}},
+			},
+		},
+		InitiativeData: &initiative.TrackerData{
+			Order: []initiative.EntityData{
+				{ID: "hero", Type: "character"},
+				{ID: "orc", Type: "monster"},
+			},
+			Current: 1,
+			Round:   1,
+		},
+		Combatants: map[string]*encounters.CombatantData{"orc": newOrc(15)},
+	})
+	s.Require().NoError(err)
+	s.expectCharacterData(hero, sheet)
+}
+
+// useClock rebuilds the orchestrator with a clock that reads *now
+func (s *OrchestratorTestSuite) useClock(now *time.Time) {
+	clk := mockclock.NewMockClock(s.ctrl)
+	clk.EXPECT().Now().DoAndReturn(func() time.Time { return *now }).AnyTimes()
+
+	var err error
+	s.orchestrator, err = encounter.NewOrchestrator(&encounter.Config{
+		IDGenerator:      s.idGen,
+		Repository:       s.repo,
+		DiceService:      s.mockDiceService,
+		CharacterRepo:    s.mockCharRepo,
+		ExternalClient:   s.mockExternalClient,
+		CharacterService: s.mockCharacterService,
+		Clock:            clk,
+		ReactionTimeout:  10 * time.Second,
+	})
+	s.Require().NoError(err)
+}
+
+func (s *OrchestratorTestSuite) respond(entityID string, accept bool) (*encounter.RespondToReactionOutput, error) {
+	return s.orchestrator.RespondToReaction(context.Background(), &encounter.RespondToReactionInput{
+		EncounterID: "enc-1",
+		EntityID:    entityID,
+		Accept:      accept,
+	})
+}
+
+// moveOrcAway moves the orc out of the hero's reach, waiting for the hero to answer
+func (s *OrchestratorTestSuite) moveOrcAway() (*encounter.MoveOutput, error) {
+	return s.orchestrator.Move(context.Background(), &encounter.MoveInput{
+		EncounterID:      "enc-1",
+		EntityID:         "orc",
+		Target:           spatial.Position{X: 3, Y: 0},
+		WaitForReactions: true,
+	})
+}
+
+func (s *OrchestratorTestSuite) attackHero() (*encounter.AttackOutput, error) {
+	return s.orchestrator.Attack(context.Background(), &encounter.AttackInput{
+		EncounterID:      "enc-1",
+		AttackerID:       "orc",
+		TargetID:         "hero",
+		WaitForReactions: true,
+	})
+}
+
+func (s *OrchestratorTestSuite) positionOf(entityID string) spatial.Position {
+	state, err := s.orchestrator.GetCombatState(context.Background(), &encounter.GetCombatStateInput{EncounterID: "enc-1", Internal: true})
+	s.Require().NoError(err)
+	for _, combatant := range state.Combatants {
+		if combatant.EntityID == entityID {
+			return combatant.Position
+		}
+	}
+	s.FailNow(entityID + " is not in the encounter")
+	return spatial.Position{}
+}
+
+func (s *OrchestratorTestSuite) armorClassOf(entityID string) int {
+	state, err := s.orchestrator.GetCombatState(context.Background(), &encounter.GetCombatStateInput{EncounterID: "enc-1", Internal: true})
+	s.Require().NoError(err)
+	for _, combatant := range state.Combatants {
+		if combatant.EntityID == entityID {
+			return combatant.ArmorClass
+		}
+	}
+	s.FailNow(entityID + " is not in the encounter")
+	return 0
+}
+
+func (s *OrchestratorTestSuite) TestMove_MonstersTakeOpportunityAttacksAtOnce() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 0}, newOrc(15))
+	s.expectHero()
+	s.expectAttackRoll("orc", "1d20", 15)
+	s.expectAttackRoll("orc", "1d6", 4)
+	s.expectHitPointsSaved(5)
+
+	output, err := s.move("hero", 0, 2)
+	s.Require().NoError(err)
+	s.Nil(output.Pending)
+	s.False(output.Interrupted)
+	s.Len(output.Path, 2)
+	s.Require().Len(output.Reactions, 1)
+	s.Equal("orc", output.Reactions[0].EntityID)
+	s.Equal(encounters.ReactionAccepted, output.Reactions[0].Response)
+	s.Require().NotNil(output.Reactions[0].Attack)
+	s.True(output.Reactions[0].Attack.Hit)
+	s.Equal(int32(5), output.Reactions[0].Attack.TargetHitPoints)
+	s.Equal(spatial.Position{X: 0, Y: 2}, s.positionOf("hero"))
+
+	_, err = s.castReaction("orc")
+	s.True(errors.IsFailedPrecondition(err), "the opportunity attack used the orc's reaction")
+}
+
+func (s *OrchestratorTestSuite) TestMove_OpportunityAttackDropsTheMover() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 0}, newOrc(15))
+	s.expectHero()
+	s.expectAttackRoll("orc", "1d20", 20)
+	s.expectAttackRoll("orc", "2d6", 6, 6)
+
+	output, err := s.move("hero", 0, 2)
+	s.Require().NoError(err)
+	s.True(output.Interrupted)
+	s.Len(output.Path, 1, "the hero falls before leaving the orc's reach")
+	s.Equal(5, output.MovementCost)
+	s.True(output.Reactions[0].Attack.TargetDefeated)
+	s.Equal(output.Path[0], s.positionOf("hero"))
+}
+
+func (s *OrchestratorTestSuite) TestMove_LeavingACharactersReachWaitsForIt() {
+	s.saveOrcTurnEncounter(&toolkitchar.Data{ID: "hero"}, nil)
+	s.expectHero()
+
+	output, err := s.moveOrcAway()
+	s.Require().NoError(err)
+	s.Empty(output.Path, "the orc stops before leaving the hero's reach")
+	s.Require().NotNil(output.Pending)
+	s.Equal(encounters.PendingActionMove, output.Pending.Type)
+	s.Require().Len(output.Pending.Reactions, 1)
+	s.Equal("hero", output.Pending.Reactions[0].EntityID)
+	s.Equal(encounter.ReactionOpportunityAttack, output.Pending.Reactions[0].Reaction)
+
+	s.expectAttackRoll("hero", "1d20", 15)
+	s.expectAttackRoll("hero", "1d8", 4)
+	responded, err := s.respond("hero", true)
+	s.Require().NoError(err)
+	s.Nil(responded.Pending)
+
+	resolution := responded.Resolution
+	s.Require().NotNil(resolution)
+	s.Require().Len(resolution.Reactions, 1)
+	s.Equal(encounters.ReactionAccepted, resolution.Reactions[0].Response)
+	s.Require().NotNil(resolution.Reactions[0].Attack)
+	s.Equal(int32(8), resolution.Reactions[0].Attack.TargetHitPoints)
+
+	s.Require().NotNil(resolution.Move)
+	s.Len(resolution.Move.Path, 2)
+	s.Equal(10, resolution.Move.MovementUsed)
+	s.Nil(resolution.Move.Pending)
+	s.Equal(spatial.Position{X: 3, Y: 0}, s.positionOf("orc"))
+
+	_, err = s.castReaction("hero")
+	s.True(errors.IsFailedPrecondition(err), "the opportunity attack used the hero's reaction")
+}
+
+func (s *OrchestratorTestSuite) TestMove_DeclinedOpportunityAttack() {
+	s.saveOrcTurnEncounter(&toolkitchar.Data{ID: "hero"}, nil)
+	s.expectHero()
+
+	_, err := s.moveOrcAway()
+	s.Require().NoError(err)
+
+	responded, err := s.respond("hero", false)
+	s.Require().NoError(err)
+	s.Require().NotNil(responded.Resolution)
+	s.Equal(encounters.ReactionDeclined, responded.Resolution.Reactions[0].Response)
+	s.Nil(responded.Resolution.Reactions[0].Attack)
+	s.Equal(spatial.Position{X: 3, Y: 0}, s.positionOf("orc"))
+
+	_, err = s.castReaction("hero")
+	s.Require().NoError(err, "declining keeps the reaction")
+}
+
+func (s *OrchestratorTestSuite) TestMove_DisengageAvoidsOpportunityAttacks() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 1, Y: 0}, newOrc(15))
+	s.expectHero()
+
+	_, err := s.useAction("hero", encounter.ActionDisengage, "")
+	s.Require().NoError(err)
+
+	output, err := s.move("hero", 0, 2)
+	s.Require().NoError(err)
+	s.Empty(output.Reactions)
+	s.Len(output.Path, 2)
+
+	_, err = s.useAction("hero", encounter.ActionDisengage, encounter.CostReaction)
+	s.True(errors.IsInvalidArgument(err), "got %v", err)
+}
+
+func (s *OrchestratorTestSuite) TestPendingAction_BlocksOtherActions() {
+	s.saveOrcTurnEncounter(&toolkitchar.Data{ID: "hero"}, nil)
+	s.expectHero()
+
+	_, err := s.moveOrcAway()
+	s.Require().NoError(err)
+
+	_, err = s.attack("orc", "hero")
+	s.True(errors.IsFailedPrecondition(err), "got %v", err)
+	_, err = s.move("orc", 1, 1)
+	s.True(errors.IsFailedPrecondition(err), "got %v", err)
+	_, err = s.useAction("orc", encounter.ActionCastSpell, "")
+	s.True(errors.IsFailedPrecondition(err), "got %v", err)
+	_, err = s.orchestrator.NextTurn(context.Background(), &encounter.NextTurnInput{EncounterID: "enc-1"})
+	s.True(errors.IsFailedPrecondition(err), "got %v", err)
+
+	state, err := s.orchestrator.GetCombatState(context.Background(), &encounter.GetCombatStateInput{EncounterID: "enc-1", Internal: true})
+	s.Require().NoError(err)
+	s.Require().NotNil(state.Pending)
+	s.Equal("orc", state.Pending.EntityID)
+
+	_, err = s.respond("orc", true)
+	s.True(errors.IsNotFound(err), "the orc was not offered a reaction: %v", err)
+}
+
+// shieldCaster is a wizard with Shield prepared and the given level 1 spell slots used
+func shieldCaster(slotsUsed int) (*toolkitchar.Data, *dnd5e.CharacterSheet) {
+	return &toolkitchar.Data{
+			ID:         "hero",
+			Level:      1,
+			SpellSlots: shieldCasterSlots(slotsUsed),
+		},
+		&dnd5e.CharacterSheet{PreparedSpells: []string{encounter.SpellShield}}
+}
+
+func shieldCasterSlots(slotsUsed int) map[int]toolkitchar.SlotInfo {
+	return map[int]toolkitchar.SlotInfo{1: {Max: 2, Used: slotsUsed}}
+}
+
+func (s *OrchestratorTestSuite) TestAttack_ShieldTurnsAHitIntoAMiss() {
+	s.saveOrcTurnEncounter(shieldCaster(0))
+	s.expectHero()
+	s.expectAttackRoll("orc", "1d20", 12)
+
+	output, err := s.attackHero()
+	s.Require().NoError(err)
+	s.True(output.Result.Hit)
+	s.Zero(output.Result.Damage)
+	s.Require().NotNil(output.Pending)
+	s.Equal(encounters.PendingActionAttack, output.Pending.Type)
+	s.Require().Len(output.Pending.Reactions, 1)
+	s.Equal(encounter.ReactionShield, output.Pending.Reactions[0].Reaction)
+
+	s.mockCharRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+			s.Equal(dnd5e.CharacterEventSpellSlotUsed, input.EventType)
+			s.Equal(1, input.CharacterData.SpellSlots[1].Used)
+			return &characterrepo.UpdateOutput{CharacterData: input.CharacterData}, nil
+		})
+	responded, err := s.respond("hero", true)
+	s.Require().NoError(err)
+	s.Require().NotNil(responded.Resolution)
+	result := responded.Resolution.Attack.Result
+	s.False(result.Hit)
+	s.Equal(int32(21), result.TargetAC)
+	s.Equal(int32(12), result.TargetHitPoints)
+
+	// The bonus lasts until the start of the hero's next turn
+	s.Equal(21, s.armorClassOf("hero"))
+	s.nextTurn()
+	s.Equal(16, s.armorClassOf("hero"))
+}
+
+func (s *OrchestratorTestSuite) TestAttack_ShieldSpendsTheSlotOnceWhenTheEncounterChanges() {
+	s.saveOrcTurnEncounter(shieldCaster(0))
+	s.expectHero()
+	s.expectAttackRoll("orc", "1d20", 12)
+
+	output, err := s.attackHero()
+	s.Require().NoError(err)
+	s.Require().NotNil(output.Pending)
+
+	// Another request writes the encounter after the response reads it, so the response starts over
+	s.raceReads(1, func() {
+		_, err := s.repo.Update(context.Background(), &encounters.UpdateInput{EncounterID: "enc-1"})
+		s.Require().NoError(err)
+	})
+	s.mockCharRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+			s.NotNil(input.ExpectedRevision)
+			s.Equal(1, input.CharacterData.SpellSlots[1].Used)
+			return &characterrepo.UpdateOutput{CharacterData: input.CharacterData}, nil
+		})
+	responded, err := s.respond("hero", true)
+	s.Require().NoError(err)
+	s.False(responded.Resolution.Attack.Result.Hit)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_ShieldSlotRewrittenWhenTheCharacterChanges() {
+	s.saveOrcTurnEncounter(shieldCaster(0))
+	s.expectHero()
+	s.expectAttackRoll("orc", "1d20", 12)
+
+	output, err := s.attackHero()
+	s.Require().NoError(err)
+	s.Require().NotNil(output.Pending)
+
+	gomock.InOrder(
+		s.mockCharRepo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+				// The mocked read hands back the same character, so undo the lost write
+				input.CharacterData.SpellSlots = shieldCasterSlots(0)
+				return nil, errors.Aborted("character changed")
+			}),
+		s.mockCharRepo.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+				s.Equal(dnd5e.CharacterEventSpellSlotUsed, input.EventType)
+				s.Equal(1, input.CharacterData.SpellSlots[1].Used)
+				return &characterrepo.UpdateOutput{CharacterData: input.CharacterData}, nil
+			}),
+	)
+	responded, err := s.respond("hero", true)
+	s.Require().NoError(err)
+	s.False(responded.Resolution.Attack.Result.Hit)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_ShieldNeedsASpellSlot() {
+	s.saveOrcTurnEncounter(shieldCaster(2))
+	s.expectHero()
+	s.expectAttackRoll("orc", "1d20", 12)
+	s.expectAttackRoll("orc", "1d6", 4)
+	s.expectHitPointsSaved(5)
+
+	output, err := s.attackHero()
+	s.Require().NoError(err)
+	s.Nil(output.Pending, "no slot left to cast Shield")
+	s.Equal(int32(7), output.Result.Damage)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_ShieldNotOfferedWhenItCannotHelp() {
+	s.saveOrcTurnEncounter(shieldCaster(0))
+	s.expectHero()
+	s.expectAttackRoll("orc", "1d20", 17)
+	s.expectAttackRoll("orc", "1d6", 4)
+	s.expectHitPointsSaved(5)
+
+	output, err := s.attackHero()
+	s.Require().NoError(err)
+	s.Nil(output.Pending, "22 hits AC 21 anyway")
+	s.Equal(int32(7), output.Result.Damage)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_ShieldLeftToThePlayer() {
+	s.saveOrcTurnEncounter(shieldCaster(0))
+	s.expectHero()
+	s.expectAttackRoll("orc", "1d20", 12)
+	s.expectAttackRoll("orc", "1d6", 4)
+	s.expectHitPointsSaved(5)
+
+	output, err := s.attack("orc", "hero")
+	s.Require().NoError(err)
+	s.Nil(output.Pending, "nobody is waiting to answer")
+	s.True(output.Result.Hit)
+	s.Require().Len(output.Reactions, 1)
+	s.Equal(encounters.ReactionDeclined, output.Reactions[0].Response)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_UncannyDodgeHalvesDamage() {
+	s.saveOrcTurnEncounter(&toolkitchar.Data{ID: "hero", ClassID: constants.ClassRogue, Level: 5}, nil)
+	s.expectHero()
+	s.expectAttackRoll("orc", "1d20", 15)
+
+	output, err := s.attackHero()
+	s.Require().NoError(err)
+	s.Require().NotNil(output.Pending)
+	s.Equal(encounter.ReactionUncannyDodge, output.Pending.Reactions[0].Reaction)
+
+	s.expectAttackRoll("orc", "1d6", 4)
+	s.expectHitPointsSaved(9)
+	responded, err := s.respond("hero", true)
+	s.Require().NoError(err)
+	result := responded.Resolution.Attack.Result
+	s.True(result.Hit)
+	s.Equal(int32(3), result.Damage)
+	s.Equal(int32(9), result.TargetHitPoints)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_UncannyDodgeTakenAtOnce() {
+	s.saveOrcTurnEncounter(&toolkitchar.Data{ID: "hero", ClassID: constants.ClassRogue, Level: 5}, nil)
+	s.expectHero()
+	s.expectAttackRoll("orc", "1d20", 15)
+	s.expectAttackRoll("orc", "1d6", 4)
+	s.expectHitPointsSaved(9)
+
+	output, err := s.attack("orc", "hero")
+	s.Require().NoError(err)
+	s.Nil(output.Pending)
+	s.Equal(int32(3), output.Result.Damage)
+	s.Require().Len(output.Reactions, 1)
+	s.Equal(encounters.ReactionAccepted, output.Reactions[0].Response)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_SavesTheCharactersHitPoints() {
+	s.saveOrcTurnEncounter(&toolkitchar.Data{ID: "hero", HitPoints: 12, MaxHitPoints: 12}, nil)
+	s.expectHero()
+	s.expectAttackRoll("orc", "1d20", 15)
+	s.expectAttackRoll("orc", "1d6", 4)
+	// The first write loses to a concurrent one, so the character is read again
+	s.mockCharRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+			input.CharacterData.HitPoints = 12
+			return nil, errors.Aborted("character was written concurrently")
+		})
+	s.expectHitPointsSaved(5)
+
+	output, err := s.attack("orc", "hero")
+	s.Require().NoError(err)
+	s.Equal(int32(5), output.Result.TargetHitPoints)
+}
+
+func (s *OrchestratorTestSuite) TestAttack_MissLeavesTheCharacterAlone() {
+	s.saveOrcTurnEncounter(&toolkitchar.Data{ID: "hero", HitPoints: 12, MaxHitPoints: 12}, nil)
+	s.expectHero()
+	s.expectAttackRoll("orc", "1d20", 2)
+
+	output, err := s.attack("orc", "hero")
+	s.Require().NoError(err)
+	s.False(output.Result.Hit)
+}
+
+func (s *OrchestratorTestSuite) TestResumeAction_AfterTheDeadline() {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.useClock(&now)
+	s.saveOrcTurnEncounter(&toolkitchar.Data{ID: "hero"}, nil)
+	s.expectHero()
+
+	output, err := s.moveOrcAway()
+	s.Require().NoError(err)
+	s.Require().NotNil(output.Pending)
+	s.Equal(now.Add(10*time.Second), output.Pending.Reactions[0].Deadline)
+
+	_, err = s.orchestrator.ResumeAction(context.Background(), &encounter.ResumeActionInput{EncounterID: "enc-1"})
+	s.True(errors.IsFailedPrecondition(err), "the hero still has time: %v", err)
+
+	now = now.Add(11 * time.Second)
+	_, err = s.respond("hero", true)
+	s.True(errors.IsFailedPrecondition(err), "too late to react: %v", err)
+
+	resumed, err := s.orchestrator.ResumeAction(context.Background(), &encounter.ResumeActionInput{EncounterID: "enc-1"})
+	s.Require().NoError(err)
+	s.Equal(encounters.ReactionExpired, resumed.Resolution.Reactions[0].Response)
+	s.Equal(spatial.Position{X: 3, Y: 0}, s.positionOf("orc"))
+}
+
+func (s *OrchestratorTestSuite) TestRespondToReaction_NothingPending() {
+	s.saveAttackEncounter(spatial.Position{X: 0, Y: 0}, spatial.Position{X: 5, Y: 0}, newOrc(15))
+
+	_, err := s.respond("orc", true)
+	s.True(errors.IsFailedPrecondition(err), "got %v", err)
+
+	_, err = s.orchestrator.RespondToReaction(context.Background(), &encounter.RespondToReactionInput{
+		EncounterID: "enc-1",
+		EntityID:    "orc",
+		Reaction:    "parry",
+	})
+	s.True(errors.IsInvalidArgument(err), "got %v", err)
+}
@@ -1,6 +1,7 @@
 package encounter
 
 import (
+	"github.com/KirkDiggler/rpg-api/internal/repositories/encounters"
 	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/initiative"
 	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
 )
@@ -15,7 +16,63 @@ type DungeonStartOutput struct {
 	EncounterID    string
 	RoomData       *spatial.RoomData
 	InitiativeData *initiative.TrackerData // Turn order for the encounter
-	CurrentTurn    string                  // ID of whose turn it is
+	Initiative     []*encounters.InitiativeEntryData
+	CurrentTurn    string // ID of whose turn it is
+}
+
+// CreateEncounterInput defines the request for creating an encounter
+type CreateEncounterInput struct {
+	CampaignID string // Optional
+	SessionID  string // Optional
+	RoomType   string // Optional, e.g. "dungeon"
+
+	GridType string // spatial.GridTypeHex or spatial.GridTypeSquare
+	Width    int    // In cells
+	Height   int    // In cells
+
+	CharacterIDs   []string
+	CharacterSpawn *SpawnArea // Required when there are characters
+	Monsters       []*MonsterGroup
+	MonsterSpawn   *SpawnArea // Required when there are monsters
+	RollHitPoints  bool       // Roll each monster's hit dice instead of using the average
+
+	Obstacles        []*Obstacle
+	DifficultTerrain []spatial.Position
+}
+
+// SpawnArea says where one side of a fight starts. Entities take the Positions in order;
+// once those run out, the rest fill the free cells of Zone row by row.
+type SpawnArea struct {
+	Positions []spatial.Position
+	Zone      *SpawnZone
+}
+
+// SpawnZone is a rectangle of cells, including both corners
+type SpawnZone struct {
+	From spatial.Position
+	To   spatial.Position
+}
+
+// MonsterGroup adds Count monsters with the same stat block
+type MonsterGroup struct {
+	MonsterID string // SRD index, e.g. "goblin"
+	Count     int
+}
+
+// Obstacle is a cell nothing can move through, such as a pillar or wall section
+type Obstacle struct {
+	Position          spatial.Position
+	BlocksLineOfSight bool
+}
+
+// CreateEncounterOutput defines the response for creating an encounter
+type CreateEncounterOutput struct {
+	EncounterID    string
+	RoomData       *spatial.RoomData
+	InitiativeData *initiative.TrackerData
+	Initiative     []*encounters.InitiativeEntryData
+	Combatants     map[string]*encounters.CombatantData // The monsters' stat blocks by entity ID
+	CurrentTurn    string
 }
 
 // Note: All spatial types (Position, EntityPlacement, RoomData) are now provided
@@ -40,5 +97,217 @@ type GetTurnOrderInput struct {
 // GetTurnOrderOutput defines the response for getting current turn order
 type GetTurnOrderOutput struct {
 	InitiativeData *initiative.TrackerData
+	Initiative     []*encounters.InitiativeEntryData // How each participant's initiative was rolled
+	CurrentTurn    string                            // ID of whose turn it is
+}
+
+// MoveInput defines the request for moving an entity during its turn
+type MoveInput struct {
+	EncounterID string
+	EntityID    string
+	Target      spatial.Position
+
+	// WaitForReactions pauses the move when it offers characters an opportunity attack,
+	// until they answer with RespondToReaction or ResumeAction. Without it, and always
+	// for monsters, reactions are resolved at once.
+	WaitForReactions bool
+}
+
+// MoveOutput defines the response for moving an entity
+type MoveOutput struct {
+	Path              []spatial.Position // Cells entered in order, ending at the target unless the move paused
+	MovementCost      int                // Feet spent on this move
+	MovementUsed      int                // Feet spent this turn, including this move
+	MovementRemaining int                // Feet left this turn
+	Speed             int                // The entity's speed in feet
+	RoomData          *spatial.RoomData
+	InitiativeData    *initiative.TrackerData
+	Initiative        []*encounters.InitiativeEntryData
+	CurrentTurn       string
+	Turn              *TurnState // What the mover has left this turn
+
+	// Interrupted is set when an opportunity attack dropped the mover before it arrived
+	Interrupted bool
+	// Reactions are the opportunity attacks made against the mover without pausing
+	Reactions []*ReactionResult
+	// Pending is set when the move left a character's reach and paused for its answer
+	Pending *encounters.PendingActionData
+}
+
+// AttackInput defines the request for the active entity attacking another entity
+type AttackInput struct {
+	EncounterID string
+	AttackerID  string
+	TargetID    string
+	WeaponID    string // Optional, the weapon or monster attack to use; defaults to the first available
+
+	// WaitForReactions pauses a hit the target can react to until it answers with
+	// RespondToReaction or ResumeAction. Without it the reaction is resolved at once.
+	WaitForReactions bool
+}
+
+// AttackOutput defines the response for an attack
+type AttackOutput struct {
+	Result         *AttackResult
+	RoomData       *spatial.RoomData // Defeated monsters are removed from the room
+	InitiativeData *initiative.TrackerData
+	Initiative     []*encounters.InitiativeEntryData
+	CurrentTurn    string
+
+	// Reactions are the target's reactions resolved without pausing
+	Reactions []*ReactionResult
+	// Pending is set when the target was hit and may react; Result then has no damage yet
+	Pending *encounters.PendingActionData
+}
+
+// AttackResult holds the outcome of an attack with the full breakdown of every roll
+type AttackResult struct {
+	AttackerID   string
+	TargetID     string
+	WeaponID     string
+	WeaponName   string
+	AttackType   string // AttackTypeMelee or AttackTypeRanged
+	DistanceFeet int
+
+	Rolls       []int32 // every d20 rolled, two with advantage or disadvantage
+	Roll        int32   // the d20 that was kept
+	AttackBonus int32
+	Total       int32 // Roll + AttackBonus
+
+	Advantage           bool
+	Disadvantage        bool
+	AdvantageSources    []string
+	DisadvantageSources []string
+
+	TargetAC int32
+	Hit      bool
+	Critical bool // natural 20, or a hit from within 5 feet on a paralyzed or unconscious target
+
+	DamageDice  string  // dice rolled for damage, doubled on a critical hit
+	DamageRolls []int32 // every damage die rolled
+	DamageBonus int32
+	Damage      int32
+	DamageType  string
+
+	TargetHitPoints int32 // the target's hit points after the attack
+	TargetDefeated  bool
+
+	// Steps describes each step of the attack in order, for a combat log
+	Steps []string
+}
+
+// GetCombatStateInput defines the request for the full state of an encounter
+type GetCombatStateInput struct {
+	EncounterID string
+	// ViewerID is the player reading; monster stats are hidden unless they are the DM of
+	// the encounter's campaign
+	ViewerID string
+
+	// Internal is set by server-side callers that need every combatant's stats. It skips
+	// the viewer check.
+	Internal bool
+}
+
+// GetCombatStateOutput defines the response for the full state of an encounter, enough
+// for a client to rebuild the fight after reconnecting
+type GetCombatStateOutput struct {
+	EncounterID    string
+	RoomData       *spatial.RoomData
+	InitiativeData *initiative.TrackerData
+	Initiative     []*encounters.InitiativeEntryData
 	CurrentTurn    string // ID of whose turn it is
+	Round          int
+	Combatants     []*CombatantState // In turn order, then anyone not in the order
+	Turn           *TurnState        // What the active combatant has left this turn; nil with no active combatant
+
+	// Pending is the action waiting on reactions, if any
+	Pending *encounters.PendingActionData
+}
+
+// CombatantState is one combatant as a viewer sees it
+type CombatantState struct {
+	EntityID   string
+	EntityType string
+	Name       string
+	Position   spatial.Position
+
+	// StatsHidden is set when the viewer may not see this combatant's hit points and
+	// armor class, which are then left zero
+	StatsHidden  bool
+	ArmorClass   int
+	HitPoints    int
+	MaxHitPoints int
+	Conditions   []string
+}
+
+// TurnState describes what the active combatant has left this turn
+type TurnState struct {
+	EntityID          string
+	Speed             int // In feet
+	MovementUsed      int
+	MovementRemaining int
+
+	ActionAvailable            bool
+	BonusActionAvailable       bool
+	ReactionAvailable          bool // Reactions come back at the start of the combatant's turn
+	ObjectInteractionAvailable bool
+}
+
+// UseActionInput defines the request for spending a resource on something the encounter
+// does not resolve itself, such as casting a spell or drinking a potion
+type UseActionInput struct {
+	EncounterID string
+	EntityID    string
+	Action      string // ActionCastSpell, ActionUseItem, ActionInteract or ActionDisengage
+	SpellID     string // Optional, the spell being cast; its casting time sets the cost
+	Cost        string // Optional, one of the Cost values; only accepted where a rule allows paying differently
+	Description string // Optional, for the log, e.g. "Healing Word"
+}
+
+// UseActionOutput defines the response for using an action
+type UseActionOutput struct {
+	EntityID string
+	Action   string
+	Cost     string     // The resource that was spent
+	Turn     *TurnState // What the entity has left; nil for a reaction outside its turn
+}
+
+// RespondToReactionInput defines the request for a combatant answering a reaction it was offered
+type RespondToReactionInput struct {
+	EncounterID string
+	EntityID    string
+	Reaction    string // Optional, one of the Reaction values; needed when accepting one of several offers
+	Accept      bool
+}
+
+// RespondToReactionOutput defines the response for answering a reaction
+type RespondToReactionOutput struct {
+	Pending    *encounters.PendingActionData // Set while other reactions are unanswered
+	Resolution *ActionResolution             // Set once the paused action has resumed
+}
+
+// ResumeActionInput defines the request for resuming an action once its reactions have expired
+type ResumeActionInput struct {
+	EncounterID string
+}
+
+// ResumeActionOutput defines the response for resuming an action
+type ResumeActionOutput struct {
+	Resolution *ActionResolution
+}
+
+// ActionResolution is how a paused action ended. Move may itself pause again when the
+// mover leaves another enemy's reach.
+type ActionResolution struct {
+	Reactions []*ReactionResult // The answered reactions, then any resolved while finishing
+	Move      *MoveOutput       // Set when a move resumed
+	Attack    *AttackOutput     // Set when an attack resumed
+}
+
+// ReactionResult is one combatant's answer to a reaction it was offered
+type ReactionResult struct {
+	EntityID string
+	Reaction string
+	Response string        // encounters.ReactionAccepted, ReactionDeclined or ReactionExpired
+	Attack   *AttackResult // The opportunity attack, when one was made
 }
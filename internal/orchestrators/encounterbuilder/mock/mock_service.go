@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/KirkDiggler/rpg-api/internal/orchestrators/encounterbuilder (interfaces: Service)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/mock_service.go -package=encounterbuildermock github.com/KirkDiggler/rpg-api/internal/orchestrators/encounterbuilder Service
+//
+
+// Package encounterbuildermock is a generated GoMock package.
+package encounterbuildermock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+
+	encounterbuilder "github.com/KirkDiggler/rpg-api/internal/orchestrators/encounterbuilder"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// BuildEncounter mocks base method.
+func (m *MockService) BuildEncounter(ctx context.Context, input *encounterbuilder.BuildEncounterInput) (*encounterbuilder.BuildEncounterOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildEncounter", ctx, input)
+	ret0, _ := ret[0].(*encounterbuilder.BuildEncounterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuildEncounter indicates an expected call of BuildEncounter.
+func (mr *MockServiceMockRecorder) BuildEncounter(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildEncounter", reflect.TypeOf((*MockService)(nil).BuildEncounter), ctx, input)
+}
+
+// CheckEncounter mocks base method.
+func (m *MockService) CheckEncounter(ctx context.Context, input *encounterbuilder.CheckEncounterInput) (*encounterbuilder.CheckEncounterOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckEncounter", ctx, input)
+	ret0, _ := ret[0].(*encounterbuilder.CheckEncounterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckEncounter indicates an expected call of CheckEncounter.
+func (mr *MockServiceMockRecorder) CheckEncounter(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckEncounter", reflect.TypeOf((*MockService)(nil).CheckEncounter), ctx, input)
+}
@@ -0,0 +1,330 @@
+// Package encounterbuilder sizes fights for a party with the 5e encounter building rules:
+// XP thresholds per character level and the multiple-monster multiplier
+package encounterbuilder
+
+//go:generate mockgen -destination=mock/mock_service.go -package=encounterbuildermock github.com/KirkDiggler/rpg-api/internal/orchestrators/encounterbuilder Service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+const (
+	maxPartySize        = 10
+	maxMonstersPerGroup = 50
+
+	// defaultMaxMonsters and maxMonsters bound how many monsters a built encounter has
+	defaultMaxMonsters = 8
+	maxMonsters        = 20
+
+	// Built encounters use a square room with the party on the west edge and the
+	// monsters on the east edge; each edge zone holds maxMonsters
+	roomWidth  = 12
+	roomHeight = 10
+)
+
+// Service defines the interface for encounter building operations
+type Service interface {
+	// CheckEncounter rates a planned set of monsters against a party
+	CheckEncounter(ctx context.Context, input *CheckEncounterInput) (*CheckEncounterOutput, error)
+
+	// BuildEncounter picks a group of SRD monsters that makes a fight of the requested
+	// difficulty and returns a ready CreateEncounter request
+	BuildEncounter(ctx context.Context, input *BuildEncounterInput) (*BuildEncounterOutput, error)
+}
+
+// Config holds the dependencies for the encounter builder
+type Config struct {
+	// CharacterRepo supplies the levels of parties given by character
+	CharacterRepo characterrepo.Repository
+	// ExternalClient supplies monster XP and the SRD monster catalog
+	ExternalClient external.Client
+}
+
+// Validate ensures all required dependencies are provided
+func (c *Config) Validate() error {
+	vb := errors.NewValidationBuilder()
+
+	if c.CharacterRepo == nil {
+		vb.RequiredField("CharacterRepo")
+	}
+	if c.ExternalClient == nil {
+		vb.RequiredField("ExternalClient")
+	}
+
+	return vb.Build()
+}
+
+type orchestrator struct {
+	charRepo       characterrepo.Repository
+	externalClient external.Client
+}
+
+// NewOrchestrator creates a new encounter builder with the provided dependencies
+func NewOrchestrator(cfg *Config) (Service, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid config")
+	}
+
+	return &orchestrator{
+		charRepo:       cfg.CharacterRepo,
+		externalClient: cfg.ExternalClient,
+	}, nil
+}
+
+// CheckEncounter rates a planned set of monsters against a party
+func (o *orchestrator) CheckEncounter(ctx context.Context, input *CheckEncounterInput) (*CheckEncounterOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	validateParty(input.Party, vb)
+	if len(input.Monsters) == 0 {
+		vb.Field("monsters", "at least one monster group is required")
+	}
+	for i, group := range input.Monsters {
+		field := fmt.Sprintf("monsters[%d]", i)
+		if group == nil {
+			vb.RequiredField(field)
+			continue
+		}
+		errors.ValidateRequired(field+".monster_id", group.MonsterID, vb)
+		errors.ValidateRange(field+".count", group.Count, 1, maxMonstersPerGroup, vb)
+	}
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+
+	levels, err := o.partyLevels(ctx, input.Party)
+	if err != nil {
+		return nil, err
+	}
+
+	totalXP, monsterCount := 0, 0
+	for _, group := range input.Monsters {
+		statBlock, err := o.externalClient.GetMonsterData(ctx, group.MonsterID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get monster %s", group.MonsterID)
+		}
+		totalXP += int(statBlock.XP) * group.Count
+		monsterCount += group.Count
+	}
+
+	return &CheckEncounterOutput{
+		Assessment: assess(levels, totalXP, monsterCount),
+	}, nil
+}
+
+// candidate is a monster, or any SRD monster of a challenge rating, that a built
+// encounter may use
+type candidate struct {
+	monsterID       string // Empty when any monster with the challenge rating will do
+	challengeRating float32
+	xp              int
+}
+
+// option is a group of one candidate that makes a fight of the requested difficulty
+type option struct {
+	candidate
+	count      int
+	adjustedXP int
+}
+
+// BuildEncounter finds the group of identical monsters whose adjusted XP lands in the
+// requested difficulty, as close to its lower threshold as possible and then with as few
+// monsters as possible. Without MonsterIDs the group is picked by challenge rating and
+// the monster is the first SRD monster listed with that rating.
+func (o *orchestrator) BuildEncounter(ctx context.Context, input *BuildEncounterInput) (*BuildEncounterOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	vb := errors.NewValidationBuilder()
+	validateParty(input.Party, vb)
+	errors.ValidateEnum("difficulty", input.Difficulty,
+		[]string{DifficultyEasy, DifficultyMedium, DifficultyHard, DifficultyDeadly}, vb)
+	for i, monsterID := range input.MonsterIDs {
+		errors.ValidateRequired(fmt.Sprintf("monster_ids[%d]", i), monsterID, vb)
+	}
+	errors.ValidateRange("max_monsters", input.MaxMonsters, 0, maxMonsters, vb)
+	if err := vb.Build(); err != nil {
+		return nil, err
+	}
+
+	levels, err := o.partyLevels(ctx, input.Party)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := o.candidates(ctx, input.MonsterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := input.MaxMonsters
+	if limit == 0 {
+		limit = defaultMaxMonsters
+	}
+	low, high := xpBand(input.Difficulty, partyThresholds(levels))
+	var options []*option
+	for _, c := range candidates {
+		for count := 1; count <= limit; count++ {
+			adjusted := adjustedXP(c.xp*count, count, len(levels))
+			if adjusted >= low && adjusted < high {
+				options = append(options, &option{candidate: c, count: count, adjustedXP: adjusted})
+			}
+		}
+	}
+	sort.SliceStable(options, func(i, j int) bool {
+		if options[i].adjustedXP != options[j].adjustedXP {
+			return options[i].adjustedXP < options[j].adjustedXP
+		}
+		return options[i].count < options[j].count
+	})
+
+	for _, opt := range options {
+		monsterID, alternatives := opt.monsterID, []string(nil)
+		if monsterID == "" {
+			monsters, err := o.externalClient.ListMonstersByChallengeRating(ctx, opt.challengeRating)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list monsters with challenge rating %g", opt.challengeRating)
+			}
+			if len(monsters) == 0 {
+				continue // Not every challenge rating has an SRD monster
+			}
+			monsterID = monsters[0].ID
+			for _, monster := range monsters[1:] {
+				alternatives = append(alternatives, monster.ID)
+			}
+		}
+
+		groups := []*encounter.MonsterGroup{{MonsterID: monsterID, Count: opt.count}}
+		return &BuildEncounterOutput{
+			Monsters:        groups,
+			Assessment:      assess(levels, opt.xp*opt.count, opt.count),
+			Alternatives:    alternatives,
+			CreateEncounter: createEncounterInput(input, groups),
+		}, nil
+	}
+
+	return nil, errors.FailedPreconditionf("no group of up to %d monsters makes a %s encounter for this party",
+		limit, input.Difficulty)
+}
+
+// candidates fetches the XP of the requested monsters, or lists every challenge rating
+// when any SRD monster will do
+func (o *orchestrator) candidates(ctx context.Context, monsterIDs []string) ([]candidate, error) {
+	if len(monsterIDs) == 0 {
+		candidates := make([]candidate, 0, len(challengeRatings))
+		for _, cr := range challengeRatings {
+			candidates = append(candidates, candidate{challengeRating: cr.rating, xp: cr.xp})
+		}
+		return candidates, nil
+	}
+
+	candidates := make([]candidate, 0, len(monsterIDs))
+	for _, monsterID := range monsterIDs {
+		statBlock, err := o.externalClient.GetMonsterData(ctx, monsterID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get monster %s", monsterID)
+		}
+		candidates = append(candidates, candidate{
+			monsterID:       monsterID,
+			challengeRating: statBlock.ChallengeRating,
+			xp:              int(statBlock.XP),
+		})
+	}
+	return candidates, nil
+}
+
+// partyLevels returns one level per party member, reading characters' levels from the repository
+func (o *orchestrator) partyLevels(ctx context.Context, party *Party) ([]int, error) {
+	if len(party.CharacterIDs) == 0 {
+		return party.Levels, nil
+	}
+
+	levels := make([]int, 0, len(party.CharacterIDs))
+	for _, characterID := range party.CharacterIDs {
+		charOutput, err := o.charRepo.Get(ctx, characterrepo.GetInput{ID: characterID})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, errors.NotFoundf("character %s not found", characterID)
+			}
+			return nil, errors.Wrapf(err, "failed to get character %s", characterID)
+		}
+		levels = append(levels, min(max(charOutput.CharacterData.Level, 1), maxLevel))
+	}
+	return levels, nil
+}
+
+// assess rates monsters worth totalXP against a party with the given levels
+func assess(levels []int, totalXP, monsterCount int) *Assessment {
+	thresholds := partyThresholds(levels)
+	adjusted := adjustedXP(totalXP, monsterCount, len(levels))
+	return &Assessment{
+		PartySize:    len(levels),
+		Thresholds:   thresholds,
+		MonsterCount: monsterCount,
+		TotalXP:      totalXP,
+		Multiplier:   multiplier(monsterCount, len(levels)),
+		AdjustedXP:   adjusted,
+		Difficulty:   difficultyOf(adjusted, thresholds),
+	}
+}
+
+// createEncounterInput lays out a built encounter: the party spawns along the west edge
+// and the monsters along the east edge of a square room
+func createEncounterInput(input *BuildEncounterInput, groups []*encounter.MonsterGroup) *encounter.CreateEncounterInput {
+	create := &encounter.CreateEncounterInput{
+		CampaignID: input.CampaignID,
+		SessionID:  input.SessionID,
+		GridType:   spatial.GridTypeSquare,
+		Width:      roomWidth,
+		Height:     roomHeight,
+		Monsters:   groups,
+		MonsterSpawn: &encounter.SpawnArea{Zone: &encounter.SpawnZone{
+			From: spatial.Position{X: roomWidth - 2, Y: 0},
+			To:   spatial.Position{X: roomWidth - 1, Y: roomHeight - 1},
+		}},
+	}
+	if len(input.Party.CharacterIDs) > 0 {
+		create.CharacterIDs = input.Party.CharacterIDs
+		create.CharacterSpawn = &encounter.SpawnArea{Zone: &encounter.SpawnZone{
+			From: spatial.Position{X: 0, Y: 0},
+			To:   spatial.Position{X: 1, Y: roomHeight - 1},
+		}}
+	}
+	return create
+}
+
+// validateParty checks that a party is given by either characters or levels
+func validateParty(party *Party, vb *errors.ValidationBuilder) {
+	if party == nil {
+		vb.RequiredField("party")
+		return
+	}
+
+	switch {
+	case len(party.CharacterIDs) == 0 && len(party.Levels) == 0:
+		vb.Field("party", "character IDs or levels are required")
+	case len(party.CharacterIDs) > 0 && len(party.Levels) > 0:
+		vb.Field("party", "give character IDs or levels, not both")
+	case len(party.CharacterIDs)+len(party.Levels) > maxPartySize:
+		vb.Fieldf("party", "a party has at most %d members", maxPartySize)
+	}
+
+	for i, characterID := range party.CharacterIDs {
+		errors.ValidateRequired(fmt.Sprintf("party.character_ids[%d]", i), characterID, vb)
+	}
+	for i, level := range party.Levels {
+		errors.ValidateRange(fmt.Sprintf("party.levels[%d]", i), level, 1, maxLevel, vb)
+	}
+}
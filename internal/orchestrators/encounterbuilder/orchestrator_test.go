@@ -0,0 +1,383 @@
+package encounterbuilder_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	externalmock "github.com/KirkDiggler/rpg-api/internal/clients/external/mock"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounterbuilder"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	characterrepomock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
+)
+
+type OrchestratorTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	mockCharRepo       *characterrepomock.MockRepository
+	mockExternalClient *externalmock.MockClient
+	orchestrator       encounterbuilder.Service
+	ctx                context.Context
+}
+
+func (s *OrchestratorTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockCharRepo = characterrepomock.NewMockRepository(s.ctrl)
+	s.mockExternalClient = externalmock.NewMockClient(s.ctrl)
+	s.ctx = context.Background()
+
+	orchestrator, err := encounterbuilder.NewOrchestrator(&encounterbuilder.Config{
+		CharacterRepo:  s.mockCharRepo,
+		ExternalClient: s.mockExternalClient,
+	})
+	s.Require().NoError(err)
+	s.orchestrator = orchestrator
+}
+
+func (s *OrchestratorTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *OrchestratorTestSuite) expectMonster(monsterID string, challengeRating float32, xp int32) {
+	s.mockExternalClient.EXPECT().
+		GetMonsterData(gomock.Any(), monsterID).
+		Return(&external.MonsterData{ID: monsterID, ChallengeRating: challengeRating, XP: xp}, nil)
+}
+
+func (s *OrchestratorTestSuite) expectCatalog(challengeRating float32, monsterIDs ...string) {
+	monsters := make([]*external.MonsterSummaryData, 0, len(monsterIDs))
+	for _, monsterID := range monsterIDs {
+		monsters = append(monsters, &external.MonsterSummaryData{ID: monsterID, ChallengeRating: challengeRating})
+	}
+	s.mockExternalClient.EXPECT().
+		ListMonstersByChallengeRating(gomock.Any(), challengeRating).
+		Return(monsters, nil)
+}
+
+func (s *OrchestratorTestSuite) expectCharacter(characterID string, level int) {
+	s.mockCharRepo.EXPECT().
+		Get(gomock.Any(), characterrepo.GetInput{ID: characterID}).
+		Return(&characterrepo.GetOutput{CharacterData: &toolkitchar.Data{ID: characterID, Level: level}}, nil)
+}
+
+func (s *OrchestratorTestSuite) TestNewOrchestrator_MissingDependencies() {
+	orchestrator, err := encounterbuilder.NewOrchestrator(&encounterbuilder.Config{})
+
+	s.Nil(orchestrator)
+	s.True(errors.IsInvalidArgument(err))
+	s.Contains(err.Error(), "CharacterRepo")
+	s.Contains(err.Error(), "ExternalClient")
+}
+
+func (s *OrchestratorTestSuite) TestCheckEncounter() {
+	testCases := []struct {
+		name       string
+		levels     []int
+		monsterID  string
+		xp         int32
+		count      int
+		multiplier float64
+		adjustedXP int
+		difficulty string
+	}{
+		{
+			name:       "four goblins against four level 1 characters",
+			levels:     []int{1, 1, 1, 1},
+			monsterID:  "goblin",
+			xp:         50,
+			count:      4,
+			multiplier: 2,
+			adjustedXP: 400,
+			difficulty: encounterbuilder.DifficultyDeadly,
+		},
+		{
+			name:       "a small party uses the next higher multiplier",
+			levels:     []int{3, 3},
+			monsterID:  "ogre",
+			xp:         450,
+			count:      1,
+			multiplier: 1.5,
+			adjustedXP: 675,
+			difficulty: encounterbuilder.DifficultyHard,
+		},
+		{
+			name:       "a large party uses the next lower multiplier",
+			levels:     []int{1, 1, 1, 1, 1, 1},
+			monsterID:  "goblin",
+			xp:         50,
+			count:      2,
+			multiplier: 1,
+			adjustedXP: 100,
+			difficulty: encounterbuilder.DifficultyTrivial,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.expectMonster(tc.monsterID, 0, tc.xp)
+
+			output, err := s.orchestrator.CheckEncounter(s.ctx, &encounterbuilder.CheckEncounterInput{
+				Party:    &encounterbuilder.Party{Levels: tc.levels},
+				Monsters: []*encounter.MonsterGroup{{MonsterID: tc.monsterID, Count: tc.count}},
+			})
+
+			s.Require().NoError(err)
+			s.Equal(len(tc.levels), output.Assessment.PartySize)
+			s.Equal(tc.count, output.Assessment.MonsterCount)
+			s.Equal(int(tc.xp)*tc.count, output.Assessment.TotalXP)
+			s.Equal(tc.multiplier, output.Assessment.Multiplier)
+			s.Equal(tc.adjustedXP, output.Assessment.AdjustedXP)
+			s.Equal(tc.difficulty, output.Assessment.Difficulty)
+		})
+	}
+}
+
+func (s *OrchestratorTestSuite) TestCheckEncounter_MixedGroupsForCharacters() {
+	s.expectCharacter("hero", 5)
+	s.expectCharacter("squire", 0)
+	s.expectCharacter("mage", 3)
+	s.expectMonster("orc", 0.5, 100)
+	s.expectMonster("ogre", 2, 450)
+
+	output, err := s.orchestrator.CheckEncounter(s.ctx, &encounterbuilder.CheckEncounterInput{
+		Party: &encounterbuilder.Party{CharacterIDs: []string{"hero", "squire", "mage"}},
+		Monsters: []*encounter.MonsterGroup{
+			{MonsterID: "orc", Count: 2},
+			{MonsterID: "ogre", Count: 1},
+		},
+	})
+
+	s.Require().NoError(err)
+	s.Equal(&encounterbuilder.Thresholds{Easy: 350, Medium: 700, Hard: 1050, Deadly: 1600},
+		output.Assessment.Thresholds, "a level 0 character counts as level 1")
+	s.Equal(650, output.Assessment.TotalXP)
+	s.Equal(3, output.Assessment.MonsterCount)
+	s.Equal(1300, output.Assessment.AdjustedXP)
+	s.Equal(encounterbuilder.DifficultyHard, output.Assessment.Difficulty)
+}
+
+func (s *OrchestratorTestSuite) TestCheckEncounter_CharacterNotFound() {
+	s.mockCharRepo.EXPECT().
+		Get(gomock.Any(), characterrepo.GetInput{ID: "ghost"}).
+		Return(nil, errors.NotFound("character not found"))
+
+	output, err := s.orchestrator.CheckEncounter(s.ctx, &encounterbuilder.CheckEncounterInput{
+		Party:    &encounterbuilder.Party{CharacterIDs: []string{"ghost"}},
+		Monsters: []*encounter.MonsterGroup{{MonsterID: "goblin", Count: 1}},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsNotFound(err))
+}
+
+func (s *OrchestratorTestSuite) TestCheckEncounter_MonsterLookupFails() {
+	s.mockExternalClient.EXPECT().
+		GetMonsterData(gomock.Any(), "tarrasque").
+		Return(nil, errors.Internal("unexpected status code: 404"))
+
+	output, err := s.orchestrator.CheckEncounter(s.ctx, &encounterbuilder.CheckEncounterInput{
+		Party:    &encounterbuilder.Party{Levels: []int{20}},
+		Monsters: []*encounter.MonsterGroup{{MonsterID: "tarrasque", Count: 1}},
+	})
+
+	s.Nil(output)
+	s.ErrorContains(err, "failed to get monster tarrasque")
+}
+
+func (s *OrchestratorTestSuite) TestCheckEncounter_InvalidInput() {
+	testCases := []struct {
+		name  string
+		input *encounterbuilder.CheckEncounterInput
+	}{
+		{name: "nil input"},
+		{
+			name: "missing party",
+			input: &encounterbuilder.CheckEncounterInput{
+				Monsters: []*encounter.MonsterGroup{{MonsterID: "goblin", Count: 1}},
+			},
+		},
+		{
+			name: "empty party",
+			input: &encounterbuilder.CheckEncounterInput{
+				Party:    &encounterbuilder.Party{},
+				Monsters: []*encounter.MonsterGroup{{MonsterID: "goblin", Count: 1}},
+			},
+		},
+		{
+			name: "characters and levels",
+			input: &encounterbuilder.CheckEncounterInput{
+				Party:    &encounterbuilder.Party{CharacterIDs: []string{"hero"}, Levels: []int{1}},
+				Monsters: []*encounter.MonsterGroup{{MonsterID: "goblin", Count: 1}},
+			},
+		},
+		{
+			name: "level above 20",
+			input: &encounterbuilder.CheckEncounterInput{
+				Party:    &encounterbuilder.Party{Levels: []int{21}},
+				Monsters: []*encounter.MonsterGroup{{MonsterID: "goblin", Count: 1}},
+			},
+		},
+		{
+			name: "no monsters",
+			input: &encounterbuilder.CheckEncounterInput{
+				Party: &encounterbuilder.Party{Levels: []int{1}},
+			},
+		},
+		{
+			name: "no monster count",
+			input: &encounterbuilder.CheckEncounterInput{
+				Party:    &encounterbuilder.Party{Levels: []int{1}},
+				Monsters: []*encounter.MonsterGroup{{MonsterID: "goblin"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			output, err := s.orchestrator.CheckEncounter(s.ctx, tc.input)
+
+			s.Nil(output)
+			s.True(errors.IsInvalidArgument(err), "got %v", err)
+		})
+	}
+}
+
+func (s *OrchestratorTestSuite) TestBuildEncounter_FromCatalog() {
+	// A medium fight for four level 1 characters needs 200 to 299 adjusted XP. One CR 1
+	// monster (200 XP) and four CR 1/8 monsters (100 XP, doubled) both land on 200;
+	// the single monster wins.
+	s.expectCatalog(1, "animated-armor", "brown-bear", "dire-wolf")
+
+	output, err := s.orchestrator.BuildEncounter(s.ctx, &encounterbuilder.BuildEncounterInput{
+		Party:      &encounterbuilder.Party{Levels: []int{1, 1, 1, 1}},
+		Difficulty: encounterbuilder.DifficultyMedium,
+		CampaignID: "camp-1",
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]*encounter.MonsterGroup{{MonsterID: "animated-armor", Count: 1}}, output.Monsters)
+	s.Equal([]string{"brown-bear", "dire-wolf"}, output.Alternatives)
+	s.Equal(200, output.Assessment.AdjustedXP)
+	s.Equal(encounterbuilder.DifficultyMedium, output.Assessment.Difficulty)
+
+	create := output.CreateEncounter
+	s.Equal("camp-1", create.CampaignID)
+	s.Equal(spatial.GridTypeSquare, create.GridType)
+	s.Equal(output.Monsters, create.Monsters)
+	s.NotNil(create.MonsterSpawn)
+	s.Empty(create.CharacterIDs, "a party given by level has no characters to place")
+}
+
+func (s *OrchestratorTestSuite) TestBuildEncounter_SkipsRatingsWithoutMonsters() {
+	s.expectCatalog(1)
+	s.expectCatalog(0.125, "bandit", "kobold")
+
+	output, err := s.orchestrator.BuildEncounter(s.ctx, &encounterbuilder.BuildEncounterInput{
+		Party:      &encounterbuilder.Party{Levels: []int{1, 1, 1, 1}},
+		Difficulty: encounterbuilder.DifficultyMedium,
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]*encounter.MonsterGroup{{MonsterID: "bandit", Count: 4}}, output.Monsters)
+	s.Equal(100, output.Assessment.TotalXP)
+	s.Equal(2.0, output.Assessment.Multiplier)
+}
+
+func (s *OrchestratorTestSuite) TestBuildEncounter_FromChosenMonsters() {
+	s.expectCharacter("hero", 1)
+	s.expectCharacter("mage", 1)
+	s.expectCharacter("rogue", 1)
+	s.expectCharacter("cleric", 1)
+	s.expectMonster("goblin", 0.25, 50)
+	s.expectMonster("bugbear", 1, 200)
+
+	output, err := s.orchestrator.BuildEncounter(s.ctx, &encounterbuilder.BuildEncounterInput{
+		Party:       &encounterbuilder.Party{CharacterIDs: []string{"hero", "mage", "rogue", "cleric"}},
+		Difficulty:  encounterbuilder.DifficultyHard,
+		MonsterIDs:  []string{"goblin", "bugbear"},
+		MaxMonsters: 4,
+	})
+
+	s.Require().NoError(err)
+	s.Equal([]*encounter.MonsterGroup{{MonsterID: "goblin", Count: 3}}, output.Monsters,
+		"three goblins are worth exactly the hard threshold of 300")
+	s.Empty(output.Alternatives)
+	s.Equal(encounterbuilder.DifficultyHard, output.Assessment.Difficulty)
+
+	create := output.CreateEncounter
+	s.Equal([]string{"hero", "mage", "rogue", "cleric"}, create.CharacterIDs)
+	s.Require().NotNil(create.CharacterSpawn)
+	s.Require().NotNil(create.MonsterSpawn)
+}
+
+func (s *OrchestratorTestSuite) TestBuildEncounter_NothingFits() {
+	s.expectMonster("tarrasque", 30, 155000)
+
+	output, err := s.orchestrator.BuildEncounter(s.ctx, &encounterbuilder.BuildEncounterInput{
+		Party:      &encounterbuilder.Party{Levels: []int{1}},
+		Difficulty: encounterbuilder.DifficultyEasy,
+		MonsterIDs: []string{"tarrasque"},
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *OrchestratorTestSuite) TestBuildEncounter_InvalidInput() {
+	testCases := []struct {
+		name  string
+		input *encounterbuilder.BuildEncounterInput
+	}{
+		{name: "nil input"},
+		{
+			name: "unknown difficulty",
+			input: &encounterbuilder.BuildEncounterInput{
+				Party:      &encounterbuilder.Party{Levels: []int{1}},
+				Difficulty: encounterbuilder.DifficultyTrivial,
+			},
+		},
+		{
+			name: "too many monsters",
+			input: &encounterbuilder.BuildEncounterInput{
+				Party:       &encounterbuilder.Party{Levels: []int{1}},
+				Difficulty:  encounterbuilder.DifficultyEasy,
+				MaxMonsters: 21,
+			},
+		},
+		{
+			name: "party too large",
+			input: &encounterbuilder.BuildEncounterInput{
+				Party:      &encounterbuilder.Party{Levels: []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+				Difficulty: encounterbuilder.DifficultyEasy,
+			},
+		},
+		{
+			name: "blank monster ID",
+			input: &encounterbuilder.BuildEncounterInput{
+				Party:      &encounterbuilder.Party{Levels: []int{1}},
+				Difficulty: encounterbuilder.DifficultyEasy,
+				MonsterIDs: []string{""},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			output, err := s.orchestrator.BuildEncounter(s.ctx, tc.input)
+
+			s.Nil(output)
+			s.True(errors.IsInvalidArgument(err), "got %v", err)
+		})
+	}
+}
+
+func TestOrchestratorTestSuite(t *testing.T) {
+	suite.Run(t, new(OrchestratorTestSuite))
+}
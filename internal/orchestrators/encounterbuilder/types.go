@@ -0,0 +1,78 @@
+package encounterbuilder
+
+import (
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/encounter"
+)
+
+// Encounter difficulties, from the 5e XP thresholds
+const (
+	DifficultyTrivial = "trivial" // Below the party's easy threshold
+	DifficultyEasy    = "easy"
+	DifficultyMedium  = "medium"
+	DifficultyHard    = "hard"
+	DifficultyDeadly  = "deadly"
+)
+
+// Party identifies who the encounter is for, either by character or by level
+type Party struct {
+	CharacterIDs []string // Levels are read from the characters
+	Levels       []int    // Used when there are no characters yet, one level per member
+}
+
+// CheckEncounterInput defines the request for rating a planned encounter
+type CheckEncounterInput struct {
+	Party    *Party
+	Monsters []*encounter.MonsterGroup
+}
+
+// CheckEncounterOutput defines the response for rating a planned encounter
+type CheckEncounterOutput struct {
+	Assessment *Assessment
+}
+
+// BuildEncounterInput defines the request for building an encounter of a target difficulty
+type BuildEncounterInput struct {
+	Party      *Party
+	Difficulty string // DifficultyEasy, DifficultyMedium, DifficultyHard or DifficultyDeadly
+
+	// MonsterIDs optionally limits the fight to these SRD monsters; by default any
+	// monster from the SRD catalog may be picked
+	MonsterIDs  []string
+	MaxMonsters int // Optional, defaults to defaultMaxMonsters
+
+	CampaignID string // Optional, copied to the CreateEncounter request
+	SessionID  string // Optional, copied to the CreateEncounter request
+}
+
+// BuildEncounterOutput defines the response for building an encounter
+type BuildEncounterOutput struct {
+	Monsters   []*encounter.MonsterGroup
+	Assessment *Assessment
+
+	// Alternatives are other SRD monsters with the same challenge rating, which can be
+	// swapped in without changing the difficulty
+	Alternatives []string
+
+	// CreateEncounter is ready to pass to the encounter service
+	CreateEncounter *encounter.CreateEncounterInput
+}
+
+// Assessment rates an encounter against a party using the 5e encounter building rules
+type Assessment struct {
+	PartySize  int
+	Thresholds *Thresholds // The party's XP thresholds, summed over its members
+
+	MonsterCount int
+	TotalXP      int     // The monsters' XP, as awarded to the party
+	Multiplier   float64 // The multiple-monster multiplier, adjusted for party size
+	AdjustedXP   int     // TotalXP times Multiplier, compared against the thresholds
+	Difficulty   string
+}
+
+// Thresholds holds the XP an encounter must reach for each difficulty
+type Thresholds struct {
+	Easy   int
+	Medium int
+	Hard   int
+	Deadly int
+}
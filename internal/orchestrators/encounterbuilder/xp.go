@@ -0,0 +1,130 @@
+package encounterbuilder
+
+import "math"
+
+// thresholdsByLevel holds each character level's XP thresholds, from the DMG
+var thresholdsByLevel = [...]Thresholds{
+	{}, // No level 0
+	{Easy: 25, Medium: 50, Hard: 75, Deadly: 100},
+	{Easy: 50, Medium: 100, Hard: 150, Deadly: 200},
+	{Easy: 75, Medium: 150, Hard: 225, Deadly: 400},
+	{Easy: 125, Medium: 250, Hard: 375, Deadly: 500},
+	{Easy: 250, Medium: 500, Hard: 750, Deadly: 1100},
+	{Easy: 300, Medium: 600, Hard: 900, Deadly: 1400},
+	{Easy: 350, Medium: 750, Hard: 1100, Deadly: 1700},
+	{Easy: 450, Medium: 900, Hard: 1400, Deadly: 2100},
+	{Easy: 550, Medium: 1100, Hard: 1600, Deadly: 2400},
+	{Easy: 600, Medium: 1200, Hard: 1900, Deadly: 2800},
+	{Easy: 800, Medium: 1600, Hard: 2400, Deadly: 3600},
+	{Easy: 1000, Medium: 2000, Hard: 3000, Deadly: 4500},
+	{Easy: 1100, Medium: 2200, Hard: 3400, Deadly: 5100},
+	{Easy: 1250, Medium: 2500, Hard: 3800, Deadly: 5700},
+	{Easy: 1400, Medium: 2800, Hard: 4300, Deadly: 6400},
+	{Easy: 1600, Medium: 3200, Hard: 4800, Deadly: 7200},
+	{Easy: 2000, Medium: 3900, Hard: 5900, Deadly: 8800},
+	{Easy: 2100, Medium: 4200, Hard: 6300, Deadly: 9500},
+	{Easy: 2400, Medium: 4900, Hard: 7300, Deadly: 10900},
+	{Easy: 2800, Medium: 5700, Hard: 8500, Deadly: 12700},
+}
+
+// maxLevel is the highest character level
+const maxLevel = len(thresholdsByLevel) - 1
+
+// challengeRating pairs a challenge rating with the XP a monster of that rating is worth
+type challengeRating struct {
+	rating float32
+	xp     int
+}
+
+// challengeRatings lists every SRD challenge rating in ascending order
+var challengeRatings = []challengeRating{
+	{0, 10}, {0.125, 25}, {0.25, 50}, {0.5, 100},
+	{1, 200}, {2, 450}, {3, 700}, {4, 1100}, {5, 1800},
+	{6, 2300}, {7, 2900}, {8, 3900}, {9, 5000}, {10, 5900},
+	{11, 7200}, {12, 8400}, {13, 10000}, {14, 11500}, {15, 13000},
+	{16, 15000}, {17, 18000}, {18, 20000}, {19, 22000}, {20, 25000},
+	{21, 33000}, {22, 41000}, {23, 50000}, {24, 62000}, {25, 75000},
+	{26, 90000}, {27, 105000}, {28, 120000}, {29, 135000}, {30, 155000},
+}
+
+// multipliers is the multiple-monster multiplier ladder. The DMG uses the middle six
+// steps and moves one step either way for small or large parties.
+var multipliers = []float64{0.5, 1, 1.5, 2, 2.5, 3, 4, 5}
+
+// partyThresholds sums each member's thresholds
+func partyThresholds(levels []int) *Thresholds {
+	total := &Thresholds{}
+	for _, level := range levels {
+		thresholds := thresholdsByLevel[level]
+		total.Easy += thresholds.Easy
+		total.Medium += thresholds.Medium
+		total.Hard += thresholds.Hard
+		total.Deadly += thresholds.Deadly
+	}
+	return total
+}
+
+// multiplier returns the multiple-monster multiplier for a fight: more monsters make a
+// fight harder than their XP suggests. Parties of fewer than three use the next higher
+// multiplier, and parties of six or more the next lower.
+func multiplier(monsterCount, partySize int) float64 {
+	var step int
+	switch {
+	case monsterCount <= 1:
+		step = 1
+	case monsterCount == 2:
+		step = 2
+	case monsterCount <= 6:
+		step = 3
+	case monsterCount <= 10:
+		step = 4
+	case monsterCount <= 14:
+		step = 5
+	default:
+		step = 6
+	}
+
+	switch {
+	case partySize < 3:
+		step++
+	case partySize >= 6:
+		step--
+	}
+	return multipliers[step]
+}
+
+// adjustedXP applies the multiple-monster multiplier to the monsters' total XP
+func adjustedXP(totalXP, monsterCount, partySize int) int {
+	return int(math.Round(float64(totalXP) * multiplier(monsterCount, partySize)))
+}
+
+// difficultyOf rates adjusted XP against a party's thresholds
+func difficultyOf(adjusted int, thresholds *Thresholds) string {
+	switch {
+	case adjusted >= thresholds.Deadly:
+		return DifficultyDeadly
+	case adjusted >= thresholds.Hard:
+		return DifficultyHard
+	case adjusted >= thresholds.Medium:
+		return DifficultyMedium
+	case adjusted >= thresholds.Easy:
+		return DifficultyEasy
+	default:
+		return DifficultyTrivial
+	}
+}
+
+// xpBand returns the adjusted XP range, from inclusive to exclusive, that rates as the
+// difficulty. A deadly fight has no upper bound.
+func xpBand(difficulty string, thresholds *Thresholds) (int, int) {
+	switch difficulty {
+	case DifficultyEasy:
+		return thresholds.Easy, thresholds.Medium
+	case DifficultyMedium:
+		return thresholds.Medium, thresholds.Hard
+	case DifficultyHard:
+		return thresholds.Hard, thresholds.Deadly
+	default:
+		return thresholds.Deadly, math.MaxInt
+	}
+}
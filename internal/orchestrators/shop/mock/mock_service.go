@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/KirkDiggler/rpg-api/internal/orchestrators/shop (interfaces: Service)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/mock_service.go -package=shopmock github.com/KirkDiggler/rpg-api/internal/orchestrators/shop Service
+//
+
+// Package shopmock is a generated GoMock package.
+package shopmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+
+	shop "github.com/KirkDiggler/rpg-api/internal/orchestrators/shop"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// BuyItem mocks base method.
+func (m *MockService) BuyItem(ctx context.Context, input *shop.BuyItemInput) (*shop.BuyItemOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuyItem", ctx, input)
+	ret0, _ := ret[0].(*shop.BuyItemOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuyItem indicates an expected call of BuyItem.
+func (mr *MockServiceMockRecorder) BuyItem(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItem", reflect.TypeOf((*MockService)(nil).BuyItem), ctx, input)
+}
+
+// GetMerchantInventory mocks base method.
+func (m *MockService) GetMerchantInventory(ctx context.Context, input *shop.GetMerchantInventoryInput) (*shop.GetMerchantInventoryOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerchantInventory", ctx, input)
+	ret0, _ := ret[0].(*shop.GetMerchantInventoryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerchantInventory indicates an expected call of GetMerchantInventory.
+func (mr *MockServiceMockRecorder) GetMerchantInventory(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerchantInventory", reflect.TypeOf((*MockService)(nil).GetMerchantInventory), ctx, input)
+}
+
+// ListMerchants mocks base method.
+func (m *MockService) ListMerchants(ctx context.Context, input *shop.ListMerchantsInput) (*shop.ListMerchantsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMerchants", ctx, input)
+	ret0, _ := ret[0].(*shop.ListMerchantsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMerchants indicates an expected call of ListMerchants.
+func (mr *MockServiceMockRecorder) ListMerchants(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMerchants", reflect.TypeOf((*MockService)(nil).ListMerchants), ctx, input)
+}
+
+// SellItem mocks base method.
+func (m *MockService) SellItem(ctx context.Context, input *shop.SellItemInput) (*shop.SellItemOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SellItem", ctx, input)
+	ret0, _ := ret[0].(*shop.SellItemOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SellItem indicates an expected call of SellItem.
+func (mr *MockServiceMockRecorder) SellItem(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SellItem", reflect.TypeOf((*MockService)(nil).SellItem), ctx, input)
+}
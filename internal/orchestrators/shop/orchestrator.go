@@ -0,0 +1,458 @@
+// Package shop implements the merchant orchestrator for buying and selling equipment
+package shop
+
+//go:generate mockgen -destination=mock/mock_service.go -package=shopmock github.com/KirkDiggler/rpg-api/internal/orchestrators/shop Service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+)
+
+const (
+	// DefaultBuyMarkup is applied when a merchant does not configure a buy markup
+	DefaultBuyMarkup = 1.0
+
+	// DefaultSellRate is applied when a merchant does not configure a sell rate
+	DefaultSellRate = 0.5
+
+	// MaxQuantity is the most of one item a single purchase or sale can trade
+	MaxQuantity = 1000
+
+	// maxTradeAttempts bounds how often a purchase or sale starts over when the character
+	// is changed by someone else between reading and saving it
+	maxTradeAttempts = 3
+)
+
+// Copper value of each coin denomination
+var coinValues = map[string]int64{
+	"cp": 1,
+	"sp": dnd5e.CopperPerSilver,
+	"ep": dnd5e.CopperPerElectrum,
+	"gp": dnd5e.CopperPerGold,
+	"pp": dnd5e.CopperPerPlatinum,
+}
+
+// Service defines the interface for merchant operations
+type Service interface {
+	// ListMerchants returns all configured merchants
+	ListMerchants(ctx context.Context, input *ListMerchantsInput) (*ListMerchantsOutput, error)
+
+	// GetMerchantInventory returns the equipment a merchant stocks with buy and sell prices
+	GetMerchantInventory(ctx context.Context, input *GetMerchantInventoryInput) (*GetMerchantInventoryOutput, error)
+
+	// BuyItem debits the character's wallet and adds the item to their equipment
+	BuyItem(ctx context.Context, input *BuyItemInput) (*BuyItemOutput, error)
+
+	// SellItem removes the item from the character's equipment and credits their wallet
+	SellItem(ctx context.Context, input *SellItemInput) (*SellItemOutput, error)
+}
+
+// Config holds the dependencies for the shop orchestrator
+type Config struct {
+	CharacterRepo  characterrepo.Repository
+	ExternalClient external.Client
+	Merchants      []*Merchant
+}
+
+// Validate ensures all required dependencies are provided
+func (c *Config) Validate() error {
+	vb := errors.NewValidationBuilder()
+
+	if c.CharacterRepo == nil {
+		vb.RequiredField("CharacterRepo")
+	}
+	if c.ExternalClient == nil {
+		vb.RequiredField("ExternalClient")
+	}
+
+	seen := make(map[string]bool)
+	for _, m := range c.Merchants {
+		if m == nil || m.ID == "" {
+			vb.RequiredField("Merchants.ID")
+			continue
+		}
+		if seen[m.ID] {
+			vb.Fieldf("Merchants", "duplicate merchant ID %s", m.ID)
+		}
+		seen[m.ID] = true
+		if m.BuyMarkup < 0 || (m.SellRate != nil && *m.SellRate < 0) {
+			vb.Fieldf("Merchants", "merchant %s cannot have negative rates", m.ID)
+		}
+	}
+
+	return vb.Build()
+}
+
+type orchestrator struct {
+	charRepo       characterrepo.Repository
+	externalClient external.Client
+	merchants      []*Merchant
+	merchantsByID  map[string]*Merchant
+}
+
+// NewOrchestrator creates a new shop orchestrator with the provided dependencies
+func NewOrchestrator(cfg *Config) (Service, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid config")
+	}
+
+	merchants := make([]*Merchant, 0, len(cfg.Merchants))
+	merchantsByID := make(map[string]*Merchant, len(cfg.Merchants))
+	for _, m := range cfg.Merchants {
+		merchant := *m
+		if merchant.BuyMarkup == 0 {
+			merchant.BuyMarkup = DefaultBuyMarkup
+		}
+		if merchant.SellRate == nil {
+			sellRate := DefaultSellRate
+			merchant.SellRate = &sellRate
+		}
+		merchants = append(merchants, &merchant)
+		merchantsByID[merchant.ID] = &merchant
+	}
+
+	return &orchestrator{
+		charRepo:       cfg.CharacterRepo,
+		externalClient: cfg.ExternalClient,
+		merchants:      merchants,
+		merchantsByID:  merchantsByID,
+	}, nil
+}
+
+// ListMerchants returns all configured merchants
+func (o *orchestrator) ListMerchants(_ context.Context, _ *ListMerchantsInput) (*ListMerchantsOutput, error) {
+	return &ListMerchantsOutput{Merchants: o.merchants}, nil
+}
+
+// GetMerchantInventory returns the equipment a merchant stocks with buy and sell prices
+func (o *orchestrator) GetMerchantInventory(
+	ctx context.Context,
+	input *GetMerchantInventoryInput,
+) (*GetMerchantInventoryOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	merchant, err := o.getMerchant(input.MerchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	equipment, err := o.externalClient.ListAvailableEquipment(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list equipment")
+	}
+
+	items := make([]*MerchantItem, 0)
+	for _, eq := range equipment {
+		if eq == nil || !merchantStocks(merchant, eq) {
+			continue
+		}
+		// Items without a price can't be traded
+		base := costInCopper(eq.Cost)
+		if base <= 0 {
+			continue
+		}
+		// Prices too large to charge are left out
+		buy, err := buyPrice(merchant, base, 1)
+		if err != nil {
+			continue
+		}
+		sell, err := sellPrice(merchant, base, 1)
+		if err != nil {
+			continue
+		}
+		items = append(items, &MerchantItem{
+			Equipment: eq,
+			BuyPrice:  buy,
+			SellPrice: sell,
+		})
+	}
+
+	return &GetMerchantInventoryOutput{
+		Merchant: merchant,
+		Items:    items,
+	}, nil
+}
+
+// BuyItem debits the character's wallet and adds the item to their equipment
+func (o *orchestrator) BuyItem(ctx context.Context, input *BuyItemInput) (*BuyItemOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.ItemID == "" {
+		return nil, errors.InvalidArgument("item ID is required")
+	}
+	quantity, err := normalizeQuantity(input.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	merchant, err := o.getMerchant(input.MerchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	equipment, err := o.externalClient.GetEquipmentData(ctx, input.ItemID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get equipment %s", input.ItemID)
+	}
+	if equipment == nil || !merchantStocks(merchant, equipment) {
+		return nil, errors.NotFoundf("merchant %s does not stock %s", merchant.ID, input.ItemID)
+	}
+
+	base := costInCopper(equipment.Cost)
+	if base <= 0 {
+		return nil, errors.FailedPreconditionf("item %s has no price", input.ItemID)
+	}
+	total, err := buyPrice(merchant, base, quantity)
+	if err != nil {
+		return nil, err
+	}
+	if total <= 0 {
+		return nil, errors.FailedPreconditionf("item %s has no price", input.ItemID)
+	}
+
+	// Wallet and equipment are written together so a purchase is all or nothing
+	updateOutput, err := o.trade(ctx, input.CharacterID, dnd5e.CharacterEventItemBought,
+		func(charData *toolkitchar.Data, sheet *dnd5e.CharacterSheet) error {
+			wallet, ok := sheet.Wallet.Spend(int64(total))
+			if !ok {
+				return errors.FailedPreconditionf(
+					"insufficient funds: need %d cp, have %d cp", total, sheet.Wallet.TotalCopper())
+			}
+			for range quantity {
+				charData.Equipment = append(charData.Equipment, input.ItemID)
+			}
+			sheet.Wallet = wallet
+			return nil
+		})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to save purchase")
+	}
+
+	slog.InfoContext(ctx, "character bought item",
+		"character_id", input.CharacterID,
+		"merchant_id", merchant.ID,
+		"item_id", input.ItemID,
+		"quantity", quantity,
+		"cost_cp", total)
+
+	return &BuyItemOutput{
+		Character: updateOutput.CharacterData,
+		Wallet:    updateOutput.Sheet.Wallet,
+		TotalCost: total,
+	}, nil
+}
+
+// SellItem removes the item from the character's equipment and credits their wallet
+func (o *orchestrator) SellItem(ctx context.Context, input *SellItemInput) (*SellItemOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+	if input.ItemID == "" {
+		return nil, errors.InvalidArgument("item ID is required")
+	}
+	quantity, err := normalizeQuantity(input.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	merchant, err := o.getMerchant(input.MerchantID)
+	if err != nil {
+		return nil, err
+	}
+	if *merchant.SellRate == 0 {
+		return nil, errors.FailedPreconditionf("merchant %s does not buy items", merchant.ID)
+	}
+
+	equipment, err := o.externalClient.GetEquipmentData(ctx, input.ItemID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get equipment %s", input.ItemID)
+	}
+	if equipment == nil {
+		return nil, errors.NotFoundf("equipment %s not found", input.ItemID)
+	}
+
+	base := costInCopper(equipment.Cost)
+	if base <= 0 {
+		return nil, errors.FailedPreconditionf("item %s has no price", input.ItemID)
+	}
+	total, err := sellPrice(merchant, base, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wallet and equipment are written together so a sale is all or nothing
+	updateOutput, err := o.trade(ctx, input.CharacterID, dnd5e.CharacterEventItemSold,
+		func(charData *toolkitchar.Data, sheet *dnd5e.CharacterSheet) error {
+			remaining, removed := dnd5e.RemoveItems(charData.Equipment, input.ItemID, quantity)
+			if removed < quantity {
+				return errors.FailedPreconditionf(
+					"character has %d of %s, cannot sell %d", removed, input.ItemID, quantity)
+			}
+			charData.Equipment = remaining
+			releaseItem(sheet, input.ItemID, dnd5e.CountItem(remaining, input.ItemID))
+			sheet.Wallet = sheet.Wallet.Receive(int64(total))
+			return nil
+		})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to save sale")
+	}
+
+	slog.InfoContext(ctx, "character sold item",
+		"character_id", input.CharacterID,
+		"merchant_id", merchant.ID,
+		"item_id", input.ItemID,
+		"quantity", quantity,
+		"received_cp", total)
+
+	return &SellItemOutput{
+		Character:     updateOutput.CharacterData,
+		Wallet:        updateOutput.Sheet.Wallet,
+		TotalReceived: total,
+	}, nil
+}
+
+// trade reads a character, lets apply change its equipment and sheet, and saves both at
+// the revision it read. If the character was saved by someone else in between, the trade
+// starts over from a fresh read so it never overwrites that change.
+func (o *orchestrator) trade(
+	ctx context.Context,
+	characterID, eventType string,
+	apply func(charData *toolkitchar.Data, sheet *dnd5e.CharacterSheet) error,
+) (*characterrepo.UpdateOutput, error) {
+	for range maxTradeAttempts {
+		getOutput, err := o.charRepo.Get(ctx, characterrepo.GetInput{ID: characterID})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get character")
+		}
+
+		sheet := dnd5e.SheetOrEmpty(getOutput.Sheet)
+		if err := apply(getOutput.CharacterData, sheet); err != nil {
+			return nil, err
+		}
+
+		updateOutput, err := o.charRepo.Update(ctx, characterrepo.UpdateInput{
+			CharacterData:    getOutput.CharacterData,
+			Sheet:            sheet,
+			EventType:        eventType,
+			ExpectedRevision: &getOutput.Revision,
+		})
+		if errors.IsAborted(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return updateOutput, nil
+	}
+	return nil, errors.Abortedf("character %s kept changing, try again", characterID)
+}
+
+func (o *orchestrator) getMerchant(id string) (*Merchant, error) {
+	if id == "" {
+		return nil, errors.InvalidArgument("merchant ID is required")
+	}
+	merchant, ok := o.merchantsByID[id]
+	if !ok {
+		return nil, errors.NotFoundf("merchant %s not found", id)
+	}
+	return merchant, nil
+}
+
+// merchantStocks reports whether the merchant sells the given equipment
+func merchantStocks(merchant *Merchant, equipment *external.EquipmentData) bool {
+	if len(merchant.Categories) == 0 && len(merchant.ItemIDs) == 0 {
+		return true
+	}
+	if slices.Contains(merchant.Categories, equipment.Category) {
+		return true
+	}
+	for _, id := range merchant.ItemIDs {
+		if strings.EqualFold(id, equipment.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeQuantity(quantity int32) (int32, error) {
+	if quantity < 0 {
+		return 0, errors.InvalidArgument("quantity cannot be negative")
+	}
+	if quantity > MaxQuantity {
+		return 0, errors.InvalidArgumentf("quantity cannot be more than %d", MaxQuantity)
+	}
+	if quantity == 0 {
+		return 1, nil
+	}
+	return quantity, nil
+}
+
+// costInCopper converts an equipment cost to copper pieces
+func costInCopper(cost *external.CostData) int64 {
+	if cost == nil {
+		return 0
+	}
+	value, ok := coinValues[strings.ToLower(cost.Unit)]
+	if !ok {
+		return 0
+	}
+	return int64(cost.Quantity) * value
+}
+
+// buyPrice rounds up so fractional markups never undercharge
+func buyPrice(merchant *Merchant, base int64, quantity int32) (int32, error) {
+	return toPrice(math.Ceil(float64(base) * merchant.BuyMarkup * float64(quantity)))
+}
+
+// sellPrice rounds down so fractional rates never overpay
+func sellPrice(merchant *Merchant, base int64, quantity int32) (int32, error) {
+	return toPrice(math.Floor(float64(base) * *merchant.SellRate * float64(quantity)))
+}
+
+// toPrice converts a computed price in copper to the int32 prices are reported in,
+// refusing one too large to hold rather than letting the conversion wrap
+func toPrice(copper float64) (int32, error) {
+	if copper > math.MaxInt32 {
+		return 0, errors.InvalidArgument("price is too large to trade")
+	}
+	return int32(copper), nil //nolint:gosec // checked against math.MaxInt32 above
+}
+
+// releaseItem unequips sold copies of an item and ends attunement once none are left
+func releaseItem(sheet *dnd5e.CharacterSheet, itemID string, remaining int) {
+	slots := make([]string, 0)
+	for slot, equippedID := range sheet.EquippedSlots {
+		if equippedID == itemID {
+			slots = append(slots, slot)
+		}
+	}
+	slices.Sort(slots)
+	for len(slots) > remaining {
+		delete(sheet.EquippedSlots, slots[len(slots)-1])
+		slots = slots[:len(slots)-1]
+	}
+
+	if remaining == 0 {
+		sheet.AttunedItems = slices.DeleteFunc(sheet.AttunedItems, func(id string) bool {
+			return id == itemID
+		})
+	}
+}
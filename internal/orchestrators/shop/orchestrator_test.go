@@ -0,0 +1,385 @@
+package shop_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	externalmock "github.com/KirkDiggler/rpg-api/internal/clients/external/mock"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/orchestrators/shop"
+	characterrepo "github.com/KirkDiggler/rpg-api/internal/repositories/character"
+	characterrepomock "github.com/KirkDiggler/rpg-api/internal/repositories/character/mock"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+)
+
+type OrchestratorTestSuite struct {
+	suite.Suite
+	ctrl         *gomock.Controller
+	mockCharRepo *characterrepomock.MockRepository
+	mockExternal *externalmock.MockClient
+	orchestrator shop.Service
+	ctx          context.Context
+
+	longsword *external.EquipmentData
+	dagger    *external.EquipmentData
+	rope      *external.EquipmentData
+}
+
+func (s *OrchestratorTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockCharRepo = characterrepomock.NewMockRepository(s.ctrl)
+	s.mockExternal = externalmock.NewMockClient(s.ctrl)
+	s.ctx = context.Background()
+
+	orchestrator, err := shop.NewOrchestrator(&shop.Config{
+		CharacterRepo:  s.mockCharRepo,
+		ExternalClient: s.mockExternal,
+		Merchants: []*shop.Merchant{
+			{
+				ID:         "blacksmith",
+				Name:       "Blacksmith",
+				Categories: []string{"weapon"},
+				BuyMarkup:  1.5,
+			},
+			{
+				ID:   "general-store",
+				Name: "General Store",
+			},
+		},
+	})
+	s.Require().NoError(err)
+	s.orchestrator = orchestrator
+
+	s.longsword = &external.EquipmentData{
+		ID:       "EQUIPMENT_LONGSWORD",
+		Name:     "Longsword",
+		Category: "weapon",
+		Cost:     &external.CostData{Quantity: 15, Unit: "gp"},
+	}
+	s.dagger = &external.EquipmentData{
+		ID:       "EQUIPMENT_DAGGER",
+		Name:     "Dagger",
+		Category: "weapon",
+		Cost:     &external.CostData{Quantity: 2, Unit: "gp"},
+	}
+	s.rope = &external.EquipmentData{
+		ID:       "EQUIPMENT_ROPE_HEMPEN_50_FEET",
+		Name:     "Rope, hempen (50 feet)",
+		Category: "adventuring-gear",
+		Cost:     &external.CostData{Quantity: 1, Unit: "gp"},
+	}
+}
+
+func (s *OrchestratorTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *OrchestratorTestSuite) TestNewOrchestrator_DuplicateMerchant() {
+	_, err := shop.NewOrchestrator(&shop.Config{
+		CharacterRepo:  s.mockCharRepo,
+		ExternalClient: s.mockExternal,
+		Merchants: []*shop.Merchant{
+			{ID: "store"},
+			{ID: "store"},
+		},
+	})
+
+	s.Error(err)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *OrchestratorTestSuite) TestListMerchants_AppliesDefaults() {
+	output, err := s.orchestrator.ListMerchants(s.ctx, &shop.ListMerchantsInput{})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.Merchants, 2)
+	s.Equal(1.5, output.Merchants[0].BuyMarkup)
+	s.Require().NotNil(output.Merchants[0].SellRate)
+	s.Equal(shop.DefaultSellRate, *output.Merchants[0].SellRate)
+	s.Equal(shop.DefaultBuyMarkup, output.Merchants[1].BuyMarkup)
+}
+
+func (s *OrchestratorTestSuite) TestGetMerchantInventory_FiltersByCategory() {
+	s.mockExternal.EXPECT().
+		ListAvailableEquipment(s.ctx).
+		Return([]*external.EquipmentData{s.longsword, s.rope, s.dagger}, nil)
+
+	output, err := s.orchestrator.GetMerchantInventory(s.ctx, &shop.GetMerchantInventoryInput{
+		MerchantID: "blacksmith",
+	})
+
+	s.Require().NoError(err)
+	s.Require().Len(output.Items, 2)
+	s.Equal("EQUIPMENT_LONGSWORD", output.Items[0].Equipment.ID)
+	s.Equal(int32(2250), output.Items[0].BuyPrice)
+	s.Equal(int32(750), output.Items[0].SellPrice)
+}
+
+func (s *OrchestratorTestSuite) TestGetMerchantInventory_UnknownMerchant() {
+	output, err := s.orchestrator.GetMerchantInventory(s.ctx, &shop.GetMerchantInventoryInput{
+		MerchantID: "nope",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsNotFound(err))
+}
+
+func (s *OrchestratorTestSuite) TestBuyItem_DebitsWalletAndAddsEquipment() {
+	charData := &toolkitchar.Data{ID: "char-1", Equipment: []string{"EQUIPMENT_DAGGER"}}
+
+	s.mockExternal.EXPECT().
+		GetEquipmentData(s.ctx, "EQUIPMENT_LONGSWORD").
+		Return(s.longsword, nil)
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: "char-1"}).
+		Return(&characterrepo.GetOutput{
+			CharacterData: charData,
+			Sheet: &dnd5e.CharacterSheet{
+				Wallet: &dnd5e.Wallet{Platinum: 2, Gold: 5, Silver: 3},
+			},
+		}, nil)
+	s.mockCharRepo.EXPECT().
+		Update(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+			// Both the equipment and the wallet change in a single update
+			s.Equal([]string{"EQUIPMENT_DAGGER", "EQUIPMENT_LONGSWORD"}, input.CharacterData.Equipment)
+			s.Require().NotNil(input.Sheet)
+			s.Equal(&dnd5e.Wallet{Gold: 2, Silver: 8}, input.Sheet.Wallet)
+			return &characterrepo.UpdateOutput{
+				CharacterData: input.CharacterData,
+				Sheet:         input.Sheet,
+			}, nil
+		})
+
+	output, err := s.orchestrator.BuyItem(s.ctx, &shop.BuyItemInput{
+		CharacterID: "char-1",
+		MerchantID:  "blacksmith",
+		ItemID:      "EQUIPMENT_LONGSWORD",
+	})
+
+	s.Require().NoError(err)
+	s.Equal(int32(2250), output.TotalCost)
+	s.Equal(int32(2), output.Wallet.Gold)
+}
+
+func (s *OrchestratorTestSuite) TestBuyItem_KeepsPlatinumAndElectrumWhenSmallerCoinsCover() {
+	s.mockExternal.EXPECT().
+		GetEquipmentData(s.ctx, "EQUIPMENT_LONGSWORD").
+		Return(s.longsword, nil)
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: "char-1"}).
+		Return(&characterrepo.GetOutput{
+			CharacterData: &toolkitchar.Data{ID: "char-1"},
+			Sheet: &dnd5e.CharacterSheet{
+				Wallet: &dnd5e.Wallet{Platinum: 3, Electrum: 2, Gold: 30, Copper: 7},
+			},
+		}, nil)
+	s.mockCharRepo.EXPECT().
+		Update(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+			// 22 gp 50 cp is paid from the gold, breaking one gold piece for the copper
+			s.Equal(&dnd5e.Wallet{Platinum: 3, Electrum: 2, Gold: 7, Silver: 5, Copper: 7}, input.Sheet.Wallet)
+			return &characterrepo.UpdateOutput{CharacterData: input.CharacterData, Sheet: input.Sheet}, nil
+		})
+
+	output, err := s.orchestrator.BuyItem(s.ctx, &shop.BuyItemInput{
+		CharacterID: "char-1",
+		MerchantID:  "blacksmith",
+		ItemID:      "EQUIPMENT_LONGSWORD",
+	})
+
+	s.Require().NoError(err)
+	s.Equal(int32(3), output.Wallet.Platinum)
+}
+
+func (s *OrchestratorTestSuite) TestBuyItem_StartsOverWhenTheCharacterChanges() {
+	s.mockExternal.EXPECT().
+		GetEquipmentData(s.ctx, "EQUIPMENT_LONGSWORD").
+		Return(s.longsword, nil)
+	gomock.InOrder(
+		s.mockCharRepo.EXPECT().
+			Get(s.ctx, characterrepo.GetInput{ID: "char-1"}).
+			Return(&characterrepo.GetOutput{
+				CharacterData: &toolkitchar.Data{ID: "char-1"},
+				Sheet:         &dnd5e.CharacterSheet{Wallet: &dnd5e.Wallet{Gold: 30}},
+				Revision:      4,
+			}, nil),
+		s.mockCharRepo.EXPECT().
+			Update(s.ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+				s.Equal(int64(4), *input.ExpectedRevision)
+				return nil, errors.Aborted("character changed")
+			}),
+		// Meanwhile another purchase spent most of the gold
+		s.mockCharRepo.EXPECT().
+			Get(s.ctx, characterrepo.GetInput{ID: "char-1"}).
+			Return(&characterrepo.GetOutput{
+				CharacterData: &toolkitchar.Data{ID: "char-1", Equipment: []string{"EQUIPMENT_SHIELD"}},
+				Sheet:         &dnd5e.CharacterSheet{Wallet: &dnd5e.Wallet{Gold: 20}},
+				Revision:      5,
+			}, nil),
+	)
+
+	output, err := s.orchestrator.BuyItem(s.ctx, &shop.BuyItemInput{
+		CharacterID: "char-1",
+		MerchantID:  "blacksmith",
+		ItemID:      "EQUIPMENT_LONGSWORD",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err), "got %v", err)
+}
+
+func (s *OrchestratorTestSuite) TestBuyItem_InsufficientFunds() {
+	s.mockExternal.EXPECT().
+		GetEquipmentData(s.ctx, "EQUIPMENT_LONGSWORD").
+		Return(s.longsword, nil)
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: "char-1"}).
+		Return(&characterrepo.GetOutput{
+			CharacterData: &toolkitchar.Data{ID: "char-1"},
+		}, nil)
+
+	output, err := s.orchestrator.BuyItem(s.ctx, &shop.BuyItemInput{
+		CharacterID: "char-1",
+		MerchantID:  "blacksmith",
+		ItemID:      "EQUIPMENT_LONGSWORD",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *OrchestratorTestSuite) TestBuyItem_NotStocked() {
+	s.mockExternal.EXPECT().
+		GetEquipmentData(s.ctx, "EQUIPMENT_ROPE_HEMPEN_50_FEET").
+		Return(s.rope, nil)
+
+	output, err := s.orchestrator.BuyItem(s.ctx, &shop.BuyItemInput{
+		CharacterID: "char-1",
+		MerchantID:  "blacksmith",
+		ItemID:      "EQUIPMENT_ROPE_HEMPEN_50_FEET",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsNotFound(err))
+}
+
+func (s *OrchestratorTestSuite) TestBuyItem_QuantityOverLimit() {
+	output, err := s.orchestrator.BuyItem(s.ctx, &shop.BuyItemInput{
+		CharacterID: "char-1",
+		MerchantID:  "blacksmith",
+		ItemID:      "EQUIPMENT_LONGSWORD",
+		Quantity:    shop.MaxQuantity + 1,
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *OrchestratorTestSuite) TestBuyItem_PriceTooLarge() {
+	s.mockExternal.EXPECT().
+		GetEquipmentData(s.ctx, "EQUIPMENT_LONGSWORD").
+		Return(&external.EquipmentData{
+			ID:       "EQUIPMENT_LONGSWORD",
+			Category: "weapon",
+			Cost:     &external.CostData{Quantity: 50_000_000, Unit: "gp"},
+		}, nil)
+
+	output, err := s.orchestrator.BuyItem(s.ctx, &shop.BuyItemInput{
+		CharacterID: "char-1",
+		MerchantID:  "blacksmith",
+		ItemID:      "EQUIPMENT_LONGSWORD",
+		Quantity:    shop.MaxQuantity,
+	})
+
+	s.Nil(output)
+	s.True(errors.IsInvalidArgument(err))
+}
+
+func (s *OrchestratorTestSuite) TestSellItem_PaysHalfPrice() {
+	charData := &toolkitchar.Data{
+		ID:        "char-1",
+		Equipment: []string{"EQUIPMENT_DAGGER", "EQUIPMENT_DAGGER", "EQUIPMENT_ROPE_HEMPEN_50_FEET"},
+	}
+
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: "char-1"}).
+		Return(&characterrepo.GetOutput{
+			CharacterData: charData,
+			Sheet:         &dnd5e.CharacterSheet{Wallet: &dnd5e.Wallet{Copper: 5}},
+		}, nil)
+	s.mockExternal.EXPECT().
+		GetEquipmentData(s.ctx, "EQUIPMENT_DAGGER").
+		Return(s.dagger, nil)
+	s.mockCharRepo.EXPECT().
+		Update(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, input characterrepo.UpdateInput) (*characterrepo.UpdateOutput, error) {
+			s.Equal([]string{"EQUIPMENT_DAGGER", "EQUIPMENT_ROPE_HEMPEN_50_FEET"}, input.CharacterData.Equipment)
+			s.Equal(&dnd5e.Wallet{Gold: 1, Copper: 5}, input.Sheet.Wallet)
+			return &characterrepo.UpdateOutput{
+				CharacterData: input.CharacterData,
+				Sheet:         input.Sheet,
+			}, nil
+		})
+
+	output, err := s.orchestrator.SellItem(s.ctx, &shop.SellItemInput{
+		CharacterID: "char-1",
+		MerchantID:  "general-store",
+		ItemID:      "EQUIPMENT_DAGGER",
+	})
+
+	s.Require().NoError(err)
+	s.Equal(int32(100), output.TotalReceived)
+}
+
+func (s *OrchestratorTestSuite) TestSellItem_NotOwned() {
+	s.mockExternal.EXPECT().
+		GetEquipmentData(s.ctx, "EQUIPMENT_DAGGER").
+		Return(s.dagger, nil)
+	s.mockCharRepo.EXPECT().
+		Get(s.ctx, characterrepo.GetInput{ID: "char-1"}).
+		Return(&characterrepo.GetOutput{
+			CharacterData: &toolkitchar.Data{ID: "char-1", Equipment: []string{"EQUIPMENT_DAGGER"}},
+		}, nil)
+
+	output, err := s.orchestrator.SellItem(s.ctx, &shop.SellItemInput{
+		CharacterID: "char-1",
+		MerchantID:  "general-store",
+		ItemID:      "EQUIPMENT_DAGGER",
+		Quantity:    2,
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func (s *OrchestratorTestSuite) TestSellItem_MerchantDoesNotBuy() {
+	sellRate := 0.0
+	orchestrator, err := shop.NewOrchestrator(&shop.Config{
+		CharacterRepo:  s.mockCharRepo,
+		ExternalClient: s.mockExternal,
+		Merchants: []*shop.Merchant{
+			{ID: "peddler", Name: "Peddler", SellRate: &sellRate},
+		},
+	})
+	s.Require().NoError(err)
+
+	output, err := orchestrator.SellItem(s.ctx, &shop.SellItemInput{
+		CharacterID: "char-1",
+		MerchantID:  "peddler",
+		ItemID:      "EQUIPMENT_DAGGER",
+	})
+
+	s.Nil(output)
+	s.True(errors.IsFailedPrecondition(err))
+}
+
+func TestOrchestratorTestSuite(t *testing.T) {
+	suite.Run(t, new(OrchestratorTestSuite))
+}
@@ -0,0 +1,83 @@
+package shop
+
+import (
+	"github.com/KirkDiggler/rpg-api/internal/clients/external"
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+)
+
+// Merchant describes a configured shop and the equipment it stocks
+type Merchant struct {
+	ID   string
+	Name string
+
+	// Categories limits stock to these equipment categories (e.g. "simple-weapons").
+	// When both Categories and ItemIDs are empty the merchant stocks all equipment.
+	Categories []string
+
+	// ItemIDs adds specific equipment to the merchant's stock
+	ItemIDs []string
+
+	// BuyMarkup multiplies the list price when a character buys (defaults to 1.0)
+	BuyMarkup float64
+
+	// SellRate is the fraction of the list price paid when a character sells. Nil uses
+	// DefaultSellRate; zero means the merchant does not buy.
+	SellRate *float64
+}
+
+// MerchantItem is a piece of equipment stocked by a merchant with its prices
+type MerchantItem struct {
+	Equipment *external.EquipmentData
+	BuyPrice  int32 // Price in copper pieces to buy one from the merchant
+	SellPrice int32 // Price in copper pieces the merchant pays for one, 0 if it does not buy
+}
+
+// ListMerchantsInput defines the request for listing merchants
+type ListMerchantsInput struct{}
+
+// ListMerchantsOutput defines the response for listing merchants
+type ListMerchantsOutput struct {
+	Merchants []*Merchant
+}
+
+// GetMerchantInventoryInput defines the request for a merchant's stock
+type GetMerchantInventoryInput struct {
+	MerchantID string
+}
+
+// GetMerchantInventoryOutput defines the response for a merchant's stock
+type GetMerchantInventoryOutput struct {
+	Merchant *Merchant
+	Items    []*MerchantItem
+}
+
+// BuyItemInput defines the request for buying an item from a merchant
+type BuyItemInput struct {
+	CharacterID string
+	MerchantID  string
+	ItemID      string
+	Quantity    int32 // Defaults to 1, at most MaxQuantity
+}
+
+// BuyItemOutput defines the response for buying an item
+type BuyItemOutput struct {
+	Character *toolkitchar.Data
+	Wallet    *dnd5e.Wallet
+	TotalCost int32 // Copper pieces debited from the wallet
+}
+
+// SellItemInput defines the request for selling an item to a merchant
+type SellItemInput struct {
+	CharacterID string
+	MerchantID  string
+	ItemID      string
+	Quantity    int32 // Defaults to 1, at most MaxQuantity
+}
+
+// SellItemOutput defines the response for selling an item
+type SellItemOutput struct {
+	Character     *toolkitchar.Data
+	Wallet        *dnd5e.Wallet
+	TotalReceived int32 // Copper pieces credited to the wallet
+}
@@ -0,0 +1,17 @@
+// Package actor carries the identity of whoever is making a request through the context
+package actor
+
+import "context"
+
+type contextKey struct{}
+
+// WithID returns a context that records the acting player or service
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// ID returns the actor recorded on the context, or an empty string if there is none
+func ID(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
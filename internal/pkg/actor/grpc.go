@@ -0,0 +1,42 @@
+package actor
+
+import (
+	"context"
+
+	middleware "github.com/grpc-ecosystem/go-grpc-middleware/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataKey is the request metadata header carrying the calling player's ID. There is no
+// authentication yet, so the header is taken as sent.
+const MetadataKey = "x-player-id"
+
+// UnaryServerInterceptor records the player from the request metadata as the actor
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(fromMetadata(ctx), req)
+	}
+}
+
+// StreamServerInterceptor records the player from the stream metadata as the actor
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		wrapped := middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = fromMetadata(stream.Context())
+		return handler(srv, wrapped)
+	}
+}
+
+// fromMetadata returns the context with the actor from the incoming metadata, if there is one
+func fromMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	values := md.Get(MetadataKey)
+	if len(values) == 0 || values[0] == "" {
+		return ctx
+	}
+	return WithID(ctx, values[0])
+}
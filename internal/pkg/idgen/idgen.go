@@ -99,3 +99,34 @@ func (g *UUIDGenerator) Generate() string {
 	}
 	return id
 }
+
+// codeAlphabet leaves out characters that are easy to misread, like 0/O and 1/I
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// CodeGenerator generates short random codes that are easy to read aloud or type, e.g. invite codes
+type CodeGenerator struct {
+	length int
+}
+
+// NewCode creates a new code generator for codes of the given length
+func NewCode(length int) *CodeGenerator {
+	return &CodeGenerator{length: length}
+}
+
+// Generate creates a new random code
+func (g *CodeGenerator) Generate() string {
+	randomBytes := make([]byte, g.length)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		// crypto/rand.Read should never fail on a properly configured system
+		// If it does, it indicates a catastrophic system failure
+		panic(fmt.Sprintf("crypto/rand.Read failed: %v", err))
+	}
+
+	code := make([]byte, g.length)
+	for i, b := range randomBytes {
+		// The alphabet has 32 characters, so every byte maps evenly
+		code[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+	}
+	return string(code)
+}
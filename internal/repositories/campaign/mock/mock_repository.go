@@ -0,0 +1,133 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/KirkDiggler/rpg-api/internal/repositories/campaign (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/mock_repository.go -package=campaignmock github.com/KirkDiggler/rpg-api/internal/repositories/campaign Repository
+//
+
+// Package campaignmock is a generated GoMock package.
+package campaignmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+
+	campaign "github.com/KirkDiggler/rpg-api/internal/repositories/campaign"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(ctx context.Context, input campaign.CreateInput) (*campaign.CreateOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, input)
+	ret0, _ := ret[0].(*campaign.CreateOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, input)
+}
+
+// Delete mocks base method.
+func (m *MockRepository) Delete(ctx context.Context, input campaign.DeleteInput) (*campaign.DeleteOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, input)
+	ret0, _ := ret[0].(*campaign.DeleteOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRepositoryMockRecorder) Delete(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, input)
+}
+
+// Get mocks base method.
+func (m *MockRepository) Get(ctx context.Context, input campaign.GetInput) (*campaign.GetOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, input)
+	ret0, _ := ret[0].(*campaign.GetOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRepositoryMockRecorder) Get(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepository)(nil).Get), ctx, input)
+}
+
+// GetByInviteCode mocks base method.
+func (m *MockRepository) GetByInviteCode(ctx context.Context, input campaign.GetByInviteCodeInput) (*campaign.GetByInviteCodeOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByInviteCode", ctx, input)
+	ret0, _ := ret[0].(*campaign.GetByInviteCodeOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByInviteCode indicates an expected call of GetByInviteCode.
+func (mr *MockRepositoryMockRecorder) GetByInviteCode(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByInviteCode", reflect.TypeOf((*MockRepository)(nil).GetByInviteCode), ctx, input)
+}
+
+// ListByMember mocks base method.
+func (m *MockRepository) ListByMember(ctx context.Context, input campaign.ListByMemberInput) (*campaign.ListByMemberOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByMember", ctx, input)
+	ret0, _ := ret[0].(*campaign.ListByMemberOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByMember indicates an expected call of ListByMember.
+func (mr *MockRepositoryMockRecorder) ListByMember(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByMember", reflect.TypeOf((*MockRepository)(nil).ListByMember), ctx, input)
+}
+
+// Update mocks base method.
+func (m *MockRepository) Update(ctx context.Context, input campaign.UpdateInput) (*campaign.UpdateOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, input)
+	ret0, _ := ret[0].(*campaign.UpdateOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRepositoryMockRecorder) Update(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, input)
+}
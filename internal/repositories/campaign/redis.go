@@ -0,0 +1,411 @@
+package campaign
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+
+	redis "github.com/redis/go-redis/v9"
+
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/clock"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/idgen"
+	redisclient "github.com/KirkDiggler/rpg-api/internal/redis"
+)
+
+const (
+	campaignKeyPrefix   = "campaign:"
+	memberIndexPrefix   = "campaign:member:"
+	inviteCodeKeyPrefix = "campaign:invite:"
+
+	// maxWriteAttempts bounds how often an update is retried when the campaign changes
+	// underneath it
+	maxWriteAttempts = 5
+
+	// Error messages
+	errCampaignNil     = "campaign cannot be nil"
+	errCampaignIDEmpty = "campaign ID cannot be empty"
+	errPlayerIDEmpty   = "player ID cannot be empty"
+)
+
+// Config holds the configuration for the Redis repository
+type Config struct {
+	Client      redisclient.Client
+	Clock       clock.Clock
+	IDGenerator idgen.Generator
+}
+
+// Validate ensures all required dependencies are provided
+func (c *Config) Validate() error {
+	if c.Client == nil {
+		return errors.InvalidArgument("redis client is required")
+	}
+	if c.Clock == nil {
+		return errors.InvalidArgument("clock is required")
+	}
+	if c.IDGenerator == nil {
+		return errors.InvalidArgument("ID generator is required")
+	}
+	return nil
+}
+
+type redisRepository struct {
+	client redisclient.Client
+	clock  clock.Clock
+	idGen  idgen.Generator
+}
+
+// NewRedis creates a new Redis-backed campaign repository
+func NewRedis(cfg *Config) (Repository, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid config")
+	}
+
+	return &redisRepository{
+		client: cfg.Client,
+		clock:  cfg.Clock,
+		idGen:  cfg.IDGenerator,
+	}, nil
+}
+
+// Ensure redisRepository implements Repository
+var _ Repository = (*redisRepository)(nil)
+
+func (r *redisRepository) Create(ctx context.Context, input CreateInput) (*CreateOutput, error) {
+	if input.Campaign == nil {
+		return nil, errors.InvalidArgument(errCampaignNil)
+	}
+
+	campaign := *input.Campaign
+	campaign.ID = r.idGen.Generate()
+	campaign.CreatedAt = r.clock.Now()
+	campaign.UpdatedAt = campaign.CreatedAt
+
+	data, err := json.Marshal(&campaign)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal campaign")
+	}
+
+	claimed, err := r.claimInviteCode(ctx, campaign.InviteCode, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start transaction
+	pipe := r.client.TxPipeline()
+
+	pipe.Set(ctx, campaignKeyPrefix+campaign.ID, data, 0)
+	for _, member := range campaign.Members {
+		pipe.SAdd(ctx, memberIndexPrefix+member.PlayerID, campaign.ID)
+	}
+
+	// Execute transaction
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		if claimed {
+			r.releaseInviteCode(ctx, campaign.InviteCode, campaign.ID)
+		}
+		return nil, errors.Wrapf(err, "failed to create campaign")
+	}
+
+	return &CreateOutput{Campaign: &campaign}, nil
+}
+
+func (r *redisRepository) Get(ctx context.Context, input GetInput) (*GetOutput, error) {
+	if input.ID == "" {
+		return nil, errors.InvalidArgument(errCampaignIDEmpty)
+	}
+
+	campaign, err := get(ctx, r.client, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetOutput{Campaign: campaign}, nil
+}
+
+func (r *redisRepository) GetByInviteCode(
+	ctx context.Context,
+	input GetByInviteCodeInput,
+) (*GetByInviteCodeOutput, error) {
+	if input.Code == "" {
+		return nil, errors.InvalidArgument("invite code cannot be empty")
+	}
+
+	campaignID, err := r.client.Get(ctx, inviteCodeKeyPrefix+input.Code).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.NotFound("invite code not found")
+		}
+		return nil, errors.Wrapf(err, "failed to look up invite code")
+	}
+
+	getOutput, err := r.Get(ctx, GetInput{ID: campaignID})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// The campaign was deleted without cleaning up its code
+			r.client.Del(ctx, inviteCodeKeyPrefix+input.Code)
+			return nil, errors.NotFound("invite code not found")
+		}
+		return nil, err
+	}
+	// The code was replaced but the old key lingered
+	if getOutput.Campaign.InviteCode != input.Code {
+		return nil, errors.NotFound("invite code not found")
+	}
+
+	return &GetByInviteCodeOutput{Campaign: getOutput.Campaign}, nil
+}
+
+func (r *redisRepository) Update(ctx context.Context, input UpdateInput) (*UpdateOutput, error) {
+	if input.Campaign == nil {
+		return nil, errors.InvalidArgument(errCampaignNil)
+	}
+	if input.Campaign.ID == "" {
+		return nil, errors.InvalidArgument(errCampaignIDEmpty)
+	}
+
+	key := campaignKeyPrefix + input.Campaign.ID
+	for range maxWriteAttempts {
+		var campaign Campaign
+		var claimed bool
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			existing, err := get(ctx, tx, input.Campaign.ID)
+			if err != nil {
+				return err
+			}
+
+			campaign = *input.Campaign
+			campaign.CreatedAt = existing.CreatedAt
+			campaign.UpdatedAt = r.clock.Now()
+
+			data, err := json.Marshal(&campaign)
+			if err != nil {
+				return errors.Wrapf(err, "failed to marshal campaign")
+			}
+
+			codeChanged := campaign.InviteCode != existing.InviteCode
+			if codeChanged {
+				claimed, err = r.claimInviteCode(ctx, campaign.InviteCode, campaign.ID)
+				if err != nil {
+					return err
+				}
+			}
+			// The old code is only ours to drop while it still points here
+			dropOldCode := false
+			if codeChanged && existing.InviteCode != "" {
+				owner, err := tx.Get(ctx, inviteCodeKeyPrefix+existing.InviteCode).Result()
+				if err != nil && err != redis.Nil {
+					return errors.Wrapf(err, "failed to check invite code")
+				}
+				dropOldCode = owner == campaign.ID
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, data, 0)
+
+				// Update member indexes for players who joined or left
+				oldMembers := memberIDs(existing)
+				newMembers := memberIDs(&campaign)
+				for _, playerID := range oldMembers {
+					if !slices.Contains(newMembers, playerID) {
+						pipe.SRem(ctx, memberIndexPrefix+playerID, campaign.ID)
+					}
+				}
+				for _, playerID := range newMembers {
+					if !slices.Contains(oldMembers, playerID) {
+						pipe.SAdd(ctx, memberIndexPrefix+playerID, campaign.ID)
+					}
+				}
+
+				if dropOldCode {
+					pipe.Del(ctx, inviteCodeKeyPrefix+existing.InviteCode)
+				}
+				return nil
+			})
+			return err
+		}, key)
+		if err != nil && claimed {
+			// Give the new code back so a retry, or another campaign, can claim it
+			r.releaseInviteCode(ctx, campaign.InviteCode, campaign.ID)
+		}
+		if err == redis.TxFailedErr {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to update campaign")
+		}
+
+		return &UpdateOutput{Campaign: &campaign}, nil
+	}
+	return nil, errors.Abortedf("campaign %s kept changing while it was being updated", input.Campaign.ID)
+}
+
+func (r *redisRepository) Delete(ctx context.Context, input DeleteInput) (*DeleteOutput, error) {
+	if input.ID == "" {
+		return nil, errors.InvalidArgument(errCampaignIDEmpty)
+	}
+
+	getOutput, err := r.Get(ctx, GetInput(input))
+	if err != nil {
+		return nil, err
+	}
+	campaign := getOutput.Campaign
+
+	// Start transaction
+	pipe := r.client.TxPipeline()
+
+	pipe.Del(ctx, campaignKeyPrefix+campaign.ID)
+	for _, playerID := range memberIDs(campaign) {
+		pipe.SRem(ctx, memberIndexPrefix+playerID, campaign.ID)
+	}
+	if campaign.InviteCode != "" {
+		pipe.Del(ctx, inviteCodeKeyPrefix+campaign.InviteCode)
+	}
+
+	// Execute transaction
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to delete campaign")
+	}
+
+	return &DeleteOutput{}, nil
+}
+
+func (r *redisRepository) ListByMember(ctx context.Context, input ListByMemberInput) (*ListByMemberOutput, error) {
+	if input.PlayerID == "" {
+		return nil, errors.InvalidArgument(errPlayerIDEmpty)
+	}
+
+	indexKey := memberIndexPrefix + input.PlayerID
+	campaignIDs, err := r.client.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get campaigns from index %s", indexKey)
+	}
+
+	campaigns := make([]*Campaign, 0, len(campaignIDs))
+	for _, id := range campaignIDs {
+		var getOutput *GetOutput
+		getOutput, err = r.Get(ctx, GetInput{ID: id})
+		if err != nil {
+			// If the campaign doesn't exist, clean up the index
+			if errors.IsNotFound(err) {
+				slog.WarnContext(ctx, "campaign not found, cleaning up index",
+					"campaign_id", id,
+					"index_key", indexKey)
+				r.client.SRem(ctx, indexKey, id)
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get campaign %s", id)
+		}
+		campaigns = append(campaigns, getOutput.Campaign)
+	}
+
+	// Newest campaigns first
+	slices.SortFunc(campaigns, func(a, b *Campaign) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return &ListByMemberOutput{Campaigns: campaigns}, nil
+}
+
+// claimInviteCode points an invite code at a campaign with SETNX, so two campaigns can never
+// take the same code. A code left behind by a deleted campaign, or one that has since moved
+// to another code, is taken over. It reports whether this call made the claim.
+func (r *redisRepository) claimInviteCode(ctx context.Context, code, campaignID string) (bool, error) {
+	if code == "" {
+		return false, nil
+	}
+	codeKey := inviteCodeKeyPrefix + code
+
+	set, err := r.client.SetNX(ctx, codeKey, campaignID, 0).Result()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to claim invite code")
+	}
+	if set {
+		return true, nil
+	}
+
+	claimed := false
+	err = r.client.Watch(ctx, func(tx *redis.Tx) error {
+		owner, err := tx.Get(ctx, codeKey).Result()
+		if err != nil && err != redis.Nil {
+			return errors.Wrapf(err, "failed to check invite code")
+		}
+		if owner == campaignID {
+			return nil
+		}
+		if owner != "" {
+			ownerCampaign, err := get(ctx, r.client, owner)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			if ownerCampaign != nil && ownerCampaign.InviteCode == code {
+				return errors.AlreadyExists("invite code is already in use")
+			}
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, codeKey, campaignID, 0)
+			return nil
+		})
+		claimed = err == nil
+		return err
+	}, codeKey)
+	if err == redis.TxFailedErr {
+		// Someone else claimed or released the code first
+		return false, errors.AlreadyExists("invite code is already in use")
+	}
+	if err != nil {
+		return false, err
+	}
+	return claimed, nil
+}
+
+// releaseInviteCode drops a claim on an invite code if it still points at the campaign.
+// Failures are only logged; a stale code is taken over by the next claim.
+func (r *redisRepository) releaseInviteCode(ctx context.Context, code, campaignID string) {
+	codeKey := inviteCodeKeyPrefix + code
+	err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+		owner, err := tx.Get(ctx, codeKey).Result()
+		if err != nil || owner != campaignID {
+			return nil
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, codeKey)
+			return nil
+		})
+		return err
+	}, codeKey)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to release invite code",
+			"campaign_id", campaignID,
+			"error", err)
+	}
+}
+
+// get loads a campaign through cmd, which is the client or a transaction watching the key
+func get(ctx context.Context, cmd redis.Cmdable, campaignID string) (*Campaign, error) {
+	result, err := cmd.Get(ctx, campaignKeyPrefix+campaignID).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.NotFoundf("campaign with ID %s not found", campaignID)
+		}
+		return nil, errors.Wrapf(err, "failed to get campaign")
+	}
+
+	var campaign Campaign
+	if err := json.Unmarshal([]byte(result), &campaign); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal campaign")
+	}
+	return &campaign, nil
+}
+
+func memberIDs(campaign *Campaign) []string {
+	ids := make([]string, 0, len(campaign.Members))
+	for _, member := range campaign.Members {
+		ids = append(ids, member.PlayerID)
+	}
+	return ids
+}
@@ -0,0 +1,171 @@
+// Package campaign provides the repository interface and types for campaigns
+package campaign
+
+//go:generate mockgen -destination=mock/mock_repository.go -package=campaignmock github.com/KirkDiggler/rpg-api/internal/repositories/campaign Repository
+
+import (
+	"context"
+	"time"
+)
+
+// Member roles within a campaign
+const (
+	RoleDM        = "dm"
+	RolePlayer    = "player"
+	RoleSpectator = "spectator"
+)
+
+// Campaign groups everything a table does: its members, their characters, the game
+// sessions played, and the encounters and dice rolls from those sessions
+type Campaign struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// OwnerID is the DM who created the campaign. The owner cannot leave or be demoted.
+	OwnerID string    `json:"owner_id"`
+	Members []*Member `json:"members"`
+
+	// InviteCode lets a player join as a player; empty when invites are closed
+	InviteCode string `json:"invite_code,omitempty"`
+
+	Rules    *RuleSettings `json:"rules,omitempty"`
+	Sessions []*Session    `json:"sessions,omitempty"`
+
+	// Things attached to the campaign
+	CharacterIDs []string          `json:"character_ids,omitempty"`
+	EncounterIDs []string          `json:"encounter_ids,omitempty"`
+	DiceSessions []*DiceSessionRef `json:"dice_sessions,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Member is a player's place in a campaign
+type Member struct {
+	PlayerID string    `json:"player_id"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// RuleSettings are the optional and variant rules a table plays with
+type RuleSettings struct {
+	// AbilityScoreMethod is how new characters generate ability scores, e.g. "4d6_drop_lowest"
+	AbilityScoreMethod string `json:"ability_score_method,omitempty"`
+	StartingLevel      int    `json:"starting_level,omitempty"`
+	AllowFeats         bool   `json:"allow_feats,omitempty"`
+	AllowMulticlassing bool   `json:"allow_multiclassing,omitempty"`
+	FlankingAdvantage  bool   `json:"flanking_advantage,omitempty"`
+	VariantEncumbrance bool   `json:"variant_encumbrance,omitempty"`
+}
+
+// Session is one sitting of the campaign. Characters join it by its ID.
+type Session struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name,omitempty"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"` // Nil while the session is running
+}
+
+// DiceSessionRef identifies a dice session by the same entity and context the dice service uses
+type DiceSessionRef struct {
+	EntityID string `json:"entity_id"`
+	Context  string `json:"context"`
+}
+
+// Repository defines the interface for campaign persistence
+type Repository interface {
+	// Create stores a new campaign, assigning its ID and timestamps
+	// Returns errors.InvalidArgument for validation failures
+	// Returns errors.AlreadyExists if the invite code is taken
+	// Returns errors.Internal for storage failures
+	Create(ctx context.Context, input CreateInput) (*CreateOutput, error)
+
+	// Get retrieves a campaign by ID
+	// Returns errors.InvalidArgument for empty/invalid IDs
+	// Returns errors.NotFound if the campaign doesn't exist
+	// Returns errors.Internal for storage failures
+	Get(ctx context.Context, input GetInput) (*GetOutput, error)
+
+	// GetByInviteCode retrieves the campaign an invite code belongs to
+	// Returns errors.InvalidArgument for an empty code
+	// Returns errors.NotFound if no campaign has the code
+	// Returns errors.Internal for storage failures
+	GetByInviteCode(ctx context.Context, input GetByInviteCodeInput) (*GetByInviteCodeOutput, error)
+
+	// Update replaces an existing campaign, keeping the member and invite indexes in step
+	// Returns errors.InvalidArgument for validation failures
+	// Returns errors.NotFound if the campaign doesn't exist
+	// Returns errors.AlreadyExists if a new invite code is taken
+	// Returns errors.Internal for storage failures
+	Update(ctx context.Context, input UpdateInput) (*UpdateOutput, error)
+
+	// Delete removes a campaign and its indexes
+	// Returns errors.InvalidArgument for empty/invalid IDs
+	// Returns errors.NotFound if the campaign doesn't exist
+	// Returns errors.Internal for storage failures
+	Delete(ctx context.Context, input DeleteInput) (*DeleteOutput, error)
+
+	// ListByMember retrieves every campaign a player is a member of
+	// Returns errors.InvalidArgument for empty/invalid player IDs
+	// Returns errors.Internal for storage failures
+	ListByMember(ctx context.Context, input ListByMemberInput) (*ListByMemberOutput, error)
+}
+
+// CreateInput defines the input for creating a campaign
+type CreateInput struct {
+	Campaign *Campaign // ID and timestamps are assigned by the repository
+}
+
+// CreateOutput defines the output for creating a campaign
+type CreateOutput struct {
+	Campaign *Campaign
+}
+
+// GetInput defines the input for getting a campaign
+type GetInput struct {
+	ID string
+}
+
+// GetOutput defines the output for getting a campaign
+type GetOutput struct {
+	Campaign *Campaign
+}
+
+// GetByInviteCodeInput defines the input for finding a campaign by invite code
+type GetByInviteCodeInput struct {
+	Code string
+}
+
+// GetByInviteCodeOutput defines the output for finding a campaign by invite code
+type GetByInviteCodeOutput struct {
+	Campaign *Campaign
+}
+
+// UpdateInput defines the input for updating a campaign
+type UpdateInput struct {
+	Campaign *Campaign
+}
+
+// UpdateOutput defines the output for updating a campaign
+type UpdateOutput struct {
+	Campaign *Campaign
+}
+
+// DeleteInput defines the input for deleting a campaign
+type DeleteInput struct {
+	ID string
+}
+
+// DeleteOutput defines the output for deleting a campaign
+type DeleteOutput struct{}
+
+// ListByMemberInput defines the input for listing a player's campaigns
+type ListByMemberInput struct {
+	PlayerID string
+}
+
+// ListByMemberOutput defines the output for listing a player's campaigns
+type ListByMemberOutput struct {
+	Campaigns []*Campaign
+}
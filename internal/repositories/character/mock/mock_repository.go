@@ -87,6 +87,21 @@ func (mr *MockRepositoryMockRecorder) Get(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepository)(nil).Get), ctx, input)
 }
 
+// List mocks base method.
+func (m *MockRepository) List(ctx context.Context, input character.ListInput) (*character.ListOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, input)
+	ret0, _ := ret[0].(*character.ListOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRepositoryMockRecorder) List(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepository)(nil).List), ctx, input)
+}
+
 // ListByPlayerID mocks base method.
 func (m *MockRepository) ListByPlayerID(ctx context.Context, input character.ListByPlayerIDInput) (*character.ListByPlayerIDOutput, error) {
 	m.ctrl.T.Helper()
@@ -117,6 +132,66 @@ func (mr *MockRepositoryMockRecorder) ListBySessionID(ctx, input any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBySessionID", reflect.TypeOf((*MockRepository)(nil).ListBySessionID), ctx, input)
 }
 
+// ListDeleted mocks base method.
+func (m *MockRepository) ListDeleted(ctx context.Context, input character.ListDeletedInput) (*character.ListDeletedOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeleted", ctx, input)
+	ret0, _ := ret[0].(*character.ListDeletedOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeleted indicates an expected call of ListDeleted.
+func (mr *MockRepositoryMockRecorder) ListDeleted(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeleted", reflect.TypeOf((*MockRepository)(nil).ListDeleted), ctx, input)
+}
+
+// ListEvents mocks base method.
+func (m *MockRepository) ListEvents(ctx context.Context, input character.ListEventsInput) (*character.ListEventsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", ctx, input)
+	ret0, _ := ret[0].(*character.ListEventsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockRepositoryMockRecorder) ListEvents(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockRepository)(nil).ListEvents), ctx, input)
+}
+
+// Restore mocks base method.
+func (m *MockRepository) Restore(ctx context.Context, input character.RestoreInput) (*character.RestoreOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, input)
+	ret0, _ := ret[0].(*character.RestoreOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRepositoryMockRecorder) Restore(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRepository)(nil).Restore), ctx, input)
+}
+
+// SoftDelete mocks base method.
+func (m *MockRepository) SoftDelete(ctx context.Context, input character.SoftDeleteInput) (*character.SoftDeleteOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDelete", ctx, input)
+	ret0, _ := ret[0].(*character.SoftDeleteOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDelete indicates an expected call of SoftDelete.
+func (mr *MockRepositoryMockRecorder) SoftDelete(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockRepository)(nil).SoftDelete), ctx, input)
+}
+
 // Update mocks base method.
 func (m *MockRepository) Update(ctx context.Context, input character.UpdateInput) (*character.UpdateOutput, error) {
 	m.ctrl.T.Helper()
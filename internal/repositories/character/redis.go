@@ -1,13 +1,20 @@
 package character
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
 
 	redis "github.com/redis/go-redis/v9"
 
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
 	"github.com/KirkDiggler/rpg-api/internal/errors"
+	"github.com/KirkDiggler/rpg-api/internal/pkg/actor"
 	"github.com/KirkDiggler/rpg-api/internal/pkg/clock"
 	redisclient "github.com/KirkDiggler/rpg-api/internal/redis"
 	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
@@ -15,8 +22,26 @@ import (
 
 const (
 	characterKeyPrefix = "character:"
+	sheetKeyPrefix     = "character:sheet:"
 	playerIndexPrefix  = "character:player:"
 	sessionIndexPrefix = "character:session:"
+	eventsKeyPrefix    = "character:events:"
+	summaryKeyPrefix   = "character:summary:"
+	revisionKeyPrefix  = "character:revision:"
+
+	// maxUpdateAttempts bounds how often an update is retried when the character is
+	// written between reading it and writing it back
+	maxUpdateAttempts = 5
+
+	// maxHistoryEvents is how long a character's history grows before an update
+	// compacts it into a single snapshot event
+	maxHistoryEvents = 500
+
+	// Soft deleted characters are renamed out of the active keyspace and tracked
+	// by deletion time so the purge sweeper can find expired ones
+	deletedKeyPrefix         = "character:deleted:"
+	deletedPlayerIndexPrefix = "character:deleted-player:"
+	deletedAtKey             = "character:deleted-at"
 
 	// Error messages
 	errCharacterNil     = "character cannot be nil"
@@ -91,11 +116,41 @@ func (r *redisRepository) Create(ctx context.Context, input CreateInput) (*Creat
 		return nil, errors.Wrapf(err, "failed to marshal character data")
 	}
 
+	sheetData, err := marshalSheet(input.Sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	eventData, err := r.marshalEvent(ctx, dnd5e.CharacterEventCreated, input.CharacterData.ID, 1,
+		nil, input.CharacterData, nil, input.Sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	now := r.clock.Now()
+	summary := newSummary(input.CharacterData, input.Sheet, now, now)
+	summaryData, err := marshalSummary(summary)
+	if err != nil {
+		return nil, err
+	}
+
 	// Start transaction
 	pipe := r.client.TxPipeline()
 
 	// Set character data
 	pipe.Set(ctx, key, data, 0) // No TTL for characters
+	pipe.Set(ctx, revisionKeyPrefix+input.CharacterData.ID, 1, 0)
+	pipe.RPush(ctx, eventsKeyPrefix+input.CharacterData.ID, eventData)
+
+	// Set sheet data alongside the character
+	if input.Sheet != nil {
+		pipe.Set(ctx, sheetKeyPrefix+input.CharacterData.ID, sheetData, 0)
+	}
+
+	// Add to session indexes
+	for _, sessionID := range sessionIDs(input.Sheet) {
+		pipe.SAdd(ctx, sessionIndexPrefix+sessionID, input.CharacterData.ID)
+	}
 
 	// Add to player index
 	if input.CharacterData.PlayerID != "" {
@@ -103,7 +158,9 @@ func (r *redisRepository) Create(ctx context.Context, input CreateInput) (*Creat
 		pipe.SAdd(ctx, playerKey, input.CharacterData.ID)
 	}
 
-	// Note: character.Data doesn't have SessionID directly, that would need to be handled at orchestrator level
+	// Record the fields List sorts and filters on
+	pipe.Set(ctx, summaryKeyPrefix+input.CharacterData.ID, summaryData, 0)
+	addToSortIndexes(ctx, pipe, summary)
 
 	// Execute transaction
 	_, err = pipe.Exec(ctx)
@@ -111,7 +168,10 @@ func (r *redisRepository) Create(ctx context.Context, input CreateInput) (*Creat
 		return nil, errors.Wrapf(err, "failed to create character")
 	}
 
-	return &CreateOutput{CharacterData: input.CharacterData}, nil
+	return &CreateOutput{
+		CharacterData: input.CharacterData,
+		Sheet:         input.Sheet,
+	}, nil
 }
 
 func (r *redisRepository) Get(ctx context.Context, input GetInput) (*GetOutput, error) {
@@ -119,8 +179,22 @@ func (r *redisRepository) Get(ctx context.Context, input GetInput) (*GetOutput,
 		return nil, errors.InvalidArgument(errCharacterIDEmpty)
 	}
 
+	// The revision is read first, so a write that lands while the character is being read
+	// leaves a stale revision and a later ExpectedRevision check fails rather than passing
+	revision, err := r.getRevision(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	key := characterKeyPrefix + input.ID
 	result, err := r.client.Get(ctx, key).Result()
+	var deletedAt *time.Time
+	if err == redis.Nil && input.IncludeDeleted {
+		result, err = r.client.Get(ctx, deletedKeyPrefix+input.ID).Result()
+		if err == nil {
+			deletedAt, err = r.getDeletedAt(ctx, input.ID)
+		}
+	}
 	if err != nil {
 		if err == redis.Nil {
 			return nil, errors.NotFoundf("character with ID %s not found", input.ID)
@@ -133,7 +207,81 @@ func (r *redisRepository) Get(ctx context.Context, input GetInput) (*GetOutput,
 		return nil, errors.Wrapf(err, "failed to unmarshal character data")
 	}
 
-	return &GetOutput{CharacterData: &charData}, nil
+	sheet, err := r.getSheet(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetOutput{
+		CharacterData: &charData,
+		Sheet:         sheet,
+		DeletedAt:     deletedAt,
+		Revision:      revision,
+	}, nil
+}
+
+// getRevision reads how often a character has been written. Characters stored before
+// revisions were tracked start at 0.
+func (r *redisRepository) getRevision(ctx context.Context, id string) (int64, error) {
+	revision, err := r.client.Get(ctx, revisionKeyPrefix+id).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, nil
+		}
+		return 0, errors.Wrapf(err, "failed to get character revision")
+	}
+	return revision, nil
+}
+
+// getDeletedAt reads when a soft deleted character was deleted
+func (r *redisRepository) getDeletedAt(ctx context.Context, id string) (*time.Time, error) {
+	score, err := r.client.ZScore(ctx, deletedAtKey, id).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.Internalf("deleted character %s has no deletion time", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get deletion time")
+	}
+	deletedAt := time.UnixMilli(int64(score)).UTC()
+	return &deletedAt, nil
+}
+
+// marshalSheet serializes an optional sheet, returning nil data for a nil sheet
+func marshalSheet(sheet *dnd5e.CharacterSheet) ([]byte, error) {
+	if sheet == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(sheet)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal character sheet")
+	}
+	return data, nil
+}
+
+// sessionIDs returns the sessions recorded on an optional sheet
+func sessionIDs(sheet *dnd5e.CharacterSheet) []string {
+	if sheet == nil {
+		return nil
+	}
+	return sheet.SessionIDs
+}
+
+// getSheet loads the sheet stored alongside a character, returning nil if none exists
+func (r *redisRepository) getSheet(ctx context.Context, id string) (*dnd5e.CharacterSheet, error) {
+	result, err := r.client.Get(ctx, sheetKeyPrefix+id).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get character sheet")
+	}
+
+	var sheet dnd5e.CharacterSheet
+	if err := json.Unmarshal([]byte(result), &sheet); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal character sheet")
+	}
+
+	return &sheet, nil
 }
 
 func (r *redisRepository) Update(ctx context.Context, input UpdateInput) (*UpdateOutput, error) {
@@ -144,6 +292,29 @@ func (r *redisRepository) Update(ctx context.Context, input UpdateInput) (*Updat
 		return nil, errors.InvalidArgument(errCharacterIDEmpty)
 	}
 
+	// The existing character decides which indexes and history entries change, so it is
+	// read under WATCH and the whole update retried if another write gets in first
+	id := input.CharacterData.ID
+	for range maxUpdateAttempts {
+		var output *UpdateOutput
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			var err error
+			output, err = r.update(ctx, tx, input)
+			return err
+		}, characterKeyPrefix+id, sheetKeyPrefix+id, revisionKeyPrefix+id)
+		if err == redis.TxFailedErr {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return output, nil
+	}
+	return nil, errors.Abortedf("character %s kept changing while it was being updated", id)
+}
+
+// update writes a character in a transaction on tx, which watches its keys
+func (r *redisRepository) update(ctx context.Context, tx *redis.Tx, input UpdateInput) (*UpdateOutput, error) {
 	key := characterKeyPrefix + input.CharacterData.ID
 
 	// Get existing character to check indexes
@@ -152,6 +323,9 @@ func (r *redisRepository) Update(ctx context.Context, input UpdateInput) (*Updat
 		return nil, err
 	}
 	existing := existingOutput.CharacterData
+	if input.ExpectedRevision != nil && *input.ExpectedRevision != existingOutput.Revision {
+		return nil, errors.Abortedf("character %s changed since it was read", input.CharacterData.ID)
+	}
 
 	// Marshal updated character data
 	data, err := json.Marshal(input.CharacterData)
@@ -159,11 +333,76 @@ func (r *redisRepository) Update(ctx context.Context, input UpdateInput) (*Updat
 		return nil, errors.Wrapf(err, "failed to marshal character data")
 	}
 
+	sheetData, err := marshalSheet(input.Sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	eventType := input.EventType
+	if eventType == "" {
+		eventType = dnd5e.CharacterEventUpdated
+	}
+	// A nil sheet is left unchanged, so it is compared against itself
+	afterSheet := input.Sheet
+	if afterSheet == nil {
+		afterSheet = existingOutput.Sheet
+	}
+	lastEvent, err := r.lastEvent(ctx, input.CharacterData.ID)
+	if err != nil {
+		return nil, err
+	}
+	eventData, err := r.marshalEvent(ctx, eventType, input.CharacterData.ID, lastEvent.Sequence+1,
+		existing, input.CharacterData, existingOutput.Sheet, afterSheet)
+	if err != nil {
+		return nil, err
+	}
+
+	// The updated time only moves when something actually changed
+	oldSummary, err := r.getSummary(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+	updatedAt := oldSummary.UpdatedAt
+	if eventData != nil {
+		updatedAt = r.clock.Now()
+	}
+	summary := newSummary(input.CharacterData, afterSheet, oldSummary.CreatedAt, updatedAt)
+	summaryData, err := marshalSummary(summary)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshotData []byte
+	if eventData != nil {
+		snapshotData, err = r.historySnapshot(ctx, input.CharacterData.ID, lastEvent, existing, existingOutput.Sheet)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Start transaction
-	pipe := r.client.TxPipeline()
+	pipe := tx.TxPipeline()
 
 	// Update character data
 	pipe.Set(ctx, key, data, 0)
+	pipe.Incr(ctx, revisionKeyPrefix+input.CharacterData.ID)
+
+	// Record the change in the character's history; no-op updates are not recorded.
+	// A full history is replaced by a snapshot of the character before this change.
+	if eventData != nil {
+		if snapshotData != nil {
+			pipe.Del(ctx, eventsKeyPrefix+input.CharacterData.ID)
+			pipe.RPush(ctx, eventsKeyPrefix+input.CharacterData.ID, snapshotData)
+		}
+		pipe.RPush(ctx, eventsKeyPrefix+input.CharacterData.ID, eventData)
+	}
+
+	// Update sheet in the same transaction so both change together
+	sheet := existingOutput.Sheet
+	if input.Sheet != nil {
+		pipe.Set(ctx, sheetKeyPrefix+input.CharacterData.ID, sheetData, 0)
+		sheet = input.Sheet
+	}
 
 	// Update player index if changed
 	if existing.PlayerID != input.CharacterData.PlayerID {
@@ -177,16 +416,39 @@ func (r *redisRepository) Update(ctx context.Context, input UpdateInput) (*Updat
 		}
 	}
 
-	// Note: Session management would need to be handled at orchestrator level
-	// since character.Data doesn't include SessionID
+	// Move the character within its player's sort indexes
+	removeFromSortIndexes(ctx, pipe, oldSummary)
+	pipe.Set(ctx, summaryKeyPrefix+input.CharacterData.ID, summaryData, 0)
+	addToSortIndexes(ctx, pipe, summary)
+
+	// Update session indexes for sessions joined or left
+	if input.Sheet != nil {
+		oldSessions := sessionIDs(existingOutput.Sheet)
+		for _, sessionID := range oldSessions {
+			if !slices.Contains(input.Sheet.SessionIDs, sessionID) {
+				pipe.SRem(ctx, sessionIndexPrefix+sessionID, input.CharacterData.ID)
+			}
+		}
+		for _, sessionID := range input.Sheet.SessionIDs {
+			if !slices.Contains(oldSessions, sessionID) {
+				pipe.SAdd(ctx, sessionIndexPrefix+sessionID, input.CharacterData.ID)
+			}
+		}
+	}
 
-	// Execute transaction
+	// Execute transaction; a watched key changing is returned as is so Update retries
 	_, err = pipe.Exec(ctx)
+	if err == redis.TxFailedErr {
+		return nil, err
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to update character")
 	}
 
-	return &UpdateOutput{CharacterData: input.CharacterData}, nil
+	return &UpdateOutput{
+		CharacterData: input.CharacterData,
+		Sheet:         sheet,
+	}, nil
 }
 
 func (r *redisRepository) Delete(ctx context.Context, input DeleteInput) (*DeleteOutput, error) {
@@ -195,27 +457,41 @@ func (r *redisRepository) Delete(ctx context.Context, input DeleteInput) (*Delet
 	}
 
 	// Get character to find indexes
-	getOutput, err := r.Get(ctx, GetInput(input))
+	getOutput, err := r.Get(ctx, GetInput{ID: input.ID, IncludeDeleted: true})
 	if err != nil {
 		return nil, err
 	}
 	charData := getOutput.CharacterData
 
+	summary, err := r.getSummary(ctx, charData)
+	if err != nil {
+		return nil, err
+	}
+
 	// Start transaction
 	pipe := r.client.TxPipeline()
 
-	// Delete character
+	// Delete character, whichever keyspace it is in
 	key := characterKeyPrefix + input.ID
 	pipe.Del(ctx, key)
+	pipe.Del(ctx, deletedKeyPrefix+input.ID)
+	pipe.Del(ctx, sheetKeyPrefix+input.ID)
+	pipe.Del(ctx, eventsKeyPrefix+input.ID)
+	pipe.Del(ctx, revisionKeyPrefix+input.ID)
+	pipe.ZRem(ctx, deletedAtKey, input.ID)
+	pipe.Del(ctx, summaryKeyPrefix+input.ID)
+	removeFromSortIndexes(ctx, pipe, summary)
+	for _, sessionID := range sessionIDs(getOutput.Sheet) {
+		pipe.SRem(ctx, sessionIndexPrefix+sessionID, input.ID)
+	}
 
-	// Remove from player index
+	// Remove from player indexes
 	if charData.PlayerID != "" {
 		playerKey := playerIndexPrefix + charData.PlayerID
 		pipe.SRem(ctx, playerKey, input.ID)
+		pipe.SRem(ctx, deletedPlayerIndexPrefix+charData.PlayerID, input.ID)
 	}
 
-	// Note: Session index cleanup would need to be handled at orchestrator level
-
 	// Execute transaction
 	_, err = pipe.Exec(ctx)
 	if err != nil {
@@ -225,6 +501,166 @@ func (r *redisRepository) Delete(ctx context.Context, input DeleteInput) (*Delet
 	return &DeleteOutput{}, nil
 }
 
+func (r *redisRepository) SoftDelete(ctx context.Context, input SoftDeleteInput) (*SoftDeleteOutput, error) {
+	if input.ID == "" {
+		return nil, errors.InvalidArgument(errCharacterIDEmpty)
+	}
+
+	getOutput, err := r.Get(ctx, GetInput{ID: input.ID})
+	if err != nil {
+		return nil, err
+	}
+	charData := getOutput.CharacterData
+
+	deletedAt := r.clock.Now()
+	lastEvent, err := r.lastEvent(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	eventData, err := r.marshalEventChanges(ctx, dnd5e.CharacterEventDeleted, input.ID, lastEvent.Sequence+1, nil)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := r.getSummary(ctx, charData)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start transaction
+	pipe := r.client.TxPipeline()
+
+	// Move the character out of the active keyspace; the sheet and history stay where they are
+	pipe.Rename(ctx, characterKeyPrefix+input.ID, deletedKeyPrefix+input.ID)
+	pipe.Incr(ctx, revisionKeyPrefix+input.ID)
+	pipe.ZAdd(ctx, deletedAtKey, redis.Z{Score: float64(deletedAt.UnixMilli()), Member: input.ID})
+	pipe.RPush(ctx, eventsKeyPrefix+input.ID, eventData)
+
+	// Move between player indexes; the summary is kept for a restore
+	if charData.PlayerID != "" {
+		pipe.SRem(ctx, playerIndexPrefix+charData.PlayerID, input.ID)
+		pipe.SAdd(ctx, deletedPlayerIndexPrefix+charData.PlayerID, input.ID)
+	}
+	removeFromSortIndexes(ctx, pipe, summary)
+
+	// Leave the character's sessions; the sheet keeps them so a restore can rejoin
+	for _, sessionID := range sessionIDs(getOutput.Sheet) {
+		pipe.SRem(ctx, sessionIndexPrefix+sessionID, input.ID)
+	}
+
+	// Execute transaction
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to soft delete character")
+	}
+
+	return &SoftDeleteOutput{DeletedAt: deletedAt}, nil
+}
+
+func (r *redisRepository) Restore(ctx context.Context, input RestoreInput) (*RestoreOutput, error) {
+	if input.ID == "" {
+		return nil, errors.InvalidArgument(errCharacterIDEmpty)
+	}
+
+	getOutput, err := r.Get(ctx, GetInput{ID: input.ID, IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+	if getOutput.DeletedAt == nil {
+		return nil, errors.NotFoundf("deleted character with ID %s not found", input.ID)
+	}
+	charData := getOutput.CharacterData
+
+	lastEvent, err := r.lastEvent(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	eventData, err := r.marshalEventChanges(ctx, dnd5e.CharacterEventRestored, input.ID, lastEvent.Sequence+1, nil)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := r.getSummary(ctx, charData)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start transaction
+	pipe := r.client.TxPipeline()
+
+	// Move the character back into the active keyspace
+	pipe.Rename(ctx, deletedKeyPrefix+input.ID, characterKeyPrefix+input.ID)
+	pipe.Incr(ctx, revisionKeyPrefix+input.ID)
+	pipe.ZRem(ctx, deletedAtKey, input.ID)
+	pipe.RPush(ctx, eventsKeyPrefix+input.ID, eventData)
+
+	// Move between player indexes
+	if charData.PlayerID != "" {
+		pipe.SRem(ctx, deletedPlayerIndexPrefix+charData.PlayerID, input.ID)
+		pipe.SAdd(ctx, playerIndexPrefix+charData.PlayerID, input.ID)
+	}
+	addToSortIndexes(ctx, pipe, summary)
+	for _, sessionID := range sessionIDs(getOutput.Sheet) {
+		pipe.SAdd(ctx, sessionIndexPrefix+sessionID, input.ID)
+	}
+
+	// Execute transaction
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to restore character")
+	}
+
+	return &RestoreOutput{CharacterData: charData}, nil
+}
+
+func (r *redisRepository) ListDeleted(ctx context.Context, input ListDeletedInput) (*ListDeletedOutput, error) {
+	if input.PlayerID == "" && input.DeletedBefore.IsZero() {
+		return nil, errors.InvalidArgument("player ID or deleted before time is required")
+	}
+
+	var characterIDs []string
+	var err error
+	if input.PlayerID != "" {
+		characterIDs, err = r.client.SMembers(ctx, deletedPlayerIndexPrefix+input.PlayerID).Result()
+	} else {
+		characterIDs, err = r.client.ZRangeByScore(ctx, deletedAtKey, &redis.ZRangeBy{
+			Min: "-inf",
+			Max: "(" + strconv.FormatInt(input.DeletedBefore.UnixMilli(), 10),
+		}).Result()
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list deleted characters")
+	}
+
+	characters := make([]*DeletedCharacter, 0, len(characterIDs))
+	for _, id := range characterIDs {
+		var getOutput *GetOutput
+		getOutput, err = r.Get(ctx, GetInput{ID: id, IncludeDeleted: true})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get deleted character %s", id)
+		}
+		// Restored between reading the index and the character
+		if getOutput.DeletedAt == nil {
+			continue
+		}
+		if !input.DeletedBefore.IsZero() && !getOutput.DeletedAt.Before(input.DeletedBefore) {
+			continue
+		}
+		characters = append(characters, &DeletedCharacter{
+			CharacterData: getOutput.CharacterData,
+			DeletedAt:     *getOutput.DeletedAt,
+		})
+	}
+
+	// Oldest deletions first, as they are the closest to being purged
+	sort.Slice(characters, func(i, j int) bool {
+		return characters[i].DeletedAt.Before(characters[j].DeletedAt)
+	})
+
+	return &ListDeletedOutput{Characters: characters}, nil
+}
+
 func (r *redisRepository) ListByPlayerID(
 	ctx context.Context,
 	input ListByPlayerIDInput,
@@ -283,6 +719,184 @@ func (r *redisRepository) ListBySessionID(
 	return &ListBySessionIDOutput{Characters: characters}, nil
 }
 
+func (r *redisRepository) ListEvents(ctx context.Context, input ListEventsInput) (*ListEventsOutput, error) {
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument(errCharacterIDEmpty)
+	}
+
+	results, err := r.client.LRange(ctx, eventsKeyPrefix+input.CharacterID, 0, -1).Result()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list character events")
+	}
+
+	events := make([]*dnd5e.CharacterEvent, 0, len(results))
+	for _, result := range results {
+		var event dnd5e.CharacterEvent
+		if err := json.Unmarshal([]byte(result), &event); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal character event")
+		}
+		events = append(events, &event)
+	}
+
+	return &ListEventsOutput{Events: events}, nil
+}
+
+// marshalEvent builds the history event for a write, returning nil data when nothing changed
+func (r *redisRepository) marshalEvent(
+	ctx context.Context,
+	eventType, characterID string,
+	sequence int64,
+	beforeData, afterData *toolkitchar.Data,
+	beforeSheet, afterSheet *dnd5e.CharacterSheet,
+) ([]byte, error) {
+	changes, err := diffFields("character.", beforeData, afterData)
+	if err != nil {
+		return nil, err
+	}
+	sheetChanges, err := diffFields("sheet.", beforeSheet, afterSheet)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, sheetChanges...)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	return r.marshalEventChanges(ctx, eventType, characterID, sequence, changes)
+}
+
+// marshalEventChanges builds a history event from changes that have already been computed.
+// Lifecycle events such as deletes are recorded with no changes.
+func (r *redisRepository) marshalEventChanges(
+	ctx context.Context,
+	eventType, characterID string,
+	sequence int64,
+	changes []dnd5e.CharacterFieldChange,
+) ([]byte, error) {
+	data, err := json.Marshal(&dnd5e.CharacterEvent{
+		CharacterID: characterID,
+		Sequence:    sequence,
+		Type:        eventType,
+		ActorID:     actor.ID(ctx),
+		Timestamp:   r.clock.Now(),
+		Changes:     changes,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal character event")
+	}
+	return data, nil
+}
+
+// lastEvent returns the newest event in a character's history, or an empty event with
+// sequence 0 when there is none
+func (r *redisRepository) lastEvent(ctx context.Context, characterID string) (*dnd5e.CharacterEvent, error) {
+	data, err := r.client.LIndex(ctx, eventsKeyPrefix+characterID, -1).Result()
+	if err == redis.Nil {
+		return &dnd5e.CharacterEvent{CharacterID: characterID}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get last character event")
+	}
+	var last dnd5e.CharacterEvent
+	if err := json.Unmarshal([]byte(data), &last); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal character event")
+	}
+	return &last, nil
+}
+
+// historySnapshot returns an event holding every field of the character as it stands, to
+// replace a history that has reached maxHistoryEvents. It takes the time and sequence of the
+// last event it replaces, so rebuilds from that time on still work and sequence numbers keep
+// counting up. Returns nil while the history is short enough to keep.
+func (r *redisRepository) historySnapshot(
+	ctx context.Context,
+	characterID string,
+	last *dnd5e.CharacterEvent,
+	charData *toolkitchar.Data,
+	sheet *dnd5e.CharacterSheet,
+) ([]byte, error) {
+	length, err := r.client.LLen(ctx, eventsKeyPrefix+characterID).Result()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to count character events")
+	}
+	if length < maxHistoryEvents {
+		return nil, nil
+	}
+
+	changes, err := diffFields("character.", nil, charData)
+	if err != nil {
+		return nil, err
+	}
+	sheetChanges, err := diffFields("sheet.", nil, sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(&dnd5e.CharacterEvent{
+		CharacterID: characterID,
+		Sequence:    last.Sequence,
+		Type:        dnd5e.CharacterEventHistoryCompacted,
+		ActorID:     last.ActorID,
+		Timestamp:   last.Timestamp,
+		Changes:     append(changes, sheetChanges...),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal character event")
+	}
+	return data, nil
+}
+
+// diffFields compares the top level JSON fields of two values, either of which may be nil
+func diffFields[T any](prefix string, before, after *T) ([]dnd5e.CharacterFieldChange, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []dnd5e.CharacterFieldChange
+	for _, name := range names {
+		if bytes.Equal(beforeFields[name], afterFields[name]) {
+			continue
+		}
+		changes = append(changes, dnd5e.CharacterFieldChange{
+			Field:  prefix + name,
+			Before: beforeFields[name],
+			After:  afterFields[name],
+		})
+	}
+	return changes, nil
+}
+
+func jsonFields[T any](value *T) (map[string]json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal character for history")
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal character for history")
+	}
+	return fields, nil
+}
+
 // listByIndex is a helper function to list characters by any index
 func (r *redisRepository) listByIndex(ctx context.Context, indexKey string) ([]*toolkitchar.Data, error) {
 	// Get character IDs from index
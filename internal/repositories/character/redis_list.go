@@ -0,0 +1,687 @@
+package character
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+)
+
+var sortFields = []string{SortByName, SortByLevel, SortByCreatedAt, SortByUpdatedAt}
+
+const (
+	// listBatchSize is how many sort index entries List reads at a time
+	listBatchSize = 100
+
+	// summaryVersion is bumped when summaries gain fields. Older summaries are rebuilt from
+	// the character the first time List reads them.
+	summaryVersion = 1
+)
+
+// characterSummary holds the fields List filters and sorts on, so a page can be chosen
+// without loading every character
+type characterSummary struct {
+	ID        string          `json:"id"`
+	PlayerID  string          `json:"player_id"`
+	Name      string          `json:"name"`
+	ClassID   constants.Class `json:"class_id"`
+	RaceID    constants.Race  `json:"race_id"`
+	Level     int             `json:"level"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+
+	// Sharing from the sheet, so List can leave out characters the viewer may not see
+	SharedWithPlayers  []string `json:"shared_with_players,omitempty"`
+	SharedWithSessions []string `json:"shared_with_sessions,omitempty"`
+
+	Version int `json:"version"`
+}
+
+func newSummary(
+	charData *toolkitchar.Data,
+	sheet *dnd5e.CharacterSheet,
+	createdAt, updatedAt time.Time,
+) *characterSummary {
+	summary := &characterSummary{
+		ID:        charData.ID,
+		PlayerID:  charData.PlayerID,
+		Name:      charData.Name,
+		ClassID:   charData.ClassID,
+		RaceID:    charData.RaceID,
+		Level:     charData.Level,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		Version:   summaryVersion,
+	}
+	if sheet != nil {
+		summary.SharedWithPlayers = sheet.SharedWithPlayers
+		summary.SharedWithSessions = sheet.SharedWithSessions
+	}
+	return summary
+}
+
+// sortEntry is a character's position in one sort index. Entries order by score then member,
+// which is also how Redis orders a sorted set, so ties are broken the same way everywhere.
+type sortEntry struct {
+	Score  float64 `json:"score"`
+	Member string  `json:"member"`
+}
+
+func (e sortEntry) compare(other sortEntry) int {
+	if c := cmp.Compare(e.Score, other.Score); c != 0 {
+		return c
+	}
+	return strings.Compare(e.Member, other.Member)
+}
+
+// follows reports whether the entry comes after the cursor entry in the listing order
+func (e sortEntry) follows(cursor sortEntry, descending bool) bool {
+	if descending {
+		return e.compare(cursor) < 0
+	}
+	return e.compare(cursor) > 0
+}
+
+// characterID recovers the ID from a member, which for the name index is prefixed with the name
+func (e sortEntry) characterID() string {
+	if _, id, ok := strings.Cut(e.Member, "\x00"); ok {
+		return id
+	}
+	return e.Member
+}
+
+// sortEntry returns the summary's entry in the index for a sort field.
+// Names share a score so the set orders them by member, case-insensitively.
+func (s *characterSummary) sortEntry(field string) sortEntry {
+	switch field {
+	case SortByName:
+		return sortEntry{Member: strings.ToLower(s.Name) + "\x00" + s.ID}
+	case SortByLevel:
+		return sortEntry{Score: float64(s.Level), Member: s.ID}
+	case SortByUpdatedAt:
+		return sortEntry{Score: float64(s.UpdatedAt.UnixMilli()), Member: s.ID}
+	default:
+		return sortEntry{Score: float64(s.CreatedAt.UnixMilli()), Member: s.ID}
+	}
+}
+
+func (s *characterSummary) matches(input ListInput, sessionMembers map[string]bool) bool {
+	if input.PlayerID != "" && s.PlayerID != input.PlayerID {
+		return false
+	}
+	if sessionMembers != nil && !sessionMembers[s.ID] {
+		return false
+	}
+	if input.ClassID != "" && s.ClassID != input.ClassID {
+		return false
+	}
+	if input.RaceID != "" && s.RaceID != input.RaceID {
+		return false
+	}
+	if input.MinLevel > 0 && s.Level < input.MinLevel {
+		return false
+	}
+	if input.MaxLevel > 0 && s.Level > input.MaxLevel {
+		return false
+	}
+	if input.ViewerID != "" && !s.visibleTo(input.ViewerID, input.ViewerSessionID) {
+		return false
+	}
+	return true
+}
+
+// visibleTo reports whether a player viewing from a session, if any, may see the character
+func (s *characterSummary) visibleTo(playerID, sessionID string) bool {
+	return s.PlayerID == playerID ||
+		slices.Contains(s.SharedWithPlayers, playerID) ||
+		(sessionID != "" && slices.Contains(s.SharedWithSessions, sessionID))
+}
+
+// narrowsPlayerIndex reports whether a listing of a player's characters can leave any out
+func narrowsPlayerIndex(input ListInput) bool {
+	return input.SessionID != "" || input.ClassID != "" || input.RaceID != "" ||
+		input.MinLevel > 0 || input.MaxLevel > 0 ||
+		(input.ViewerID != "" && input.ViewerID != input.PlayerID)
+}
+
+func sortIndexKey(playerID, field string) string {
+	return playerIndexPrefix + playerID + ":sort:" + field
+}
+
+// addToSortIndexes queues adding a character to its player's sort indexes
+func addToSortIndexes(ctx context.Context, pipe redis.Pipeliner, summary *characterSummary) {
+	if summary.PlayerID == "" {
+		return
+	}
+	for _, field := range sortFields {
+		entry := summary.sortEntry(field)
+		pipe.ZAdd(ctx, sortIndexKey(summary.PlayerID, field), redis.Z{Score: entry.Score, Member: entry.Member})
+	}
+}
+
+// removeFromSortIndexes queues removing a character from its player's sort indexes
+func removeFromSortIndexes(ctx context.Context, pipe redis.Pipeliner, summary *characterSummary) {
+	if summary.PlayerID == "" {
+		return
+	}
+	for _, field := range sortFields {
+		pipe.ZRem(ctx, sortIndexKey(summary.PlayerID, field), summary.sortEntry(field).Member)
+	}
+}
+
+// getSummary loads a character's summary, building one from the character data for
+// characters stored before summaries existed
+func (r *redisRepository) getSummary(ctx context.Context, charData *toolkitchar.Data) (*characterSummary, error) {
+	result, err := r.client.Get(ctx, summaryKeyPrefix+charData.ID).Result()
+	if err != nil {
+		if err == redis.Nil {
+			now := r.clock.Now()
+			return newSummary(charData, nil, now, now), nil
+		}
+		return nil, errors.Wrapf(err, "failed to get character summary")
+	}
+
+	var summary characterSummary
+	if err := json.Unmarshal([]byte(result), &summary); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal character summary")
+	}
+	return &summary, nil
+}
+
+func marshalSummary(summary *characterSummary) ([]byte, error) {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal character summary")
+	}
+	return data, nil
+}
+
+// loadSummaries fetches summaries for the IDs in one round trip, skipping any that are missing.
+// Summaries from an older version are rebuilt.
+func (r *redisRepository) loadSummaries(ctx context.Context, ids []string) (map[string]*characterSummary, error) {
+	summaries := make(map[string]*characterSummary, len(ids))
+	if len(ids) == 0 {
+		return summaries, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = summaryKeyPrefix + id
+	}
+	results, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get character summaries")
+	}
+
+	for _, result := range results {
+		data, ok := result.(string)
+		if !ok {
+			continue
+		}
+		var summary *characterSummary
+		if err := json.Unmarshal([]byte(data), &summary); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal character summary")
+		}
+		if summary.Version < summaryVersion {
+			summary, err = r.rebuildSummary(ctx, summary)
+			if err != nil {
+				return nil, err
+			}
+			if summary == nil {
+				continue
+			}
+		}
+		summaries[summary.ID] = summary
+	}
+	return summaries, nil
+}
+
+// rebuildSummary stores a current summary for a character in place of an older one, keeping
+// its created and updated times. It returns nil if the character no longer exists.
+func (r *redisRepository) rebuildSummary(ctx context.Context, old *characterSummary) (*characterSummary, error) {
+	getOutput, err := r.Get(ctx, GetInput{ID: old.ID})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	summary := newSummary(getOutput.CharacterData, getOutput.Sheet, old.CreatedAt, old.UpdatedAt)
+	summaryData, err := marshalSummary(summary)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.client.Set(ctx, summaryKeyPrefix+summary.ID, summaryData, 0).Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to store character summary")
+	}
+	return summary, nil
+}
+
+// ensureSortIndexes rebuilds a player's sort indexes when they have drifted from the player
+// index, which happens for characters stored before the indexes existed
+func (r *redisRepository) ensureSortIndexes(ctx context.Context, playerID string) error {
+	playerKey := playerIndexPrefix + playerID
+	indexed, err := r.client.ZCard(ctx, sortIndexKey(playerID, SortByCreatedAt)).Result()
+	if err != nil {
+		return errors.Wrapf(err, "failed to count sort index")
+	}
+	owned, err := r.client.SCard(ctx, playerKey).Result()
+	if err != nil {
+		return errors.Wrapf(err, "failed to count player index")
+	}
+	if indexed == owned {
+		return nil
+	}
+
+	slog.InfoContext(ctx, "rebuilding character sort indexes",
+		"player_id", playerID,
+		"indexed", indexed,
+		"owned", owned)
+
+	characterIDs, err := r.client.SMembers(ctx, playerKey).Result()
+	if err != nil {
+		return errors.Wrapf(err, "failed to get characters from index %s", playerKey)
+	}
+
+	pipe := r.client.TxPipeline()
+	for _, field := range sortFields {
+		pipe.Del(ctx, sortIndexKey(playerID, field))
+	}
+	for _, id := range characterIDs {
+		var getOutput *GetOutput
+		getOutput, err = r.Get(ctx, GetInput{ID: id})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				pipe.SRem(ctx, playerKey, id)
+				continue
+			}
+			return err
+		}
+
+		var summary *characterSummary
+		summary, err = r.getSummary(ctx, getOutput.CharacterData)
+		if err != nil {
+			return err
+		}
+		summary = newSummary(getOutput.CharacterData, getOutput.Sheet, summary.CreatedAt, summary.UpdatedAt)
+		var summaryData []byte
+		summaryData, err = marshalSummary(summary)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, summaryKeyPrefix+id, summaryData, 0)
+		addToSortIndexes(ctx, pipe, summary)
+	}
+
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to rebuild sort indexes")
+	}
+	return nil
+}
+
+// pageCursor marks the last character returned, so the next page starts after it even if
+// characters are added or removed in between
+type pageCursor struct {
+	SortBy     string    `json:"sort_by"`
+	Descending bool      `json:"descending"`
+	After      sortEntry `json:"after"`
+}
+
+func encodePageCursor(cursor pageCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to encode page token")
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodePageCursor(token string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.InvalidArgument("invalid page token")
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, errors.InvalidArgument("invalid page token")
+	}
+	return &cursor, nil
+}
+
+func (r *redisRepository) List(ctx context.Context, input ListInput) (*ListOutput, error) {
+	if input.PlayerID == "" && input.SessionID == "" {
+		return nil, errors.InvalidArgument("player ID or session ID is required")
+	}
+	if input.SortBy == "" {
+		input.SortBy = SortByCreatedAt
+	}
+	if !slices.Contains(sortFields, input.SortBy) {
+		return nil, errors.InvalidArgumentf("unknown sort field %s", input.SortBy)
+	}
+	if input.MinLevel > 0 && input.MaxLevel > 0 && input.MinLevel > input.MaxLevel {
+		return nil, errors.InvalidArgument("min level cannot be greater than max level")
+	}
+	pageSize := int(input.PageSize)
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	pageSize = min(pageSize, MaxPageSize)
+
+	var cursor *pageCursor
+	if input.PageToken != "" {
+		var err error
+		cursor, err = decodePageCursor(input.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.SortBy != input.SortBy || cursor.Descending != input.Descending {
+			return nil, errors.InvalidArgument("page token does not match the requested sort")
+		}
+	}
+
+	var sessionMembers map[string]bool
+	if input.SessionID != "" {
+		memberIDs, err := r.client.SMembers(ctx, sessionIndexPrefix+input.SessionID).Result()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get characters in session %s", input.SessionID)
+		}
+		sessionMembers = make(map[string]bool, len(memberIDs))
+		for _, id := range memberIDs {
+			sessionMembers[id] = true
+		}
+	}
+
+	var page []sortEntry
+	var totalSize int
+	var more bool
+	var err error
+	if input.PlayerID == "" {
+		page, totalSize, more, err = r.sessionPage(ctx, input, sessionMembers, cursor, pageSize)
+	} else {
+		page, totalSize, more, err = r.playerPage(ctx, input, sessionMembers, cursor, pageSize)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ListOutput{
+		TotalSize: int32(totalSize), // nolint:gosec // bounded by the number of stored characters
+	}
+	if more {
+		output.NextPageToken, err = encodePageCursor(pageCursor{
+			SortBy:     input.SortBy,
+			Descending: input.Descending,
+			After:      page[len(page)-1],
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	output.Characters, err = r.loadCharacters(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// playerPage reads the player's sort index from the cursor in batches until it has a page,
+// loading only the summaries of the entries it reads. The total is the size of the index
+// when nothing is filtered out, and otherwise is counted over the summaries.
+func (r *redisRepository) playerPage(
+	ctx context.Context,
+	input ListInput,
+	sessionMembers map[string]bool,
+	cursor *pageCursor,
+	pageSize int,
+) ([]sortEntry, int, bool, error) {
+	if err := r.ensureSortIndexes(ctx, input.PlayerID); err != nil {
+		return nil, 0, false, err
+	}
+
+	var after *sortEntry
+	if cursor != nil {
+		after = &cursor.After
+	}
+	page := make([]sortEntry, 0, pageSize)
+	more := false
+	err := r.scanSortIndex(ctx, input, after, func(entry sortEntry, summary *characterSummary) bool {
+		if !summary.matches(input, sessionMembers) {
+			return true
+		}
+		if len(page) == pageSize {
+			more = true
+			return false
+		}
+		page = append(page, entry)
+		return true
+	})
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	switch {
+	case cursor == nil && !more:
+		return page, len(page), more, nil
+	case !narrowsPlayerIndex(input):
+		var total int64
+		total, err = r.client.ZCard(ctx, sortIndexKey(input.PlayerID, input.SortBy)).Result()
+		if err != nil {
+			return nil, 0, false, errors.Wrapf(err, "failed to count sort index")
+		}
+		return page, int(total), more, nil
+	}
+
+	total := 0
+	err = r.scanSortIndex(ctx, input, nil, func(_ sortEntry, summary *characterSummary) bool {
+		if summary.matches(input, sessionMembers) {
+			total++
+		}
+		return true
+	})
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return page, total, more, nil
+}
+
+// scanSortIndex visits the player's sort index entries after the cursor entry, if any, in
+// listing order with their summaries, reading a batch at a time until visit returns false
+func (r *redisRepository) scanSortIndex(
+	ctx context.Context,
+	input ListInput,
+	after *sortEntry,
+	visit func(entry sortEntry, summary *characterSummary) bool,
+) error {
+	key := sortIndexKey(input.PlayerID, input.SortBy)
+	for offset := int64(0); ; offset += listBatchSize {
+		entries, err := r.readSortIndex(ctx, key, input.SortBy, input.Descending, after, offset)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, len(entries))
+		for i, entry := range entries {
+			ids[i] = entry.characterID()
+		}
+		summaries, err := r.loadSummaries(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			// The range starts at the cursor's score, so entries tied with it can come first
+			if after != nil && !entry.follows(*after, input.Descending) {
+				continue
+			}
+			summary, ok := summaries[entry.characterID()]
+			if !ok {
+				continue
+			}
+			if !visit(entry, summary) {
+				return nil
+			}
+		}
+		if len(entries) < listBatchSize {
+			return nil
+		}
+	}
+}
+
+// readSortIndex reads one batch of a sort index in listing order, starting from the cursor
+// entry when there is one. The name index is read by member since every score is 0.
+func (r *redisRepository) readSortIndex(
+	ctx context.Context,
+	key, field string,
+	descending bool,
+	after *sortEntry,
+	offset int64,
+) ([]sortEntry, error) {
+	if field == SortByName {
+		by := &redis.ZRangeBy{Min: "-", Max: "+", Offset: offset, Count: listBatchSize}
+		var members []string
+		var err error
+		if descending {
+			if after != nil {
+				by.Max = "(" + after.Member
+			}
+			members, err = r.client.ZRevRangeByLex(ctx, key, by).Result()
+		} else {
+			if after != nil {
+				by.Min = "(" + after.Member
+			}
+			members, err = r.client.ZRangeByLex(ctx, key, by).Result()
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read sort index")
+		}
+		entries := make([]sortEntry, len(members))
+		for i, member := range members {
+			entries[i] = sortEntry{Member: member}
+		}
+		return entries, nil
+	}
+
+	by := &redis.ZRangeBy{Min: "-inf", Max: "+inf", Offset: offset, Count: listBatchSize}
+	var results []redis.Z
+	var err error
+	if descending {
+		if after != nil {
+			by.Max = strconv.FormatFloat(after.Score, 'f', -1, 64)
+		}
+		results, err = r.client.ZRevRangeByScoreWithScores(ctx, key, by).Result()
+	} else {
+		if after != nil {
+			by.Min = strconv.FormatFloat(after.Score, 'f', -1, 64)
+		}
+		results, err = r.client.ZRangeByScoreWithScores(ctx, key, by).Result()
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read sort index")
+	}
+	entries := make([]sortEntry, len(results))
+	for i, result := range results {
+		member, _ := result.Member.(string)
+		entries[i] = sortEntry{Score: result.Score, Member: member}
+	}
+	return entries, nil
+}
+
+// sessionPage sorts and filters a session's characters in memory, since a party is small
+func (r *redisRepository) sessionPage(
+	ctx context.Context,
+	input ListInput,
+	sessionMembers map[string]bool,
+	cursor *pageCursor,
+	pageSize int,
+) ([]sortEntry, int, bool, error) {
+	ids := make([]string, 0, len(sessionMembers))
+	for id := range sessionMembers {
+		ids = append(ids, id)
+	}
+	summaries, err := r.loadSummaries(ctx, ids)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	page, total, more := pageSummaries(summaries, input, sessionMembers, cursor, pageSize)
+	return page, total, more, nil
+}
+
+// pageSummaries returns the page of matching summaries after the cursor in listing order,
+// how many match in total, and whether more follow the page
+func pageSummaries(
+	summaries map[string]*characterSummary,
+	input ListInput,
+	sessionMembers map[string]bool,
+	cursor *pageCursor,
+	pageSize int,
+) ([]sortEntry, int, bool) {
+	matching := make([]sortEntry, 0, len(summaries))
+	for _, summary := range summaries {
+		if summary.matches(input, sessionMembers) {
+			matching = append(matching, summary.sortEntry(input.SortBy))
+		}
+	}
+	slices.SortFunc(matching, sortEntry.compare)
+	if input.Descending {
+		slices.Reverse(matching)
+	}
+
+	start := 0
+	if cursor != nil {
+		start = len(matching)
+		for i, entry := range matching {
+			if entry.follows(cursor.After, input.Descending) {
+				start = i
+				break
+			}
+		}
+	}
+	end := min(start+pageSize, len(matching))
+	return matching[start:end], len(matching), end < len(matching)
+}
+
+// loadCharacters fetches the characters for a page in one round trip, in page order.
+// Characters deleted since the page was chosen are skipped.
+func (r *redisRepository) loadCharacters(ctx context.Context, page []sortEntry) ([]*toolkitchar.Data, error) {
+	characters := make([]*toolkitchar.Data, 0, len(page))
+	if len(page) == 0 {
+		return characters, nil
+	}
+
+	keys := make([]string, len(page))
+	for i, entry := range page {
+		keys[i] = characterKeyPrefix + entry.characterID()
+	}
+	results, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get characters")
+	}
+
+	for _, result := range results {
+		data, ok := result.(string)
+		if !ok {
+			continue
+		}
+		var charData toolkitchar.Data
+		if err := json.Unmarshal([]byte(data), &charData); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal character data")
+		}
+		characters = append(characters, &charData)
+	}
+	return characters, nil
+}
@@ -0,0 +1,146 @@
+package character
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
+)
+
+type ListPagingTestSuite struct {
+	suite.Suite
+	summaries map[string]*characterSummary
+}
+
+func (s *ListPagingTestSuite) SetupTest() {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.summaries = map[string]*characterSummary{}
+	for i, summary := range []*characterSummary{
+		{ID: "char-a", PlayerID: "player-1", Name: "brienne", ClassID: constants.ClassFighter, Level: 3},
+		{ID: "char-b", PlayerID: "player-1", Name: "Arya", ClassID: constants.ClassRogue, Level: 5},
+		{ID: "char-c", PlayerID: "player-2", Name: "Davos", ClassID: constants.ClassFighter, Level: 3,
+			SharedWithPlayers: []string{"player-1"}},
+		{ID: "char-d", PlayerID: "player-2", Name: "Cersei", ClassID: constants.ClassFighter, Level: 7,
+			SharedWithSessions: []string{"session-1"}},
+		{ID: "char-e", PlayerID: "player-3", Name: "Edd", ClassID: constants.ClassFighter, Level: 1},
+	} {
+		summary.CreatedAt = created.Add(time.Duration(i) * time.Hour)
+		s.summaries[summary.ID] = summary
+	}
+}
+
+func (s *ListPagingTestSuite) ids(page []sortEntry) []string {
+	ids := make([]string, len(page))
+	for i, entry := range page {
+		ids[i] = entry.characterID()
+	}
+	return ids
+}
+
+func (s *ListPagingTestSuite) TestSortsByNameCaseInsensitively() {
+	page, total, more := pageSummaries(s.summaries, ListInput{SortBy: SortByName}, nil, nil, 10)
+
+	s.Equal([]string{"char-b", "char-a", "char-d", "char-c", "char-e"}, s.ids(page))
+	s.Equal(5, total)
+	s.False(more)
+}
+
+func (s *ListPagingTestSuite) TestLevelTiesBreakByID() {
+	input := ListInput{SortBy: SortByLevel, Descending: true}
+
+	page, _, _ := pageSummaries(s.summaries, input, nil, nil, 10)
+
+	s.Equal([]string{"char-d", "char-b", "char-c", "char-a", "char-e"}, s.ids(page))
+}
+
+func (s *ListPagingTestSuite) TestCursorContinuesAfterTheLastEntry() {
+	input := ListInput{SortBy: SortByLevel}
+
+	first, total, more := pageSummaries(s.summaries, input, nil, nil, 2)
+	s.Equal([]string{"char-e", "char-a"}, s.ids(first))
+	s.Equal(5, total)
+	s.True(more)
+
+	// The tie at level 3 is split across pages without repeating or skipping anyone
+	cursor := &pageCursor{SortBy: SortByLevel, After: first[len(first)-1]}
+	second, _, more := pageSummaries(s.summaries, input, nil, cursor, 2)
+	s.Equal([]string{"char-c", "char-b"}, s.ids(second))
+	s.True(more)
+
+	cursor.After = second[len(second)-1]
+	third, _, more := pageSummaries(s.summaries, input, nil, cursor, 2)
+	s.Equal([]string{"char-d"}, s.ids(third))
+	s.False(more)
+}
+
+func (s *ListPagingTestSuite) TestCursorSurvivesDeletion() {
+	input := ListInput{SortBy: SortByCreatedAt}
+	first, _, _ := pageSummaries(s.summaries, input, nil, nil, 2)
+	delete(s.summaries, "char-b")
+
+	cursor := &pageCursor{SortBy: SortByCreatedAt, After: first[len(first)-1]}
+	second, total, _ := pageSummaries(s.summaries, input, nil, cursor, 2)
+
+	s.Equal([]string{"char-c", "char-d"}, s.ids(second))
+	s.Equal(4, total)
+}
+
+func (s *ListPagingTestSuite) TestFilters() {
+	input := ListInput{SortBy: SortByCreatedAt, ClassID: constants.ClassFighter, MinLevel: 2, MaxLevel: 5}
+
+	page, total, _ := pageSummaries(s.summaries, input, nil, nil, 10)
+
+	s.Equal([]string{"char-a", "char-c"}, s.ids(page))
+	s.Equal(2, total)
+}
+
+func (s *ListPagingTestSuite) TestSessionMembers() {
+	members := map[string]bool{"char-a": true, "char-e": true}
+
+	page, total, _ := pageSummaries(s.summaries, ListInput{SortBy: SortByCreatedAt}, members, nil, 10)
+
+	s.Equal([]string{"char-a", "char-e"}, s.ids(page))
+	s.Equal(2, total)
+}
+
+func (s *ListPagingTestSuite) TestViewerOnlySeesOwnedAndSharedCharacters() {
+	input := ListInput{SortBy: SortByCreatedAt, ViewerID: "player-1"}
+
+	page, total, _ := pageSummaries(s.summaries, input, nil, nil, 10)
+	s.Equal([]string{"char-a", "char-b", "char-c"}, s.ids(page))
+	s.Equal(3, total)
+
+	input.ViewerSessionID = "session-1"
+	page, total, _ = pageSummaries(s.summaries, input, nil, nil, 10)
+	s.Equal([]string{"char-a", "char-b", "char-c", "char-d"}, s.ids(page))
+	s.Equal(4, total)
+}
+
+func (s *ListPagingTestSuite) TestNarrowsPlayerIndex() {
+	s.False(narrowsPlayerIndex(ListInput{PlayerID: "player-1"}))
+	s.False(narrowsPlayerIndex(ListInput{PlayerID: "player-1", ViewerID: "player-1"}))
+	s.True(narrowsPlayerIndex(ListInput{PlayerID: "player-1", ViewerID: "player-2"}))
+	s.True(narrowsPlayerIndex(ListInput{PlayerID: "player-1", RaceID: constants.RaceElf}))
+	s.True(narrowsPlayerIndex(ListInput{PlayerID: "player-1", SessionID: "session-1"}))
+}
+
+func (s *ListPagingTestSuite) TestPageTokenRoundTrip() {
+	cursor := pageCursor{SortBy: SortByName, Descending: true, After: sortEntry{Member: "arya\x00char-b"}}
+
+	token, err := encodePageCursor(cursor)
+	s.Require().NoError(err)
+	decoded, err := decodePageCursor(token)
+	s.Require().NoError(err)
+
+	s.Equal(cursor, *decoded)
+	s.Equal("char-b", decoded.After.characterID())
+
+	_, err = decodePageCursor("not a token")
+	s.Error(err)
+}
+
+func TestListPagingTestSuite(t *testing.T) {
+	suite.Run(t, new(ListPagingTestSuite))
+}
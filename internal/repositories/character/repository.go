@@ -5,8 +5,11 @@ package character
 
 import (
 	"context"
+	"time"
 
+	"github.com/KirkDiggler/rpg-api/internal/entities/dnd5e"
 	toolkitchar "github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/character"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/constants"
 )
 
 // Repository defines the interface for character persistence
@@ -29,7 +32,8 @@ type Repository interface {
 	// Returns errors.Internal for storage failures
 	Update(ctx context.Context, input UpdateInput) (*UpdateOutput, error)
 
-	// Delete deletes a character by ID
+	// Delete permanently deletes a character by ID, whether active or soft deleted,
+	// including its sheet and history
 	// Returns errors.InvalidArgument for empty/invalid IDs
 	// Returns errors.NotFound if character doesn't exist
 	// Returns errors.Internal for storage failures
@@ -44,36 +48,81 @@ type Repository interface {
 	// Returns errors.InvalidArgument for empty/invalid session IDs
 	// Returns errors.Internal for storage failures
 	ListBySessionID(ctx context.Context, input ListBySessionIDInput) (*ListBySessionIDOutput, error)
+
+	// List retrieves a filtered, sorted page of a player's or session's characters
+	// Returns errors.InvalidArgument if neither player nor session is set, for an unknown
+	// sort field or an invalid page token
+	// Returns errors.Internal for storage failures
+	List(ctx context.Context, input ListInput) (*ListOutput, error)
+
+	// SoftDelete hides a character from Get and player listings while keeping its data,
+	// sheet and history so it can be restored
+	// Returns errors.InvalidArgument for empty/invalid IDs
+	// Returns errors.NotFound if no active character has the ID
+	// Returns errors.Internal for storage failures
+	SoftDelete(ctx context.Context, input SoftDeleteInput) (*SoftDeleteOutput, error)
+
+	// Restore makes a soft deleted character active again
+	// Returns errors.InvalidArgument for empty/invalid IDs
+	// Returns errors.NotFound if no soft deleted character has the ID
+	// Returns errors.Internal for storage failures
+	Restore(ctx context.Context, input RestoreInput) (*RestoreOutput, error)
+
+	// ListDeleted retrieves soft deleted characters, either for a player or deleted before a time
+	// Returns errors.InvalidArgument if neither filter is set
+	// Returns errors.Internal for storage failures
+	ListDeleted(ctx context.Context, input ListDeletedInput) (*ListDeletedOutput, error)
+
+	// ListEvents retrieves a character's change history, oldest first
+	// Every Create and Update records an event in the same transaction as the write
+	// Returns errors.InvalidArgument for empty/invalid IDs
+	// Returns errors.Internal for storage failures
+	ListEvents(ctx context.Context, input ListEventsInput) (*ListEventsOutput, error)
 }
 
 // CreateInput defines the input for creating a character
 type CreateInput struct {
 	CharacterData *toolkitchar.Data
+	Sheet         *dnd5e.CharacterSheet // Optional rpg-api owned state
 }
 
 // CreateOutput defines the output for creating a character
 type CreateOutput struct {
 	CharacterData *toolkitchar.Data
+	Sheet         *dnd5e.CharacterSheet
 }
 
 // GetInput defines the input for getting a character
 type GetInput struct {
-	ID string
+	ID             string
+	IncludeDeleted bool // Also return soft deleted characters
 }
 
 // GetOutput defines the output for getting a character
 type GetOutput struct {
 	CharacterData *toolkitchar.Data
+	Sheet         *dnd5e.CharacterSheet // Nil if no sheet has been stored
+	DeletedAt     *time.Time            // Set when the character is soft deleted
+	Revision      int64                 // Bumped by every write; pass to UpdateInput.ExpectedRevision
 }
 
 // UpdateInput defines the input for updating a character
+// A nil Sheet leaves the stored sheet unchanged. The character data and
+// sheet are written in the same transaction.
 type UpdateInput struct {
 	CharacterData *toolkitchar.Data
+	Sheet         *dnd5e.CharacterSheet
+	EventType     string // Recorded in the character's history, defaults to dnd5e.CharacterEventUpdated
+	// ExpectedRevision, when set, makes the update fail with Aborted if the character has
+	// been written since it was read at that revision, so read-modify-write callers such as
+	// purchases never overwrite a concurrent change
+	ExpectedRevision *int64
 }
 
 // UpdateOutput defines the output for updating a character
 type UpdateOutput struct {
 	CharacterData *toolkitchar.Data
+	Sheet         *dnd5e.CharacterSheet
 }
 
 // DeleteInput defines the input for deleting a character
@@ -105,3 +154,92 @@ type ListBySessionIDInput struct {
 type ListBySessionIDOutput struct {
 	Characters []*toolkitchar.Data
 }
+
+// ListEventsInput defines the input for listing a character's history
+type ListEventsInput struct {
+	CharacterID string
+}
+
+// ListEventsOutput defines the output for listing a character's history
+type ListEventsOutput struct {
+	Events []*dnd5e.CharacterEvent
+}
+
+// Sort fields for List
+const (
+	SortByName      = "name"
+	SortByLevel     = "level"
+	SortByCreatedAt = "created_at"
+	SortByUpdatedAt = "updated_at"
+)
+
+// Page sizes for List
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ListInput defines the input for listing a page of characters.
+// At least one of PlayerID or SessionID is required; the other filters are optional.
+type ListInput struct {
+	PlayerID   string
+	SessionID  string
+	ClassID    constants.Class
+	RaceID     constants.Race
+	MinLevel   int
+	MaxLevel   int
+	SortBy     string // One of the SortBy values, defaults to SortByCreatedAt
+	Descending bool
+	PageSize   int32 // Defaults to DefaultPageSize, capped at MaxPageSize
+	PageToken  string
+
+	// ViewerID, when set, leaves out characters the player neither owns nor has had shared
+	// with them. ViewerSessionID also lets in characters shared with that session; the
+	// caller checks the viewer belongs to it.
+	ViewerID        string
+	ViewerSessionID string
+}
+
+// ListOutput defines the output for listing a page of characters
+type ListOutput struct {
+	Characters    []*toolkitchar.Data
+	NextPageToken string // Empty on the last page
+	TotalSize     int32  // Characters matching the filters and visible to the viewer across all pages
+}
+
+// SoftDeleteInput defines the input for soft deleting a character
+type SoftDeleteInput struct {
+	ID string
+}
+
+// SoftDeleteOutput defines the output for soft deleting a character
+type SoftDeleteOutput struct {
+	DeletedAt time.Time
+}
+
+// RestoreInput defines the input for restoring a soft deleted character
+type RestoreInput struct {
+	ID string
+}
+
+// RestoreOutput defines the output for restoring a soft deleted character
+type RestoreOutput struct {
+	CharacterData *toolkitchar.Data
+}
+
+// ListDeletedInput defines the input for listing soft deleted characters
+type ListDeletedInput struct {
+	PlayerID      string    // Characters deleted by this player
+	DeletedBefore time.Time // Characters deleted before this time, for purging
+}
+
+// ListDeletedOutput defines the output for listing soft deleted characters
+type ListDeletedOutput struct {
+	Characters []*DeletedCharacter
+}
+
+// DeletedCharacter is a soft deleted character and when it was deleted
+type DeletedCharacter struct {
+	CharacterData *toolkitchar.Data
+	DeletedAt     time.Time
+}
@@ -2,6 +2,9 @@ package encounters
 
 import (
 	"context"
+	"encoding/json"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/KirkDiggler/rpg-api/internal/errors"
@@ -33,10 +36,23 @@ func (r *InMemoryRepository) Save(ctx context.Context, input *SaveInput) (*SaveO
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	// Keep counting from any encounter this replaces, so updates read before it fail
+	var revision int64
+	if existing, ok := r.store[input.EncounterID]; ok {
+		revision = existing.Revision
+	}
+
 	r.store[input.EncounterID] = &EncounterData{
 		ID:             input.EncounterID,
+		CampaignID:     input.CampaignID,
+		SessionID:      input.SessionID,
 		RoomData:       input.RoomData,
 		InitiativeData: input.InitiativeData,
+		Initiative:     input.Initiative,
+
+		DifficultTerrain: input.DifficultTerrain,
+		Combatants:       input.Combatants,
+		Revision:         revision + 1,
 	}
 
 	return &SaveOutput{Success: true}, nil
@@ -61,13 +77,11 @@ func (r *InMemoryRepository) Get(ctx context.Context, input *GetInput) (*GetOutp
 	}
 
 	// Return a copy to prevent external modification
-	return &GetOutput{
-		Data: &EncounterData{
-			ID:             data.ID,
-			RoomData:       data.RoomData,
-			InitiativeData: data.InitiativeData,
-		},
-	}, nil
+	copied, err := clone(data)
+	if err != nil {
+		return nil, err
+	}
+	return &GetOutput{Data: copied}, nil
 }
 
 // Update modifies an existing encounter
@@ -83,15 +97,42 @@ func (r *InMemoryRepository) Update(ctx context.Context, input *UpdateInput) (*U
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	data, exists := r.store[input.EncounterID]
+	existing, exists := r.store[input.EncounterID]
 	if !exists {
 		return nil, errors.NotFound("encounter not found")
 	}
+	if input.ExpectedRevision != nil && *input.ExpectedRevision != existing.Revision {
+		return nil, errors.Abortedf("encounter %s changed since it was read", input.EncounterID)
+	}
+
+	// Update a copy so encounters handed out by Get never change underneath their readers
+	data := new(EncounterData)
+	*data = *existing
+	data.Revision++
+	r.store[input.EncounterID] = data
 
 	// Update only what's provided
 	if input.InitiativeData != nil {
 		data.InitiativeData = input.InitiativeData
 	}
+	if input.RoomData != nil {
+		data.RoomData = input.RoomData
+	}
+	if input.Turn != nil {
+		data.Turn = input.Turn
+	}
+	if input.Combatants != nil {
+		data.Combatants = input.Combatants
+	}
+	if input.ReactionsUsed != nil {
+		data.ReactionsUsed = input.ReactionsUsed
+	}
+	if input.PendingAction != nil {
+		data.PendingAction = input.PendingAction
+	}
+	if input.ClearPendingAction {
+		data.PendingAction = nil
+	}
 
 	return &UpdateOutput{Success: true}, nil
 }
@@ -117,3 +158,104 @@ func (r *InMemoryRepository) Delete(ctx context.Context, input *DeleteInput) (*D
 
 	return &DeleteOutput{Success: true}, nil
 }
+
+// ListByCampaignID retrieves every encounter in a campaign
+func (r *InMemoryRepository) ListByCampaignID(
+	ctx context.Context,
+	input *ListByCampaignIDInput,
+) (*ListByCampaignIDOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	if input.CampaignID == "" {
+		return nil, errors.InvalidArgument("campaign ID is required")
+	}
+
+	matches, err := r.filter(func(data *EncounterData) bool { return data.CampaignID == input.CampaignID })
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListByCampaignIDOutput{Encounters: matches}, nil
+}
+
+// ListBySessionID retrieves every encounter in a game session
+func (r *InMemoryRepository) ListBySessionID(
+	ctx context.Context,
+	input *ListBySessionIDInput,
+) (*ListBySessionIDOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	if input.SessionID == "" {
+		return nil, errors.InvalidArgument("session ID is required")
+	}
+
+	matches, err := r.filter(func(data *EncounterData) bool { return data.SessionID == input.SessionID })
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListBySessionIDOutput{Encounters: matches}, nil
+}
+
+// ListByCharacterID retrieves every encounter a character takes part in
+func (r *InMemoryRepository) ListByCharacterID(
+	ctx context.Context,
+	input *ListByCharacterIDInput,
+) (*ListByCharacterIDOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+
+	matches, err := r.filter(func(data *EncounterData) bool {
+		return slices.Contains(characterIDs(data), input.CharacterID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListByCharacterIDOutput{Encounters: matches}, nil
+}
+
+// filter returns copies of the matching encounters, ordered by ID
+func (r *InMemoryRepository) filter(match func(*EncounterData) bool) ([]*EncounterData, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []*EncounterData
+	for _, data := range r.store {
+		if match(data) {
+			copied, err := clone(data)
+			if err != nil {
+				return nil, err
+			}
+			matches = append(matches, copied)
+		}
+	}
+	slices.SortFunc(matches, func(a, b *EncounterData) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return matches, nil
+}
+
+// clone deep copies an encounter the way Redis stores it, so changes a caller makes to
+// an encounter it read, such as moving an entity in its room, never reach the store
+// unless they are written back
+func clone(data *EncounterData) (*EncounterData, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal encounter")
+	}
+	var copied EncounterData
+	if err := json.Unmarshal(payload, &copied); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal encounter")
+	}
+	return &copied, nil
+}
@@ -72,6 +72,51 @@ func (mr *MockRepositoryMockRecorder) Get(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepository)(nil).Get), ctx, input)
 }
 
+// ListByCampaignID mocks base method.
+func (m *MockRepository) ListByCampaignID(ctx context.Context, input *encounters.ListByCampaignIDInput) (*encounters.ListByCampaignIDOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCampaignID", ctx, input)
+	ret0, _ := ret[0].(*encounters.ListByCampaignIDOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByCampaignID indicates an expected call of ListByCampaignID.
+func (mr *MockRepositoryMockRecorder) ListByCampaignID(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCampaignID", reflect.TypeOf((*MockRepository)(nil).ListByCampaignID), ctx, input)
+}
+
+// ListByCharacterID mocks base method.
+func (m *MockRepository) ListByCharacterID(ctx context.Context, input *encounters.ListByCharacterIDInput) (*encounters.ListByCharacterIDOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCharacterID", ctx, input)
+	ret0, _ := ret[0].(*encounters.ListByCharacterIDOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByCharacterID indicates an expected call of ListByCharacterID.
+func (mr *MockRepositoryMockRecorder) ListByCharacterID(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCharacterID", reflect.TypeOf((*MockRepository)(nil).ListByCharacterID), ctx, input)
+}
+
+// ListBySessionID mocks base method.
+func (m *MockRepository) ListBySessionID(ctx context.Context, input *encounters.ListBySessionIDInput) (*encounters.ListBySessionIDOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBySessionID", ctx, input)
+	ret0, _ := ret[0].(*encounters.ListBySessionIDOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBySessionID indicates an expected call of ListBySessionID.
+func (mr *MockRepositoryMockRecorder) ListBySessionID(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBySessionID", reflect.TypeOf((*MockRepository)(nil).ListBySessionID), ctx, input)
+}
+
 // Save mocks base method.
 func (m *MockRepository) Save(ctx context.Context, input *encounters.SaveInput) (*encounters.SaveOutput, error) {
 	m.ctrl.T.Helper()
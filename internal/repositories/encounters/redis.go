@@ -0,0 +1,393 @@
+package encounters
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+
+	"github.com/KirkDiggler/rpg-api/internal/errors"
+	redisclient "github.com/KirkDiggler/rpg-api/internal/redis"
+)
+
+const (
+	encounterKeyPrefix     = "encounter:"
+	campaignIndexPrefix    = "encounter:campaign:"
+	sessionIndexPrefix     = "encounter:session:"
+	characterIndexPrefix   = "encounter:character:"
+	defaultTTL             = 24 * time.Hour
+	errEncounterIDRequired = "encounter ID is required"
+
+	// maxWriteAttempts bounds how often a write is retried when the encounter changes
+	// between reading it and writing it back
+	maxWriteAttempts = 5
+)
+
+// RedisConfig holds the configuration for the Redis repository
+type RedisConfig struct {
+	Client redisclient.Client
+
+	// TTL is how long an encounter is kept after it was last saved or updated, so
+	// abandoned encounters clean themselves up. Defaults to 24 hours.
+	TTL time.Duration
+}
+
+// Validate ensures all required dependencies are provided
+func (c *RedisConfig) Validate() error {
+	if c == nil {
+		return errors.InvalidArgument("config cannot be nil")
+	}
+	if c.Client == nil {
+		return errors.InvalidArgument("redis client is required")
+	}
+	if c.TTL < 0 {
+		return errors.InvalidArgument("TTL cannot be negative")
+	}
+	return nil
+}
+
+type redisRepository struct {
+	client redisclient.Client
+	ttl    time.Duration
+}
+
+// NewRedis creates a new Redis-backed encounter repository
+func NewRedis(cfg *RedisConfig) (Repository, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	ttl := cfg.TTL
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+
+	return &redisRepository{
+		client: cfg.Client,
+		ttl:    ttl,
+	}, nil
+}
+
+// Ensure redisRepository implements Repository
+var _ Repository = (*redisRepository)(nil)
+
+// Save stores an encounter, replacing any existing encounter with the same ID
+func (r *redisRepository) Save(ctx context.Context, input *SaveInput) (*SaveOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.EncounterID == "" {
+		return nil, errors.InvalidArgument(errEncounterIDRequired)
+	}
+
+	data := &EncounterData{
+		ID:             input.EncounterID,
+		CampaignID:     input.CampaignID,
+		SessionID:      input.SessionID,
+		RoomData:       input.RoomData,
+		InitiativeData: input.InitiativeData,
+		Initiative:     input.Initiative,
+
+		DifficultTerrain: input.DifficultTerrain,
+		Combatants:       input.Combatants,
+	}
+
+	err := r.write(ctx, input.EncounterID, func(existing *EncounterData) (*EncounterData, error) {
+		// Keep counting from any encounter this replaces, so updates read before it fail
+		data.Revision = 1
+		if existing != nil {
+			data.Revision = existing.Revision + 1
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to save encounter")
+	}
+
+	return &SaveOutput{Success: true}, nil
+}
+
+// Get retrieves an encounter by ID
+func (r *redisRepository) Get(ctx context.Context, input *GetInput) (*GetOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.EncounterID == "" {
+		return nil, errors.InvalidArgument(errEncounterIDRequired)
+	}
+
+	data, err := get(ctx, r.client, input.EncounterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetOutput{Data: data}, nil
+}
+
+// Update modifies an existing encounter and refreshes its TTL
+func (r *redisRepository) Update(ctx context.Context, input *UpdateInput) (*UpdateOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.EncounterID == "" {
+		return nil, errors.InvalidArgument(errEncounterIDRequired)
+	}
+
+	err := r.write(ctx, input.EncounterID, func(existing *EncounterData) (*EncounterData, error) {
+		if existing == nil {
+			return nil, errors.NotFound("encounter not found")
+		}
+		if input.ExpectedRevision != nil && *input.ExpectedRevision != existing.Revision {
+			return nil, errors.Abortedf("encounter %s changed since it was read", input.EncounterID)
+		}
+
+		// Update only what's provided
+		data := *existing
+		data.Revision++
+		if input.InitiativeData != nil {
+			data.InitiativeData = input.InitiativeData
+		}
+		if input.RoomData != nil {
+			data.RoomData = input.RoomData
+		}
+		if input.Turn != nil {
+			data.Turn = input.Turn
+		}
+		if input.Combatants != nil {
+			data.Combatants = input.Combatants
+		}
+		if input.ReactionsUsed != nil {
+			data.ReactionsUsed = input.ReactionsUsed
+		}
+		if input.PendingAction != nil {
+			data.PendingAction = input.PendingAction
+		}
+		if input.ClearPendingAction {
+			data.PendingAction = nil
+		}
+		return &data, nil
+	})
+	if err != nil {
+		if errors.IsNotFound(err) || errors.IsAborted(err) {
+			return nil, err
+		}
+		return nil, errors.Wrapf(err, "failed to update encounter")
+	}
+
+	return &UpdateOutput{Success: true}, nil
+}
+
+// Delete removes an encounter and its index entries
+func (r *redisRepository) Delete(ctx context.Context, input *DeleteInput) (*DeleteOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.EncounterID == "" {
+		return nil, errors.InvalidArgument(errEncounterIDRequired)
+	}
+
+	getOutput, err := r.Get(ctx, &GetInput{EncounterID: input.EncounterID})
+	if err != nil {
+		return nil, err
+	}
+	data := getOutput.Data
+
+	// Start transaction
+	pipe := r.client.TxPipeline()
+
+	pipe.Del(ctx, encounterKeyPrefix+data.ID)
+	for _, indexKey := range indexKeys(data) {
+		pipe.SRem(ctx, indexKey, data.ID)
+	}
+
+	// Execute transaction
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to delete encounter")
+	}
+
+	return &DeleteOutput{Success: true}, nil
+}
+
+// ListByCampaignID retrieves every encounter in a campaign
+func (r *redisRepository) ListByCampaignID(
+	ctx context.Context,
+	input *ListByCampaignIDInput,
+) (*ListByCampaignIDOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CampaignID == "" {
+		return nil, errors.InvalidArgument("campaign ID is required")
+	}
+
+	encounters, err := r.listByIndex(ctx, campaignIndexPrefix+input.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListByCampaignIDOutput{Encounters: encounters}, nil
+}
+
+// ListBySessionID retrieves every encounter in a game session
+func (r *redisRepository) ListBySessionID(
+	ctx context.Context,
+	input *ListBySessionIDInput,
+) (*ListBySessionIDOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.SessionID == "" {
+		return nil, errors.InvalidArgument("session ID is required")
+	}
+
+	encounters, err := r.listByIndex(ctx, sessionIndexPrefix+input.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListBySessionIDOutput{Encounters: encounters}, nil
+}
+
+// ListByCharacterID retrieves every encounter a character takes part in
+func (r *redisRepository) ListByCharacterID(
+	ctx context.Context,
+	input *ListByCharacterIDInput,
+) (*ListByCharacterIDOutput, error) {
+	if input == nil {
+		return nil, errors.InvalidArgument("input is required")
+	}
+	if input.CharacterID == "" {
+		return nil, errors.InvalidArgument("character ID is required")
+	}
+
+	encounters, err := r.listByIndex(ctx, characterIndexPrefix+input.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListByCharacterIDOutput{Encounters: encounters}, nil
+}
+
+// write reads the current version of an encounter, if any, builds the new version from it
+// and stores that with a fresh TTL, moving its index entries, in one transaction. The
+// encounter key is watched so a concurrent write makes the transaction fail, in which case
+// the read and build are retried.
+func (r *redisRepository) write(
+	ctx context.Context,
+	encounterID string,
+	build func(existing *EncounterData) (*EncounterData, error),
+) error {
+	key := encounterKeyPrefix + encounterID
+	for range maxWriteAttempts {
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			existing, err := get(ctx, tx, encounterID)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+
+			data, err := build(existing)
+			if err != nil {
+				return err
+			}
+			payload, err := json.Marshal(data)
+			if err != nil {
+				return errors.Wrapf(err, "failed to marshal encounter")
+			}
+
+			newIndexes := indexKeys(data)
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, payload, r.ttl)
+				if existing != nil {
+					for _, indexKey := range indexKeys(existing) {
+						if !slices.Contains(newIndexes, indexKey) {
+							pipe.SRem(ctx, indexKey, data.ID)
+						}
+					}
+				}
+				// Indexes outlive their newest encounter by at most one TTL; entries for
+				// encounters that expired sooner are cleaned up when listing
+				for _, indexKey := range newIndexes {
+					pipe.SAdd(ctx, indexKey, data.ID)
+					pipe.Expire(ctx, indexKey, r.ttl)
+				}
+				return nil
+			})
+			return err
+		}, key)
+		if err == redis.TxFailedErr {
+			continue
+		}
+		return err
+	}
+	return errors.Abortedf("encounter %s kept changing while it was being written", encounterID)
+}
+
+// get loads an encounter through cmd, which is the client or a transaction watching the key
+func get(ctx context.Context, cmd redis.Cmdable, encounterID string) (*EncounterData, error) {
+	result, err := cmd.Get(ctx, encounterKeyPrefix+encounterID).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.NotFound("encounter not found")
+		}
+		return nil, errors.Wrapf(err, "failed to get encounter")
+	}
+
+	var data EncounterData
+	if err := json.Unmarshal([]byte(result), &data); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal encounter")
+	}
+	return &data, nil
+}
+
+// listByIndex loads every encounter in an index, dropping entries for expired encounters
+func (r *redisRepository) listByIndex(ctx context.Context, indexKey string) ([]*EncounterData, error) {
+	encounterIDs, err := r.client.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get encounters from index %s", indexKey)
+	}
+
+	encounters := make([]*EncounterData, 0, len(encounterIDs))
+	for _, id := range encounterIDs {
+		var getOutput *GetOutput
+		getOutput, err = r.Get(ctx, &GetInput{EncounterID: id})
+		if err != nil {
+			// If the encounter expired or was deleted, clean up the index
+			if errors.IsNotFound(err) {
+				slog.WarnContext(ctx, "encounter not found, cleaning up index",
+					"encounter_id", id,
+					"index_key", indexKey)
+				r.client.SRem(ctx, indexKey, id)
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get encounter %s", id)
+		}
+		encounters = append(encounters, getOutput.Data)
+	}
+
+	// The index is a set, so order the results for a stable listing
+	slices.SortFunc(encounters, func(a, b *EncounterData) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return encounters, nil
+}
+
+// indexKeys returns every index an encounter belongs in
+func indexKeys(data *EncounterData) []string {
+	var keys []string
+	if data.CampaignID != "" {
+		keys = append(keys, campaignIndexPrefix+data.CampaignID)
+	}
+	if data.SessionID != "" {
+		keys = append(keys, sessionIndexPrefix+data.SessionID)
+	}
+	for _, characterID := range characterIDs(data) {
+		keys = append(keys, characterIndexPrefix+characterID)
+	}
+	return keys
+}
@@ -4,8 +4,11 @@ package encounters
 
 import (
 	"context"
+	"slices"
+	"time"
 
 	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/initiative"
+	"github.com/KirkDiggler/rpg-toolkit/rulebooks/dnd5e/shared"
 	"github.com/KirkDiggler/rpg-toolkit/tools/spatial"
 )
 
@@ -22,20 +25,192 @@ type Repository interface {
 
 	// Delete removes an encounter
 	Delete(ctx context.Context, input *DeleteInput) (*DeleteOutput, error)
+
+	// ListByCampaignID retrieves every encounter in a campaign
+	ListByCampaignID(ctx context.Context, input *ListByCampaignIDInput) (*ListByCampaignIDOutput, error)
+
+	// ListBySessionID retrieves every encounter in a game session
+	ListBySessionID(ctx context.Context, input *ListBySessionIDInput) (*ListBySessionIDOutput, error)
+
+	// ListByCharacterID retrieves every encounter a character takes part in
+	ListByCharacterID(ctx context.Context, input *ListByCharacterIDInput) (*ListByCharacterIDOutput, error)
 }
 
+// entityTypeCharacter is the room and initiative entity type used for player characters
+const entityTypeCharacter = "character"
+
 // EncounterData represents the persistent state of an encounter
 type EncounterData struct {
-	ID             string
-	RoomData       *spatial.RoomData
-	InitiativeData *initiative.TrackerData
+	ID             string                  `json:"id"`
+	CampaignID     string                  `json:"campaign_id,omitempty"`
+	SessionID      string                  `json:"session_id,omitempty"`
+	RoomData       *spatial.RoomData       `json:"room_data,omitempty"`
+	InitiativeData *initiative.TrackerData `json:"initiative_data,omitempty"`
+
+	// Initiative records how each participant's initiative was rolled, in turn order
+	Initiative []*InitiativeEntryData `json:"initiative,omitempty"`
+
+	// DifficultTerrain lists the cells that cost double movement to enter
+	DifficultTerrain []spatial.Position `json:"difficult_terrain,omitempty"`
+
+	// Turn tracks what the active entity has spent this turn; nil before anyone acts
+	Turn *TurnData `json:"turn,omitempty"`
+
+	// ReactionsUsed lists the entities that have spent their reaction since their own
+	// turn last started
+	ReactionsUsed []string `json:"reactions_used,omitempty"`
+
+	// PendingAction is an action paused until combatants decide whether to react to it;
+	// nothing else happens in the encounter until it resumes
+	PendingAction *PendingActionData `json:"pending_action,omitempty"`
+
+	// Combatants holds each entity's combat stats by entity ID. Characters are added the
+	// first time they are needed, from their character sheet.
+	Combatants map[string]*CombatantData `json:"combatants,omitempty"`
+
+	// Revision is bumped by every save and update; pass it to UpdateInput.ExpectedRevision
+	Revision int64 `json:"revision"`
+}
+
+// InitiativeEntryData records how one participant's initiative was rolled
+type InitiativeEntryData struct {
+	EntityID          string                 `json:"entity_id"`
+	Roll              int                    `json:"roll"` // The d20
+	DexterityModifier int                    `json:"dexterity_modifier"`
+	Bonuses           []*InitiativeBonusData `json:"bonuses,omitempty"`
+	Modifier          int                    `json:"modifier"` // DexterityModifier plus bonuses
+	Total             int                    `json:"total"`    // Roll + Modifier
+
+	// DexterityScore breaks ties in Total; RollOff, a d20 rolled only when the scores
+	// tie too, breaks what is left
+	DexterityScore int `json:"dexterity_score"`
+	RollOff        int `json:"roll_off,omitempty"`
+}
+
+// InitiativeBonusData is a bonus to initiative beyond the DEX modifier, such as the Alert feat
+type InitiativeBonusData struct {
+	Source string `json:"source"`
+	Value  int    `json:"value"`
+}
+
+// CombatantData is an entity's combat state for the length of an encounter. A character's
+// hit points are written back to the character whenever an action changes them.
+type CombatantData struct {
+	EntityID     string   `json:"entity_id"`
+	EntityType   string   `json:"entity_type"`
+	Name         string   `json:"name,omitempty"`
+	ArmorClass   int      `json:"armor_class"`
+	HitPoints    int      `json:"hit_points"`
+	MaxHitPoints int      `json:"max_hit_points"`
+	Conditions   []string `json:"conditions,omitempty"` // Condition types such as "prone" or "poisoned"
+
+	// AbilityScores are a monster's ability scores; characters' stay on their sheet
+	AbilityScores shared.AbilityScores `json:"ability_scores,omitempty"`
+
+	// Speed is a monster's walking speed in feet; characters use the speed on their sheet
+	Speed int `json:"speed,omitempty"`
+
+	// Damage types, such as "fire", the entity takes half, no or double damage from
+	DamageResistances     []string `json:"damage_resistances,omitempty"`
+	DamageImmunities      []string `json:"damage_immunities,omitempty"`
+	DamageVulnerabilities []string `json:"damage_vulnerabilities,omitempty"`
+
+	// Attacks lists the attacks a monster can make; characters attack with their equipped weapons
+	Attacks []*AttackData `json:"attacks,omitempty"`
+
+	// Shielded is set from casting Shield until the start of the combatant's next turn,
+	// adding 5 to its armor class
+	Shielded bool `json:"shielded,omitempty"`
+}
+
+// AttackData describes one attack from a monster's stat block
+type AttackData struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	AttackBonus int    `json:"attack_bonus"`
+	DamageDice  string `json:"damage_dice,omitempty"` // e.g. "1d6"
+	DamageBonus int    `json:"damage_bonus"`
+	DamageType  string `json:"damage_type,omitempty"`
+	ReachFeet   int    `json:"reach_feet,omitempty"`   // Melee reach, 0 for ranged attacks
+	NormalRange int    `json:"normal_range,omitempty"` // Ranged normal range in feet
+	LongRange   int    `json:"long_range,omitempty"`   // Ranged long range in feet
+}
+
+// TurnData tracks resources spent during one entity's turn. It belongs to the turn
+// identified by EntityID and Round; once initiative moves on it no longer applies.
+type TurnData struct {
+	EntityID              string `json:"entity_id"`
+	Round                 int    `json:"round"`
+	MovementUsed          int    `json:"movement_used"` // In feet
+	ActionUsed            bool   `json:"action_used,omitempty"`
+	BonusActionUsed       bool   `json:"bonus_action_used,omitempty"`
+	ObjectInteractionUsed bool   `json:"object_interaction_used,omitempty"` // The one free interaction, such as drawing a weapon
+	Disengaged            bool   `json:"disengaged,omitempty"`              // Movement this turn provokes no opportunity attacks
+}
+
+// Kinds of paused action
+const (
+	PendingActionMove   = "move"
+	PendingActionAttack = "attack"
+)
+
+// Responses to a pending reaction
+const (
+	ReactionAccepted = "accepted"
+	ReactionDeclined = "declined"
+	ReactionExpired  = "expired"
+)
+
+// PendingActionData is an action paused at the moment it triggered reactions
+type PendingActionData struct {
+	Type      string                 `json:"type"`      // PendingActionMove or PendingActionAttack
+	EntityID  string                 `json:"entity_id"` // The entity taking the action
+	Move      *PendingMoveData       `json:"move,omitempty"`
+	Attack    *PendingAttackData     `json:"attack,omitempty"`
+	Reactions []*PendingReactionData `json:"reactions"`
+}
+
+// PendingMoveData is the rest of a move that stopped where the mover was about to leave
+// an enemy's reach
+type PendingMoveData struct {
+	Path []spatial.Position `json:"path"` // Cells still to enter, ending at the target
+
+	// Offered lists the enemies already offered an opportunity attack during this move
+	Offered []string `json:"offered,omitempty"`
+}
+
+// PendingAttackData is an attack that hit and is waiting on the target's reaction. The
+// attack roll is kept so the attack resumes where it stopped.
+type PendingAttackData struct {
+	TargetID            string   `json:"target_id"`
+	WeaponID            string   `json:"weapon_id"`
+	Rolls               []int32  `json:"rolls"`
+	Roll                int32    `json:"roll"`
+	Critical            bool     `json:"critical,omitempty"`
+	AdvantageSources    []string `json:"advantage_sources,omitempty"`
+	DisadvantageSources []string `json:"disadvantage_sources,omitempty"`
+	Steps               []string `json:"steps,omitempty"`
+}
+
+// PendingReactionData is a reaction a combatant may take before a paused action resumes
+type PendingReactionData struct {
+	EntityID string    `json:"entity_id"`
+	Reaction string    `json:"reaction"` // e.g. "opportunity_attack" or "shield"
+	Deadline time.Time `json:"deadline"` // An unanswered reaction counts as declined after this
+	Response string    `json:"response,omitempty"`
 }
 
 // SaveInput defines the request for saving an encounter
 type SaveInput struct {
 	EncounterID    string
+	CampaignID     string // Optional
+	SessionID      string // Optional
 	RoomData       *spatial.RoomData
 	InitiativeData *initiative.TrackerData
+	Initiative     []*InitiativeEntryData
+
+	DifficultTerrain []spatial.Position
+	Combatants       map[string]*CombatantData
 }
 
 // SaveOutput defines the response for saving an encounter
@@ -54,9 +229,23 @@ type GetOutput struct {
 }
 
 // UpdateInput defines the request for updating an encounter
+// Nil fields are left unchanged.
 type UpdateInput struct {
 	EncounterID    string
 	InitiativeData *initiative.TrackerData // Usually what changes during encounter
+	RoomData       *spatial.RoomData       // Changes when entities move
+	Turn           *TurnData
+	Combatants     map[string]*CombatantData // Changes when entities take damage
+	ReactionsUsed  []string                  // An empty, non-nil slice clears every reaction
+	PendingAction  *PendingActionData
+
+	// ClearPendingAction removes the pending action once it has resumed
+	ClearPendingAction bool
+
+	// ExpectedRevision, when set, makes the update fail with Aborted if the encounter has
+	// been written since it was read at that revision, so an action checked against one
+	// version of the encounter is never applied on top of another
+	ExpectedRevision *int64
 }
 
 // UpdateOutput defines the response for updating an encounter
@@ -73,3 +262,55 @@ type DeleteInput struct {
 type DeleteOutput struct {
 	Success bool
 }
+
+// ListByCampaignIDInput defines the request for listing a campaign's encounters
+type ListByCampaignIDInput struct {
+	CampaignID string
+}
+
+// ListByCampaignIDOutput defines the response for listing a campaign's encounters
+type ListByCampaignIDOutput struct {
+	Encounters []*EncounterData
+}
+
+// ListBySessionIDInput defines the request for listing a session's encounters
+type ListBySessionIDInput struct {
+	SessionID string
+}
+
+// ListBySessionIDOutput defines the response for listing a session's encounters
+type ListBySessionIDOutput struct {
+	Encounters []*EncounterData
+}
+
+// ListByCharacterIDInput defines the request for listing a character's encounters
+type ListByCharacterIDInput struct {
+	CharacterID string
+}
+
+// ListByCharacterIDOutput defines the response for listing a character's encounters
+type ListByCharacterIDOutput struct {
+	Encounters []*EncounterData
+}
+
+// characterIDs returns the characters taking part in an encounter, whether placed in
+// the room or in the initiative order
+func characterIDs(data *EncounterData) []string {
+	var ids []string
+	if data.RoomData != nil {
+		for id, placement := range data.RoomData.Entities {
+			if placement.EntityType == entityTypeCharacter && !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	if data.InitiativeData != nil {
+		for _, entity := range data.InitiativeData.Order {
+			if entity.Type == entityTypeCharacter && !slices.Contains(ids, entity.ID) {
+				ids = append(ids, entity.ID)
+			}
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}